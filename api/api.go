@@ -1,14 +1,35 @@
 package api
 
 import (
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 
 	route "github.com/pandusatrianura/code-with-umam-categories-api/api/router"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/blob"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/scalar"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
 
+	CategoriesGraphQL "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/graphql"
+	CategoriesGRPC "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/grpc"
 	CategoriesHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/http"
+	CategoriesHandlerV2 "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/httpv2"
+	CategoriesSSE "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/sse"
 	CategoriesRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
 	CategoriesService "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	CategoriesStream "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/stream"
+	ProductsHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/delivery/http"
+	ProductsRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/repository"
+	ProductsService "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/service"
+	TenantsHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/delivery/http"
+	TenantsRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/repository"
+	TenantsService "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/service"
+	WebhooksHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/delivery/http"
+	WebhooksRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/repository"
+	WebhooksService "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/service"
+	"google.golang.org/grpc"
 )
 
 // Server represents an HTTP server with an address for listening to incoming requests.
@@ -22,14 +43,141 @@ func NewAPIServer(addr string) *Server {
 }
 
 // Run starts the server, initializes dependencies, registers routes, and listens for incoming HTTP requests.
+// When a gRPC port is configured, the gRPC API is served alongside on that port.
+// Category changes are published to registered webhooks and to the Server-Sent Events change stream.
+// Every request is resolved to a tenant first, and each tenant only sees its own categories and products.
 func (s *Server) Run() error {
 
-	categoriesRepo, err := CategoriesRepository.NewCategoriesRepository()
+	cfg := LoadConfig()
+
+	tenantResolver, err := tenant.NewResolver(tenant.Options{
+		Header:     cfg.TenantHeader,
+		BaseDomain: cfg.TenantBaseDomain,
+		Secret:     []byte(cfg.TenantSecret),
+		Claim:      cfg.TenantClaim,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	tenantsRepo, err := TenantsRepository.NewTenantsRepository()
+	if err != nil {
+		panic(err)
+	}
+
+	tenantsService, err := TenantsService.NewTenantsService(tenantsRepo)
+	if err != nil {
+		panic(err)
+	}
+
+	tenantsHandler, err := TenantsHandler.NewTenantsHandler(tenantsService, cfg.TenantAdminToken)
+	if err != nil {
+		panic(err)
+	}
+
+	var categoriesRepo CategoriesRepository.ICategoriesRepository
+	categoriesRepo, err = CategoriesRepository.NewCategoriesRepository()
+	if err != nil {
+		panic(err)
+	}
+
+	if cfg.CacheEnabled {
+		categoriesRepo, err = CategoriesRepository.NewCachedCategoriesRepository(categoriesRepo, cfg.CacheSize, cfg.CacheTTL)
+		if err != nil {
+			panic(err)
+		}
+		log.Printf("Repository cache enabled (size %d, ttl %s)", cfg.CacheSize, cfg.CacheTTL)
+	}
+
+	// The search index sits outermost so it sees every write, including those served by the cache.
+	categoriesRepo, err = CategoriesRepository.NewIndexedCategoriesRepository(categoriesRepo)
+	if err != nil {
+		panic(err)
+	}
+
+	attributesRepo, err := CategoriesRepository.NewAttributesRepository()
+	if err != nil {
+		panic(err)
+	}
+
+	attributesService, err := CategoriesService.NewAttributesService(attributesRepo, categoriesRepo)
+	if err != nil {
+		panic(err)
+	}
+
+	attributesHandler, err := CategoriesHandler.NewAttributesHandler(attributesService)
+	if err != nil {
+		panic(err)
+	}
+
+	webhooksRepo, err := WebhooksRepository.NewWebhooksRepository(cfg.WebhooksStorePath)
+	if err != nil {
+		panic(err)
+	}
+	defer webhooksRepo.Close()
+
+	webhooksService, err := WebhooksService.NewWebhooksService(webhooksRepo, WebhooksService.Options{
+		MaxAttempts:         cfg.WebhooksMaxAttempts,
+		AllowPrivateTargets: cfg.WebhooksAllowPrivate,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer webhooksService.Close()
+	webhooksService.ResumePending()
+
+	webhooksHandler, err := WebhooksHandler.NewWebhooksHandler(webhooksService)
+	if err != nil {
+		panic(err)
+	}
+
+	categoriesBroker, err := CategoriesStream.NewBroker(cfg.StreamReplaySize)
+	if err != nil {
+		panic(err)
+	}
+
+	categoriesStream, err := CategoriesSSE.NewStreamHandler(categoriesBroker, cfg.StreamHeartbeat)
+	if err != nil {
+		panic(err)
+	}
+
+	imagesStore, err := blob.NewLocalStore(cfg.ImagesDir)
 	if err != nil {
 		panic(err)
 	}
 
-	categoriesService, err := CategoriesService.NewCategoriesService(categoriesRepo)
+	imagesService, err := CategoriesService.NewImagesService(categoriesRepo, imagesStore, CategoriesService.ImageOptions{
+		MaxBytes:     int64(cfg.ImagesMaxBytes),
+		MaxDimension: cfg.ImagesMaxDimension,
+	}, webhooksService, categoriesBroker)
+	if err != nil {
+		panic(err)
+	}
+	defer imagesService.Close()
+
+	imagesHandler, err := CategoriesHandler.NewImagesHandler(imagesService, cfg.ImagesCacheMaxAge)
+	if err != nil {
+		panic(err)
+	}
+
+	productsRepo, err := ProductsRepository.NewProductsRepository()
+	if err != nil {
+		panic(err)
+	}
+
+	productsService, err := ProductsService.NewProductsService(productsRepo, categoriesRepo, ProductsService.Options{CategoryDeletePolicy: cfg.CategoryDeletePolicy})
+	if err != nil {
+		panic(err)
+	}
+
+	// Category deletes go through the products service, which refuses or cascades them while products remain. The
+	// images service is notified of deleted categories so their images are removed from the store.
+	categoriesService, err := CategoriesService.NewCategoriesService(categoriesRepo, attributesRepo, productsService, webhooksService, categoriesBroker, imagesService)
+	if err != nil {
+		panic(err)
+	}
+
+	productsHandler, err := ProductsHandler.NewProductsHandler(productsService)
 	if err != nil {
 		panic(err)
 	}
@@ -39,10 +187,90 @@ func (s *Server) Run() error {
 		panic(err)
 	}
 
-	r := route.NewRouter(categoriesHandler)
+	categoriesHandlerV2, err := CategoriesHandlerV2.NewCategoriesHandler(categoriesService)
+	if err != nil {
+		panic(err)
+	}
+
+	if cfg.GRPCPort != "" {
+		grpcServer, err := s.serveGRPC(fmt.Sprintf(":%s", cfg.GRPCPort), categoriesService, tenantResolver, tenantsService.Exists)
+		if err != nil {
+			return err
+		}
+		defer grpcServer.GracefulStop()
+	}
+
+	categoriesGraphQL, err := CategoriesGraphQL.NewGraphQLHandler(categoriesService)
+	if err != nil {
+		panic(err)
+	}
+
+	docsRenderer, err := scalar.RendererByName(cfg.DocsRenderer)
+	if err != nil {
+		panic(err)
+	}
+
+	idempotency, err := middleware.NewIdempotency(middleware.IdempotencyOptions{
+		TTL:        cfg.IdempotencyTTL,
+		MaxEntries: cfg.IdempotencyMaxEntries,
+		MaxBytes:   int64(cfg.IdempotencyMaxBytes),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	tenancy, err := middleware.NewTenancy(middleware.TenancyOptions{Resolver: tenantResolver, Exists: tenantsService.Exists})
+	if err != nil {
+		panic(err)
+	}
+
+	// Every API version is wrapped in its own middleware chain, so their errors are answered in its own format. The
+	// chains wrap the mounts before the version prefix is stripped, so idempotency keys never match across versions.
+	// Tenancy runs before idempotency so replayed responses are scoped to the tenant that recorded them.
+	mount := func(next http.Handler, writeError middleware.ErrorWriter) http.Handler {
+		return middleware.Recovery(tenancy.Middleware(idempotency.Middleware(next, writeError), writeError), writeError)
+	}
+
+	r := route.NewRouter(categoriesHandler, route.WithGraphQL(categoriesGraphQL), route.WithWebhooks(webhooksHandler), route.WithStream(categoriesStream), route.WithDocsRenderer(docsRenderer), route.WithV2(categoriesHandlerV2), route.WithTenants(tenantsHandler), route.WithAttributes(attributesHandler), route.WithImages(imagesHandler), route.WithProducts(productsHandler))
 	routes := r.RegisterRoutes()
 	router := http.NewServeMux()
-	router.Handle("/api/v1/", http.StripPrefix("/api/v1", routes))
+	router.Handle("/api/v1/", mount(http.StripPrefix("/api/v1", routes), middleware.WriteJSONError))
+	router.Handle("/api/v2/", mount(http.StripPrefix("/api/v2", r.RegisterV2Routes()), middleware.WriteProblemError))
+	// The OpenAPI spec is also served from the root, where tooling looks for it by default.
+	router.Handle("GET /openapi.json", mount(routes, middleware.WriteJSONError))
+	router.Handle("GET /openapi.yaml", mount(routes, middleware.WriteJSONError))
+
 	log.Println("Starting server on port", s.addr)
-	return http.ListenAndServe(s.addr, router)
+	return http.ListenAndServe(s.addr, middleware.Recovery(route.WithJSONFallback(router), middleware.WriteJSONError))
+}
+
+// serveGRPC starts a gRPC server exposing the categories API on addr in the background and returns it so the caller can stop it.
+// Calls are resolved to a tenant from their metadata the same way HTTP requests are.
+func (s *Server) serveGRPC(addr string, categoriesService CategoriesService.ICategoriesService, resolver *tenant.Resolver, exists func(string) bool) (*grpc.Server, error) {
+	categoriesServer, err := CategoriesGRPC.NewCategoriesServer(categoriesService)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantInterceptor, err := CategoriesGRPC.NewTenantInterceptor(resolver, exists)
+	if err != nil {
+		return nil, err
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(tenantInterceptor.Unary()), grpc.ChainStreamInterceptor(tenantInterceptor.Stream()))
+	categoriesServer.Register(grpcServer)
+
+	go func() {
+		log.Println("Starting gRPC server on port", addr)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
+
+	return grpcServer, nil
 }
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	CategoriesService "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	ProductsService "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/scalar"
+)
+
+const (
+	// defaultCacheSize is the number of repository entries kept when caching is enabled without an explicit size.
+	defaultCacheSize = 1024
+
+	// defaultCacheTTL is how long a cached repository entry stays valid when no TTL is configured.
+	defaultCacheTTL = 30 * time.Second
+
+	// defaultStreamReplaySize is the number of recent category events kept for clients resuming the change stream.
+	defaultStreamReplaySize = 256
+
+	// defaultStreamHeartbeat is the interval between keep-alive comments on an idle change stream.
+	defaultStreamHeartbeat = 15 * time.Second
+
+	// defaultWebhooksMaxAttempts is the number of delivery attempts before a webhook delivery is dead-lettered.
+	defaultWebhooksMaxAttempts = 5
+
+	// defaultIdempotencyTTL is how long responses to requests with an Idempotency-Key are replayed.
+	defaultIdempotencyTTL = 24 * time.Hour
+
+	// defaultImagesDir is the directory category images are stored in when none is configured.
+	defaultImagesDir = "data/images"
+
+	// defaultImagesCacheMaxAge is how long clients may cache a category image before revalidating it.
+	defaultImagesCacheMaxAge = time.Hour
+
+	// defaultCategoryDeletePolicy applies to deletes of categories with products when none or an unknown policy is
+	// configured.
+	defaultCategoryDeletePolicy = ProductsService.DeleteRestrict
+
+	// defaultDocsRenderer is the renderer of the API reference page when none or an unknown one is configured.
+	defaultDocsRenderer = "scalar"
+)
+
+// Config holds the runtime settings the server reads from the environment.
+type Config struct {
+	GRPCPort     string
+	CacheEnabled bool
+	CacheSize    int
+	CacheTTL     time.Duration
+
+	StreamReplaySize int
+	StreamHeartbeat  time.Duration
+
+	WebhooksStorePath    string
+	WebhooksMaxAttempts  int
+	WebhooksAllowPrivate bool
+
+	IdempotencyTTL        time.Duration
+	IdempotencyMaxEntries int
+	IdempotencyMaxBytes   int
+
+	ImagesDir          string
+	ImagesMaxBytes     int
+	ImagesMaxDimension int
+	ImagesCacheMaxAge  time.Duration
+
+	CategoryDeletePolicy ProductsService.DeletePolicy
+
+	DocsRenderer string
+
+	TenantHeader     string
+	TenantBaseDomain string
+	TenantSecret     string
+	TenantClaim      string
+	TenantAdminToken string
+}
+
+// LoadConfig reads the server configuration from environment variables, falling back to defaults for unset or invalid values.
+//
+//	GRPC_PORT                    port of the gRPC API; the gRPC server is not started when empty
+//	CATEGORIES_CACHE_ENABLED     enables the read-through repository cache ("true"/"false")
+//	CATEGORIES_CACHE_SIZE        maximum number of cached entries
+//	CATEGORIES_CACHE_TTL         lifetime of a cached entry as a Go duration, e.g. "30s"
+//	CATEGORIES_STREAM_REPLAY     number of recent events kept so change stream clients can resume with Last-Event-ID
+//	CATEGORIES_STREAM_HEARTBEAT  interval between keep-alive comments on the change stream, e.g. "15s"
+//	WEBHOOKS_STORE_PATH          JSON file persisting webhook subscriptions, the delivery log and dead letters; in-memory when empty
+//	WEBHOOKS_MAX_ATTEMPTS        delivery attempts before a webhook delivery is dead-lettered
+//	WEBHOOKS_ALLOW_PRIVATE       allows webhooks to loopback, private and link-local addresses, e.g. in development ("true"/"false")
+//	IDEMPOTENCY_TTL              how long responses to requests with an Idempotency-Key are replayed, e.g. "24h"
+//	IDEMPOTENCY_MAX_ENTRIES      number of responses kept for replay; the least recently used are dropped first
+//	IDEMPOTENCY_MAX_BYTES        total size in bytes of the responses kept for replay
+//	CATEGORY_IMAGES_DIR          directory category images are stored in; "data/images" when empty
+//	CATEGORY_IMAGES_MAX_BYTES    size of the largest accepted category image in bytes
+//	CATEGORY_IMAGES_MAX_PIXELS   largest accepted width and height of a category image in pixels
+//	CATEGORY_IMAGES_MAX_AGE      how long clients may cache a category image before revalidating it, e.g. "1h"
+//	CATEGORY_DELETE_POLICY       what deleting a category with products does: "restrict" refuses it, "cascade" deletes the products too
+//	DOCS_RENDERER                renderer of the API reference page: "scalar", "swagger-ui" or "redoc"
+//	TENANT_HEADER                request header naming the tenant; "X-Tenant-ID" when empty
+//	TENANT_BASE_DOMAIN           domain whose subdomains name tenants, e.g. "shop.example.com"; subdomains are ignored when empty
+//	TENANT_JWT_SECRET            HS256 secret of bearer tokens naming the tenant in a claim; when set, every request needs a token
+//	TENANT_CLAIM                 token claim naming the tenant; "tenant" when empty
+//	TENANT_ADMIN_TOKEN           token the tenant administration endpoints require in X-Admin-Token; they are closed when empty
+func LoadConfig() Config {
+	return Config{
+		GRPCPort:     os.Getenv("GRPC_PORT"),
+		CacheEnabled: envBool("CATEGORIES_CACHE_ENABLED", false),
+		CacheSize:    envInt("CATEGORIES_CACHE_SIZE", defaultCacheSize),
+		CacheTTL:     envDuration("CATEGORIES_CACHE_TTL", defaultCacheTTL),
+
+		StreamReplaySize: envInt("CATEGORIES_STREAM_REPLAY", defaultStreamReplaySize),
+		StreamHeartbeat:  envDuration("CATEGORIES_STREAM_HEARTBEAT", defaultStreamHeartbeat),
+
+		WebhooksStorePath:    os.Getenv("WEBHOOKS_STORE_PATH"),
+		WebhooksMaxAttempts:  envInt("WEBHOOKS_MAX_ATTEMPTS", defaultWebhooksMaxAttempts),
+		WebhooksAllowPrivate: envBool("WEBHOOKS_ALLOW_PRIVATE", false),
+
+		IdempotencyTTL:        envDuration("IDEMPOTENCY_TTL", defaultIdempotencyTTL),
+		IdempotencyMaxEntries: envInt("IDEMPOTENCY_MAX_ENTRIES", middleware.DefaultIdempotencyMaxEntries),
+		IdempotencyMaxBytes:   envInt("IDEMPOTENCY_MAX_BYTES", int(middleware.DefaultIdempotencyMaxBytes)),
+
+		ImagesDir:          envString("CATEGORY_IMAGES_DIR", defaultImagesDir),
+		ImagesMaxBytes:     envInt("CATEGORY_IMAGES_MAX_BYTES", CategoriesService.DefaultMaxImageBytes),
+		ImagesMaxDimension: envInt("CATEGORY_IMAGES_MAX_PIXELS", CategoriesService.DefaultMaxImageDimension),
+		ImagesCacheMaxAge:  envDuration("CATEGORY_IMAGES_MAX_AGE", defaultImagesCacheMaxAge),
+
+		CategoryDeletePolicy: envDeletePolicy("CATEGORY_DELETE_POLICY", defaultCategoryDeletePolicy),
+
+		DocsRenderer: envRenderer("DOCS_RENDERER", defaultDocsRenderer),
+
+		TenantHeader:     os.Getenv("TENANT_HEADER"),
+		TenantBaseDomain: os.Getenv("TENANT_BASE_DOMAIN"),
+		TenantSecret:     os.Getenv("TENANT_JWT_SECRET"),
+		TenantClaim:      os.Getenv("TENANT_CLAIM"),
+		TenantAdminToken: os.Getenv("TENANT_ADMIN_TOKEN"),
+	}
+}
+
+// envString returns the value of the environment variable key or fallback when it is unset or empty.
+func envString(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envBool returns the boolean value of the environment variable key or fallback when it is unset or unparsable.
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// envInt returns the positive integer value of the environment variable key or fallback when it is unset or invalid.
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// envDuration returns the positive duration value of the environment variable key or fallback when it is unset or invalid.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// envRenderer returns the API reference renderer named by the environment variable key or fallback when it is unset
+// or names no known renderer.
+func envRenderer(key string, fallback string) string {
+	value := strings.ToLower(os.Getenv(key))
+	if _, err := scalar.RendererByName(value); err != nil {
+		return fallback
+	}
+	return value
+}
+
+// envDeletePolicy returns the category delete policy named by the environment variable key or fallback when it is
+// unset or names no known policy.
+func envDeletePolicy(key string, fallback ProductsService.DeletePolicy) ProductsService.DeletePolicy {
+	switch policy := ProductsService.DeletePolicy(strings.ToLower(os.Getenv(key))); policy {
+	case ProductsService.DeleteRestrict, ProductsService.DeleteCascade:
+		return policy
+	}
+	return fallback
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	CategoriesService "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	ProductsService "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Config
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{},
+			want: Config{
+				CacheEnabled:          false,
+				CacheSize:             defaultCacheSize,
+				CacheTTL:              defaultCacheTTL,
+				StreamReplaySize:      defaultStreamReplaySize,
+				StreamHeartbeat:       defaultStreamHeartbeat,
+				WebhooksMaxAttempts:   defaultWebhooksMaxAttempts,
+				IdempotencyTTL:        defaultIdempotencyTTL,
+				IdempotencyMaxEntries: middleware.DefaultIdempotencyMaxEntries,
+				IdempotencyMaxBytes:   int(middleware.DefaultIdempotencyMaxBytes),
+				ImagesDir:             defaultImagesDir,
+				ImagesMaxBytes:        CategoriesService.DefaultMaxImageBytes,
+				ImagesMaxDimension:    CategoriesService.DefaultMaxImageDimension,
+				ImagesCacheMaxAge:     defaultImagesCacheMaxAge,
+				CategoryDeletePolicy:  defaultCategoryDeletePolicy,
+				DocsRenderer:          defaultDocsRenderer,
+			},
+		},
+		{
+			name: "cache",
+			env: map[string]string{
+				"CATEGORIES_CACHE_ENABLED":    "true",
+				"CATEGORIES_CACHE_SIZE":       "10",
+				"CATEGORIES_CACHE_TTL":        "5s",
+				"CATEGORIES_STREAM_REPLAY":    "32",
+				"CATEGORIES_STREAM_HEARTBEAT": "1s",
+				"GRPC_PORT":                   "9000",
+				"WEBHOOKS_STORE_PATH":         "data/webhooks.json",
+				"WEBHOOKS_MAX_ATTEMPTS":       "3",
+				"WEBHOOKS_ALLOW_PRIVATE":      "true",
+				"IDEMPOTENCY_TTL":             "1h",
+				"IDEMPOTENCY_MAX_ENTRIES":     "100",
+				"IDEMPOTENCY_MAX_BYTES":       "4096",
+				"CATEGORY_IMAGES_DIR":         "/var/lib/categories/images",
+				"CATEGORY_IMAGES_MAX_BYTES":   "2048",
+				"CATEGORY_IMAGES_MAX_PIXELS":  "256",
+				"CATEGORY_IMAGES_MAX_AGE":     "10m",
+				"CATEGORY_DELETE_POLICY":      "Cascade",
+				"DOCS_RENDERER":               "Redoc",
+				"TENANT_HEADER":               "X-Store",
+				"TENANT_BASE_DOMAIN":          "shop.example.com",
+				"TENANT_JWT_SECRET":           "s3cret",
+				"TENANT_CLAIM":                "store",
+				"TENANT_ADMIN_TOKEN":          "admin-s3cret",
+			},
+			want: Config{
+				GRPCPort:              "9000",
+				CacheEnabled:          true,
+				CacheSize:             10,
+				CacheTTL:              5 * time.Second,
+				StreamReplaySize:      32,
+				StreamHeartbeat:       time.Second,
+				WebhooksStorePath:     "data/webhooks.json",
+				WebhooksMaxAttempts:   3,
+				WebhooksAllowPrivate:  true,
+				IdempotencyTTL:        time.Hour,
+				IdempotencyMaxEntries: 100,
+				IdempotencyMaxBytes:   4096,
+				ImagesDir:             "/var/lib/categories/images",
+				ImagesMaxBytes:        2048,
+				ImagesMaxDimension:    256,
+				ImagesCacheMaxAge:     10 * time.Minute,
+				CategoryDeletePolicy:  ProductsService.DeleteCascade,
+				DocsRenderer:          "redoc",
+				TenantHeader:          "X-Store",
+				TenantBaseDomain:      "shop.example.com",
+				TenantSecret:          "s3cret",
+				TenantClaim:           "store",
+				TenantAdminToken:      "admin-s3cret",
+			},
+		},
+		{
+			name: "invalid",
+			env: map[string]string{
+				"CATEGORIES_CACHE_ENABLED":    "maybe",
+				"CATEGORIES_CACHE_SIZE":       "-1",
+				"CATEGORIES_CACHE_TTL":        "soon",
+				"CATEGORIES_STREAM_REPLAY":    "none",
+				"CATEGORIES_STREAM_HEARTBEAT": "-1s",
+				"WEBHOOKS_MAX_ATTEMPTS":       "0",
+				"IDEMPOTENCY_TTL":             "forever",
+				"IDEMPOTENCY_MAX_ENTRIES":     "all",
+				"IDEMPOTENCY_MAX_BYTES":       "-1",
+				"CATEGORY_IMAGES_MAX_BYTES":   "1MB",
+				"CATEGORY_IMAGES_MAX_PIXELS":  "-5",
+				"CATEGORY_IMAGES_MAX_AGE":     "a while",
+				"CATEGORY_DELETE_POLICY":      "nullify",
+				"DOCS_RENDERER":               "rapidoc",
+			},
+			want: Config{
+				CacheEnabled:          false,
+				CacheSize:             defaultCacheSize,
+				CacheTTL:              defaultCacheTTL,
+				StreamReplaySize:      defaultStreamReplaySize,
+				StreamHeartbeat:       defaultStreamHeartbeat,
+				WebhooksMaxAttempts:   defaultWebhooksMaxAttempts,
+				IdempotencyTTL:        defaultIdempotencyTTL,
+				IdempotencyMaxEntries: middleware.DefaultIdempotencyMaxEntries,
+				IdempotencyMaxBytes:   int(middleware.DefaultIdempotencyMaxBytes),
+				ImagesDir:             defaultImagesDir,
+				ImagesMaxBytes:        CategoriesService.DefaultMaxImageBytes,
+				ImagesMaxDimension:    CategoriesService.DefaultMaxImageDimension,
+				ImagesCacheMaxAge:     defaultImagesCacheMaxAge,
+				CategoryDeletePolicy:  defaultCategoryDeletePolicy,
+				DocsRenderer:          defaultDocsRenderer,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"GRPC_PORT", "CATEGORIES_CACHE_ENABLED", "CATEGORIES_CACHE_SIZE", "CATEGORIES_CACHE_TTL", "CATEGORIES_STREAM_REPLAY", "CATEGORIES_STREAM_HEARTBEAT", "WEBHOOKS_STORE_PATH", "WEBHOOKS_MAX_ATTEMPTS", "WEBHOOKS_ALLOW_PRIVATE", "IDEMPOTENCY_TTL", "IDEMPOTENCY_MAX_ENTRIES", "IDEMPOTENCY_MAX_BYTES", "CATEGORY_IMAGES_DIR", "CATEGORY_IMAGES_MAX_BYTES", "CATEGORY_IMAGES_MAX_PIXELS", "CATEGORY_IMAGES_MAX_AGE", "CATEGORY_DELETE_POLICY", "DOCS_RENDERER", "TENANT_HEADER", "TENANT_BASE_DOMAIN", "TENANT_JWT_SECRET", "TENANT_CLAIM", "TENANT_ADMIN_TOKEN"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got := LoadConfig()
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
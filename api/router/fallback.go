@@ -0,0 +1,90 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
+)
+
+// fallbackRecorder captures the status and headers written by the mux's built-in 404/405 handlers while discarding their plain-text body.
+type fallbackRecorder struct {
+	header http.Header
+	status int
+}
+
+// Header returns the header map the built-in handler writes into, e.g. the Allow header of a 405.
+func (f *fallbackRecorder) Header() http.Header {
+	return f.header
+}
+
+// Write discards the plain-text body produced by the built-in handler.
+func (f *fallbackRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// WriteHeader records the status chosen by the built-in handler.
+func (f *fallbackRecorder) WriteHeader(status int) {
+	f.status = status
+}
+
+// serveFallback serves r with mux when a route matches it. Otherwise it reports the status and Allow header the
+// mux's built-in handler would have answered with, and false.
+func serveFallback(mux *http.ServeMux, w http.ResponseWriter, r *http.Request) (int, string, bool) {
+	handler, pattern := mux.Handler(r)
+	if pattern != "" {
+		mux.ServeHTTP(w, r)
+		return 0, "", true
+	}
+
+	rec := &fallbackRecorder{header: http.Header{}}
+	handler.ServeHTTP(rec, r)
+	return rec.status, rec.header.Get("Allow"), false
+}
+
+// WithJSONFallback wraps mux so unmatched paths and methods are answered with the json_wrapper.APIResponse envelope
+// instead of net/http's plain-text bodies. A 405 response keeps the Allow header computed by the mux.
+func WithJSONFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, allow, served := serveFallback(mux, w, r)
+		if served {
+			return
+		}
+
+		var result json_wrapper.APIResponse
+		result.Code = constants.ErrorCode
+
+		if status == http.StatusMethodNotAllowed {
+			if allow != "" {
+				w.Header().Set("Allow", allow)
+			}
+			result.SetMessage(constants.Messages, r, constants.MsgMethodNotAllowed)
+			json_wrapper.WriteJSONResponse(w, http.StatusMethodNotAllowed, result)
+			return
+		}
+
+		result.SetMessage(constants.Messages, r, constants.MsgRouteNotFound)
+		json_wrapper.WriteJSONResponse(w, http.StatusNotFound, result)
+	})
+}
+
+// WithProblemFallback wraps mux like WithJSONFallback, answering unmatched paths and methods with problem details.
+func WithProblemFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, allow, served := serveFallback(mux, w, r)
+		if served {
+			return
+		}
+
+		if status == http.StatusMethodNotAllowed {
+			if allow != "" {
+				w.Header().Set("Allow", allow)
+			}
+			problem.WriteMessage(w, r, constants.Messages, http.StatusMethodNotAllowed, constants.MsgMethodNotAllowed, "", nil)
+			return
+		}
+
+		problem.WriteMessage(w, r, constants.Messages, http.StatusNotFound, constants.MsgRouteNotFound, "", nil)
+	})
+}
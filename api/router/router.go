@@ -1,10 +1,24 @@
 package router
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
+	"sync"
 
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	categoriesGraphQL "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/graphql"
 	categoriesHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/http"
+	categoriesHandlerV2 "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/httpv2"
+	categoriesSSE "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/sse"
+	productsHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/delivery/http"
+	tenantsHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/delivery/http"
+	webhooksHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/delivery/http"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/graphiql"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/openapi"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
 	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/scalar"
 )
 
@@ -12,41 +26,275 @@ import (
 // It integrates handlers for health checks and category-related operations.
 type Router struct {
 	categories *categoriesHandler.CategoriesHandler
+	graphql    *categoriesGraphQL.GraphQLHandler
+	webhooks   *webhooksHandler.WebhooksHandler
+	stream     *categoriesSSE.StreamHandler
+	v2         *categoriesHandlerV2.CategoriesHandler
+	tenants    *tenantsHandler.TenantsHandler
+	attributes *categoriesHandler.AttributesHandler
+	images     *categoriesHandler.ImagesHandler
+	products   *productsHandler.ProductsHandler
+
+	specOnce sync.Once
+	spec     *openapi.Document
+	specErr  error
+
+	v2SpecOnce sync.Once
+	v2Spec     *openapi.Document
+	v2SpecErr  error
+
+	docsRenderer scalar.Renderer
+
+	docsMu   sync.Mutex
+	docsHTML []byte
+}
+
+// Option configures optional handlers on a Router.
+type Option func(*Router)
+
+// WithGraphQL mounts the GraphQL endpoint and its playground page on the router.
+func WithGraphQL(graphqlHandler *categoriesGraphQL.GraphQLHandler) Option {
+	return func(r *Router) {
+		r.graphql = graphqlHandler
+	}
+}
+
+// WithWebhooks mounts the webhook subscription and delivery log endpoints on the router.
+func WithWebhooks(webhooksHandler *webhooksHandler.WebhooksHandler) Option {
+	return func(r *Router) {
+		r.webhooks = webhooksHandler
+	}
+}
+
+// WithStream mounts the Server-Sent Events change stream at /categories/stream.
+func WithStream(streamHandler *categoriesSSE.StreamHandler) Option {
+	return func(r *Router) {
+		r.stream = streamHandler
+	}
+}
+
+// WithV2 serves the v2 category endpoints from RegisterV2Routes.
+func WithV2(v2Handler *categoriesHandlerV2.CategoriesHandler) Option {
+	return func(r *Router) {
+		r.v2 = v2Handler
+	}
+}
+
+// WithTenants mounts the tenant administration endpoints on the router.
+func WithTenants(tenantsHandler *tenantsHandler.TenantsHandler) Option {
+	return func(r *Router) {
+		r.tenants = tenantsHandler
+	}
+}
+
+// WithAttributes mounts the category attribute definition endpoints on the router.
+func WithAttributes(attributesHandler *categoriesHandler.AttributesHandler) Option {
+	return func(r *Router) {
+		r.attributes = attributesHandler
+	}
+}
+
+// WithImages mounts the category image upload and download endpoints on the router.
+func WithImages(imagesHandler *categoriesHandler.ImagesHandler) Option {
+	return func(r *Router) {
+		r.images = imagesHandler
+	}
+}
+
+// WithProducts mounts the product endpoints and the product list of every category on the router.
+func WithProducts(productsHandler *productsHandler.ProductsHandler) Option {
+	return func(r *Router) {
+		r.products = productsHandler
+	}
+}
+
+// WithDocsRenderer renders the API reference page at /categories/docs with renderer instead of Scalar.
+func WithDocsRenderer(renderer scalar.Renderer) Option {
+	return func(r *Router) {
+		r.docsRenderer = renderer
+	}
 }
 
 // NewRouter initializes a new Router with the given health check and categories handlers.
-func NewRouter(categoriesHandler *categoriesHandler.CategoriesHandler) *Router {
-	return &Router{
-		categories: categoriesHandler,
+// Optional handlers such as GraphQL are attached through opts.
+func NewRouter(categoriesHandler *categoriesHandler.CategoriesHandler, opts ...Option) *Router {
+	r := &Router{
+		categories:   categoriesHandler,
+		docsRenderer: scalar.Scalar{},
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// RegisterRoutes registers every route of the route table returned by Routes.
+// Unknown paths and unsupported methods are answered with JSON 404/405 responses.
+func (h *Router) RegisterRoutes() http.Handler {
+	return WithJSONFallback(h.mux())
 }
 
-// RegisterRoutes initializes and registers all HTTP routes for health checks and category operations.
-func (h *Router) RegisterRoutes() *http.ServeMux {
+// mux returns a ServeMux serving the route table.
+func (h *Router) mux() *http.ServeMux {
 	r := http.NewServeMux()
-	r.HandleFunc("GET /categories/health", h.categories.API)
-	r.HandleFunc("POST /categories", h.categories.InsertCategory)
-	r.HandleFunc("GET /categories", h.categories.GetAllCategories)
-	r.HandleFunc("GET /categories/{id}", h.categories.GetCategoryByID)
-	r.HandleFunc("PUT /categories/{id}", h.categories.UpdateCategory)
-	r.HandleFunc("DELETE /categories/{id}", h.categories.DeleteCategory)
-	r.HandleFunc("GET /categories/docs", func(w http.ResponseWriter, r *http.Request) {
-		htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
-			SpecURL: "./docs/swagger.json",
-			CustomOptions: scalar.CustomOptions{
-				PageTitle: "Test Categories API",
-			},
-			DarkMode: true,
-		})
+	for _, route := range h.Routes() {
+		r.HandleFunc(route.Pattern(), route.Handler)
+	}
 
-		if err != nil {
-			fmt.Printf("%v", err)
-		}
+	return r
+}
+
+// RegisterV2Routes registers every route of the route table returned by V2Routes.
+// Unknown paths and unsupported methods are answered with problem+json 404/405 responses.
+func (h *Router) RegisterV2Routes() http.Handler {
+	r := http.NewServeMux()
+	for _, route := range h.V2Routes() {
+		r.HandleFunc(route.Pattern(), route.Handler)
+	}
 
-		_, err = fmt.Fprintln(w, htmlContent)
+	return WithProblemFallback(r)
+}
+
+// Spec returns the OpenAPI document generated from the documented routes. It is built once and reused.
+func (h *Router) Spec() (*openapi.Document, error) {
+	h.specOnce.Do(func() {
+		h.spec, h.specErr = openapi.Build(specConfig, h.Routes())
+	})
+
+	return h.spec, h.specErr
+}
+
+// V2Spec returns the OpenAPI document generated from the documented v2 routes. It is built once and reused.
+func (h *Router) V2Spec() (*openapi.Document, error) {
+	h.v2SpecOnce.Do(func() {
+		h.v2Spec, h.v2SpecErr = openapi.Build(v2SpecConfig, h.V2Routes())
+	})
+
+	return h.v2Spec, h.v2SpecErr
+}
+
+// openAPIJSON serves the OpenAPI spec as JSON.
+func (h *Router) openAPIJSON(w http.ResponseWriter, r *http.Request) {
+	h.writeSpec(w, r, "application/json", (*openapi.Document).JSON)
+}
+
+// openAPIYAML serves the OpenAPI spec as YAML.
+func (h *Router) openAPIYAML(w http.ResponseWriter, r *http.Request) {
+	h.writeSpec(w, r, "application/yaml", (*openapi.Document).YAML)
+}
+
+// v2OpenAPIJSON serves the v2 OpenAPI spec as JSON, or a problem when the spec cannot be built.
+func (h *Router) v2OpenAPIJSON(w http.ResponseWriter, r *http.Request) {
+	spec, err := h.V2Spec()
+	var content []byte
+	if err == nil {
+		content, err = spec.JSON()
+	}
+	if err != nil {
+		log.Printf("router: %v", err)
+		problem.WriteMessage(w, r, constants.Messages, http.StatusInternalServerError, constants.MsgInternalServer, "", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(content)
+}
+
+// writeSpec writes the OpenAPI spec encoded by encode, or a 500 response when the spec cannot be built.
+func (h *Router) writeSpec(w http.ResponseWriter, r *http.Request, contentType string, encode func(*openapi.Document) ([]byte, error)) {
+	content, err := h.specContent(encode)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(content)
+}
+
+// specContent returns the OpenAPI spec encoded by encode.
+func (h *Router) specContent(encode func(*openapi.Document) ([]byte, error)) ([]byte, error) {
+	spec, err := h.Spec()
+	if err != nil {
+		return nil, err
+	}
+
+	return encode(spec)
+}
+
+// docs serves the API reference for the generated OpenAPI spec, rendered with the router's docs renderer. The page
+// is rendered once and reused; a failed render, e.g. of a request that went away, is retried by the next request.
+func (h *Router) docs(w http.ResponseWriter, r *http.Request) {
+	h.docsMu.Lock()
+	if h.docsHTML == nil {
+		content, err := h.renderDocs(r.Context())
 		if err != nil {
+			h.docsMu.Unlock()
+			writeInternalError(w, r, err)
 			return
 		}
+		h.docsHTML = content
+	}
+	content := h.docsHTML
+	h.docsMu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(content)
+}
+
+// renderDocs renders the API reference page. The Scalar renderer loads the vendored Scalar bundle from
+// docsBundlePath when one is embedded and falls back to the Scalar CDN otherwise.
+func (h *Router) renderDocs(ctx context.Context) ([]byte, error) {
+	content, err := h.specContent((*openapi.Document).JSON)
+	if err != nil {
+		return nil, err
+	}
+
+	cdn := scalar.DefaultCDN
+	if _, ok := scalar.Bundle(); ok {
+		// Relative to /categories/docs, so the page works under any mount prefix.
+		cdn = "docs/assets/" + scalar.BundleName
+	} else if _, ok := h.docsRenderer.(scalar.Scalar); ok {
+		log.Printf("router: scalar bundle is not vendored, docs load it from %s", cdn)
+	}
+
+	htmlContent, err := scalar.RenderHTML(ctx, h.docsRenderer, &scalar.Options{
+		CDN:         cdn,
+		SpecContent: string(content),
+		CustomOptions: scalar.CustomOptions{
+			PageTitle: "Categories API",
+		},
+		DarkMode: true,
 	})
-	return r
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(htmlContent + "\n"), nil
+}
+
+// playground serves the GraphiQL playground for the GraphQL endpoint.
+func (h *Router) playground(w http.ResponseWriter, r *http.Request) {
+	htmlContent, err := graphiql.PlaygroundHTML(&graphiql.Options{
+		PageTitle: "Categories GraphQL Playground",
+	})
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = fmt.Fprintln(w, htmlContent)
+}
+
+// writeInternalError logs err and answers with a JSON 500 response that does not leak its details.
+func writeInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("router: %v", err)
+
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, constants.MsgInternalServer)
+	json_wrapper.WriteJSONResponse(w, http.StatusInternalServerError, result)
 }
@@ -1,16 +1,46 @@
 package router
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
-	"os"
-	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	categoriesGraphQL "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/graphql"
 	categoriesHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/http"
+	categoriesHandlerV2 "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/httpv2"
+	categoriesSSE "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/sse"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	categoriesRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	categoriesService "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	categoriesStream "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/stream"
+	productsHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/delivery/http"
+	productsRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/repository"
+	productsService "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/service"
+	tenantsHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/delivery/http"
+	tenantsRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/repository"
+	tenantsService "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/service"
+	webhooksHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/delivery/http"
+	webhooksRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/repository"
+	webhooksService "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/blob"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/openapi"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/pagination"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/scalar"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+	"gopkg.in/yaml.v3"
 )
 
 type fakeCategoriesService struct {
@@ -21,6 +51,9 @@ type fakeCategoriesService struct {
 	getByIDResp entity.Category
 	getByIDErr  error
 
+	getBySlugResp  entity.Category
+	getBySlugMoved bool
+
 	insertResp entity.Category
 
 	updateResp entity.Category
@@ -29,25 +62,41 @@ type fakeCategoriesService struct {
 	deleteResp int64
 	deleteErr  error
 
+	searchResp []entity.SearchResult
+
+	translationResp entity.Category
+	translationErr  error
+
+	reorderResp []entity.Category
+	reorderErr  error
+
 	apiCalls     int
 	getAllCalls  int
 	getByIDCalls int
 	insertCalls  int
 	updateCalls  int
 	deleteCalls  int
+	searchCalls  int
+
+	translationCalls int
+	reorderCalls     int
 
 	lastGetByID int64
 	lastInsert  entity.Category
 	lastUpdate  entity.Category
 	lastDelete  int64
+	lastSearch  string
+
+	lastTranslationLocale string
+	lastReorder           []int64
 }
 
-func (f *fakeCategoriesService) GetAllCategories() []entity.Category {
+func (f *fakeCategoriesService) GetAllCategories(_ context.Context) []entity.Category {
 	f.getAllCalls++
 	return f.getAllResp
 }
 
-func (f *fakeCategoriesService) GetCategoryByID(categoryID int64) (entity.Category, error) {
+func (f *fakeCategoriesService) GetCategoryByID(_ context.Context, categoryID int64) (entity.Category, error) {
 	f.getByIDCalls++
 	f.lastGetByID = categoryID
 	if f.getByIDErr != nil {
@@ -56,13 +105,20 @@ func (f *fakeCategoriesService) GetCategoryByID(categoryID int64) (entity.Catego
 	return f.getByIDResp, nil
 }
 
-func (f *fakeCategoriesService) InsertCategory(parameter entity.Category) entity.Category {
+func (f *fakeCategoriesService) GetCategoryBySlug(_ context.Context, slug string) (entity.Category, bool, error) {
+	if f.getBySlugResp.ID == 0 {
+		return entity.Category{}, false, entity.ErrCategoryNotFound
+	}
+	return f.getBySlugResp, f.getBySlugMoved, nil
+}
+
+func (f *fakeCategoriesService) InsertCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
 	f.insertCalls++
 	f.lastInsert = parameter
-	return f.insertResp
+	return f.insertResp, nil
 }
 
-func (f *fakeCategoriesService) UpdateCategory(parameter entity.Category) (entity.Category, error) {
+func (f *fakeCategoriesService) UpdateCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
 	f.updateCalls++
 	f.lastUpdate = parameter
 	if f.updateErr != nil {
@@ -71,7 +127,7 @@ func (f *fakeCategoriesService) UpdateCategory(parameter entity.Category) (entit
 	return f.updateResp, nil
 }
 
-func (f *fakeCategoriesService) DeleteCategory(categoryID int64) (int64, error) {
+func (f *fakeCategoriesService) DeleteCategory(_ context.Context, categoryID int64) (int64, error) {
 	f.deleteCalls++
 	f.lastDelete = categoryID
 	if f.deleteErr != nil {
@@ -80,6 +136,30 @@ func (f *fakeCategoriesService) DeleteCategory(categoryID int64) (int64, error)
 	return f.deleteResp, nil
 }
 
+func (f *fakeCategoriesService) SearchCategories(_ context.Context, query string, limit int) []entity.SearchResult {
+	f.searchCalls++
+	f.lastSearch = query
+	return f.searchResp
+}
+
+func (f *fakeCategoriesService) UpsertCategoryTranslation(_ context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	f.translationCalls++
+	f.lastTranslationLocale = locale
+	if f.translationErr != nil {
+		return entity.Category{}, f.translationErr
+	}
+	return f.translationResp, nil
+}
+
+func (f *fakeCategoriesService) ReorderCategories(_ context.Context, ids []int64) ([]entity.Category, error) {
+	f.reorderCalls++
+	f.lastReorder = ids
+	if f.reorderErr != nil {
+		return nil, f.reorderErr
+	}
+	return f.reorderResp, nil
+}
+
 func (f *fakeCategoriesService) API() entity.HealthResponse {
 	f.apiCalls++
 	return f.apiResp
@@ -115,23 +195,29 @@ func TestNewRouter(t *testing.T) {
 
 func TestRouter_RegisterRoutes(t *testing.T) {
 	type callCounts struct {
-		api     int
-		getAll  int
-		getByID int
-		insert  int
-		update  int
-		del     int
+		api         int
+		getAll      int
+		getByID     int
+		insert      int
+		update      int
+		del         int
+		search      int
+		translation int
+		reorder     int
 	}
 
 	type expectations struct {
-		calls         callCounts
-		getByID       *int64
-		updateID      *int64
-		insertName    *string
-		deleteID      *int64
-		bodyContains  string
-		expectStatus  int
-		needsRepoRoot bool
+		calls        callCounts
+		getByID      *int64
+		updateID     *int64
+		insertName   *string
+		deleteID     *int64
+		searchQuery  *string
+		locale       *string
+		reorder      []int64
+		bodyContains string
+		expectStatus int
+		expectAllow  string
 	}
 
 	int64Ptr := func(v int64) *int64 { return &v }
@@ -169,6 +255,35 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 				calls:        callCounts{api: 1},
 			},
 		},
+		{
+			name:   "search",
+			method: http.MethodGet,
+			path:   "/categories/search?q=hanphone",
+			setupSvc: func(svc *fakeCategoriesService) {
+				svc.searchResp = []entity.SearchResult{{Category: entity.Category{ID: 3, Name: "Handphone"}, Score: 1}}
+			},
+			expect: expectations{
+				expectStatus: http.StatusOK,
+				calls:        callCounts{search: 1},
+				searchQuery:  stringPtr("hanphone"),
+				bodyContains: `"Handphone"`,
+			},
+		},
+		{
+			name:   "reorder",
+			method: http.MethodPost,
+			path:   "/categories/reorder",
+			body:   `{"ids":[3,1]}`,
+			setupSvc: func(svc *fakeCategoriesService) {
+				svc.reorderResp = []entity.Category{{ID: 3, Name: "Handphone", Position: 512}, {ID: 1, Name: "Elektronik", Position: 1024}}
+			},
+			expect: expectations{
+				expectStatus: http.StatusOK,
+				calls:        callCounts{reorder: 1},
+				reorder:      []int64{3, 1},
+				bodyContains: `"message_code":"CATEGORIES_REORDERED"`,
+			},
+		},
 		{
 			name:   "get all",
 			method: http.MethodGet,
@@ -210,11 +325,36 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 		{
 			name:   "get by id bad",
 			method: http.MethodGet,
-			path:   "/categories/abc",
+			path:   "/categories/Bad_Slug",
 			expect: expectations{
 				expectStatus: http.StatusBadRequest,
 			},
 		},
+		{
+			name:   "get by slug",
+			method: http.MethodGet,
+			path:   "/categories/handphone",
+			setupSvc: func(svc *fakeCategoriesService) {
+				svc.getBySlugResp = entity.Category{ID: 3, Name: "Handphone", Slug: "handphone"}
+			},
+			expect: expectations{
+				expectStatus: http.StatusOK,
+				bodyContains: `"slug":"handphone"`,
+			},
+		},
+		{
+			name:   "get by old slug",
+			method: http.MethodGet,
+			path:   "/categories/hp",
+			setupSvc: func(svc *fakeCategoriesService) {
+				svc.getBySlugResp = entity.Category{ID: 3, Name: "Handphone", Slug: "handphone"}
+				svc.getBySlugMoved = true
+			},
+			expect: expectations{
+				expectStatus: http.StatusMovedPermanently,
+				bodyContains: `"slug":"handphone"`,
+			},
+		},
 		{
 			name:   "insert ok",
 			method: http.MethodPost,
@@ -270,6 +410,29 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 				expectStatus: http.StatusBadRequest,
 			},
 		},
+		{
+			name:   "translation ok",
+			method: http.MethodPut,
+			path:   "/categories/10/translations/en",
+			body:   `{"name":"Electronics","description":"D"}`,
+			setupSvc: func(svc *fakeCategoriesService) {
+				svc.translationResp = entity.Category{ID: 10, Name: "Elektronik"}
+			},
+			expect: expectations{
+				expectStatus: http.StatusOK,
+				calls:        callCounts{translation: 1},
+				locale:       stringPtr("en"),
+			},
+		},
+		{
+			name:   "translation bad locale",
+			method: http.MethodPut,
+			path:   "/categories/10/translations/english",
+			body:   `{"name":"Electronics"}`,
+			expect: expectations{
+				expectStatus: http.StatusBadRequest,
+			},
+		},
 		{
 			name:   "delete ok",
 			method: http.MethodDelete,
@@ -292,21 +455,31 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 			},
 		},
 		{
-			name:   "reference",
+			name:   "docs",
 			method: http.MethodGet,
-			path:   "/categories/reference",
+			path:   "/categories/docs",
 			expect: expectations{
-				expectStatus:  http.StatusOK,
-				bodyContains:  "Simple API",
-				needsRepoRoot: true,
+				expectStatus: http.StatusOK,
+				bodyContains: "Categories API",
 			},
 		},
 		{
 			name:   "method mismatch",
 			method: http.MethodPost,
 			path:   "/categories/health",
+			expect: expectations{
+				expectStatus: http.StatusMethodNotAllowed,
+				expectAllow:  "DELETE, GET, HEAD, PUT",
+				bodyContains: `"message":"metode tidak diizinkan"`,
+			},
+		},
+		{
+			name:   "unknown path",
+			method: http.MethodGet,
+			path:   "/unknown",
 			expect: expectations{
 				expectStatus: http.StatusNotFound,
+				bodyContains: `"message":"endpoint tidak ditemukan"`,
 			},
 		},
 	}
@@ -329,22 +502,6 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 				t.Fatal("expected mux")
 			}
 
-			if tc.expect.needsRepoRoot {
-				root := findRepoRoot(t)
-				cwd, err := os.Getwd()
-				if err != nil {
-					t.Fatalf("getwd: %v", err)
-				}
-				if err := os.Chdir(root); err != nil {
-					t.Fatalf("chdir: %v", err)
-				}
-				defer func() {
-					if err := os.Chdir(cwd); err != nil {
-						t.Fatalf("restore chdir: %v", err)
-					}
-				}()
-			}
-
 			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
 			rec := httptest.NewRecorder()
 			mux.ServeHTTP(rec, req)
@@ -353,6 +510,10 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 				t.Fatalf("expected status %d, got %d", tc.expect.expectStatus, rec.Code)
 			}
 
+			if tc.expect.expectAllow != "" && rec.Header().Get("Allow") != tc.expect.expectAllow {
+				t.Fatalf("expected Allow %q, got %q", tc.expect.expectAllow, rec.Header().Get("Allow"))
+			}
+
 			if tc.expect.bodyContains != "" && !strings.Contains(rec.Body.String(), tc.expect.bodyContains) {
 				t.Fatalf("expected body to contain %q", tc.expect.bodyContains)
 			}
@@ -375,6 +536,15 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 			if svc.deleteCalls != tc.expect.calls.del {
 				t.Fatalf("expected delete calls %d, got %d", tc.expect.calls.del, svc.deleteCalls)
 			}
+			if svc.searchCalls != tc.expect.calls.search {
+				t.Fatalf("expected search calls %d, got %d", tc.expect.calls.search, svc.searchCalls)
+			}
+			if svc.translationCalls != tc.expect.calls.translation {
+				t.Fatalf("expected translation calls %d, got %d", tc.expect.calls.translation, svc.translationCalls)
+			}
+			if svc.reorderCalls != tc.expect.calls.reorder {
+				t.Fatalf("expected reorder calls %d, got %d", tc.expect.calls.reorder, svc.reorderCalls)
+			}
 
 			if tc.expect.getByID != nil && svc.lastGetByID != *tc.expect.getByID {
 				t.Fatalf("expected getByID %d, got %d", *tc.expect.getByID, svc.lastGetByID)
@@ -382,30 +552,868 @@ func TestRouter_RegisterRoutes(t *testing.T) {
 			if tc.expect.updateID != nil && svc.lastUpdate.ID != *tc.expect.updateID {
 				t.Fatalf("expected update id %d, got %d", *tc.expect.updateID, svc.lastUpdate.ID)
 			}
+			if tc.expect.searchQuery != nil && svc.lastSearch != *tc.expect.searchQuery {
+				t.Fatalf("expected search query %q, got %q", *tc.expect.searchQuery, svc.lastSearch)
+			}
+			if tc.expect.locale != nil && svc.lastTranslationLocale != *tc.expect.locale {
+				t.Fatalf("expected translation locale %q, got %q", *tc.expect.locale, svc.lastTranslationLocale)
+			}
 			if tc.expect.insertName != nil && svc.lastInsert.Name != *tc.expect.insertName {
 				t.Fatalf("expected insert name %q, got %q", *tc.expect.insertName, svc.lastInsert.Name)
 			}
 			if tc.expect.deleteID != nil && svc.lastDelete != *tc.expect.deleteID {
 				t.Fatalf("expected delete id %d, got %d", *tc.expect.deleteID, svc.lastDelete)
 			}
+			if tc.expect.reorder != nil && !slices.Equal(svc.lastReorder, tc.expect.reorder) {
+				t.Fatalf("expected reorder %v, got %v", tc.expect.reorder, svc.lastReorder)
+			}
+		})
+	}
+}
+
+func TestRouter_GraphQLRoutes(t *testing.T) {
+	svc := &fakeCategoriesService{getAllResp: []entity.Category{{ID: 1, Name: "A"}}}
+	handler, err := categoriesHandler.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	gql, err := categoriesGraphQL.NewGraphQLHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected graphql error: %v", err)
+	}
+
+	cases := []struct {
+		name         string
+		opts         []Option
+		method       string
+		path         string
+		body         string
+		expectStatus int
+		bodyContains string
+	}{
+		{
+			name:         "query",
+			opts:         []Option{WithGraphQL(gql)},
+			method:       http.MethodPost,
+			path:         "/graphql",
+			body:         `{"query":"{ categories { items { name } } }"}`,
+			expectStatus: http.StatusOK,
+			bodyContains: `"name":"A"`,
+		},
+		{
+			name:         "playground",
+			opts:         []Option{WithGraphQL(gql)},
+			method:       http.MethodGet,
+			path:         "/graphql/playground",
+			expectStatus: http.StatusOK,
+			bodyContains: "Categories GraphQL Playground",
+		},
+		{
+			name:         "disabled",
+			method:       http.MethodPost,
+			path:         "/graphql",
+			body:         `{"query":"{ categories { total } }"}`,
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := NewRouter(handler, tc.opts...).RegisterRoutes()
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectStatus, rec.Code)
+			}
+			if tc.bodyContains != "" && !strings.Contains(rec.Body.String(), tc.bodyContains) {
+				t.Fatalf("expected body to contain %q, got %s", tc.bodyContains, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouter_StreamRoute(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	broker, _ := categoriesStream.NewBroker(4)
+	streamHandler, err := categoriesSSE.NewStreamHandler(broker, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected stream handler error: %v", err)
+	}
+
+	cases := []struct {
+		name              string
+		opts              []Option
+		expectStatus      int
+		expectContentType string
+	}{
+		{name: "enabled", opts: []Option{WithStream(streamHandler)}, expectStatus: http.StatusOK, expectContentType: "text/event-stream"},
+		{name: "disabled", expectStatus: http.StatusInternalServerError, expectContentType: "application/json"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := NewRouter(handler, tc.opts...).RegisterRoutes()
+
+			// A cancelled context ends the stream right after its preamble.
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req := httptest.NewRequest(http.MethodGet, "/categories/stream", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tc.expectContentType) {
+				t.Fatalf("expected content type %q, got %q", tc.expectContentType, got)
+			}
+		})
+	}
+}
+
+func TestRouter_WebhookRoutes(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	repo, _ := webhooksRepository.NewWebhooksRepository("")
+	svc, err := webhooksService.NewWebhooksService(repo, webhooksService.Options{})
+	if err != nil {
+		t.Fatalf("unexpected webhooks service error: %v", err)
+	}
+	defer svc.Close()
+	webhooks, _ := webhooksHandler.NewWebhooksHandler(svc)
+
+	mux := NewRouter(handler, WithWebhooks(webhooks)).RegisterRoutes()
+
+	cases := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectStatus int
+		bodyContains string
+	}{
+		{
+			name:         "register",
+			method:       http.MethodPost,
+			path:         "/webhooks",
+			body:         `{"url":"https://example.com/hook"}`,
+			expectStatus: http.StatusCreated,
+			bodyContains: `"secret":"whsec_`,
+		},
+		{name: "list", method: http.MethodGet, path: "/webhooks", expectStatus: http.StatusOK, bodyContains: "https://example.com/hook"},
+		{name: "get", method: http.MethodGet, path: "/webhooks/1", expectStatus: http.StatusOK},
+		{name: "deliveries", method: http.MethodGet, path: "/webhooks/1/deliveries", expectStatus: http.StatusOK},
+		{name: "dead letters", method: http.MethodGet, path: "/webhooks/dead-letters", expectStatus: http.StatusOK},
+		{name: "retry unknown delivery", method: http.MethodPost, path: "/webhooks/dead-letters/1/retry", expectStatus: http.StatusNotFound},
+		{name: "delete", method: http.MethodDelete, path: "/webhooks/1", expectStatus: http.StatusOK},
+		{name: "get deleted", method: http.MethodGet, path: "/webhooks/1", expectStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectStatus, rec.Code, rec.Body.String())
+			}
+			if tc.bodyContains != "" && !strings.Contains(rec.Body.String(), tc.bodyContains) {
+				t.Fatalf("expected body to contain %q, got %s", tc.bodyContains, rec.Body.String())
+			}
+		})
+	}
+}
+
+// newTenantsHandler returns a tenants handler backed by a real, freshly seeded tenants service.
+func newTenantsHandler(t *testing.T) *tenantsHandler.TenantsHandler {
+	t.Helper()
+
+	repo, _ := tenantsRepository.NewTenantsRepository()
+	svc, err := tenantsService.NewTenantsService(repo)
+	if err != nil {
+		t.Fatalf("unexpected tenants service error: %v", err)
+	}
+	handler, _ := tenantsHandler.NewTenantsHandler(svc, testAdminToken)
+	return handler
+}
+
+// testAdminToken is the admin token of the tenants handler returned by newTenantsHandler.
+const testAdminToken = "s3cret-admin"
+
+func TestRouter_TenantRoutes(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	mux := NewRouter(handler, WithTenants(newTenantsHandler(t))).RegisterRoutes()
+
+	cases := []struct {
+		name         string
+		method       string
+		path         string
+		tenant       string
+		noToken      bool
+		body         string
+		expectStatus int
+		bodyContains string
+	}{
+		{
+			name:         "register",
+			method:       http.MethodPost,
+			path:         "/tenants",
+			body:         `{"id":"acme","name":"Acme"}`,
+			expectStatus: http.StatusCreated,
+			bodyContains: `"id":"acme"`,
+		},
+		{name: "register duplicate", method: http.MethodPost, path: "/tenants", body: `{"id":"acme","name":"Acme"}`, expectStatus: http.StatusConflict},
+		{name: "list", method: http.MethodGet, path: "/tenants", expectStatus: http.StatusOK, bodyContains: `"id":"acme"`},
+		{name: "list from another tenant", method: http.MethodGet, path: "/tenants", tenant: "acme", expectStatus: http.StatusForbidden},
+		{name: "list without admin token", method: http.MethodGet, path: "/tenants", noToken: true, expectStatus: http.StatusForbidden},
+		{name: "register without admin token", method: http.MethodPost, path: "/tenants", body: `{"id":"globex"}`, noToken: true, expectStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if !tc.noToken {
+				req.Header.Set(tenantsHandler.AdminTokenHeader, testAdminToken)
+			}
+			if tc.tenant != "" {
+				req = req.WithContext(tenant.WithID(req.Context(), tc.tenant))
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectStatus, rec.Code, rec.Body.String())
+			}
+			if tc.bodyContains != "" && !strings.Contains(rec.Body.String(), tc.bodyContains) {
+				t.Fatalf("expected body to contain %q, got %s", tc.bodyContains, rec.Body.String())
+			}
+		})
+	}
+}
+
+// newAttributesHandler returns an attributes handler backed by a real attributes service over the default categories.
+func newAttributesHandler(t *testing.T) *categoriesHandler.AttributesHandler {
+	t.Helper()
+
+	repo, _ := categoriesRepository.NewAttributesRepository()
+	categories, _ := categoriesRepository.NewCategoriesRepository()
+	svc, err := categoriesService.NewAttributesService(repo, categories)
+	if err != nil {
+		t.Fatalf("unexpected attributes service error: %v", err)
+	}
+	handler, _ := categoriesHandler.NewAttributesHandler(svc)
+	return handler
+}
+
+func TestRouter_AttributeRoutes(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	mux := NewRouter(handler, WithAttributes(newAttributesHandler(t))).RegisterRoutes()
+
+	cases := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectStatus int
+		bodyContains string
+	}{
+		{
+			name:         "define",
+			method:       http.MethodPost,
+			path:         "/category-attributes",
+			body:         `{"key":"color","type":"enum","values":["red","blue"],"default":"red"}`,
+			expectStatus: http.StatusCreated,
+			bodyContains: `"key":"color"`,
+		},
+		{name: "define duplicate", method: http.MethodPost, path: "/category-attributes", body: `{"key":"color","type":"string"}`, expectStatus: http.StatusConflict},
+		{name: "define invalid", method: http.MethodPost, path: "/category-attributes", body: `{"key":"Color","type":"string"}`, expectStatus: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, path: "/category-attributes", expectStatus: http.StatusOK, bodyContains: `"values":["red","blue"]`},
+		{name: "get", method: http.MethodGet, path: "/category-attributes/color", expectStatus: http.StatusOK, bodyContains: `"default":"red"`},
+		{name: "update", method: http.MethodPut, path: "/category-attributes/color", body: `{"type":"string","required":true}`, expectStatus: http.StatusOK, bodyContains: `"required":true`},
+		{name: "delete", method: http.MethodDelete, path: "/category-attributes/color", expectStatus: http.StatusOK},
+		{name: "get deleted", method: http.MethodGet, path: "/category-attributes/color", expectStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectStatus, rec.Code, rec.Body.String())
+			}
+			if tc.bodyContains != "" && !strings.Contains(rec.Body.String(), tc.bodyContains) {
+				t.Fatalf("expected body to contain %q, got %s", tc.bodyContains, rec.Body.String())
+			}
+		})
+	}
+}
+
+// newImagesHandler returns an ImagesHandler over the default categories, storing images in a temporary directory.
+func newImagesHandler(t *testing.T) *categoriesHandler.ImagesHandler {
+	t.Helper()
+
+	repo, _ := categoriesRepository.NewCategoriesRepository()
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	svc, err := categoriesService.NewImagesService(repo, store, categoriesService.ImageOptions{})
+	if err != nil {
+		t.Fatalf("unexpected images service error: %v", err)
+	}
+	t.Cleanup(svc.Close)
+	handler, _ := categoriesHandler.NewImagesHandler(svc, time.Hour)
+	return handler
+}
+
+func TestRouter_ImageRoutes(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	mux := NewRouter(handler, WithImages(newImagesHandler(t))).RegisterRoutes()
+
+	var icon bytes.Buffer
+	png.Encode(&icon, image.NewGray(image.Rect(0, 0, 32, 32)))
+	var upload bytes.Buffer
+	writer := multipart.NewWriter(&upload)
+	part, _ := writer.CreateFormFile("image", "icon.png")
+	part.Write(icon.Bytes())
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/1/image", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d before an upload, got %d: %s", http.StatusNotFound, rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/categories/1/image", &upload)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"content_type":"image/png"`) {
+		t.Fatalf("expected the image to be stored, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/categories/1/image", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), icon.Bytes()) {
+		t.Fatalf("expected the uploaded image, got %d", rec.Code)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("unexpected headers %v", rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/categories/1/image", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected status %d for a matching ETag, got %d", http.StatusNotModified, rec.Code)
+	}
+}
+
+// newProductsHandler returns a products handler over the default categories.
+func newProductsHandler(t *testing.T) *productsHandler.ProductsHandler {
+	t.Helper()
+
+	categories, _ := categoriesRepository.NewCategoriesRepository()
+	repo, _ := productsRepository.NewProductsRepository()
+	svc, err := productsService.NewProductsService(repo, categories, productsService.Options{})
+	if err != nil {
+		t.Fatalf("unexpected products service error: %v", err)
+	}
+	handler, _ := productsHandler.NewProductsHandler(svc)
+	return handler
+}
+
+func TestRouter_ProductRoutes(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	mux := NewRouter(handler, WithProducts(newProductsHandler(t))).RegisterRoutes()
+
+	for _, body := range []string{
+		`{"category_id":1,"name":"Laptop","price":1500000}`,
+		`{"category_id":2,"name":"Kaos","price":75000}`,
+		`{"category_id":1,"name":"Mouse","price":120000}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+	}
+
+	cases := []struct {
+		name         string
+		method       string
+		path         string
+		expectStatus int
+		bodyContains string
+		expectTotal  string
+	}{
+		{
+			name: "list", method: http.MethodGet, path: "/products?per_page=2",
+			expectStatus: http.StatusOK, bodyContains: `"name":"Kaos"`, expectTotal: "3",
+		},
+		{
+			name: "category products", method: http.MethodGet, path: "/categories/1/products",
+			expectStatus: http.StatusOK, bodyContains: `"name":"Mouse"`, expectTotal: "2",
+		},
+		{name: "unknown category products", method: http.MethodGet, path: "/categories/99/products", expectStatus: http.StatusNotFound},
+		{name: "by id", method: http.MethodGet, path: "/products/2", expectStatus: http.StatusOK, bodyContains: `"category_id":2`},
+		{name: "delete", method: http.MethodDelete, path: "/products/2", expectStatus: http.StatusOK},
+		{name: "deleted", method: http.MethodGet, path: "/products/2", expectStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tc.bodyContains) {
+				t.Errorf("expected body to contain %s, got %s", tc.bodyContains, rec.Body.String())
+			}
+			if got := rec.Header().Get(pagination.TotalCountHeader); got != tc.expectTotal {
+				t.Errorf("expected %s %q, got %q", pagination.TotalCountHeader, tc.expectTotal, got)
+			}
+		})
+	}
+}
+
+// newFullRouter returns a router with every optional handler mounted, so the whole API is covered.
+func newFullRouter(t *testing.T) *Router {
+	t.Helper()
+
+	svc := &fakeCategoriesService{}
+	handler, err := categoriesHandler.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	gql, err := categoriesGraphQL.NewGraphQLHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected graphql error: %v", err)
+	}
+	broker, _ := categoriesStream.NewBroker(4)
+	streamHandler, err := categoriesSSE.NewStreamHandler(broker, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected stream handler error: %v", err)
+	}
+	repo, _ := webhooksRepository.NewWebhooksRepository("")
+	webhooksSvc, err := webhooksService.NewWebhooksService(repo, webhooksService.Options{})
+	if err != nil {
+		t.Fatalf("unexpected webhooks service error: %v", err)
+	}
+	t.Cleanup(webhooksSvc.Close)
+	webhooks, _ := webhooksHandler.NewWebhooksHandler(webhooksSvc)
+
+	return NewRouter(handler, WithGraphQL(gql), WithWebhooks(webhooks), WithStream(streamHandler), WithTenants(newTenantsHandler(t)), WithAttributes(newAttributesHandler(t)), WithImages(newImagesHandler(t)), WithProducts(newProductsHandler(t)))
+}
+
+func TestRouter_SlugsOfStaticRoutes(t *testing.T) {
+	full := newFullRouter(t)
+	var segments []string
+	for _, route := range full.Routes() {
+		segment, ok := strings.CutPrefix(route.Path, "/categories/")
+		if ok && !strings.ContainsAny(segment, "/{") && !slices.Contains(segments, segment) {
+			segments = append(segments, segment)
+		}
+	}
+	for _, want := range []string{"docs", "health", "search", "stream"} {
+		if !slices.Contains(segments, want) {
+			t.Fatalf("expected a static route /categories/%s, got %v", want, segments)
+		}
+	}
+
+	repo, _ := categoriesRepository.NewCategoriesRepository()
+	svc, _ := categoriesService.NewCategoriesService(repo, nil, nil)
+	handler, err := categoriesHandler.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	mux := NewRouter(handler).RegisterRoutes()
+
+	for _, segment := range segments {
+		t.Run(segment, func(t *testing.T) {
+			cat, err := svc.InsertCategory(t.Context(), entity.Category{Name: strings.ToUpper(segment[:1]) + segment[1:]})
+			if err != nil {
+				t.Fatalf("unexpected insert error: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/categories/"+cat.Slug, nil)
+			if _, pattern := full.mux().Handler(req); pattern != "GET /categories/{id}" {
+				t.Fatalf("expected slug %q to be looked up by GET /categories/{id}, it is served by %q", cat.Slug, pattern)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if want := fmt.Sprintf(`"id":%d`, cat.ID); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), want) {
+				t.Fatalf("expected 200 with %s, got %d: %s", want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouter_OpenAPIRoutes(t *testing.T) {
+	mux := newFullRouter(t).RegisterRoutes()
+
+	cases := []struct {
+		name              string
+		path              string
+		expectContentType string
+		decode            func([]byte, interface{}) error
+	}{
+		{name: "json", path: "/openapi.json", expectContentType: "application/json", decode: json.Unmarshal},
+		{name: "yaml", path: "/openapi.yaml", expectContentType: "application/yaml", decode: yaml.Unmarshal},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); got != tc.expectContentType {
+				t.Fatalf("expected content type %q, got %q", tc.expectContentType, got)
+			}
+
+			var spec struct {
+				OpenAPI string                 `json:"openapi" yaml:"openapi"`
+				Info    map[string]interface{} `json:"info" yaml:"info"`
+				Paths   map[string]interface{} `json:"paths" yaml:"paths"`
+			}
+			if err := tc.decode(rec.Body.Bytes(), &spec); err != nil {
+				t.Fatalf("unexpected decode error: %v", err)
+			}
+			if spec.OpenAPI != openapi.Version {
+				t.Fatalf("expected openapi %s, got %q", openapi.Version, spec.OpenAPI)
+			}
+			if spec.Info["title"] != "Categories API" {
+				t.Fatalf("expected title Categories API, got %v", spec.Info["title"])
+			}
+			for _, path := range []string{"/categories", "/categories/{id}", "/graphql", "/webhooks", "/tenants", "/category-attributes", "/categories/{id}/image", "/products", "/products/{id}", "/categories/{id}/products"} {
+				if _, ok := spec.Paths[path]; !ok {
+					t.Fatalf("expected path %q in spec", path)
+				}
+			}
+		})
+	}
+}
+
+// undocumentedRoutes are the routes deliberately left out of the OpenAPI spec: pages for humans and the spec itself.
+var undocumentedRoutes = map[string]bool{
+	"GET /categories/docs":    true,
+	"GET /graphql/playground": true,
+	"GET /openapi.json":       true,
+	"GET /openapi.yaml":       true,
+	"GET " + docsBundlePath:   true,
+}
+
+// specPathParam matches the path parameters of an OpenAPI path.
+var specPathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// TestRouter_SpecMatchesRoutes fails when the OpenAPI spec and the routes actually served diverge: every operation
+// in the spec must be routed to the pattern it documents, and every served route must be documented or explicitly
+// listed as undocumented.
+func TestRouter_SpecMatchesRoutes(t *testing.T) {
+	svc := &fakeCategoriesService{}
+	handler, err := categoriesHandler.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		router *Router
+	}{
+		{name: "categories only", router: NewRouter(handler)},
+		{name: "all handlers", router: newFullRouter(t)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := tc.router.Spec()
+			if err != nil {
+				t.Fatalf("unexpected spec error: %v", err)
+			}
+			mux := tc.router.mux()
+
+			for _, operation := range spec.Operations() {
+				method, path, _ := strings.Cut(operation, " ")
+				req := httptest.NewRequest(method, specPathParam.ReplaceAllString(path, "1"), nil)
+				if _, pattern := mux.Handler(req); pattern != operation {
+					t.Errorf("spec operation %q is served by %q", operation, pattern)
+				}
+			}
+
+			documented := 0
+			for _, route := range tc.router.Routes() {
+				if route.Doc == nil {
+					if !undocumentedRoutes[route.Pattern()] {
+						t.Errorf("route %q is served but not documented in the spec", route.Pattern())
+					}
+					continue
+				}
+
+				documented++
+				if undocumentedRoutes[route.Pattern()] {
+					t.Errorf("route %q is documented but listed as undocumented", route.Pattern())
+				}
+				if spec.Operation(route.Method, route.Path) == nil {
+					t.Errorf("route %q is missing from the spec", route.Pattern())
+				}
+			}
+
+			if got := len(spec.Operations()); got != documented {
+				t.Errorf("expected %d operations in the spec, got %d", documented, got)
+			}
+		})
+	}
+}
+
+func TestRouter_DocsFromAnyDirectory(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	mux := NewRouter(handler).RegisterRoutes()
+
+	// Nothing the docs page needs may be read from the working directory.
+	t.Chdir(t.TempDir())
+
+	expectScript := `<script src="` + scalar.DefaultCDN + `">`
+	expectBundleStatus := http.StatusNotFound
+	if _, ok := scalar.Bundle(); ok {
+		expectScript = `<script src="docs/assets/` + scalar.BundleName + `">`
+		expectBundleStatus = http.StatusOK
+	}
+
+	var first string
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/categories/docs", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+			t.Fatalf("expected html content type, got %q", got)
+		}
+		if !strings.Contains(rec.Body.String(), expectScript) {
+			t.Fatalf("expected body to contain %q, got %s", expectScript, rec.Body.String())
+		}
+		if i == 0 {
+			first = rec.Body.String()
+		} else if rec.Body.String() != first {
+			t.Fatalf("expected the cached page to be served again")
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, docsBundlePath, nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != expectBundleStatus {
+		t.Fatalf("expected bundle status %d, got %d", expectBundleStatus, rec.Code)
+	}
+}
+
+func TestRouter_DocsRenderer(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	cases := []struct {
+		name         string
+		opts         []Option
+		bodyContains string
+	}{
+		{name: "default", bodyContains: `id="api-reference"`},
+		{name: "swagger ui", opts: []Option{WithDocsRenderer(scalar.SwaggerUI{})}, bodyContains: "SwaggerUIBundle("},
+		{name: "redoc", opts: []Option{WithDocsRenderer(scalar.Redoc{})}, bodyContains: "Redoc.init("},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := NewRouter(handler, tc.opts...).RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodGet, "/categories/docs", nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tc.bodyContains) {
+				t.Fatalf("expected body to contain %q, got %s", tc.bodyContains, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), `"title":"Categories API"`) {
+				t.Fatalf("expected the generated spec in the page, got %s", rec.Body.String())
+			}
 		})
 	}
 }
 
-func findRepoRoot(t *testing.T) string {
+func newV2Router(t *testing.T, svc *fakeCategoriesService) *Router {
 	t.Helper()
-	dir, err := os.Getwd()
+
+	handler, err := categoriesHandler.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+	v2, err := categoriesHandlerV2.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected v2 handler error: %v", err)
+	}
+
+	return NewRouter(handler, WithV2(v2))
+}
+
+func TestRouter_V2Routes(t *testing.T) {
+	svc := &fakeCategoriesService{
+		getAllResp:  []entity.Category{{ID: 1, Name: "Buku"}, {ID: 2, Name: "Pena"}},
+		getByIDResp: entity.Category{ID: 1, Name: "Buku"},
+	}
+	mux := newV2Router(t, svc).RegisterV2Routes()
+
+	cases := []struct {
+		name              string
+		method            string
+		path              string
+		expectStatus      int
+		expectContentType string
+		expectAllow       string
+		bodyContains      string
+	}{
+		{
+			name: "list is a bare array", method: http.MethodGet, path: "/categories?per_page=1",
+			expectStatus: http.StatusOK, expectContentType: "application/json", bodyContains: `[{"id":1,`,
+		},
+		{
+			name: "category is a bare object", method: http.MethodGet, path: "/categories/1",
+			expectStatus: http.StatusOK, expectContentType: "application/json", bodyContains: `{"id":1,`,
+		},
+		{
+			name: "unknown path", method: http.MethodGet, path: "/unknown",
+			expectStatus: http.StatusNotFound, expectContentType: problem.MediaType, bodyContains: `"code":"ROUTE_NOT_FOUND"`,
+		},
+		{
+			name: "unsupported method", method: http.MethodPatch, path: "/categories",
+			expectStatus: http.StatusMethodNotAllowed, expectContentType: problem.MediaType, expectAllow: "GET, HEAD, POST",
+			bodyContains: `"code":"METHOD_NOT_ALLOWED"`,
+		},
+		{
+			name: "spec", method: http.MethodGet, path: "/openapi.json",
+			expectStatus: http.StatusOK, expectContentType: "application/json", bodyContains: `"url": "/api/v2"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectStatus, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); got != tc.expectContentType {
+				t.Fatalf("expected content type %q, got %q", tc.expectContentType, got)
+			}
+			if got := rec.Header().Get("Allow"); got != tc.expectAllow {
+				t.Fatalf("expected Allow %q, got %q", tc.expectAllow, got)
+			}
+			if !strings.Contains(rec.Body.String(), tc.bodyContains) {
+				t.Fatalf("expected body to contain %q, got %s", tc.bodyContains, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouter_V2SpecMatchesRoutes(t *testing.T) {
+	router := newV2Router(t, &fakeCategoriesService{})
+	spec, err := router.V2Spec()
 	if err != nil {
-		t.Fatalf("getwd: %v", err)
+		t.Fatalf("unexpected spec error: %v", err)
 	}
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "docs", "swagger.json")); err == nil {
-			return dir
+
+	documented := 0
+	for _, route := range router.V2Routes() {
+		if route.Doc == nil {
+			if route.Pattern() != "GET /openapi.json" {
+				t.Errorf("route %q is served but not documented in the spec", route.Pattern())
+			}
+			continue
 		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			t.Fatal("docs/swagger.json not found")
+
+		documented++
+		operation := spec.Operation(route.Method, route.Path)
+		if operation == nil {
+			t.Errorf("route %q is missing from the spec", route.Pattern())
+			continue
+		}
+		for status, response := range operation.Responses {
+			if status >= "400" {
+				if _, ok := response.Content[problem.MediaType]; !ok || len(response.Content) != 1 {
+					t.Errorf("route %q documents %s without problem details: %v", route.Pattern(), status, response.Content)
+				}
+			}
 		}
-		dir = parent
+	}
+
+	if got := len(spec.Operations()); got != documented {
+		t.Errorf("expected %d operations in the spec, got %d", documented, got)
+	}
+}
+
+func TestRouter_V2RoutesWithoutHandler(t *testing.T) {
+	handler, err := categoriesHandler.NewCategoriesHandler(&fakeCategoriesService{})
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewRouter(handler).RegisterV2Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/categories", nil))
+
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != problem.MediaType {
+		t.Fatalf("expected a 404 problem, got %d %s", rec.Code, rec.Body.String())
 	}
 }
@@ -0,0 +1,623 @@
+package router
+
+import (
+	"net/http"
+
+	categoriesGraphQL "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/graphql"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	productsEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/products/entity"
+	tenantsHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/delivery/http"
+	tenantsEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/entity"
+	webhooksHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/delivery/http"
+	webhooksEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/openapi"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/scalar"
+)
+
+// specConfig holds the document-wide settings of the generated OpenAPI spec. Paths are relative to /api/v1,
+// where api.Server mounts the router.
+var specConfig = openapi.Config{
+	Info: openapi.Info{
+		Title:       "Categories API",
+		Description: "API untuk mengelola kategori, terjemahannya, produk di dalamnya dan webhook perubahan kategori.",
+		Version:     "1.0",
+	},
+	Servers:  []openapi.Server{{URL: "/api/v1"}},
+	Envelope: json_wrapper.APIResponse{},
+}
+
+// docsBundlePath is where the vendored Scalar bundle used by the docs page is served.
+const docsBundlePath = "/categories/docs/assets/" + scalar.BundleName
+
+// localeParams document the locale negotiation shared by the localized category reads.
+var localeParams = []openapi.Param{
+	{Name: "lang", In: "query", Description: "Locale, mis. en; mengesampingkan Accept-Language"},
+	{Name: "Accept-Language", In: "header", Description: "Locale yang diinginkan"},
+}
+
+// attributeFilterParam documents the attribute filter shared by the category lists.
+var attributeFilterParam = openapi.Param{
+	Name: entity.AttributeFilterPrefix + "{key}", In: "query",
+	Description: "Nilai atribut {key}, mis. attr.color=red; dapat diulang untuk menerima beberapa nilai",
+}
+
+// categoryMediaTypes are the representations category endpoints offer besides JSON, negotiated with Accept and
+// Content-Type. List endpoints also offer text/csv.
+var categoryMediaTypes = []string{json_wrapper.MediaTypeXML, json_wrapper.MediaTypeMsgPack}
+
+// Routes returns the route table served by RegisterRoutes. Routes with a Doc make up the OpenAPI spec, so an
+// endpoint cannot be served without being documented unless it is deliberately left without one.
+func (h *Router) Routes() []openapi.Route {
+	routes := []openapi.Route{
+		{
+			Method: http.MethodGet, Path: "/categories/health", Handler: h.categories.API,
+			Doc: &openapi.Doc{
+				ID: "getCategoriesHealth", Tags: []string{"categories"},
+				Summary:     "Get health status of categories API",
+				Description: "Memeriksa status kesehatan API kategori",
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK},
+					{Status: http.StatusServiceUnavailable},
+				},
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/categories", Handler: h.categories.InsertCategory,
+			Doc: &openapi.Doc{
+				ID: "createCategory", Tags: []string{"categories"},
+				Summary:     "Create a new category",
+				Description: "Membuat kategori baru. Atribut diperiksa terhadap definisi atribut dan dilengkapi dengan nilai default. Request dengan Idempotency-Key yang sama menerima ulang respons pertama.",
+				Params: []openapi.Param{
+					{Name: middleware.IdempotencyKeyHeader, In: "header", Description: "Kunci unik per request, untuk mengulang request dengan aman"},
+				},
+				Body:       entity.Category{},
+				MediaTypes: categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusCreated, Data: entity.Category{}},
+					{Status: http.StatusBadRequest},
+					{Status: http.StatusRequestEntityTooLarge},
+					{Status: http.StatusUnsupportedMediaType},
+					{Status: http.StatusConflict},
+					{Status: http.StatusUnprocessableEntity},
+				},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories", Handler: h.categories.GetAllCategories,
+			Doc: &openapi.Doc{
+				ID: "listCategories", Tags: []string{"categories"},
+				Summary:     "Get all categories",
+				Description: "Mengambil semua data kategori sesuai urutannya, dalam bahasa dari parameter lang atau header Accept-Language. Parameter attr.{key} menyaring berdasarkan nilai atribut. Tersedia juga sebagai CSV dengan Accept: text/csv",
+				Params:      append([]openapi.Param{attributeFilterParam}, localeParams...),
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Data: []entity.Category{}},
+				},
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/categories/reorder", Handler: h.categories.ReorderCategories,
+			Doc: &openapi.Doc{
+				ID: "reorderCategories", Tags: []string{"categories"},
+				Summary:     "Reorder categories",
+				Description: "Mengubah urutan kategori: kategori dengan id yang diberikan ditempatkan paling depan sesuai urutannya, diikuti kategori lainnya dalam urutan semula",
+				Params:      localeParams,
+				Body:        entity.CategoryOrder{},
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Data: []entity.Category{}},
+					{Status: http.StatusBadRequest},
+					{Status: http.StatusNotFound},
+					{Status: http.StatusRequestEntityTooLarge},
+					{Status: http.StatusUnsupportedMediaType},
+				},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories/search", Handler: h.categories.SearchCategories,
+			Doc: &openapi.Doc{
+				ID: "searchCategories", Tags: []string{"categories"},
+				Summary:     "Search categories",
+				Description: "Mencari kategori berdasarkan nama dan deskripsi, termasuk awalan kata dan salah ketik. Tersedia juga sebagai CSV dengan Accept: text/csv",
+				Params: append([]openapi.Param{
+					{Name: "q", In: "query", Description: "Kata kunci pencarian", Required: true},
+					{Name: "limit", In: "query", Description: "Jumlah hasil maksimum (1-100, default 20)", Type: 0},
+				}, localeParams...),
+				MediaTypes: categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Data: []entity.SearchResult{}},
+					{Status: http.StatusBadRequest},
+				},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories/{id}", Handler: h.categories.GetCategoryByID,
+			Doc: &openapi.Doc{
+				ID: "getCategory", Tags: []string{"categories"},
+				Summary:     "Get category by ID or slug",
+				Description: "Mengambil kategori berdasarkan ID atau slug. Slug lama dialihkan ke slug terbaru dengan 301.",
+				Params: append([]openapi.Param{
+					{Name: "id", In: "path", Description: "Category ID or slug"},
+				}, localeParams...),
+				MediaTypes: categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Data: entity.Category{}},
+					{Status: http.StatusMovedPermanently, Data: entity.Category{}},
+					{Status: http.StatusBadRequest},
+					{Status: http.StatusInternalServerError},
+				},
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/categories/{id}", Handler: h.categories.UpdateCategory,
+			Doc: &openapi.Doc{
+				ID: "updateCategory", Tags: []string{"categories"},
+				Summary:     "Update category",
+				Description: "Update kategori berdasarkan ID. Atribut yang diberikan menggantikan atribut yang ada; tanpa atribut, atribut yang ada dipertahankan.",
+				Params:      []openapi.Param{{Name: "id", In: "path", Description: "Category ID", Type: int64(0)}},
+				Body:        entity.Category{},
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Data: entity.Category{}},
+					{Status: http.StatusBadRequest},
+					{Status: http.StatusRequestEntityTooLarge},
+					{Status: http.StatusUnsupportedMediaType},
+					{Status: http.StatusInternalServerError},
+				},
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/categories/{id}", Handler: h.categories.DeleteCategory,
+			Doc: &openapi.Doc{
+				ID: "deleteCategory", Tags: []string{"categories"},
+				Summary:     "Delete category",
+				Description: "Menghapus kategori berdasarkan ID. Kategori yang masih memiliki produk ditolak dengan 409, kecuali kebijakan penghapusan cascade juga menghapus produknya.",
+				Params:      []openapi.Param{{Name: "id", In: "path", Description: "Category ID", Type: int64(0)}},
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK},
+					{Status: http.StatusBadRequest},
+					{Status: http.StatusConflict},
+					{Status: http.StatusInternalServerError},
+				},
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/categories/{id}/translations/{locale}", Handler: h.categories.UpsertCategoryTranslation,
+			Doc: &openapi.Doc{
+				ID: "upsertCategoryTranslation", Tags: []string{"categories"},
+				Summary:     "Set category translation",
+				Description: "Menyimpan nama dan deskripsi kategori dalam satu locale. Locale default memperbarui kategori itu sendiri.",
+				Params: []openapi.Param{
+					{Name: "id", In: "path", Description: "Category ID", Type: int64(0)},
+					{Name: "locale", In: "path", Description: "Locale BCP 47, mis. en atau en-US"},
+				},
+				Body:       entity.Translation{},
+				MediaTypes: categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Data: entity.Category{}},
+					{Status: http.StatusBadRequest},
+					{Status: http.StatusRequestEntityTooLarge},
+					{Status: http.StatusUnsupportedMediaType},
+					{Status: http.StatusInternalServerError},
+				},
+			},
+		},
+		{Method: http.MethodGet, Path: "/categories/docs", Handler: h.docs},
+		{Method: http.MethodGet, Path: "/openapi.json", Handler: h.openAPIJSON},
+		{Method: http.MethodGet, Path: "/openapi.yaml", Handler: h.openAPIYAML},
+	}
+
+	if _, ok := scalar.Bundle(); ok {
+		routes = append(routes, openapi.Route{Method: http.MethodGet, Path: docsBundlePath, Handler: scalar.BundleHandler().ServeHTTP})
+	}
+
+	if h.stream != nil {
+		routes = append(routes, openapi.Route{
+			Method: http.MethodGet, Path: "/categories/stream", Handler: h.stream.Stream,
+			Doc: &openapi.Doc{
+				ID: "streamCategoryChanges", Tags: []string{"categories"},
+				Summary:     "Stream category changes",
+				Description: "Mengirim perubahan kategori secara langsung sebagai Server-Sent Events (category.created, category.updated, category.deleted)",
+				Params: []openapi.Param{
+					{Name: "Last-Event-ID", In: "header", Description: "ID event terakhir yang diterima, untuk melanjutkan stream", Type: int64(0)},
+				},
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, ContentType: "text/event-stream", Raw: true, Data: ""},
+					{Status: http.StatusBadRequest},
+					{Status: http.StatusInternalServerError},
+				},
+			},
+		})
+	}
+
+	if h.graphql != nil {
+		routes = append(routes,
+			openapi.Route{
+				Method: http.MethodPost, Path: "/graphql", Handler: h.graphql.Query,
+				Doc: &openapi.Doc{
+					ID: "queryGraphQL", Tags: []string{"graphql"},
+					Summary:     "Execute a GraphQL query",
+					Description: "Menjalankan query atau mutation GraphQL untuk kategori",
+					Body:        categoriesGraphQL.Request{},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Raw: true, Data: map[string]interface{}{}},
+						{Status: http.StatusBadRequest, Raw: true, Data: map[string]interface{}{}},
+						{Status: http.StatusRequestEntityTooLarge, Raw: true, Data: map[string]interface{}{}},
+						{Status: http.StatusUnsupportedMediaType, Raw: true, Data: map[string]interface{}{}},
+					},
+				},
+			},
+			openapi.Route{Method: http.MethodGet, Path: "/graphql/playground", Handler: h.playground},
+		)
+	}
+
+	if h.webhooks != nil {
+		routes = append(routes,
+			openapi.Route{
+				Method: http.MethodPost, Path: "/webhooks", Handler: h.webhooks.InsertWebhook,
+				Doc: &openapi.Doc{
+					ID: "createWebhook", Tags: []string{"webhooks"},
+					Summary:     "Register a webhook",
+					Description: "Mendaftarkan webhook untuk event category.created, category.updated dan category.deleted",
+					Body:        webhooksHandler.SubscriptionRequest{},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusCreated, Data: webhooksEntity.Subscription{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusRequestEntityTooLarge},
+						{Status: http.StatusUnsupportedMediaType},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/webhooks", Handler: h.webhooks.GetAllWebhooks,
+				Doc: &openapi.Doc{
+					ID: "listWebhooks", Tags: []string{"webhooks"},
+					Summary:     "Get all webhooks",
+					Description: "Mengambil semua langganan webhook",
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: []webhooksEntity.Subscription{}},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/webhooks/dead-letters", Handler: h.webhooks.GetDeadLetters,
+				Doc: &openapi.Doc{
+					ID: "listDeadLetters", Tags: []string{"webhooks"},
+					Summary:     "Get dead-lettered deliveries",
+					Description: "Mengambil pengiriman webhook yang gagal setelah semua percobaan",
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: []webhooksEntity.Delivery{}},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodPost, Path: "/webhooks/dead-letters/{id}/retry", Handler: h.webhooks.RetryDeadLetter,
+				Doc: &openapi.Doc{
+					ID: "retryDeadLetter", Tags: []string{"webhooks"},
+					Summary:     "Retry a dead-lettered delivery",
+					Description: "Mengirim ulang pengiriman webhook yang gagal",
+					Params:      []openapi.Param{{Name: "id", In: "path", Description: "Delivery ID", Type: int64(0)}},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusAccepted, Data: webhooksEntity.Delivery{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+						{Status: http.StatusConflict},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/webhooks/{id}", Handler: h.webhooks.GetWebhookByID,
+				Doc: &openapi.Doc{
+					ID: "getWebhook", Tags: []string{"webhooks"},
+					Summary:     "Get webhook by ID",
+					Description: "Mengambil langganan webhook berdasarkan ID",
+					Params:      []openapi.Param{{Name: "id", In: "path", Description: "Webhook ID", Type: int64(0)}},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: webhooksEntity.Subscription{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodDelete, Path: "/webhooks/{id}", Handler: h.webhooks.DeleteWebhook,
+				Doc: &openapi.Doc{
+					ID: "deleteWebhook", Tags: []string{"webhooks"},
+					Summary:     "Delete webhook",
+					Description: "Menghapus langganan webhook berdasarkan ID",
+					Params:      []openapi.Param{{Name: "id", In: "path", Description: "Webhook ID", Type: int64(0)}},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/webhooks/{id}/deliveries", Handler: h.webhooks.GetWebhookDeliveries,
+				Doc: &openapi.Doc{
+					ID: "listWebhookDeliveries", Tags: []string{"webhooks"},
+					Summary:     "Get webhook deliveries",
+					Description: "Mengambil log pengiriman sebuah webhook",
+					Params:      []openapi.Param{{Name: "id", In: "path", Description: "Webhook ID", Type: int64(0)}},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: []webhooksEntity.Delivery{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+		)
+	}
+
+	if h.attributes != nil {
+		keyParam := []openapi.Param{{Name: "key", In: "path", Description: "Attribute key"}}
+		routes = append(routes,
+			openapi.Route{
+				Method: http.MethodPost, Path: "/category-attributes", Handler: h.attributes.InsertAttribute,
+				Doc: &openapi.Doc{
+					ID: "createCategoryAttribute", Tags: []string{"category-attributes"},
+					Summary:     "Define a category attribute",
+					Description: "Menambahkan definisi atribut kategori dengan tipe string, number, bool atau enum",
+					Body:        entity.AttributeDefinition{},
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusCreated, Data: entity.AttributeDefinition{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusConflict},
+						{Status: http.StatusRequestEntityTooLarge},
+						{Status: http.StatusUnsupportedMediaType},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/category-attributes", Handler: h.attributes.GetAllAttributes,
+				Doc: &openapi.Doc{
+					ID: "listCategoryAttributes", Tags: []string{"category-attributes"},
+					Summary:     "Get all category attributes",
+					Description: "Mengambil semua definisi atribut kategori, diurutkan berdasarkan key",
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: []entity.AttributeDefinition{}},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/category-attributes/{key}", Handler: h.attributes.GetAttributeByKey,
+				Doc: &openapi.Doc{
+					ID: "getCategoryAttribute", Tags: []string{"category-attributes"},
+					Summary:     "Get category attribute by key",
+					Description: "Mengambil definisi atribut kategori berdasarkan key",
+					Params:      keyParam,
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: entity.AttributeDefinition{}},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodPut, Path: "/category-attributes/{key}", Handler: h.attributes.UpdateAttribute,
+				Doc: &openapi.Doc{
+					ID: "updateCategoryAttribute", Tags: []string{"category-attributes"},
+					Summary:     "Update category attribute",
+					Description: "Mengganti definisi atribut kategori berdasarkan key. Ditolak jika kategori memiliki nilai yang tidak sesuai dengan definisi baru.",
+					Params:      keyParam,
+					Body:        entity.AttributeDefinition{},
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: entity.AttributeDefinition{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+						{Status: http.StatusConflict},
+						{Status: http.StatusRequestEntityTooLarge},
+						{Status: http.StatusUnsupportedMediaType},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodDelete, Path: "/category-attributes/{key}", Handler: h.attributes.DeleteAttribute,
+				Doc: &openapi.Doc{
+					ID: "deleteCategoryAttribute", Tags: []string{"category-attributes"},
+					Summary:     "Delete category attribute",
+					Description: "Menghapus definisi atribut kategori berdasarkan key. Ditolak selama masih ada kategori yang memiliki nilai untuk atribut tersebut.",
+					Params:      keyParam,
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK},
+						{Status: http.StatusNotFound},
+						{Status: http.StatusConflict},
+					},
+				},
+			},
+		)
+	}
+
+	if h.images != nil {
+		idParam := []openapi.Param{{Name: "id", In: "path", Description: "Category ID", Type: int64(0)}}
+		routes = append(routes,
+			openapi.Route{
+				Method: http.MethodPut, Path: "/categories/{id}/image", Handler: h.images.PutCategoryImage,
+				Doc: &openapi.Doc{
+					ID: "putCategoryImage", Tags: []string{"categories"},
+					Summary:     "Upload category image",
+					Description: "Menyimpan ikon atau gambar kategori dari unggahan multipart pada field image, menggantikan gambar sebelumnya. Format dideteksi dari isi berkas (PNG, JPEG atau GIF), dengan batas ukuran dan dimensi gambar.",
+					Params:      idParam,
+					Body: struct {
+						Image openapi.Binary `json:"image"`
+					}{},
+					BodyContentType: "multipart/form-data",
+					MediaTypes:      categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: entity.Category{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+						{Status: http.StatusRequestEntityTooLarge},
+						{Status: http.StatusUnsupportedMediaType},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/categories/{id}/image", Handler: h.images.GetCategoryImage,
+				Doc: &openapi.Doc{
+					ID: "getCategoryImage", Tags: []string{"categories"},
+					Summary:     "Get category image",
+					Description: "Mengambil gambar kategori dengan header ETag dan Cache-Control. Permintaan dengan If-None-Match yang cocok dijawab 304.",
+					Params: append([]openapi.Param{
+						{Name: "If-None-Match", In: "header", Description: "ETag gambar yang sudah dimiliki klien"},
+					}, idParam...),
+					MediaTypes: categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, ContentType: "image/*", Raw: true, Data: openapi.Binary{}},
+						{Status: http.StatusNotModified},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+		)
+	}
+
+	if h.products != nil {
+		idParam := []openapi.Param{{Name: "id", In: "path", Description: "Product ID", Type: int64(0)}}
+		routes = append(routes,
+			openapi.Route{
+				Method: http.MethodPost, Path: "/products", Handler: h.products.InsertProduct,
+				Doc: &openapi.Doc{
+					ID: "createProduct", Tags: []string{"products"},
+					Summary:     "Create a new product",
+					Description: "Membuat produk baru pada kategori yang sudah ada. Harga dalam satuan terkecil mata uang dan tidak boleh negatif.",
+					Body:        productsEntity.Product{},
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusCreated, Data: productsEntity.Product{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusRequestEntityTooLarge},
+						{Status: http.StatusUnsupportedMediaType},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/products", Handler: h.products.GetAllProducts,
+				Doc: &openapi.Doc{
+					ID: "listProducts", Tags: []string{"products"},
+					Summary:     "Get all products",
+					Description: "Mengambil satu halaman produk, diurutkan berdasarkan ID. Jumlah total ada di header X-Total-Count dan halaman lain di header Link.",
+					Params:      paginationParams,
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: []productsEntity.Product{}},
+						{Status: http.StatusBadRequest},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/products/{id}", Handler: h.products.GetProductByID,
+				Doc: &openapi.Doc{
+					ID: "getProduct", Tags: []string{"products"},
+					Summary:     "Get product by ID",
+					Description: "Mengambil produk berdasarkan ID",
+					Params:      idParam,
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: productsEntity.Product{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodPut, Path: "/products/{id}", Handler: h.products.UpdateProduct,
+				Doc: &openapi.Doc{
+					ID: "updateProduct", Tags: []string{"products"},
+					Summary:     "Update product",
+					Description: "Mengganti produk berdasarkan ID. Produk dapat dipindahkan ke kategori lain yang sudah ada.",
+					Params:      idParam,
+					Body:        productsEntity.Product{},
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: productsEntity.Product{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+						{Status: http.StatusRequestEntityTooLarge},
+						{Status: http.StatusUnsupportedMediaType},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodDelete, Path: "/products/{id}", Handler: h.products.DeleteProduct,
+				Doc: &openapi.Doc{
+					ID: "deleteProduct", Tags: []string{"products"},
+					Summary:     "Delete product",
+					Description: "Menghapus produk berdasarkan ID",
+					Params:      idParam,
+					MediaTypes:  categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/categories/{id}/products", Handler: h.products.GetCategoryProducts,
+				Doc: &openapi.Doc{
+					ID: "listCategoryProducts", Tags: []string{"products"},
+					Summary:     "Get products of a category",
+					Description: "Mengambil satu halaman produk dari sebuah kategori, diurutkan berdasarkan ID. Jumlah total ada di header X-Total-Count dan halaman lain di header Link.",
+					Params: append([]openapi.Param{
+						{Name: "id", In: "path", Description: "Category ID", Type: int64(0)},
+					}, paginationParams...),
+					MediaTypes: categoryMediaTypes,
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: []productsEntity.Product{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusNotFound},
+					},
+				},
+			},
+		)
+	}
+
+	if h.tenants != nil {
+		routes = append(routes,
+			openapi.Route{
+				Method: http.MethodPost, Path: "/tenants", Handler: h.tenants.InsertTenant,
+				Doc: &openapi.Doc{
+					ID: "createTenant", Tags: []string{"tenants"},
+					Summary:     "Register a tenant",
+					Description: "Mendaftarkan tenant baru dengan kategori yang terpisah dari tenant lain; hanya untuk tenant default dengan X-Admin-Token",
+					Params:      []openapi.Param{{Name: tenantsHandler.AdminTokenHeader, In: "header", Required: true, Description: "Token admin (TENANT_ADMIN_TOKEN)"}},
+					Body:        tenantsHandler.TenantRequest{},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusCreated, Data: tenantsEntity.Tenant{}},
+						{Status: http.StatusBadRequest},
+						{Status: http.StatusForbidden},
+						{Status: http.StatusConflict},
+						{Status: http.StatusRequestEntityTooLarge},
+						{Status: http.StatusUnsupportedMediaType},
+					},
+				},
+			},
+			openapi.Route{
+				Method: http.MethodGet, Path: "/tenants", Handler: h.tenants.GetAllTenants,
+				Doc: &openapi.Doc{
+					ID: "listTenants", Tags: []string{"tenants"},
+					Summary:     "Get all tenants",
+					Description: "Mengambil semua tenant; hanya untuk tenant default dengan X-Admin-Token",
+					Params:      []openapi.Param{{Name: tenantsHandler.AdminTokenHeader, In: "header", Required: true, Description: "Token admin (TENANT_ADMIN_TOKEN)"}},
+					Responses: []openapi.ResponseDoc{
+						{Status: http.StatusOK, Data: []tenantsEntity.Tenant{}},
+						{Status: http.StatusForbidden},
+					},
+				},
+			},
+		)
+	}
+
+	return routes
+}
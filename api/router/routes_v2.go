@@ -0,0 +1,192 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/openapi"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
+)
+
+// v2SpecConfig holds the document-wide settings of the v2 OpenAPI spec. Paths are relative to /api/v2, where
+// api.Server mounts RegisterV2Routes. v2 responses are not enveloped, so every response is documented as raw.
+var v2SpecConfig = openapi.Config{
+	Info: openapi.Info{
+		Title:       "Categories API v2",
+		Description: "API untuk mengelola kategori. Respons sukses berisi resource tanpa envelope; error mengikuti RFC 7807 (application/problem+json).",
+		Version:     "2.0",
+	},
+	Servers: []openapi.Server{{URL: "/api/v2"}},
+}
+
+// paginationParams document the page selection of paginated lists.
+var paginationParams = []openapi.Param{
+	{Name: "page", In: "query", Description: "Nomor halaman, mulai dari 1", Type: 0},
+	{Name: "per_page", In: "query", Description: "Jumlah item per halaman (1-100, default 20)", Type: 0},
+}
+
+// problemResponse documents an error answered with problem details.
+func problemResponse(status int) openapi.ResponseDoc {
+	return openapi.ResponseDoc{Status: status, ContentType: problem.MediaType, Raw: true, Data: problem.Problem{}}
+}
+
+// V2Routes returns the route table served by RegisterV2Routes, or nil when the router has no v2 handler.
+func (h *Router) V2Routes() []openapi.Route {
+	if h.v2 == nil {
+		return nil
+	}
+
+	return []openapi.Route{
+		{
+			Method: http.MethodGet, Path: "/categories/health", Handler: h.v2.API,
+			Doc: &openapi.Doc{
+				ID: "getCategoriesHealth", Tags: []string{"categories"},
+				Summary:     "Get health status of categories API",
+				Description: "Memeriksa status kesehatan API kategori",
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Raw: true, Data: entity.HealthResponse{}},
+					problemResponse(http.StatusServiceUnavailable),
+				},
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/categories", Handler: h.v2.InsertCategory,
+			Doc: &openapi.Doc{
+				ID: "createCategory", Tags: []string{"categories"},
+				Summary:     "Create a new category",
+				Description: "Membuat kategori baru. Header Location menunjuk ke kategori yang dibuat. Atribut diperiksa terhadap definisi atribut dan dilengkapi dengan nilai default.",
+				Body:        entity.Category{},
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusCreated, Raw: true, Data: entity.Category{}},
+					problemResponse(http.StatusBadRequest),
+					problemResponse(http.StatusRequestEntityTooLarge),
+					problemResponse(http.StatusUnsupportedMediaType),
+				},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories", Handler: h.v2.GetAllCategories,
+			Doc: &openapi.Doc{
+				ID: "listCategories", Tags: []string{"categories"},
+				Summary:     "List categories",
+				Description: "Mengambil satu halaman kategori sesuai urutannya. Jumlah total ada di header X-Total-Count dan halaman lain di header Link. Parameter attr.{key} menyaring berdasarkan nilai atribut. Tersedia juga sebagai CSV dengan Accept: text/csv",
+				Params:      append(append([]openapi.Param{attributeFilterParam}, paginationParams...), localeParams...),
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Raw: true, Data: []entity.Category{}},
+					problemResponse(http.StatusBadRequest),
+				},
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/categories/reorder", Handler: h.v2.ReorderCategories,
+			Doc: &openapi.Doc{
+				ID: "reorderCategories", Tags: []string{"categories"},
+				Summary:     "Reorder categories",
+				Description: "Mengubah urutan kategori: kategori dengan id yang diberikan ditempatkan paling depan sesuai urutannya, diikuti kategori lainnya dalam urutan semula",
+				Params:      localeParams,
+				Body:        entity.CategoryOrder{},
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Raw: true, Data: []entity.Category{}},
+					problemResponse(http.StatusBadRequest),
+					problemResponse(http.StatusNotFound),
+					problemResponse(http.StatusRequestEntityTooLarge),
+					problemResponse(http.StatusUnsupportedMediaType),
+				},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories/search", Handler: h.v2.SearchCategories,
+			Doc: &openapi.Doc{
+				ID: "searchCategories", Tags: []string{"categories"},
+				Summary:     "Search categories",
+				Description: "Mencari kategori berdasarkan nama dan deskripsi, termasuk awalan kata dan salah ketik. Tersedia juga sebagai CSV dengan Accept: text/csv",
+				Params: append([]openapi.Param{
+					{Name: "q", In: "query", Description: "Kata kunci pencarian", Required: true},
+					{Name: "limit", In: "query", Description: "Jumlah hasil maksimum (1-100, default 20)", Type: 0},
+				}, localeParams...),
+				MediaTypes: categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Raw: true, Data: []entity.SearchResult{}},
+					problemResponse(http.StatusBadRequest),
+				},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/categories/{id}", Handler: h.v2.GetCategory,
+			Doc: &openapi.Doc{
+				ID: "getCategory", Tags: []string{"categories"},
+				Summary:     "Get category by ID or slug",
+				Description: "Mengambil kategori berdasarkan ID atau slug. Slug lama dialihkan ke slug terbaru dengan 301.",
+				Params: append([]openapi.Param{
+					{Name: "id", In: "path", Description: "Category ID or slug"},
+				}, localeParams...),
+				MediaTypes: categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Raw: true, Data: entity.Category{}},
+					{Status: http.StatusMovedPermanently, Raw: true, Data: entity.Category{}},
+					problemResponse(http.StatusBadRequest),
+					problemResponse(http.StatusNotFound),
+				},
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/categories/{id}", Handler: h.v2.UpdateCategory,
+			Doc: &openapi.Doc{
+				ID: "updateCategory", Tags: []string{"categories"},
+				Summary:     "Update category",
+				Description: "Update kategori berdasarkan ID. Atribut yang diberikan menggantikan atribut yang ada; tanpa atribut, atribut yang ada dipertahankan.",
+				Params:      []openapi.Param{{Name: "id", In: "path", Description: "Category ID", Type: int64(0)}},
+				Body:        entity.Category{},
+				MediaTypes:  categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Raw: true, Data: entity.Category{}},
+					problemResponse(http.StatusBadRequest),
+					problemResponse(http.StatusNotFound),
+					problemResponse(http.StatusRequestEntityTooLarge),
+					problemResponse(http.StatusUnsupportedMediaType),
+				},
+			},
+		},
+		{
+			Method: http.MethodDelete, Path: "/categories/{id}", Handler: h.v2.DeleteCategory,
+			Doc: &openapi.Doc{
+				ID: "deleteCategory", Tags: []string{"categories"},
+				Summary:     "Delete category",
+				Description: "Menghapus kategori berdasarkan ID. Kategori yang masih memiliki produk ditolak dengan 409, kecuali kebijakan penghapusan cascade juga menghapus produknya.",
+				Params:      []openapi.Param{{Name: "id", In: "path", Description: "Category ID", Type: int64(0)}},
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusNoContent, Raw: true},
+					problemResponse(http.StatusBadRequest),
+					problemResponse(http.StatusNotFound),
+					problemResponse(http.StatusConflict),
+				},
+			},
+		},
+		{
+			Method: http.MethodPut, Path: "/categories/{id}/translations/{locale}", Handler: h.v2.UpsertCategoryTranslation,
+			Doc: &openapi.Doc{
+				ID: "upsertCategoryTranslation", Tags: []string{"categories"},
+				Summary:     "Set category translation",
+				Description: "Menyimpan nama dan deskripsi kategori dalam satu locale. Locale default memperbarui kategori itu sendiri.",
+				Params: []openapi.Param{
+					{Name: "id", In: "path", Description: "Category ID", Type: int64(0)},
+					{Name: "locale", In: "path", Description: "Locale BCP 47, mis. en atau en-US"},
+				},
+				Body:       entity.Translation{},
+				MediaTypes: categoryMediaTypes,
+				Responses: []openapi.ResponseDoc{
+					{Status: http.StatusOK, Raw: true, Data: entity.Category{}},
+					problemResponse(http.StatusBadRequest),
+					problemResponse(http.StatusNotFound),
+					problemResponse(http.StatusRequestEntityTooLarge),
+					problemResponse(http.StatusUnsupportedMediaType),
+				},
+			},
+		},
+		{Method: http.MethodGet, Path: "/openapi.json", Handler: h.v2OpenAPIJSON},
+	}
+}
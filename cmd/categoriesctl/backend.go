@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/client"
+)
+
+// httpTimeout bounds every request the http backend sends.
+const httpTimeout = 30 * time.Second
+
+// backend is where categoriesctl reads and writes categories. Get, Update and Delete return an error wrapping
+// entity.ErrCategoryNotFound for unknown IDs.
+type backend interface {
+	List(ctx context.Context) ([]entity.Category, error)
+	Get(ctx context.Context, id int64) (entity.Category, error)
+	// Create stores a new category with its translations. A non-zero ID is kept.
+	Create(ctx context.Context, category entity.Category) (entity.Category, error)
+	// Update replaces the name, description and slug of a category and stores the translations it carries.
+	Update(ctx context.Context, category entity.Category) (entity.Category, error)
+	Delete(ctx context.Context, id int64) error
+	// Close persists pending changes and releases the backend.
+	Close() error
+}
+
+// openBackend opens the backend described by spec:
+//
+//	memory            the categories repository of this process, starting with its built-in categories
+//	file:PATH         the categories repository loaded from and saved back to the JSON export at PATH
+//	http(s)://HOST    the HTTP API; the path defaults to /api/v1
+func openBackend(spec string) (backend, error) {
+	switch {
+	case spec == "" || spec == "memory":
+		return newRepositoryBackend("")
+	case strings.HasPrefix(spec, "file:"):
+		path := strings.TrimPrefix(spec, "file:")
+		if path == "" {
+			return nil, fmt.Errorf("backend %q has no file path", spec)
+		}
+		return newRepositoryBackend(path)
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return newHTTPBackend(spec, nil)
+	default:
+		return nil, fmt.Errorf("unknown backend %q, expected memory, file:PATH or an http(s) URL", spec)
+	}
+}
+
+// repositoryBackend works directly against an ICategoriesRepository. With a path, the repository is loaded from
+// the JSON export at path and written back to it on Close when anything changed. The backend works on the tenant
+// carried by the context of each call, which is the default tenant unless the caller sets one.
+type repositoryBackend struct {
+	repo  repository.ICategoriesRepository
+	path  string
+	dirty bool
+}
+
+// newRepositoryBackend opens the categories repository, replacing its content with the categories stored at path
+// when path is set. A missing file starts an empty repository.
+func newRepositoryBackend(path string) (*repositoryBackend, error) {
+	repo, err := repository.NewCategoriesRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &repositoryBackend{repo: repo, path: path}
+	if path == "" {
+		return b, nil
+	}
+
+	var categories []entity.Category
+	file, err := os.Open(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		defer file.Close()
+		categories, err = readCategories(file, formatJSON)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	ctx := context.Background()
+	for _, category := range repo.GetAllCategories(ctx) {
+		if _, err := repo.DeleteCategory(ctx, category.ID); err != nil {
+			return nil, err
+		}
+	}
+	for _, category := range categories {
+		if _, err := b.Create(ctx, category); err != nil {
+			return nil, err
+		}
+	}
+	b.dirty = false
+
+	return b, nil
+}
+
+// List returns every category in the repository.
+func (b *repositoryBackend) List(ctx context.Context) ([]entity.Category, error) {
+	return b.repo.GetAllCategories(ctx), nil
+}
+
+// Get returns the category with the given ID.
+func (b *repositoryBackend) Get(ctx context.Context, id int64) (entity.Category, error) {
+	category := b.repo.GetCategoryByID(ctx, id)
+	if category.ID == 0 {
+		return entity.Category{}, fmt.Errorf("category %d: %w", id, entity.ErrCategoryNotFound)
+	}
+	return category, nil
+}
+
+// Create inserts the category and then each of its translations.
+func (b *repositoryBackend) Create(ctx context.Context, category entity.Category) (entity.Category, error) {
+	created := b.repo.InsertCategory(ctx, category)
+	b.dirty = true
+	return b.storeTranslations(ctx, created, category.Translations)
+}
+
+// Update updates the category and then stores each of its translations.
+func (b *repositoryBackend) Update(ctx context.Context, category entity.Category) (entity.Category, error) {
+	updated, err := b.repo.UpdateCategory(ctx, category)
+	if err != nil {
+		return entity.Category{}, fmt.Errorf("category %d: %w", category.ID, err)
+	}
+	b.dirty = true
+	return b.storeTranslations(ctx, updated, category.Translations)
+}
+
+// storeTranslations upserts translations into category and returns the category as stored afterwards.
+func (b *repositoryBackend) storeTranslations(ctx context.Context, category entity.Category, translations map[string]entity.Translation) (entity.Category, error) {
+	for _, locale := range sortedLocales(translations) {
+		var err error
+		category, err = b.repo.UpsertTranslation(ctx, category.ID, locale, translations[locale])
+		if err != nil {
+			return entity.Category{}, err
+		}
+	}
+	return category, nil
+}
+
+// Delete removes the category with the given ID.
+func (b *repositoryBackend) Delete(ctx context.Context, id int64) error {
+	if _, err := b.repo.DeleteCategory(ctx, id); err != nil {
+		return fmt.Errorf("category %d: %w", id, err)
+	}
+	b.dirty = true
+	return nil
+}
+
+// Close writes the repository back to its file when it has one and anything changed.
+func (b *repositoryBackend) Close() error {
+	if b.path == "" || !b.dirty {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := writeCategories(&buf, formatJSON, b.repo.GetAllCategories(context.Background())); err != nil {
+		return err
+	}
+
+	// Write next to the target and rename, so an interrupted save never leaves a truncated file behind.
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
+
+// httpBackend works remotely against the categories HTTP API.
+type httpBackend struct {
+	client *client.Client
+}
+
+// newHTTPBackend returns a backend for the API at rawURL, sending requests with httpClient or, when nil, a client
+// with httpTimeout.
+func newHTTPBackend(rawURL string, httpClient *http.Client) (*httpBackend, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: httpTimeout}
+	}
+
+	c, err := client.New(rawURL, client.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return &httpBackend{client: c}, nil
+}
+
+// List returns every category served by the API.
+func (b *httpBackend) List(ctx context.Context) ([]entity.Category, error) {
+	categories, err := b.client.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]entity.Category, len(categories))
+	for i, category := range categories {
+		listed[i] = fromClient(category)
+	}
+	return listed, nil
+}
+
+// Get returns the category with the given ID.
+func (b *httpBackend) Get(ctx context.Context, id int64) (entity.Category, error) {
+	category, err := b.client.Get(ctx, id)
+	if err != nil {
+		return entity.Category{}, notFound(err)
+	}
+	return fromClient(category), nil
+}
+
+// Create creates the category and then puts each of its translations.
+func (b *httpBackend) Create(ctx context.Context, category entity.Category) (entity.Category, error) {
+	created, err := b.client.Create(ctx, toClient(category))
+	if err != nil {
+		return entity.Category{}, err
+	}
+	return b.storeTranslations(ctx, fromClient(created), category.Translations)
+}
+
+// Update updates the category and then puts each of its translations.
+func (b *httpBackend) Update(ctx context.Context, category entity.Category) (entity.Category, error) {
+	updated, err := b.client.Update(ctx, toClient(category))
+	if err != nil {
+		return entity.Category{}, notFound(err)
+	}
+	return b.storeTranslations(ctx, fromClient(updated), category.Translations)
+}
+
+// storeTranslations puts translations of category and returns the category as stored afterwards.
+func (b *httpBackend) storeTranslations(ctx context.Context, category entity.Category, translations map[string]entity.Translation) (entity.Category, error) {
+	if len(translations) == 0 {
+		return category, nil
+	}
+
+	for _, locale := range sortedLocales(translations) {
+		translation := client.Translation{Name: translations[locale].Name, Description: translations[locale].Description}
+		if _, err := b.client.PutTranslation(ctx, category.ID, locale, translation); err != nil {
+			return entity.Category{}, err
+		}
+	}
+	return b.Get(ctx, category.ID)
+}
+
+// Delete deletes the category with the given ID.
+func (b *httpBackend) Delete(ctx context.Context, id int64) error {
+	return notFound(b.client.Delete(ctx, id))
+}
+
+// Close does nothing; the http client needs no cleanup.
+func (b *httpBackend) Close() error {
+	return nil
+}
+
+// notFound wraps client.ErrNotFound errors with entity.ErrCategoryNotFound, as the backend interface promises.
+func notFound(err error) error {
+	if errors.Is(err, client.ErrNotFound) {
+		return fmt.Errorf("%w: %w", entity.ErrCategoryNotFound, err)
+	}
+	return err
+}
+
+// toClient returns the fields of category the client sends when creating or updating it.
+func toClient(category entity.Category) client.Category {
+	return client.Category{
+		ID:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		Position:    category.Position,
+		Attributes:  category.Attributes,
+	}
+}
+
+// fromClient returns the category served by the API as an entity.Category.
+func fromClient(category client.Category) entity.Category {
+	converted := entity.Category{
+		ID:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		Position:    category.Position,
+		Attributes:  category.Attributes,
+		Locale:      category.Locale,
+	}
+	if image := category.Image; image != nil {
+		converted.Image = &entity.CategoryImage{
+			ContentType: image.ContentType,
+			Size:        image.Size,
+			Width:       image.Width,
+			Height:      image.Height,
+			Checksum:    image.Checksum,
+		}
+	}
+	if len(category.Translations) > 0 {
+		converted.Translations = make(map[string]entity.Translation, len(category.Translations))
+		for locale, translation := range category.Translations {
+			converted.Translations[locale] = entity.Translation{Name: translation.Name, Description: translation.Description}
+		}
+	}
+	return converted
+}
+
+// sortedLocales returns the locales of translations in sorted order, so they are stored deterministically.
+func sortedLocales(translations map[string]entity.Translation) []string {
+	locales := make([]string, 0, len(translations))
+	for locale := range translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/api/router"
+	categoriesHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/http"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/client"
+)
+
+// newAPIServer serves the categories API over the categories repository, with its content replaced by
+// seedCategories, and returns its URL.
+func newAPIServer(t *testing.T) string {
+	t.Helper()
+
+	seed, err := newRepositoryBackend(writeSeed(t))
+	if err != nil {
+		t.Fatalf("unexpected seed error: %v", err)
+	}
+
+	svc, err := service.NewCategoriesService(seed.repo, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected service error: %v", err)
+	}
+	handler, err := categoriesHandler.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", router.NewRouter(handler).RegisterRoutes()))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestHTTPBackend(t *testing.T) {
+	b, err := newHTTPBackend(newAPIServer(t), nil)
+	if err != nil {
+		t.Fatalf("unexpected backend error: %v", err)
+	}
+	defer b.Close()
+	ctx := context.Background()
+
+	categories, err := b.List(ctx)
+	if err != nil || len(categories) != 2 {
+		t.Fatalf("expected the 2 seeded categories, got %+v (%v)", categories, err)
+	}
+
+	created, err := b.Create(ctx, entity.Category{ID: 10, Name: "Buku Anak",
+		Translations: map[string]entity.Translation{"en": {Name: "Children's books"}}})
+	if err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	if created.ID != 10 || created.Slug != "buku-anak" || created.Translations["en"].Name != "Children's books" {
+		t.Errorf("unexpected created category %+v", created)
+	}
+
+	updated, err := b.Update(ctx, entity.Category{ID: 10, Name: "Buku", Description: "Bacaan"})
+	if err != nil {
+		t.Fatalf("unexpected update error: %v", err)
+	}
+	if updated.Name != "Buku" || updated.Slug != "buku" {
+		t.Errorf("unexpected updated category %+v", updated)
+	}
+
+	if err := b.Delete(ctx, 10); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+
+	_, err = b.Get(ctx, 10)
+	if !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected the api error to be kept, got %#v", err)
+	}
+
+	if err := b.Delete(ctx, 10); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("expected deleting twice to report not found, got %v", err)
+	}
+}
+
+func TestRun_HTTPBackend(t *testing.T) {
+	url := newAPIServer(t)
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout string
+	}{
+		{
+			name:       "list",
+			args:       []string{"-o", "csv", "list"},
+			wantStdout: "id,name,slug,description\n1,Elektronik,elektronik,Kategori Elektronik\n2,Komputer,komputer,Kategori Komputer\n",
+		},
+		{
+			name:       "import upserts",
+			args:       []string{"import", "-format", "csv", "-"},
+			stdin:      "id,name\n2,Laptop\n20,Kamera\n",
+			wantStdout: "imported 2 categories (1 created, 1 updated)\n",
+		},
+		{
+			name:       "get imported",
+			args:       []string{"get", "20"},
+			wantStdout: "20  Kamera  kamera",
+		},
+		{
+			name:     "get unknown",
+			args:     []string{"get", "99"},
+			wantCode: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-backend", url}, tt.args...)
+			code, stdout, stderr := runCLI(t, tt.stdin, args...)
+			if code != tt.wantCode {
+				t.Fatalf("expected exit code %d, got %d (stderr %q)", tt.wantCode, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("expected stdout to contain %q, got %q", tt.wantStdout, stdout)
+			}
+		})
+	}
+}
+
+func TestNewHTTPBackend_BaseURL(t *testing.T) {
+	tests := []struct {
+		rawURL string
+		want   string
+	}{
+		{rawURL: "http://localhost:8000", want: "http://localhost:8000/api/v1"},
+		{rawURL: "http://localhost:8000/", want: "http://localhost:8000/api/v1"},
+		{rawURL: "https://example.com/categories-api/api/v1/", want: "https://example.com/categories-api/api/v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawURL, func(t *testing.T) {
+			b, err := newHTTPBackend(tt.rawURL, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := b.client.BaseURL(); got != tt.want {
+				t.Errorf("expected base url %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,464 @@
+// Command categoriesctl seeds, inspects and fixes categories, either directly against a categories repository or
+// remotely against the HTTP API.
+//
+// Usage:
+//
+//	categoriesctl [-backend BACKEND] [-o table|json|csv] COMMAND [ARGS]
+//
+// Run categoriesctl -h for the list of commands and backends.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+// backendEnv names the environment variable holding the default backend.
+const backendEnv = "CATEGORIESCTL_BACKEND"
+
+// errUsage is returned by commands called with invalid arguments; their usage has already been printed.
+var errUsage = errors.New("usage")
+
+// command is a categoriesctl subcommand.
+type command struct {
+	usage       string
+	description string
+	run         func(c *cli, args []string) error
+}
+
+// commands maps subcommand names to their command.
+var commands = map[string]command{
+	"list":    {"list", "list all categories", (*cli).list},
+	"get":     {"get ID", "show one category", (*cli).get},
+	"create":  {"create -name NAME [-slug SLUG] [-description TEXT] [-id ID]", "create a category", (*cli).create},
+	"update":  {"update ID [-name NAME] [-slug SLUG] [-description TEXT]", "change the given fields of a category", (*cli).update},
+	"delete":  {"delete ID", "delete a category", (*cli).delete},
+	"import":  {"import [-format json|csv] FILE", "create or update, by ID, the categories in FILE (- is stdin)", (*cli).importFile},
+	"export":  {"export [-format json|csv] [-file FILE]", "write all categories to stdout or FILE", (*cli).export},
+	"migrate": {"migrate -to BACKEND", "create or update, by ID, every category in BACKEND", (*cli).migrate},
+}
+
+// commandOrder is the order commands are listed in the usage.
+var commandOrder = []string{"list", "get", "create", "update", "delete", "import", "export", "migrate"}
+
+// cli holds what the commands of one categoriesctl run share.
+type cli struct {
+	ctx     context.Context
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	backend backend
+	format  string
+	// usage is the usage line of the command being run.
+	usage string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run runs categoriesctl with args and returns its exit code: 0 on success, 1 on failure and 2 on invalid usage.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("categoriesctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { usage(fs) }
+
+	defaultBackend := os.Getenv(backendEnv)
+	if defaultBackend == "" {
+		defaultBackend = "memory"
+	}
+	backendSpec := fs.String("backend", defaultBackend, "`backend` to work against: memory, file:PATH or an http(s) URL of the API (env "+backendEnv+")")
+	output := fs.String("o", formatTable, "output `format`: table, json or csv")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 {
+		usage(fs)
+		return 2
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "categoriesctl: unknown command %q\n", fs.Arg(0))
+		usage(fs)
+		return 2
+	}
+
+	format, err := parseFormat(*output)
+	if err != nil {
+		fmt.Fprintf(stderr, "categoriesctl: %v\n", err)
+		return 2
+	}
+
+	b, err := openBackend(*backendSpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "categoriesctl: %v\n", err)
+		return 1
+	}
+
+	c := &cli{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, backend: b, format: format, usage: cmd.usage}
+	err = cmd.run(c, fs.Args()[1:])
+	if closeErr := b.Close(); err == nil {
+		err = closeErr
+	}
+
+	switch {
+	case errors.Is(err, errUsage):
+		return 2
+	case err != nil:
+		fmt.Fprintf(stderr, "categoriesctl: %v\n", err)
+		return 1
+	default:
+		return 0
+	}
+}
+
+// usage prints the global usage with the list of commands.
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Usage: categoriesctl [-backend BACKEND] [-o table|json|csv] COMMAND [ARGS]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(out, "  %-62s %s\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flags:")
+	fs.PrintDefaults()
+}
+
+// flagSet returns the flag set of the named command, printing its usage on errors.
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: categoriesctl %s\n", c.usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args into fs and checks that exactly nArgs positional arguments remain. Positional arguments may
+// come before the flags, as in "update 3 -name Laptop".
+func parse(fs *flag.FlagSet, args []string, nArgs int) ([]string, error) {
+	var leading []string
+	for len(args) > 0 && len(leading) < nArgs && !strings.HasPrefix(args[0], "-") {
+		leading = append(leading, args[0])
+		args = args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, errUsage
+	}
+
+	positional := append(leading, fs.Args()...)
+	if len(positional) != nArgs {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// parseID parses a category ID argument.
+func parseID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid category id %q", arg)
+	}
+	return id, nil
+}
+
+// list writes all categories.
+func (c *cli) list(args []string) error {
+	fs := c.flagSet("list")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	categories, err := c.backend.List(c.ctx)
+	if err != nil {
+		return err
+	}
+	return writeCategories(c.stdout, c.format, categories)
+}
+
+// get writes the category with the given ID.
+func (c *cli) get(args []string) error {
+	fs := c.flagSet("get")
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	category, err := c.backend.Get(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return writeCategory(c.stdout, c.format, category)
+}
+
+// create creates a category and writes it.
+func (c *cli) create(args []string) error {
+	fs := c.flagSet("create")
+	var category entity.Category
+	fs.StringVar(&category.Name, "name", "", "category `name`")
+	fs.StringVar(&category.Slug, "slug", "", "category `slug`; generated from the name when empty")
+	fs.StringVar(&category.Description, "description", "", "category `description`")
+	fs.Int64Var(&category.ID, "id", 0, "category `id`; the next free ID when 0")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if strings.TrimSpace(category.Name) == "" {
+		fmt.Fprintln(c.stderr, "categoriesctl: create needs -name")
+		fs.Usage()
+		return errUsage
+	}
+
+	if category.ID != 0 {
+		if _, err := c.backend.Get(c.ctx, category.ID); err == nil {
+			return fmt.Errorf("category %d already exists", category.ID)
+		} else if !errors.Is(err, entity.ErrCategoryNotFound) {
+			return err
+		}
+	}
+
+	created, err := c.backend.Create(c.ctx, category)
+	if err != nil {
+		return err
+	}
+	return writeCategory(c.stdout, c.format, created)
+}
+
+// update changes the fields given as flags of the category with the given ID and writes it. Without -slug, the
+// slug is regenerated when the name changes.
+func (c *cli) update(args []string) error {
+	fs := c.flagSet("update")
+	name := fs.String("name", "", "new category `name`")
+	slug := fs.String("slug", "", "new category `slug`")
+	description := fs.String("description", "", "new category `description`")
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if len(set) == 0 {
+		fmt.Fprintln(c.stderr, "categoriesctl: update needs at least one of -name, -slug and -description")
+		fs.Usage()
+		return errUsage
+	}
+
+	category, err := c.backend.Get(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	category.Translations = nil
+	category.Slug = ""
+	if set["name"] {
+		category.Name = *name
+	}
+	if set["slug"] {
+		category.Slug = *slug
+	}
+	if set["description"] {
+		category.Description = *description
+	}
+	if strings.TrimSpace(category.Name) == "" {
+		return fmt.Errorf("category name must not be empty")
+	}
+
+	updated, err := c.backend.Update(c.ctx, category)
+	if err != nil {
+		return err
+	}
+	return writeCategory(c.stdout, c.format, updated)
+}
+
+// delete deletes the category with the given ID.
+func (c *cli) delete(args []string) error {
+	fs := c.flagSet("delete")
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if err := c.backend.Delete(c.ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "deleted category %d\n", id)
+	return nil
+}
+
+// importFile creates or updates the categories of a JSON or CSV file.
+func (c *cli) importFile(args []string) error {
+	fs := c.flagSet("import")
+	format := fs.String("format", "", "input `format`, json or csv; taken from the file extension when empty")
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+
+	inputFormat := *format
+	if inputFormat == "" {
+		inputFormat = formatJSON
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			inputFormat = formatCSV
+		}
+	}
+	if inputFormat, err = parseFormat(inputFormat); err != nil || inputFormat == formatTable {
+		return fmt.Errorf("unknown input format %q, expected json or csv", *format)
+	}
+
+	in := c.stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	categories, err := readCategories(in, inputFormat)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	created, updated, err := upsertAll(c.ctx, c.backend, categories)
+	fmt.Fprintf(c.stdout, "imported %d categories (%d created, %d updated)\n", created+updated, created, updated)
+	return err
+}
+
+// export writes all categories as JSON or CSV to stdout or a file.
+func (c *cli) export(args []string) error {
+	fs := c.flagSet("export")
+	format := fs.String("format", formatJSON, "export `format`, json or csv")
+	path := fs.String("file", "", "`file` to write to instead of stdout")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	exportFormat, err := parseFormat(*format)
+	if err != nil || exportFormat == formatTable {
+		return fmt.Errorf("unknown export format %q, expected json or csv", *format)
+	}
+
+	categories, err := c.backend.List(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return writeCategories(c.stdout, exportFormat, categories)
+	}
+
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	if err := writeCategories(file, exportFormat, categories); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "exported %d categories to %s\n", len(categories), *path)
+	return nil
+}
+
+// migrate copies every category of the backend into another backend.
+func (c *cli) migrate(args []string) error {
+	fs := c.flagSet("migrate")
+	to := fs.String("to", "", "`backend` to copy the categories to")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *to == "" {
+		fmt.Fprintln(c.stderr, "categoriesctl: migrate needs -to")
+		fs.Usage()
+		return errUsage
+	}
+
+	categories, err := c.backend.List(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	target, err := openBackend(*to)
+	if err != nil {
+		return err
+	}
+	created, updated, err := upsertAll(c.ctx, target, categories)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	fmt.Fprintf(c.stdout, "migrated %d categories (%d created, %d updated)\n", created+updated, created, updated)
+	return err
+}
+
+// upsertAll updates the categories that exist in b by ID and creates the others, stopping at the first error.
+func upsertAll(ctx context.Context, b backend, categories []entity.Category) (created, updated int, err error) {
+	for _, category := range categories {
+		if strings.TrimSpace(category.Name) == "" {
+			return created, updated, fmt.Errorf("category %d has no name", category.ID)
+		}
+
+		exists := false
+		if category.ID != 0 {
+			_, err := b.Get(ctx, category.ID)
+			switch {
+			case err == nil:
+				exists = true
+			case !errors.Is(err, entity.ErrCategoryNotFound):
+				return created, updated, err
+			}
+		}
+
+		if exists {
+			if _, err := b.Update(ctx, category); err != nil {
+				return created, updated, err
+			}
+			updated++
+			continue
+		}
+
+		if _, err := b.Create(ctx, category); err != nil {
+			return created, updated, err
+		}
+		created++
+	}
+
+	return created, updated, nil
+}
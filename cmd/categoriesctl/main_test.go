@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+// seedCategories are the categories writeSeed stores.
+var seedCategories = []entity.Category{
+	{ID: 1, Name: "Elektronik", Slug: "elektronik", Description: "Kategori Elektronik"},
+	{ID: 2, Name: "Komputer", Slug: "komputer", Description: "Kategori Komputer",
+		Translations: map[string]entity.Translation{"en": {Name: "Computers", Description: "Computer category"}}},
+}
+
+// writeSeed writes seedCategories as a JSON export into a temporary directory and returns its path.
+func writeSeed(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "categories.json")
+	content, err := json.Marshal(seedCategories)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	return path
+}
+
+// runCLI runs categoriesctl with args and returns its exit code, stdout and stderr.
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// readExport reads the categories stored in the JSON export at path.
+func readExport(t *testing.T, path string) []entity.Category {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected open error: %v", err)
+	}
+	defer file.Close()
+
+	categories, err := readCategories(file, formatJSON)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	return categories
+}
+
+func TestRun_Commands(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout []string
+		wantStderr string
+		wantStored []string
+	}{
+		{
+			name:       "list table",
+			args:       []string{"list"},
+			wantStdout: []string{"ID  NAME", "1   Elektronik  elektronik  Kategori Elektronik", "2   Komputer"},
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "list csv",
+			args:       []string{"-o", "csv", "list"},
+			wantStdout: []string{"id,name,slug,description\n1,Elektronik,elektronik,Kategori Elektronik\n"},
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "get json keeps translations",
+			args:       []string{"-o", "json", "get", "2"},
+			wantStdout: []string{`"name": "Komputer"`, `"en": {`, `"name": "Computers"`},
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "get unknown id",
+			args:       []string{"get", "9"},
+			wantCode:   1,
+			wantStderr: "category 9",
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "get invalid id",
+			args:       []string{"get", "abc"},
+			wantCode:   1,
+			wantStderr: `invalid category id "abc"`,
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "create",
+			args:       []string{"create", "-name", "Buku Anak", "-description", "Bacaan"},
+			wantStdout: []string{"3   Buku Anak  buku-anak  Bacaan"},
+			wantStored: []string{"Elektronik", "Komputer", "Buku Anak"},
+		},
+		{
+			name:       "create with existing id",
+			args:       []string{"create", "-name", "Buku", "-id", "1"},
+			wantCode:   1,
+			wantStderr: "category 1 already exists",
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "create without name",
+			args:       []string{"create", "-description", "Bacaan"},
+			wantCode:   2,
+			wantStderr: "create needs -name",
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "update renames and regenerates slug",
+			args:       []string{"update", "2", "-name", "Laptop"},
+			wantStdout: []string{"2   Laptop  laptop  Kategori Komputer"},
+			wantStored: []string{"Elektronik", "Laptop"},
+		},
+		{
+			name:       "update with id after flags",
+			args:       []string{"update", "-description", "Gawai", "1"},
+			wantStdout: []string{"1   Elektronik  elektronik  Gawai"},
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "update without fields",
+			args:       []string{"update", "1"},
+			wantCode:   2,
+			wantStderr: "update needs at least one of",
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "update unknown id",
+			args:       []string{"update", "9", "-name", "Laptop"},
+			wantCode:   1,
+			wantStderr: "category 9",
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "delete",
+			args:       []string{"delete", "1"},
+			wantStdout: []string{"deleted category 1"},
+			wantStored: []string{"Komputer"},
+		},
+		{
+			name:       "delete unknown id",
+			args:       []string{"delete", "9"},
+			wantCode:   1,
+			wantStderr: "category 9",
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "import csv from stdin",
+			args:       []string{"import", "-format", "csv", "-"},
+			stdin:      "id,name,description\n1,Gawai,Perangkat\n,Buku,Bacaan\n",
+			wantStdout: []string{"imported 2 categories (1 created, 1 updated)"},
+			wantStored: []string{"Gawai", "Komputer", "Buku"},
+		},
+		{
+			name:       "import json from stdin",
+			args:       []string{"import", "-"},
+			stdin:      `[{"id":7,"name":"Olahraga","translations":{"en":{"name":"Sports"}}}]`,
+			wantStdout: []string{"imported 1 categories (1 created, 0 updated)"},
+			wantStored: []string{"Elektronik", "Komputer", "Olahraga"},
+		},
+		{
+			name:       "import without name",
+			args:       []string{"import", "-format", "csv", "-"},
+			stdin:      "id,name\n5,\n",
+			wantCode:   1,
+			wantStderr: "category 5 has no name",
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "export csv",
+			args:       []string{"export", "-format", "csv"},
+			wantStdout: []string{"id,name,slug,description\n1,Elektronik", "2,Komputer,komputer"},
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "unknown command",
+			args:       []string{"purge"},
+			wantCode:   2,
+			wantStderr: `unknown command "purge"`,
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+		{
+			name:       "unknown output format",
+			args:       []string{"-o", "yaml", "list"},
+			wantCode:   2,
+			wantStderr: `unknown output format "yaml"`,
+			wantStored: []string{"Elektronik", "Komputer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeSeed(t)
+			args := append([]string{"-backend", "file:" + path}, tt.args...)
+
+			code, stdout, stderr := runCLI(t, tt.stdin, args...)
+			if code != tt.wantCode {
+				t.Fatalf("expected exit code %d, got %d (stderr %q)", tt.wantCode, code, stderr)
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("expected stdout to contain %q, got %q", want, stdout)
+				}
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.wantStderr, stderr)
+			}
+
+			stored := readExport(t, path)
+			if len(stored) != len(tt.wantStored) {
+				t.Fatalf("expected %d stored categories, got %+v", len(tt.wantStored), stored)
+			}
+			for i, name := range tt.wantStored {
+				if stored[i].Name != name {
+					t.Errorf("expected stored category %d to be %q, got %q", i, name, stored[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_ExportToFileAndImport(t *testing.T) {
+	source := writeSeed(t)
+	exported := filepath.Join(t.TempDir(), "export.json")
+
+	code, stdout, stderr := runCLI(t, "", "-backend", "file:"+source, "export", "-file", exported)
+	if code != 0 {
+		t.Fatalf("expected export to succeed, got %d (stderr %q)", code, stderr)
+	}
+	if !strings.Contains(stdout, "exported 2 categories") {
+		t.Errorf("unexpected export output %q", stdout)
+	}
+
+	target := filepath.Join(t.TempDir(), "target.json")
+	code, _, stderr = runCLI(t, "", "-backend", "file:"+target, "import", exported)
+	if code != 0 {
+		t.Fatalf("expected import to succeed, got %d (stderr %q)", code, stderr)
+	}
+
+	stored := readExport(t, target)
+	if len(stored) != 2 || stored[1].Translations["en"].Name != "Computers" {
+		t.Errorf("expected the export to round trip with translations, got %+v", stored)
+	}
+}
+
+func TestRun_Migrate(t *testing.T) {
+	source := writeSeed(t)
+	target := filepath.Join(t.TempDir(), "target.json")
+	if err := os.WriteFile(target, []byte(`[{"id":2,"name":"Lama","slug":"lama","description":""},{"id":5,"name":"Lain","slug":"lain","description":""}]`), 0o644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	code, stdout, stderr := runCLI(t, "", "-backend", "file:"+source, "migrate", "-to", "file:"+target)
+	if code != 0 {
+		t.Fatalf("expected migrate to succeed, got %d (stderr %q)", code, stderr)
+	}
+	if !strings.Contains(stdout, "migrated 2 categories (1 created, 1 updated)") {
+		t.Errorf("unexpected migrate output %q", stdout)
+	}
+
+	stored := readExport(t, target)
+	names := make([]string, len(stored))
+	for i, category := range stored {
+		names[i] = category.Name
+	}
+	if got := strings.Join(names, ","); got != "Komputer,Lain,Elektronik" {
+		t.Errorf("expected the target to hold Komputer,Lain,Elektronik, got %s", got)
+	}
+	if stored[0].Translations["en"].Name != "Computers" {
+		t.Errorf("expected translations to be migrated, got %+v", stored[0])
+	}
+
+	if source := readExport(t, source); len(source) != 2 {
+		t.Errorf("expected the source to be left alone, got %+v", source)
+	}
+}
+
+func TestRun_MissingFileStartsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.json")
+
+	code, stdout, _ := runCLI(t, "", "-backend", "file:"+path, "-o", "json", "list")
+	if code != 0 || strings.TrimSpace(stdout) != "[]" {
+		t.Fatalf("expected an empty list, got %d %q", code, stdout)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected reads not to create the file, got %v", err)
+	}
+}
+
+func TestOpenBackend(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{spec: "memory"},
+		{spec: ""},
+		{spec: "file:" + filepath.Join(t.TempDir(), "categories.json")},
+		{spec: "http://localhost:8000"},
+		{spec: "file:", wantErr: "has no file path"},
+		{spec: "postgres://db", wantErr: "unknown backend"},
+		{spec: "http://", wantErr: "has no host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			b, err := openBackend(tt.spec)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				_ = b.Close()
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+// Formats categories are written and read in.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// csvHeader is the header row of CSV exports. Translations are not part of CSV exports.
+var csvHeader = []string{"id", "name", "slug", "description"}
+
+// parseFormat validates an output format name.
+func parseFormat(name string) (string, error) {
+	switch format := strings.ToLower(name); format {
+	case formatTable, formatJSON, formatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected table, json or csv", name)
+	}
+}
+
+// writeCategories writes categories to w in format. JSON output is the format file backends and imports read.
+func writeCategories(w io.Writer, format string, categories []entity.Category) error {
+	// Locale only says which language a read was localized to, so it is not part of the output.
+	stored := make([]entity.Category, len(categories))
+	for i, category := range categories {
+		category.Locale = ""
+		stored[i] = category
+	}
+
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stored)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, category := range stored {
+			record := []string{strconv.FormatInt(category.ID, 10), category.Name, category.Slug, category.Description}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tSLUG\tDESCRIPTION")
+		for _, category := range stored {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", category.ID, category.Name, category.Slug, strings.TrimSpace(category.Description))
+		}
+		return tw.Flush()
+	}
+}
+
+// readCategories reads categories written by writeCategories in the json or csv format. CSV input needs a header
+// row naming at least the name column; columns are matched by name, so their order does not matter.
+func readCategories(r io.Reader, format string) ([]entity.Category, error) {
+	switch format {
+	case formatJSON:
+		var categories []entity.Category
+		if err := json.NewDecoder(r).Decode(&categories); err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return categories, nil
+	case formatCSV:
+		return readCSV(r)
+	default:
+		return nil, fmt.Errorf("cannot read categories in %s format", format)
+	}
+}
+
+// readCSV reads categories from CSV with a header row.
+func readCSV(r io.Reader) ([]entity.Category, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("csv header has no name column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var categories []entity.Category
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return categories, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var category entity.Category
+		if id := strings.TrimSpace(field(record, "id")); id != "" {
+			category.ID, err = strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				line, _ := cr.FieldPos(0)
+				return nil, fmt.Errorf("line %d: invalid id %q", line, id)
+			}
+		}
+		category.Name = field(record, "name")
+		category.Slug = field(record, "slug")
+		category.Description = field(record, "description")
+		categories = append(categories, category)
+	}
+}
+
+// writeCategory writes a single category to w in format. JSON output is the category object itself.
+func writeCategory(w io.Writer, format string, category entity.Category) error {
+	if format != formatJSON {
+		return writeCategories(w, format, []entity.Category{category})
+	}
+
+	category.Locale = ""
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(category)
+}
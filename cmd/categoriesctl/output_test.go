@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+func TestWriteCategories(t *testing.T) {
+	categories := []entity.Category{
+		{ID: 1, Name: "Elektronik", Slug: "elektronik", Description: "Kategori, Elektronik", Position: 1024, Locale: "id"},
+		{ID: 12, Name: "Buku Anak", Slug: "buku-anak", Description: " Bacaan", Position: 2048},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: formatTable,
+			want: "ID  NAME        SLUG        DESCRIPTION\n" +
+				"1   Elektronik  elektronik  Kategori, Elektronik\n" +
+				"12  Buku Anak   buku-anak   Bacaan\n",
+		},
+		{
+			format: formatCSV,
+			want:   "id,name,slug,description\n1,Elektronik,elektronik,\"Kategori, Elektronik\"\n12,Buku Anak,buku-anak,\" Bacaan\"\n",
+		},
+		{
+			format: formatJSON,
+			want: "[\n  {\n    \"id\": 1,\n    \"name\": \"Elektronik\",\n    \"slug\": \"elektronik\",\n    \"description\": \"Kategori, Elektronik\",\n    \"position\": 1024\n  },\n" +
+				"  {\n    \"id\": 12,\n    \"name\": \"Buku Anak\",\n    \"slug\": \"buku-anak\",\n    \"description\": \" Bacaan\",\n    \"position\": 2048\n  }\n]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCategories(&buf, tt.format, categories); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReadCategories_RoundTrip(t *testing.T) {
+	categories := []entity.Category{
+		{ID: 1, Name: "Elektronik", Slug: "elektronik", Description: "Kategori \"Elektronik\", baru"},
+		{ID: 2, Name: "Komputer", Slug: "komputer", Description: "",
+			Translations: map[string]entity.Translation{"en": {Name: "Computers"}}},
+	}
+
+	for _, format := range []string{formatJSON, formatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCategories(&buf, format, categories); err != nil {
+				t.Fatalf("unexpected write error: %v", err)
+			}
+
+			got, err := readCategories(&buf, format)
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+
+			want := categories
+			if format == formatCSV {
+				want = []entity.Category{categories[0], categories[1]}
+				want[1].Translations = nil
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestReadCategories_CSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []entity.Category
+		wantErr string
+	}{
+		{
+			name:  "columns in any order",
+			input: "Description,Name,ID\nBacaan,Buku,4\n",
+			want:  []entity.Category{{ID: 4, Name: "Buku", Description: "Bacaan"}},
+		},
+		{
+			name:  "no id column",
+			input: "name\nBuku\n",
+			want:  []entity.Category{{Name: "Buku"}},
+		},
+		{
+			name:  "empty input",
+			input: "",
+		},
+		{
+			name:    "no name column",
+			input:   "id,slug\n1,buku\n",
+			wantErr: "no name column",
+		},
+		{
+			name:    "invalid id",
+			input:   "id,name\n1,Buku\nx,Pena\n",
+			wantErr: `line 3: invalid id "x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCategories(strings.NewReader(tt.input), formatCSV)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "table", want: formatTable},
+		{name: "JSON", want: formatJSON},
+		{name: "csv", want: formatCSV},
+		{name: "yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
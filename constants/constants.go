@@ -16,4 +16,129 @@ const (
 
 	// ErrInvalidRequest represents an error message for an invalid category request during request parsing or validation.
 	ErrInvalidRequest = "request kategori tidak valid"
+
+	// ErrInvalidLocale indicates that the locale given for a translation is not a valid BCP 47 language tag.
+	ErrInvalidLocale = "locale tidak valid"
+
+	// ErrInvalidTranslation indicates that a category translation request failed parsing or has no name.
+	ErrInvalidTranslation = "terjemahan kategori tidak valid"
+
+	// ErrInvalidOrder indicates that a category order is empty or names a category more than once.
+	ErrInvalidOrder = "urutan kategori tidak valid"
+
+	// ErrInvalidAttribute indicates that the attributes of a category do not match the attribute definitions.
+	ErrInvalidAttribute = "atribut kategori tidak valid"
+
+	// ErrAttributeNotFound indicates that no category attribute is defined with the requested key.
+	ErrAttributeNotFound = "definisi atribut tidak ditemukan"
+
+	// ErrAttributeExists indicates that a category attribute is already defined with the requested key.
+	ErrAttributeExists = "definisi atribut sudah ada"
+
+	// ErrInvalidAttributeDefinition indicates that a category attribute definition failed parsing or validation.
+	ErrInvalidAttributeDefinition = "definisi atribut tidak valid"
+
+	// ErrAttributeInUse indicates that a category attribute definition cannot be changed or deleted because
+	// categories hold values that would no longer match it.
+	ErrAttributeInUse = "definisi atribut masih digunakan oleh kategori"
+
+	// ErrInvalidImage indicates that an uploaded category image is missing, cannot be decoded or has dimensions
+	// outside the allowed range.
+	ErrInvalidImage = "gambar kategori tidak valid"
+
+	// ErrUnsupportedImageType indicates that an uploaded category image is not in one of the supported formats.
+	ErrUnsupportedImageType = "format gambar kategori tidak didukung"
+
+	// ErrImageTooLarge indicates that an uploaded category image exceeds the maximum size.
+	ErrImageTooLarge = "ukuran gambar kategori terlalu besar"
+
+	// ErrImageNotFound indicates that the requested category has no image.
+	ErrImageNotFound = "gambar kategori tidak ditemukan"
+
+	// ErrCategoryInUse indicates that a category cannot be deleted because products are still classified by it.
+	ErrCategoryInUse = "kategori masih memiliki produk"
+
+	// ErrProductNotFound indicates that the specified product could not be found.
+	ErrProductNotFound = "produk tidak ditemukan"
+
+	// ErrInvalidProductID indicates that the provided product ID is invalid or cannot be processed.
+	ErrInvalidProductID = "id produk tidak valid"
+
+	// ErrInvalidProduct indicates that a product failed parsing or validation, e.g. because its category does not exist.
+	ErrInvalidProduct = "data produk tidak valid"
+
+	// ErrInvalidSearchQuery indicates that a search request has no usable query text.
+	ErrInvalidSearchQuery = "kata kunci pencarian tidak valid"
+
+	// ErrInvalidSearchLimit indicates that the requested number of search results is out of range.
+	ErrInvalidSearchLimit = "batas hasil pencarian tidak valid"
+
+	// ErrWebhookNotFound indicates that the specified webhook subscription could not be found.
+	ErrWebhookNotFound = "webhook tidak ditemukan"
+
+	// ErrInvalidWebhookID indicates that the provided webhook ID is invalid or cannot be processed.
+	ErrInvalidWebhookID = "id webhook tidak valid"
+
+	// ErrInvalidWebhookRequest indicates that a webhook subscription request failed parsing or validation.
+	ErrInvalidWebhookRequest = "request webhook tidak valid"
+
+	// ErrDeliveryNotFound indicates that the specified webhook delivery could not be found.
+	ErrDeliveryNotFound = "pengiriman webhook tidak ditemukan"
+
+	// ErrDeliveryNotRetryable indicates that only dead-lettered webhook deliveries can be retried manually.
+	ErrDeliveryNotRetryable = "pengiriman webhook tidak dapat diulang"
+
+	// ErrInvalidLastEventID indicates that the Last-Event-ID sent to resume an event stream is not a valid event ID.
+	ErrInvalidLastEventID = "Last-Event-ID tidak valid"
+
+	// ErrStreamingUnsupported indicates that the connection cannot flush Server-Sent Events to the client.
+	ErrStreamingUnsupported = "streaming tidak didukung"
+
+	// ErrRouteNotFound indicates that no route is registered for the requested path.
+	ErrRouteNotFound = "endpoint tidak ditemukan"
+
+	// ErrMethodNotAllowed indicates that the requested path exists but does not accept the request method.
+	ErrMethodNotAllowed = "metode tidak diizinkan"
+
+	// ErrInvalidIdempotencyKey indicates that the Idempotency-Key header is empty or too long.
+	ErrInvalidIdempotencyKey = "Idempotency-Key tidak valid"
+
+	// ErrIdempotencyConflict indicates that a request with the same Idempotency-Key is still being processed.
+	ErrIdempotencyConflict = "request dengan Idempotency-Key yang sama sedang diproses"
+
+	// ErrIdempotencyKeyReused indicates that an Idempotency-Key was sent again with a different request.
+	ErrIdempotencyKeyReused = "Idempotency-Key sudah digunakan untuk request lain"
+
+	// ErrUnsupportedMediaType indicates that a request body was sent with a Content-Type other than JSON.
+	ErrUnsupportedMediaType = "Content-Type harus application/json"
+
+	// ErrRequestTooLarge indicates that a request body exceeds the size accepted by the API.
+	ErrRequestTooLarge = "ukuran body request terlalu besar"
+
+	// ErrInvalidPagination indicates that the page or per_page query parameter is not a valid number or out of range.
+	ErrInvalidPagination = "parameter page atau per_page tidak valid"
+
+	// ErrTenantNotFound indicates that the tenant named by the request is not registered.
+	ErrTenantNotFound = "tenant tidak ditemukan"
+
+	// ErrTenantExists indicates that a tenant with the requested ID is already registered.
+	ErrTenantExists = "tenant sudah terdaftar"
+
+	// ErrInvalidTenant indicates that a tenant ID or a tenant registration request is invalid.
+	ErrInvalidTenant = "tenant tidak valid"
+
+	// ErrInvalidTenantToken indicates that the bearer token naming the tenant is malformed, expired or not signed by us.
+	ErrInvalidTenantToken = "token tenant tidak valid"
+
+	// ErrTenantTokenRequired indicates that a request carries no bearer token while tenants are resolved from tokens.
+	ErrTenantTokenRequired = "token tenant wajib disertakan"
+
+	// ErrTenantMismatch indicates that the tenant of the bearer token differs from the tenant named by the request.
+	ErrTenantMismatch = "tenant pada token tidak sesuai dengan tenant request"
+
+	// ErrTenantAdminOnly indicates that tenants can only be managed through the default tenant with the admin token.
+	ErrTenantAdminOnly = "tenant hanya dapat dikelola melalui tenant default dengan token admin"
+
+	// ErrInternalServer indicates that an unexpected failure occurred while the request was being handled.
+	ErrInternalServer = "terjadi kesalahan pada server"
 )
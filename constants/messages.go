@@ -0,0 +1,402 @@
+package constants
+
+import "github.com/pandusatrianura/code-with-umam-categories-api/pkg/i18n"
+
+// DefaultLocale is the locale messages and category texts fall back to when the client prefers none we support.
+const DefaultLocale = "id"
+
+// Message codes identify every message returned in the APIResponse envelope. They are part of the API contract:
+// clients may rely on them, so existing codes must never change meaning. The text for each code lives in Messages.
+const (
+	// MsgCategoriesHealthy reports that the categories API is healthy; it takes the service name.
+	MsgCategoriesHealthy = "CATEGORIES_HEALTHY"
+
+	// MsgCategoriesUnhealthy reports that the categories API is not healthy; it takes the service name.
+	MsgCategoriesUnhealthy = "CATEGORIES_UNHEALTHY"
+
+	// MsgCategoriesListed confirms that all categories were returned.
+	MsgCategoriesListed = "CATEGORIES_LISTED"
+
+	// MsgCategoryFound confirms that a category was returned by ID.
+	MsgCategoryFound = "CATEGORY_FOUND"
+
+	// MsgCategoryFoundBySlug confirms that a category was returned by slug.
+	MsgCategoryFoundBySlug = "CATEGORY_FOUND_BY_SLUG"
+
+	// MsgCategoryMoved reports that a slug lookup was redirected to the category's current slug.
+	MsgCategoryMoved = "CATEGORY_MOVED"
+
+	// MsgCategoryCreated confirms that a category was created.
+	MsgCategoryCreated = "CATEGORY_CREATED"
+
+	// MsgCategoryUpdated confirms that a category was updated.
+	MsgCategoryUpdated = "CATEGORY_UPDATED"
+
+	// MsgCategoryDeleted confirms that a category was deleted; it takes the category ID.
+	MsgCategoryDeleted = "CATEGORY_DELETED"
+
+	// MsgCategoryTranslationUpdated confirms that a category translation was stored.
+	MsgCategoryTranslationUpdated = "CATEGORY_TRANSLATION_UPDATED"
+
+	// MsgCategoriesReordered confirms that categories were put in a new order.
+	MsgCategoriesReordered = "CATEGORIES_REORDERED"
+
+	// MsgCategoriesSearched confirms that a category search was answered.
+	MsgCategoriesSearched = "CATEGORIES_SEARCHED"
+
+	// MsgCategoryImageUpdated confirms that the image of a category was stored.
+	MsgCategoryImageUpdated = "CATEGORY_IMAGE_UPDATED"
+
+	// MsgAttributesListed confirms that all category attribute definitions were returned.
+	MsgAttributesListed = "ATTRIBUTES_LISTED"
+
+	// MsgAttributeFound confirms that a category attribute definition was returned by key.
+	MsgAttributeFound = "ATTRIBUTE_FOUND"
+
+	// MsgAttributeCreated confirms that a category attribute was defined.
+	MsgAttributeCreated = "ATTRIBUTE_CREATED"
+
+	// MsgAttributeUpdated confirms that a category attribute definition was updated.
+	MsgAttributeUpdated = "ATTRIBUTE_UPDATED"
+
+	// MsgAttributeDeleted confirms that a category attribute definition was deleted; it takes the attribute key.
+	MsgAttributeDeleted = "ATTRIBUTE_DELETED"
+
+	// MsgProductsListed confirms that a page of products was returned.
+	MsgProductsListed = "PRODUCTS_LISTED"
+
+	// MsgProductFound confirms that a product was returned by ID.
+	MsgProductFound = "PRODUCT_FOUND"
+
+	// MsgProductCreated confirms that a product was created.
+	MsgProductCreated = "PRODUCT_CREATED"
+
+	// MsgProductUpdated confirms that a product was updated.
+	MsgProductUpdated = "PRODUCT_UPDATED"
+
+	// MsgProductDeleted confirms that a product was deleted; it takes the product ID.
+	MsgProductDeleted = "PRODUCT_DELETED"
+
+	// MsgWebhooksListed confirms that all webhook subscriptions were returned.
+	MsgWebhooksListed = "WEBHOOKS_LISTED"
+
+	// MsgWebhookFound confirms that a webhook subscription was returned by ID.
+	MsgWebhookFound = "WEBHOOK_FOUND"
+
+	// MsgWebhookCreated confirms that a webhook subscription was registered.
+	MsgWebhookCreated = "WEBHOOK_CREATED"
+
+	// MsgWebhookDeleted confirms that a webhook subscription was deleted; it takes the subscription ID.
+	MsgWebhookDeleted = "WEBHOOK_DELETED"
+
+	// MsgWebhookDeliveriesListed confirms that the delivery log of a webhook was returned.
+	MsgWebhookDeliveriesListed = "WEBHOOK_DELIVERIES_LISTED"
+
+	// MsgDeadLettersListed confirms that the dead-lettered webhook deliveries were returned.
+	MsgDeadLettersListed = "DEAD_LETTERS_LISTED"
+
+	// MsgDeliveryRetried confirms that a dead-lettered webhook delivery was queued again.
+	MsgDeliveryRetried = "DELIVERY_RETRIED"
+
+	// MsgTenantsListed confirms that all tenants were returned.
+	MsgTenantsListed = "TENANTS_LISTED"
+
+	// MsgTenantCreated confirms that a tenant was registered.
+	MsgTenantCreated = "TENANT_CREATED"
+
+	// MsgCategoryNotFound is the code of ErrCategoryNotFound.
+	MsgCategoryNotFound = "CATEGORY_NOT_FOUND"
+
+	// MsgInvalidCategoryID is the code of ErrInvalidCategoryID.
+	MsgInvalidCategoryID = "INVALID_CATEGORY_ID"
+
+	// MsgInvalidRequest is the code of ErrInvalidRequest.
+	MsgInvalidRequest = "INVALID_CATEGORY_REQUEST"
+
+	// MsgInvalidLocale is the code of ErrInvalidLocale.
+	MsgInvalidLocale = "INVALID_LOCALE"
+
+	// MsgInvalidTranslation is the code of ErrInvalidTranslation.
+	MsgInvalidTranslation = "INVALID_TRANSLATION"
+
+	// MsgInvalidOrder is the code of ErrInvalidOrder.
+	MsgInvalidOrder = "INVALID_ORDER"
+
+	// MsgInvalidAttribute is the code of ErrInvalidAttribute.
+	MsgInvalidAttribute = "INVALID_ATTRIBUTE"
+
+	// MsgAttributeNotFound is the code of ErrAttributeNotFound.
+	MsgAttributeNotFound = "ATTRIBUTE_NOT_FOUND"
+
+	// MsgAttributeExists is the code of ErrAttributeExists.
+	MsgAttributeExists = "ATTRIBUTE_EXISTS"
+
+	// MsgInvalidAttributeDefinition is the code of ErrInvalidAttributeDefinition.
+	MsgInvalidAttributeDefinition = "INVALID_ATTRIBUTE_DEFINITION"
+
+	// MsgAttributeInUse is the code of ErrAttributeInUse.
+	MsgAttributeInUse = "ATTRIBUTE_IN_USE"
+
+	// MsgInvalidImage is the code of ErrInvalidImage.
+	MsgInvalidImage = "INVALID_IMAGE"
+
+	// MsgUnsupportedImageType is the code of ErrUnsupportedImageType.
+	MsgUnsupportedImageType = "UNSUPPORTED_IMAGE_TYPE"
+
+	// MsgImageTooLarge is the code of ErrImageTooLarge.
+	MsgImageTooLarge = "IMAGE_TOO_LARGE"
+
+	// MsgImageNotFound is the code of ErrImageNotFound.
+	MsgImageNotFound = "IMAGE_NOT_FOUND"
+
+	// MsgCategoryInUse is the code of ErrCategoryInUse.
+	MsgCategoryInUse = "CATEGORY_IN_USE"
+
+	// MsgProductNotFound is the code of ErrProductNotFound.
+	MsgProductNotFound = "PRODUCT_NOT_FOUND"
+
+	// MsgInvalidProductID is the code of ErrInvalidProductID.
+	MsgInvalidProductID = "INVALID_PRODUCT_ID"
+
+	// MsgInvalidProduct is the code of ErrInvalidProduct.
+	MsgInvalidProduct = "INVALID_PRODUCT"
+
+	// MsgInvalidSearchQuery is the code of ErrInvalidSearchQuery.
+	MsgInvalidSearchQuery = "INVALID_SEARCH_QUERY"
+
+	// MsgInvalidSearchLimit is the code of ErrInvalidSearchLimit.
+	MsgInvalidSearchLimit = "INVALID_SEARCH_LIMIT"
+
+	// MsgWebhookNotFound is the code of ErrWebhookNotFound.
+	MsgWebhookNotFound = "WEBHOOK_NOT_FOUND"
+
+	// MsgInvalidWebhookID is the code of ErrInvalidWebhookID.
+	MsgInvalidWebhookID = "INVALID_WEBHOOK_ID"
+
+	// MsgInvalidWebhookRequest is the code of ErrInvalidWebhookRequest.
+	MsgInvalidWebhookRequest = "INVALID_WEBHOOK_REQUEST"
+
+	// MsgDeliveryNotFound is the code of ErrDeliveryNotFound.
+	MsgDeliveryNotFound = "DELIVERY_NOT_FOUND"
+
+	// MsgDeliveryNotRetryable is the code of ErrDeliveryNotRetryable.
+	MsgDeliveryNotRetryable = "DELIVERY_NOT_RETRYABLE"
+
+	// MsgInvalidLastEventID is the code of ErrInvalidLastEventID.
+	MsgInvalidLastEventID = "INVALID_LAST_EVENT_ID"
+
+	// MsgStreamingUnsupported is the code of ErrStreamingUnsupported.
+	MsgStreamingUnsupported = "STREAMING_UNSUPPORTED"
+
+	// MsgRouteNotFound is the code of ErrRouteNotFound.
+	MsgRouteNotFound = "ROUTE_NOT_FOUND"
+
+	// MsgMethodNotAllowed is the code of ErrMethodNotAllowed.
+	MsgMethodNotAllowed = "METHOD_NOT_ALLOWED"
+
+	// MsgInvalidIdempotencyKey is the code of ErrInvalidIdempotencyKey.
+	MsgInvalidIdempotencyKey = "INVALID_IDEMPOTENCY_KEY"
+
+	// MsgIdempotencyConflict is the code of ErrIdempotencyConflict.
+	MsgIdempotencyConflict = "IDEMPOTENCY_CONFLICT"
+
+	// MsgIdempotencyKeyReused is the code of ErrIdempotencyKeyReused.
+	MsgIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+
+	// MsgUnsupportedMediaType is the code of ErrUnsupportedMediaType.
+	MsgUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+
+	// MsgRequestTooLarge is the code of ErrRequestTooLarge.
+	MsgRequestTooLarge = "REQUEST_TOO_LARGE"
+
+	// MsgInvalidPagination is the code of ErrInvalidPagination.
+	MsgInvalidPagination = "INVALID_PAGINATION"
+
+	// MsgTenantNotFound is the code of ErrTenantNotFound.
+	MsgTenantNotFound = "TENANT_NOT_FOUND"
+
+	// MsgTenantExists is the code of ErrTenantExists.
+	MsgTenantExists = "TENANT_EXISTS"
+
+	// MsgInvalidTenant is the code of ErrInvalidTenant.
+	MsgInvalidTenant = "INVALID_TENANT"
+
+	// MsgInvalidTenantToken is the code of ErrInvalidTenantToken.
+	MsgInvalidTenantToken = "INVALID_TENANT_TOKEN"
+
+	// MsgTenantTokenRequired is the code of ErrTenantTokenRequired.
+	MsgTenantTokenRequired = "TENANT_TOKEN_REQUIRED"
+
+	// MsgTenantMismatch is the code of ErrTenantMismatch.
+	MsgTenantMismatch = "TENANT_MISMATCH"
+
+	// MsgTenantAdminOnly is the code of ErrTenantAdminOnly.
+	MsgTenantAdminOnly = "TENANT_ADMIN_ONLY"
+
+	// MsgInternalServer is the code of ErrInternalServer.
+	MsgInternalServer = "INTERNAL_SERVER_ERROR"
+)
+
+// Messages is the catalog of every message code in Indonesian and English. Indonesian is the fallback.
+var Messages = i18n.MustNewCatalog(DefaultLocale, messageBundles)
+
+// messageBundles holds the text of every message code per locale. The Indonesian bundle reuses the Err* texts,
+// which remain the text of the matching domain errors.
+var messageBundles = map[string]i18n.Bundle{
+	"id": {
+		MsgCategoriesHealthy:          "%s dalam kondisi sehat",
+		MsgCategoriesUnhealthy:        "%s tidak dalam kondisi sehat",
+		MsgCategoriesListed:           "Berhasil mengambil semua kategori",
+		MsgCategoryFound:              "Berhasil mengambil kategori berdasarkan id",
+		MsgCategoryFoundBySlug:        "Berhasil mengambil kategori berdasarkan slug",
+		MsgCategoryMoved:              "Kategori dipindahkan ke slug baru",
+		MsgCategoryCreated:            "Berhasil menambahkan kategori baru",
+		MsgCategoryUpdated:            "Berhasil memperbarui kategori",
+		MsgCategoryDeleted:            "Berhasil menghapus kategori dengan id %d",
+		MsgCategoryTranslationUpdated: "Berhasil memperbarui terjemahan kategori",
+		MsgCategoriesReordered:        "Berhasil mengubah urutan kategori",
+		MsgCategoriesSearched:         "Berhasil mencari kategori",
+		MsgCategoryImageUpdated:       "Berhasil menyimpan gambar kategori",
+		MsgAttributesListed:           "Berhasil mengambil semua definisi atribut",
+		MsgAttributeFound:             "Berhasil mengambil definisi atribut berdasarkan key",
+		MsgAttributeCreated:           "Berhasil menambahkan definisi atribut baru",
+		MsgAttributeUpdated:           "Berhasil memperbarui definisi atribut",
+		MsgAttributeDeleted:           "Berhasil menghapus definisi atribut dengan key %s",
+		MsgProductsListed:             "Berhasil mengambil daftar produk",
+		MsgProductFound:               "Berhasil mengambil produk berdasarkan id",
+		MsgProductCreated:             "Berhasil menambahkan produk baru",
+		MsgProductUpdated:             "Berhasil memperbarui produk",
+		MsgProductDeleted:             "Berhasil menghapus produk dengan id %d",
+		MsgWebhooksListed:             "Berhasil mengambil semua webhook",
+		MsgWebhookFound:               "Berhasil mengambil webhook berdasarkan id",
+		MsgWebhookCreated:             "Berhasil mendaftarkan webhook baru",
+		MsgWebhookDeleted:             "Berhasil menghapus webhook dengan id %d",
+		MsgWebhookDeliveriesListed:    "Berhasil mengambil pengiriman webhook",
+		MsgDeadLettersListed:          "Berhasil mengambil pengiriman webhook yang gagal",
+		MsgDeliveryRetried:            "Berhasil mengirim ulang pengiriman webhook",
+		MsgTenantsListed:              "Berhasil mengambil semua tenant",
+		MsgTenantCreated:              "Berhasil mendaftarkan tenant baru",
+		MsgCategoryNotFound:           ErrCategoryNotFound,
+		MsgInvalidCategoryID:          ErrInvalidCategoryID,
+		MsgInvalidRequest:             ErrInvalidRequest,
+		MsgInvalidLocale:              ErrInvalidLocale,
+		MsgInvalidTranslation:         ErrInvalidTranslation,
+		MsgInvalidOrder:               ErrInvalidOrder,
+		MsgInvalidAttribute:           ErrInvalidAttribute,
+		MsgAttributeNotFound:          ErrAttributeNotFound,
+		MsgAttributeExists:            ErrAttributeExists,
+		MsgInvalidAttributeDefinition: ErrInvalidAttributeDefinition,
+		MsgAttributeInUse:             ErrAttributeInUse,
+		MsgInvalidImage:               ErrInvalidImage,
+		MsgUnsupportedImageType:       ErrUnsupportedImageType,
+		MsgImageTooLarge:              ErrImageTooLarge,
+		MsgImageNotFound:              ErrImageNotFound,
+		MsgCategoryInUse:              ErrCategoryInUse,
+		MsgProductNotFound:            ErrProductNotFound,
+		MsgInvalidProductID:           ErrInvalidProductID,
+		MsgInvalidProduct:             ErrInvalidProduct,
+		MsgInvalidSearchQuery:         ErrInvalidSearchQuery,
+		MsgInvalidSearchLimit:         ErrInvalidSearchLimit,
+		MsgWebhookNotFound:            ErrWebhookNotFound,
+		MsgInvalidWebhookID:           ErrInvalidWebhookID,
+		MsgInvalidWebhookRequest:      ErrInvalidWebhookRequest,
+		MsgDeliveryNotFound:           ErrDeliveryNotFound,
+		MsgDeliveryNotRetryable:       ErrDeliveryNotRetryable,
+		MsgInvalidLastEventID:         ErrInvalidLastEventID,
+		MsgStreamingUnsupported:       ErrStreamingUnsupported,
+		MsgRouteNotFound:              ErrRouteNotFound,
+		MsgMethodNotAllowed:           ErrMethodNotAllowed,
+		MsgInvalidIdempotencyKey:      ErrInvalidIdempotencyKey,
+		MsgIdempotencyConflict:        ErrIdempotencyConflict,
+		MsgIdempotencyKeyReused:       ErrIdempotencyKeyReused,
+		MsgUnsupportedMediaType:       ErrUnsupportedMediaType,
+		MsgRequestTooLarge:            ErrRequestTooLarge,
+		MsgInvalidPagination:          ErrInvalidPagination,
+		MsgTenantNotFound:             ErrTenantNotFound,
+		MsgTenantExists:               ErrTenantExists,
+		MsgInvalidTenant:              ErrInvalidTenant,
+		MsgInvalidTenantToken:         ErrInvalidTenantToken,
+		MsgTenantTokenRequired:        ErrTenantTokenRequired,
+		MsgTenantMismatch:             ErrTenantMismatch,
+		MsgTenantAdminOnly:            ErrTenantAdminOnly,
+		MsgInternalServer:             ErrInternalServer,
+	},
+	"en": {
+		MsgCategoriesHealthy:          "%s is healthy",
+		MsgCategoriesUnhealthy:        "%s is not healthy",
+		MsgCategoriesListed:           "Success get all categories",
+		MsgCategoryFound:              "Success get category by id",
+		MsgCategoryFoundBySlug:        "Success get category by slug",
+		MsgCategoryMoved:              "Category moved to a new slug",
+		MsgCategoryCreated:            "Success insert new category",
+		MsgCategoryUpdated:            "Success update existing category",
+		MsgCategoryDeleted:            "Success delete category with id %d",
+		MsgCategoryTranslationUpdated: "Success update category translation",
+		MsgCategoriesReordered:        "Success reorder categories",
+		MsgCategoriesSearched:         "Success search categories",
+		MsgCategoryImageUpdated:       "Success store category image",
+		MsgAttributesListed:           "Success get all attribute definitions",
+		MsgAttributeFound:             "Success get attribute definition by key",
+		MsgAttributeCreated:           "Success insert new attribute definition",
+		MsgAttributeUpdated:           "Success update existing attribute definition",
+		MsgAttributeDeleted:           "Success delete attribute definition with key %s",
+		MsgProductsListed:             "Success get products",
+		MsgProductFound:               "Success get product by id",
+		MsgProductCreated:             "Success insert new product",
+		MsgProductUpdated:             "Success update existing product",
+		MsgProductDeleted:             "Success delete product with id %d",
+		MsgWebhooksListed:             "Success get all webhooks",
+		MsgWebhookFound:               "Success get webhook by id",
+		MsgWebhookCreated:             "Success register new webhook",
+		MsgWebhookDeleted:             "Success delete webhook with id %d",
+		MsgWebhookDeliveriesListed:    "Success get webhook deliveries",
+		MsgDeadLettersListed:          "Success get dead letters",
+		MsgDeliveryRetried:            "Success retry webhook delivery",
+		MsgTenantsListed:              "Success get all tenants",
+		MsgTenantCreated:              "Success register new tenant",
+		MsgCategoryNotFound:           "category not found",
+		MsgInvalidCategoryID:          "invalid category id",
+		MsgInvalidRequest:             "invalid category request",
+		MsgInvalidLocale:              "invalid locale",
+		MsgInvalidTranslation:         "invalid category translation",
+		MsgInvalidOrder:               "invalid category order",
+		MsgInvalidAttribute:           "invalid category attributes",
+		MsgAttributeNotFound:          "attribute definition not found",
+		MsgAttributeExists:            "attribute definition already exists",
+		MsgInvalidAttributeDefinition: "invalid attribute definition",
+		MsgAttributeInUse:             "attribute definition is still used by categories",
+		MsgInvalidImage:               "invalid category image",
+		MsgUnsupportedImageType:       "unsupported category image format",
+		MsgImageTooLarge:              "category image is too large",
+		MsgImageNotFound:              "category image not found",
+		MsgCategoryInUse:              "category still has products",
+		MsgProductNotFound:            "product not found",
+		MsgInvalidProductID:           "invalid product id",
+		MsgInvalidProduct:             "invalid product",
+		MsgInvalidSearchQuery:         "invalid search query",
+		MsgInvalidSearchLimit:         "invalid search result limit",
+		MsgWebhookNotFound:            "webhook not found",
+		MsgInvalidWebhookID:           "invalid webhook id",
+		MsgInvalidWebhookRequest:      "invalid webhook request",
+		MsgDeliveryNotFound:           "webhook delivery not found",
+		MsgDeliveryNotRetryable:       "webhook delivery cannot be retried",
+		MsgInvalidLastEventID:         "invalid Last-Event-ID",
+		MsgStreamingUnsupported:       "streaming is not supported",
+		MsgRouteNotFound:              "endpoint not found",
+		MsgMethodNotAllowed:           "method not allowed",
+		MsgInvalidIdempotencyKey:      "invalid Idempotency-Key",
+		MsgIdempotencyConflict:        "a request with the same Idempotency-Key is still being processed",
+		MsgIdempotencyKeyReused:       "Idempotency-Key was already used for a different request",
+		MsgUnsupportedMediaType:       "Content-Type must be application/json",
+		MsgRequestTooLarge:            "request body is too large",
+		MsgInvalidPagination:          "invalid page or per_page parameter",
+		MsgTenantNotFound:             "tenant not found",
+		MsgTenantExists:               "tenant already exists",
+		MsgInvalidTenant:              "invalid tenant",
+		MsgInvalidTenantToken:         "invalid tenant token",
+		MsgTenantTokenRequired:        "a tenant token is required",
+		MsgTenantMismatch:             "the tenant of the token does not match the tenant of the request",
+		MsgTenantAdminOnly:            "tenants can only be managed through the default tenant with the admin token",
+		MsgInternalServer:             "an internal server error occurred",
+	},
+}
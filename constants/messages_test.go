@@ -0,0 +1,40 @@
+package constants
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestMessageBundles(t *testing.T) {
+	codes := func(locale string) []string {
+		var keys []string
+		for code, text := range messageBundles[locale] {
+			if text == "" {
+				t.Errorf("message %s has no text in locale %s", code, locale)
+			}
+			keys = append(keys, code)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	want := codes(DefaultLocale)
+	for locale := range messageBundles {
+		got := codes(locale)
+		if len(got) != len(want) {
+			t.Fatalf("locale %s has %d messages, want %d", locale, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("locale %s: expected message %s, got %s", locale, want[i], got[i])
+			}
+		}
+	}
+
+	if got := Messages.Text("en", MsgCategoryDeleted, 7); got != "Success delete category with id 7" {
+		t.Fatalf("unexpected english text %q", got)
+	}
+	if got := Messages.Text(DefaultLocale, MsgCategoryNotFound); got != ErrCategoryNotFound {
+		t.Fatalf("expected the indonesian text to match ErrCategoryNotFound, got %q", got)
+	}
+}
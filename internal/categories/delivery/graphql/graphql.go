@@ -0,0 +1,316 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+)
+
+const (
+	// defaultPage is the page returned by the categories query when no page is requested.
+	defaultPage = 1
+
+	// defaultPageSize is the number of categories per page when no size is requested.
+	defaultPageSize = 20
+
+	// maxPageSize caps the page size a client may request in a single query.
+	maxPageSize = 100
+)
+
+// Request is the standard GraphQL-over-HTTP request body.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLHandler serves the categories GraphQL schema, resolving every field through ICategoriesService.
+type GraphQLHandler struct {
+	service service.ICategoriesService
+	schema  graphql.Schema
+}
+
+// NewGraphQLHandler initializes and returns a new GraphQLHandler with its schema bound to the provided ICategoriesService implementation.
+func NewGraphQLHandler(service service.ICategoriesService) (*GraphQLHandler, error) {
+	if service == nil {
+		return nil, fmt.Errorf("categories service must not be nil")
+	}
+
+	h := &GraphQLHandler{service: service}
+
+	schema, err := h.newSchema()
+	if err != nil {
+		return nil, err
+	}
+	h.schema = schema
+
+	return h, nil
+}
+
+// Query executes the GraphQL query in the body of r against the categories schema.
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json_wrapper.ParseJSON(r, &req); err != nil {
+		writeError(w, json_wrapper.DecodeStatus(err), err.Error())
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, constants.ErrInvalidRequest)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        r.Context(),
+	})
+
+	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// newSchema builds the categories schema with its query and mutation roots.
+func (h *GraphQLHandler) newSchema() (graphql.Schema, error) {
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Category",
+		Description: "A grouping of products with a name and a description.",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"slug":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Unique, URL-safe identifier generated from the name."},
+			"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"position":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Rank in the order categories are listed in, lowest first."},
+		},
+	})
+
+	categoryPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CategoryPage",
+		Fields: graphql.Fields{
+			"items":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType)))},
+			"total":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"page":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"pageSize": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	filterInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "CategoryFilter",
+		Description: "Restricts the categories query. All given fields must match.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"ids":    &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.Int))},
+			"search": &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Case-insensitive substring of name or description."},
+		},
+	})
+
+	pageInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "PageInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"page": &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: defaultPage},
+			"size": &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: defaultPageSize},
+		},
+	})
+
+	categoryInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CategoryInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"slug":        &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Generated from the name when omitted."},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.String, DefaultValue: ""},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"categories": &graphql.Field{
+				Type: graphql.NewNonNull(categoryPageType),
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: filterInput},
+					"page":   &graphql.ArgumentConfig{Type: pageInput},
+				},
+				Resolve: h.resolveCategories,
+			},
+			"category": &graphql.Field{
+				Type: categoryType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: h.resolveCategory,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCategory": &graphql.Field{
+				Type: graphql.NewNonNull(categoryType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(categoryInput)},
+				},
+				Resolve: h.resolveCreateCategory,
+			},
+			"updateCategory": &graphql.Field{
+				Type: graphql.NewNonNull(categoryType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(categoryInput)},
+				},
+				Resolve: h.resolveUpdateCategory,
+			},
+			"deleteCategory": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: h.resolveDeleteCategory,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// resolveCategories returns one page of categories matching the optional filter.
+func (h *GraphQLHandler) resolveCategories(p graphql.ResolveParams) (interface{}, error) {
+	categories := h.service.GetAllCategories(p.Context)
+
+	if filter, ok := p.Args["filter"].(map[string]interface{}); ok {
+		categories = filterCategories(categories, filter)
+	}
+
+	page, size := defaultPage, defaultPageSize
+	if pageArgs, ok := p.Args["page"].(map[string]interface{}); ok {
+		if v, ok := pageArgs["page"].(int); ok {
+			page = v
+		}
+		if v, ok := pageArgs["size"].(int); ok {
+			size = v
+		}
+	}
+
+	if page < 1 {
+		return nil, fmt.Errorf("page must be greater than zero")
+	}
+	if size < 1 || size > maxPageSize {
+		return nil, fmt.Errorf("size must be between 1 and %d", maxPageSize)
+	}
+
+	total := len(categories)
+	start := (page - 1) * size
+	if start > total {
+		start = total
+	}
+	end := start + size
+	if end > total {
+		end = total
+	}
+
+	return map[string]interface{}{
+		"items":    categories[start:end],
+		"total":    total,
+		"page":     page,
+		"pageSize": size,
+	}, nil
+}
+
+// resolveCategory returns the category with the requested ID, or null when it does not exist.
+func (h *GraphQLHandler) resolveCategory(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(int)
+
+	category, err := h.service.GetCategoryByID(p.Context, int64(id))
+	if errors.Is(err, entity.ErrCategoryNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// resolveCreateCategory inserts a new category from the mutation input.
+func (h *GraphQLHandler) resolveCreateCategory(p graphql.ResolveParams) (interface{}, error) {
+	return h.service.InsertCategory(p.Context, categoryFromInput(p.Args["input"]))
+}
+
+// resolveUpdateCategory replaces the name and description of an existing category.
+func (h *GraphQLHandler) resolveUpdateCategory(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(int)
+
+	category := categoryFromInput(p.Args["input"])
+	category.ID = int64(id)
+
+	return h.service.UpdateCategory(p.Context, category)
+}
+
+// resolveDeleteCategory removes a category and returns its ID.
+func (h *GraphQLHandler) resolveDeleteCategory(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(int)
+	return h.service.DeleteCategory(p.Context, int64(id))
+}
+
+// categoryFromInput converts a CategoryInput argument into a domain category.
+func categoryFromInput(input interface{}) entity.Category {
+	var category entity.Category
+
+	fields, ok := input.(map[string]interface{})
+	if !ok {
+		return category
+	}
+
+	category.Name, _ = fields["name"].(string)
+	category.Slug, _ = fields["slug"].(string)
+	category.Description, _ = fields["description"].(string)
+	return category
+}
+
+// filterCategories keeps only the categories matching every field set in filter.
+func filterCategories(categories []entity.Category, filter map[string]interface{}) []entity.Category {
+	var ids map[int64]bool
+	if rawIDs, ok := filter["ids"].([]interface{}); ok {
+		ids = make(map[int64]bool, len(rawIDs))
+		for _, rawID := range rawIDs {
+			if id, ok := rawID.(int); ok {
+				ids[int64(id)] = true
+			}
+		}
+	}
+
+	search, _ := filter["search"].(string)
+	search = strings.ToLower(strings.TrimSpace(search))
+
+	filtered := make([]entity.Category, 0, len(categories))
+	for _, category := range categories {
+		if ids != nil && !ids[category.ID] {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(category.Name), search) &&
+			!strings.Contains(strings.ToLower(category.Description), search) {
+			continue
+		}
+		filtered = append(filtered, category)
+	}
+
+	return filtered
+}
+
+// writeError writes a GraphQL-shaped error response so clients can parse every failure the same way.
+func writeError(w http.ResponseWriter, status int, message string) {
+	json_wrapper.WriteJSONResponse(w, status, map[string]interface{}{
+		"errors": []map[string]string{{"message": message}},
+	})
+}
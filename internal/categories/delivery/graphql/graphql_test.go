@@ -0,0 +1,305 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+type mockService struct {
+	categories []entity.Category
+	getByIDErr error
+	lastInsert entity.Category
+	lastUpdate entity.Category
+	lastDelete int64
+}
+
+func (m *mockService) GetAllCategories(_ context.Context) []entity.Category {
+	return m.categories
+}
+
+func (m *mockService) GetCategoryByID(_ context.Context, categoryID int64) (entity.Category, error) {
+	if m.getByIDErr != nil {
+		return entity.Category{}, m.getByIDErr
+	}
+	for _, c := range m.categories {
+		if c.ID == categoryID {
+			return c, nil
+		}
+	}
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) GetCategoryBySlug(_ context.Context, slug string) (entity.Category, bool, error) {
+	return entity.Category{}, false, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) InsertCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
+	m.lastInsert = parameter
+	parameter.ID = 99
+	return parameter, nil
+}
+
+func (m *mockService) UpdateCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
+	m.lastUpdate = parameter
+	if parameter.ID == 404 {
+		return entity.Category{}, entity.ErrCategoryNotFound
+	}
+	return parameter, nil
+}
+
+func (m *mockService) DeleteCategory(_ context.Context, categoryID int64) (int64, error) {
+	m.lastDelete = categoryID
+	return categoryID, nil
+}
+
+func (m *mockService) SearchCategories(_ context.Context, query string, limit int) []entity.SearchResult {
+	return nil
+}
+
+func (m *mockService) UpsertCategoryTranslation(_ context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) ReorderCategories(_ context.Context, ids []int64) ([]entity.Category, error) {
+	return nil, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) API() entity.HealthResponse {
+	return entity.HealthResponse{}
+}
+
+type gqlResponse struct {
+	Data   map[string]interface{}   `json:"data"`
+	Errors []map[string]interface{} `json:"errors"`
+}
+
+func execute(t *testing.T, h *GraphQLHandler, body string) (int, gqlResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.Query(w, req)
+
+	var resp gqlResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal %q: %v", w.Body.String(), err)
+	}
+	return w.Code, resp
+}
+
+func graphqlBody(query string, variables map[string]interface{}) string {
+	b, _ := json.Marshal(Request{Query: query, Variables: variables})
+	return string(b)
+}
+
+func TestNewGraphQLHandler(t *testing.T) {
+	if _, err := NewGraphQLHandler(nil); err == nil {
+		t.Fatalf("expected error for nil service")
+	}
+
+	svc := &mockService{}
+	h, err := NewGraphQLHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.service != svc {
+		t.Fatalf("expected service to be set")
+	}
+}
+
+func TestGraphQLHandler_Categories(t *testing.T) {
+	svc := &mockService{categories: []entity.Category{
+		{ID: 1, Name: "Elektronik", Description: "Kategori Elektronik"},
+		{ID: 2, Name: "Komputer", Description: "Kategori Komputer"},
+		{ID: 3, Name: "Handphone", Description: "Kategori Handphone"},
+	}}
+	h, _ := NewGraphQLHandler(svc)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantIDs   []interface{}
+		wantTotal float64
+		wantErr   bool
+	}{
+		{
+			name:      "all",
+			query:     `{ categories { items { id } total } }`,
+			wantIDs:   []interface{}{float64(1), float64(2), float64(3)},
+			wantTotal: 3,
+		},
+		{
+			name:      "search",
+			query:     `{ categories(filter: {search: "KOMP"}) { items { id } total } }`,
+			wantIDs:   []interface{}{float64(2)},
+			wantTotal: 1,
+		},
+		{
+			name:      "ids",
+			query:     `{ categories(filter: {ids: [1, 3]}) { items { id } total } }`,
+			wantIDs:   []interface{}{float64(1), float64(3)},
+			wantTotal: 2,
+		},
+		{
+			name:      "page",
+			query:     `{ categories(page: {page: 2, size: 2}) { items { id } total } }`,
+			wantIDs:   []interface{}{float64(3)},
+			wantTotal: 3,
+		},
+		{
+			name:      "page past end",
+			query:     `{ categories(page: {page: 5, size: 2}) { items { id } total } }`,
+			wantIDs:   []interface{}{},
+			wantTotal: 3,
+		},
+		{
+			name:    "invalid size",
+			query:   `{ categories(page: {size: 1000}) { total } }`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := execute(t, h, graphqlBody(tt.query, nil))
+			if status != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", status)
+			}
+			if tt.wantErr {
+				if len(resp.Errors) == 0 {
+					t.Fatalf("expected errors")
+				}
+				return
+			}
+			if len(resp.Errors) != 0 {
+				t.Fatalf("unexpected errors: %v", resp.Errors)
+			}
+
+			page := resp.Data["categories"].(map[string]interface{})
+			var gotIDs []interface{}
+			for _, item := range page["items"].([]interface{}) {
+				gotIDs = append(gotIDs, item.(map[string]interface{})["id"])
+			}
+			if gotIDs == nil {
+				gotIDs = []interface{}{}
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Fatalf("expected ids %v, got %v", tt.wantIDs, gotIDs)
+			}
+			if page["total"] != tt.wantTotal {
+				t.Fatalf("expected total %v, got %v", tt.wantTotal, page["total"])
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler_Category(t *testing.T) {
+	tests := []struct {
+		name    string
+		svc     *mockService
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "found",
+			svc:  &mockService{categories: []entity.Category{{ID: 1, Name: "A", Description: "D", Position: 1024}}},
+			want: map[string]interface{}{"name": "A", "position": float64(1024)},
+		},
+		{
+			name: "missing",
+			svc:  &mockService{},
+			want: nil,
+		},
+		{
+			name:    "error",
+			svc:     &mockService{getByIDErr: errors.New("boom")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewGraphQLHandler(tt.svc)
+			_, resp := execute(t, h, graphqlBody(`query($id: Int!) { category(id: $id) { name position } }`, map[string]interface{}{"id": 1}))
+
+			if (len(resp.Errors) != 0) != tt.wantErr {
+				t.Fatalf("expected errors %v, got %v", tt.wantErr, resp.Errors)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(resp.Data["category"], tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, resp.Data["category"])
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler_Mutations(t *testing.T) {
+	svc := &mockService{}
+	h, _ := NewGraphQLHandler(svc)
+
+	_, resp := execute(t, h, graphqlBody(`mutation { createCategory(input: {name: "Susu", description: "Kategori Susu"}) { id name } }`, nil))
+	if len(resp.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if svc.lastInsert.Name != "Susu" || svc.lastInsert.Description != "Kategori Susu" {
+		t.Fatalf("unexpected insert: %+v", svc.lastInsert)
+	}
+	if created := resp.Data["createCategory"].(map[string]interface{}); created["id"] != float64(99) {
+		t.Fatalf("unexpected created category: %v", created)
+	}
+
+	_, resp = execute(t, h, graphqlBody(`mutation { updateCategory(id: 7, input: {name: "Minuman"}) { id name } }`, nil))
+	if len(resp.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if svc.lastUpdate.ID != 7 || svc.lastUpdate.Name != "Minuman" {
+		t.Fatalf("unexpected update: %+v", svc.lastUpdate)
+	}
+
+	_, resp = execute(t, h, graphqlBody(`mutation { updateCategory(id: 404, input: {name: "X"}) { id } }`, nil))
+	if len(resp.Errors) == 0 {
+		t.Fatalf("expected error for missing category")
+	}
+
+	_, resp = execute(t, h, graphqlBody(`mutation { deleteCategory(id: 9) }`, nil))
+	if len(resp.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if svc.lastDelete != 9 || resp.Data["deleteCategory"] != float64(9) {
+		t.Fatalf("unexpected delete: %d %v", svc.lastDelete, resp.Data)
+	}
+}
+
+func TestGraphQLHandler_BadRequest(t *testing.T) {
+	h, _ := NewGraphQLHandler(&mockService{})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: `{`},
+		{name: "empty query", body: `{"query":"  "}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := execute(t, h, tt.body)
+			if status != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", status)
+			}
+			if len(resp.Errors) != 1 {
+				t.Fatalf("expected one error, got %v", resp.Errors)
+			}
+		})
+	}
+}
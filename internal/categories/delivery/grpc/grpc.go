@@ -0,0 +1,169 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	categoriesv1 "github.com/pandusatrianura/code-with-umam-categories-api/proto/categories/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// CategoriesServer implements the categories.v1.CategoryService gRPC API on top of ICategoriesService.
+type CategoriesServer struct {
+	categoriesv1.UnimplementedCategoryServiceServer
+	service service.ICategoriesService
+}
+
+// NewCategoriesServer initializes and returns a new CategoriesServer instance with the provided ICategoriesService implementation.
+func NewCategoriesServer(service service.ICategoriesService) (*CategoriesServer, error) {
+	if service == nil {
+		return nil, fmt.Errorf("categories service must not be nil")
+	}
+
+	return &CategoriesServer{
+		service: service,
+	}, nil
+}
+
+// Register attaches the CategoryService implementation to the given gRPC server.
+func (s *CategoriesServer) Register(server *grpc.Server) {
+	categoriesv1.RegisterCategoryServiceServer(server, s)
+}
+
+// GetCategory returns the category with the requested ID or a NotFound status.
+func (s *CategoriesServer) GetCategory(ctx context.Context, req *categoriesv1.GetCategoryRequest) (*categoriesv1.Category, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, constants.ErrInvalidCategoryID)
+	}
+
+	category, err := s.service.GetCategoryByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return toProto(category), nil
+}
+
+// CreateCategory stores a new category and returns it with its assigned ID.
+func (s *CategoriesServer) CreateCategory(ctx context.Context, req *categoriesv1.CreateCategoryRequest) (*categoriesv1.Category, error) {
+	category, err := s.service.InsertCategory(ctx, entity.Category{
+		Name:        req.GetName(),
+		Slug:        req.GetSlug(),
+		Description: req.GetDescription(),
+		Attributes:  fromStruct(req.GetAttributes()),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return toProto(category), nil
+}
+
+// UpdateCategory replaces the name and description of an existing category, and its attributes when they are given.
+func (s *CategoriesServer) UpdateCategory(ctx context.Context, req *categoriesv1.UpdateCategoryRequest) (*categoriesv1.Category, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, constants.ErrInvalidCategoryID)
+	}
+
+	category, err := s.service.UpdateCategory(ctx, entity.Category{
+		ID:          req.GetId(),
+		Name:        req.GetName(),
+		Slug:        req.GetSlug(),
+		Description: req.GetDescription(),
+		Attributes:  fromStruct(req.GetAttributes()),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return toProto(category), nil
+}
+
+// DeleteCategory removes the category with the requested ID and echoes the deleted ID.
+func (s *CategoriesServer) DeleteCategory(ctx context.Context, req *categoriesv1.DeleteCategoryRequest) (*categoriesv1.DeleteCategoryResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, constants.ErrInvalidCategoryID)
+	}
+
+	id, err := s.service.DeleteCategory(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &categoriesv1.DeleteCategoryResponse{Id: id}, nil
+}
+
+// ListCategories streams every category to the client, stopping early if the client goes away.
+func (s *CategoriesServer) ListCategories(req *categoriesv1.ListCategoriesRequest, stream grpc.ServerStreamingServer[categoriesv1.Category]) error {
+	for _, category := range s.service.GetAllCategories(stream.Context()) {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		if err := stream.Send(toProto(category)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toStatusError maps domain errors to gRPC status codes. Anything unrecognised is logged and reported as Internal with
+// a generic message so its details do not leak to clients.
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrCategoryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, entity.ErrInvalidAttribute):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrCategoryInUse):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		log.Printf("categories: %v", err)
+		return status.Error(codes.Internal, constants.ErrInternalServer)
+	}
+}
+
+// toProto converts a domain category into its protobuf representation.
+func toProto(category entity.Category) *categoriesv1.Category {
+	return &categoriesv1.Category{
+		Id:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		Position:    category.Position,
+		Attributes:  toStruct(category.Attributes),
+	}
+}
+
+// toStruct converts category attributes into a protobuf Struct, or nil when the category has none. Attribute values
+// are always strings, numbers or booleans, which every Struct can hold.
+func toStruct(attributes map[string]any) *structpb.Struct {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	fields, err := structpb.NewStruct(attributes)
+	if err != nil {
+		log.Printf("categories: %v", err)
+		return nil
+	}
+	return fields
+}
+
+// fromStruct converts a protobuf Struct into category attributes, or nil when it is unset so updates keep the
+// current attributes. Numbers arrive as float64, the type the attribute definitions check them against.
+func fromStruct(fields *structpb.Struct) map[string]any {
+	if fields == nil {
+		return nil
+	}
+	return fields.AsMap()
+}
@@ -0,0 +1,300 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	categoriesv1 "github.com/pandusatrianura/code-with-umam-categories-api/proto/categories/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type mockService struct {
+	categories []entity.Category
+	updated    entity.Category
+	updateErr  error
+	deleteErr  error
+}
+
+func (m *mockService) GetAllCategories(_ context.Context) []entity.Category {
+	return m.categories
+}
+
+func (m *mockService) GetCategoryByID(_ context.Context, categoryID int64) (entity.Category, error) {
+	for _, c := range m.categories {
+		if c.ID == categoryID {
+			return c, nil
+		}
+	}
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) GetCategoryBySlug(_ context.Context, slug string) (entity.Category, bool, error) {
+	return entity.Category{}, false, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) InsertCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
+	parameter.ID = int64(len(m.categories) + 1)
+	m.categories = append(m.categories, parameter)
+	return parameter, nil
+}
+
+func (m *mockService) UpdateCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
+	m.updated = parameter
+	if m.updateErr != nil {
+		return entity.Category{}, m.updateErr
+	}
+	return parameter, nil
+}
+
+func (m *mockService) DeleteCategory(_ context.Context, categoryID int64) (int64, error) {
+	if m.deleteErr != nil {
+		return 0, m.deleteErr
+	}
+	return categoryID, nil
+}
+
+func (m *mockService) SearchCategories(_ context.Context, query string, limit int) []entity.SearchResult {
+	return nil
+}
+
+func (m *mockService) UpsertCategoryTranslation(_ context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) ReorderCategories(_ context.Context, ids []int64) ([]entity.Category, error) {
+	return nil, entity.ErrCategoryNotFound
+}
+
+func (m *mockService) API() entity.HealthResponse {
+	return entity.HealthResponse{Name: "Categories API", IsHealthy: true}
+}
+
+func newTestClient(t *testing.T, svc *mockService) categoriesv1.CategoryServiceClient {
+	t.Helper()
+
+	srv, err := NewCategoriesServer(svc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	srv.Register(server)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return categoriesv1.NewCategoryServiceClient(conn)
+}
+
+func TestNewCategoriesServer(t *testing.T) {
+	if _, err := NewCategoriesServer(nil); err == nil {
+		t.Fatalf("expected error for nil service")
+	}
+
+	svc := &mockService{}
+	srv, err := NewCategoriesServer(svc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if srv.service != svc {
+		t.Fatalf("expected service to be set")
+	}
+}
+
+func TestCategoriesServer_GetCategory(t *testing.T) {
+	client := newTestClient(t, &mockService{categories: []entity.Category{{ID: 1, Name: "A", Description: "D", Position: 3000}}})
+
+	tests := []struct {
+		name     string
+		id       int64
+		wantCode codes.Code
+		wantName string
+	}{
+		{name: "found", id: 1, wantCode: codes.OK, wantName: "A"},
+		{name: "missing", id: 2, wantCode: codes.NotFound},
+		{name: "invalid", id: 0, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.GetCategory(context.Background(), &categoriesv1.GetCategoryRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected code %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && (got.GetName() != tt.wantName || got.GetPosition() != 3000) {
+				t.Fatalf("expected name %q at position 3000, got %q at %d", tt.wantName, got.GetName(), got.GetPosition())
+			}
+		})
+	}
+}
+
+func TestCategoriesServer_CreateCategory(t *testing.T) {
+	svc := &mockService{}
+	client := newTestClient(t, svc)
+
+	got, err := client.CreateCategory(context.Background(), &categoriesv1.CreateCategoryRequest{Name: "Books", Description: "All books"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.GetId() != 1 || got.GetName() != "Books" || got.GetDescription() != "All books" {
+		t.Fatalf("unexpected category: %v", got)
+	}
+	if len(svc.categories) != 1 {
+		t.Fatalf("expected category to be inserted through the service")
+	}
+}
+
+func TestCategoriesServer_Attributes(t *testing.T) {
+	svc := &mockService{}
+	client := newTestClient(t, svc)
+
+	attributes, _ := structpb.NewStruct(map[string]any{"color": "red", "commission": 2.5, "featured": true})
+	created, err := client.CreateCategory(context.Background(), &categoriesv1.CreateCategoryRequest{Name: "Books", Attributes: attributes})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{"color": "red", "commission": 2.5, "featured": true}
+	if !reflect.DeepEqual(svc.categories[0].Attributes, want) {
+		t.Fatalf("expected the service to receive attributes %v, got %v", want, svc.categories[0].Attributes)
+	}
+	if !reflect.DeepEqual(created.GetAttributes().AsMap(), want) {
+		t.Fatalf("expected attributes %v, got %v", want, created.GetAttributes().AsMap())
+	}
+
+	got, err := client.GetCategory(context.Background(), &categoriesv1.GetCategoryRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got.GetAttributes().AsMap(), want) {
+		t.Fatalf("expected attributes %v, got %v", want, got.GetAttributes().AsMap())
+	}
+
+	if _, err := client.UpdateCategory(context.Background(), &categoriesv1.UpdateCategoryRequest{Id: created.GetId(), Name: "Books"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.updated.Attributes != nil {
+		t.Fatalf("expected an update without attributes to keep them, got %v", svc.updated.Attributes)
+	}
+
+	empty, _ := structpb.NewStruct(nil)
+	if _, err := client.UpdateCategory(context.Background(), &categoriesv1.UpdateCategoryRequest{Id: created.GetId(), Name: "Books", Attributes: empty}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.updated.Attributes == nil || len(svc.updated.Attributes) != 0 {
+		t.Fatalf("expected an update with empty attributes to clear them, got %v", svc.updated.Attributes)
+	}
+}
+
+func TestCategoriesServer_UpdateCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int64
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "ok", id: 1, wantCode: codes.OK},
+		{name: "missing", id: 2, err: entity.ErrCategoryNotFound, wantCode: codes.NotFound},
+		{name: "internal", id: 3, err: errors.New("boom"), wantCode: codes.Internal},
+		{name: "invalid", id: -1, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, &mockService{updateErr: tt.err})
+
+			got, err := client.UpdateCategory(context.Background(), &categoriesv1.UpdateCategoryRequest{Id: tt.id, Name: "New"})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected code %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && (got.GetId() != tt.id || got.GetName() != "New") {
+				t.Fatalf("unexpected category: %v", got)
+			}
+			if tt.wantCode == codes.Internal && status.Convert(err).Message() != constants.ErrInternalServer {
+				t.Fatalf("expected the generic message %q, got %q", constants.ErrInternalServer, status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+func TestCategoriesServer_DeleteCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int64
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "ok", id: 4, wantCode: codes.OK},
+		{name: "missing", id: 5, err: entity.ErrCategoryNotFound, wantCode: codes.NotFound},
+		{name: "in use", id: 4, err: entity.ErrCategoryInUse, wantCode: codes.FailedPrecondition},
+		{name: "invalid", id: 0, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, &mockService{deleteErr: tt.err})
+
+			got, err := client.DeleteCategory(context.Background(), &categoriesv1.DeleteCategoryRequest{Id: tt.id})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected code %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && got.GetId() != tt.id {
+				t.Fatalf("expected id %d, got %d", tt.id, got.GetId())
+			}
+		})
+	}
+}
+
+func TestCategoriesServer_ListCategories(t *testing.T) {
+	want := []entity.Category{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "C"}}
+	client := newTestClient(t, &mockService{categories: want})
+
+	stream, err := client.ListCategories(context.Background(), &categoriesv1.ListCategoriesRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []int64
+	for {
+		category, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("recv: %v", err)
+		}
+		got = append(got, category.GetId())
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d categories, got %d", len(want), len(got))
+	}
+	for i, id := range got {
+		if id != want[i].ID {
+			t.Fatalf("expected id %d at %d, got %d", want[i].ID, i, id)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TenantInterceptor resolves the tenant of every call from its metadata, the gRPC counterpart of the HTTP tenant
+// middleware, and stores it in the call context where the service reads it with tenant.FromContext.
+type TenantInterceptor struct {
+	resolver *tenant.Resolver
+	exists   func(tenantID string) bool
+}
+
+// NewTenantInterceptor initializes a TenantInterceptor resolving tenants with resolver. exists reports whether a
+// tenant is registered; nil accepts every tenant.
+func NewTenantInterceptor(resolver *tenant.Resolver, exists func(tenantID string) bool) (*TenantInterceptor, error) {
+	if resolver == nil {
+		return nil, fmt.Errorf("tenant resolver must not be nil")
+	}
+	if exists == nil {
+		exists = func(string) bool { return true }
+	}
+
+	return &TenantInterceptor{resolver: resolver, exists: exists}, nil
+}
+
+// Unary returns the interceptor for unary calls.
+func (t *TenantInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := t.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the interceptor for streaming calls.
+func (t *TenantInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := t.resolve(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// resolve returns ctx carrying the tenant named by its incoming metadata, or a status error describing why the
+// tenant cannot be resolved.
+func (t *TenantInterceptor) resolve(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	header := http.Header{}
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	var host string
+	if authority := md.Get(":authority"); len(authority) > 0 {
+		host = authority[0]
+	}
+
+	tenantID, err := t.resolver.Resolve(host, header)
+	switch {
+	case errors.Is(err, tenant.ErrMissingToken):
+		return nil, status.Error(codes.Unauthenticated, constants.ErrTenantTokenRequired)
+	case errors.Is(err, tenant.ErrInvalidToken):
+		return nil, status.Error(codes.Unauthenticated, constants.ErrInvalidTenantToken)
+	case errors.Is(err, tenant.ErrConflict):
+		return nil, status.Error(codes.PermissionDenied, constants.ErrTenantMismatch)
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, constants.ErrInvalidTenant)
+	case !t.exists(tenantID):
+		return nil, status.Error(codes.NotFound, constants.ErrTenantNotFound)
+	}
+
+	return tenant.WithID(ctx, tenantID), nil
+}
+
+// tenantStream is a grpc.ServerStream whose context carries the tenant.
+type tenantStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context carrying the tenant.
+func (s *tenantStream) Context() context.Context {
+	return s.ctx
+}
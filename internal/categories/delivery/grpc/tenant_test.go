@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// contextStream is a grpc.ServerStream that only carries a context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestNewTenantInterceptor(t *testing.T) {
+	if _, err := NewTenantInterceptor(nil, nil); err == nil {
+		t.Fatalf("expected error for nil resolver")
+	}
+}
+
+func TestTenantInterceptor(t *testing.T) {
+	resolver, _ := tenant.NewResolver(tenant.Options{BaseDomain: "shop.example.com"})
+	interceptor, err := NewTenantInterceptor(resolver, func(tenantID string) bool {
+		return tenantID == tenant.DefaultID || tenantID == "acme"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		md         metadata.MD
+		wantCode   codes.Code
+		wantTenant string
+	}{
+		{name: "no tenant", md: metadata.MD{}, wantCode: codes.OK, wantTenant: tenant.DefaultID},
+		{name: "header", md: metadata.Pairs("x-tenant-id", "acme"), wantCode: codes.OK, wantTenant: "acme"},
+		{name: "authority", md: metadata.Pairs(":authority", "acme.shop.example.com:9000"), wantCode: codes.OK, wantTenant: "acme"},
+		{name: "unknown tenant", md: metadata.Pairs("x-tenant-id", "globex"), wantCode: codes.NotFound},
+		{name: "invalid tenant", md: metadata.Pairs("x-tenant-id", "Acme Inc"), wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var unaryTenant string
+			_, err := interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				unaryTenant = tenant.FromContext(ctx)
+				return nil, nil
+			})
+			if status.Code(err) != tt.wantCode || unaryTenant != tt.wantTenant {
+				t.Errorf("unary: expected %v and tenant %q, got %v and %q", tt.wantCode, tt.wantTenant, err, unaryTenant)
+			}
+
+			var streamTenant string
+			err = interceptor.Stream()(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+				streamTenant = tenant.FromContext(ss.Context())
+				return nil
+			})
+			if status.Code(err) != tt.wantCode || streamTenant != tt.wantTenant {
+				t.Errorf("stream: expected %v and tenant %q, got %v and %q", tt.wantCode, tt.wantTenant, err, streamTenant)
+			}
+		})
+	}
+}
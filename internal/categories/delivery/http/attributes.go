@@ -0,0 +1,139 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+)
+
+// AttributesHandler serves the category attribute definition endpoints with the help of IAttributesService.
+type AttributesHandler struct {
+	service service.IAttributesService
+}
+
+// NewAttributesHandler initializes and returns a new AttributesHandler instance with the provided IAttributesService implementation.
+func NewAttributesHandler(service service.IAttributesService) (*AttributesHandler, error) {
+	delegate := &AttributesHandler{
+		service: service,
+	}
+
+	return delegate, nil
+}
+
+// GetAllAttributes answers with every category attribute definition.
+func (d *AttributesHandler) GetAllAttributes(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgAttributesListed)
+	result.Data = d.service.GetAllAttributes(r.Context())
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// GetAttributeByKey answers with the category attribute definition named by the key in the path.
+func (d *AttributesHandler) GetAttributeByKey(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	attribute, err := d.service.GetAttributeByKey(r.Context(), r.PathValue("key"))
+	if err != nil {
+		writeAttributeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgAttributeFound)
+	result.Data = attribute
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// InsertAttribute defines a new category attribute from the request body.
+func (d *AttributesHandler) InsertAttribute(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	var attribute entity.AttributeDefinition
+	if err := json_wrapper.ParseRequest(r, &attribute, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeError(w, r, constants.MsgInvalidAttributeDefinition, err)
+		return
+	}
+
+	created, err := d.service.InsertAttribute(r.Context(), attribute)
+	if err != nil {
+		writeAttributeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgAttributeCreated)
+	result.Data = created
+	json_wrapper.WriteResponse(w, r, http.StatusCreated, result)
+}
+
+// UpdateAttribute replaces the category attribute definition named by the key in the path.
+func (d *AttributesHandler) UpdateAttribute(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	var attribute entity.AttributeDefinition
+	if err := json_wrapper.ParseRequest(r, &attribute, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeError(w, r, constants.MsgInvalidAttributeDefinition, err)
+		return
+	}
+
+	attribute.Key = r.PathValue("key")
+	updated, err := d.service.UpdateAttribute(r.Context(), attribute)
+	if err != nil {
+		writeAttributeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgAttributeUpdated)
+	result.Data = updated
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// DeleteAttribute removes the category attribute definition named by the key in the path.
+func (d *AttributesHandler) DeleteAttribute(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	key := r.PathValue("key")
+	if err := d.service.DeleteAttribute(r.Context(), key); err != nil {
+		writeAttributeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgAttributeDeleted, key)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// writeAttributeError answers r with the error envelope describing an error returned by IAttributesService; invalid
+// definitions carry the reason as error detail. Errors that are not part of the API contract are logged and reported
+// as an internal server error so their details do not leak.
+func writeAttributeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := http.StatusInternalServerError, constants.MsgInternalServer
+	switch {
+	case errors.Is(err, entity.ErrAttributeNotFound):
+		status, code = http.StatusNotFound, constants.MsgAttributeNotFound
+	case errors.Is(err, entity.ErrAttributeExists):
+		status, code = http.StatusConflict, constants.MsgAttributeExists
+	case errors.Is(err, entity.ErrAttributeInUse):
+		status, code = http.StatusConflict, constants.MsgAttributeInUse
+	case errors.Is(err, entity.ErrInvalidAttributeDefinition):
+		status, code = http.StatusBadRequest, constants.MsgInvalidAttributeDefinition
+	default:
+		log.Printf("category attributes: %v", err)
+	}
+
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, code)
+	if status == http.StatusBadRequest {
+		result.Error = &json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonInvalidValue, Message: err.Error()}
+	}
+	json_wrapper.WriteResponse(w, r, status, result)
+}
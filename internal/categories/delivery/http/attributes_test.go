@@ -0,0 +1,238 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+)
+
+type mockAttributesService struct {
+	GetAllAttributesFunc  func() []entity.AttributeDefinition
+	GetAttributeByKeyFunc func(key string) (entity.AttributeDefinition, error)
+	InsertAttributeFunc   func(parameter entity.AttributeDefinition) (entity.AttributeDefinition, error)
+	UpdateAttributeFunc   func(parameter entity.AttributeDefinition) (entity.AttributeDefinition, error)
+	DeleteAttributeFunc   func(key string) error
+}
+
+func (m *mockAttributesService) GetAllAttributes(_ context.Context) []entity.AttributeDefinition {
+	return m.GetAllAttributesFunc()
+}
+func (m *mockAttributesService) GetAttributeByKey(_ context.Context, key string) (entity.AttributeDefinition, error) {
+	return m.GetAttributeByKeyFunc(key)
+}
+func (m *mockAttributesService) InsertAttribute(_ context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+	return m.InsertAttributeFunc(parameter)
+}
+func (m *mockAttributesService) UpdateAttribute(_ context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+	return m.UpdateAttributeFunc(parameter)
+}
+func (m *mockAttributesService) DeleteAttribute(_ context.Context, key string) error {
+	return m.DeleteAttributeFunc(key)
+}
+
+func TestAttributesHandler_GetAllAttributes(t *testing.T) {
+	svc := &mockAttributesService{
+		GetAllAttributesFunc: func() []entity.AttributeDefinition {
+			return []entity.AttributeDefinition{{Key: "color", Type: entity.AttributeString}}
+		},
+	}
+	h, _ := NewAttributesHandler(svc)
+	w := httptest.NewRecorder()
+
+	h.GetAllAttributes(w, httptest.NewRequest(http.MethodGet, "/category-attributes", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetAllAttributes() status = %v, want %v", w.Code, http.StatusOK)
+	}
+	var gotBody struct {
+		MessageCode string                       `json:"message_code"`
+		Data        []entity.AttributeDefinition `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &gotBody)
+	if gotBody.MessageCode != constants.MsgAttributesListed || len(gotBody.Data) != 1 || gotBody.Data[0].Key != "color" {
+		t.Errorf("GetAllAttributes() body = %s", w.Body.String())
+	}
+}
+
+func TestAttributesHandler_GetAttributeByKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+	}{
+		{name: "success", wantStatus: http.StatusOK, wantMsgCode: constants.MsgAttributeFound},
+		{name: "not found", mockErr: entity.ErrAttributeNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgAttributeNotFound},
+		{name: "service error", mockErr: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantMsgCode: constants.MsgInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotKey string
+			svc := &mockAttributesService{
+				GetAttributeByKeyFunc: func(key string) (entity.AttributeDefinition, error) {
+					gotKey = key
+					return entity.AttributeDefinition{Key: key, Type: entity.AttributeBool}, tt.mockErr
+				},
+			}
+			h, _ := NewAttributesHandler(svc)
+			req := httptest.NewRequest(http.MethodGet, "/category-attributes/featured", nil)
+			req.SetPathValue("key", "featured")
+			w := httptest.NewRecorder()
+
+			h.GetAttributeByKey(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetAttributeByKey() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if gotKey != "featured" {
+				t.Errorf("GetAttributeByKey() key = %q, want %q", gotKey, "featured")
+			}
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantMsgCode {
+				t.Errorf("GetAttributeByKey() message code = %v, want %v", gotBody.MessageCode, tt.wantMsgCode)
+			}
+		})
+	}
+}
+
+func TestAttributesHandler_InsertAttribute(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+	}{
+		{name: "success", body: `{"key":"color","type":"enum","values":["red","blue"]}`, wantStatus: http.StatusCreated, wantMsgCode: constants.MsgAttributeCreated},
+		{name: "invalid json", body: `{"key":`, wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidAttributeDefinition},
+		{name: "unknown field", body: `{"key":"color","kind":"enum"}`, wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidAttributeDefinition},
+		{
+			name:        "invalid definition",
+			body:        `{"key":"color","type":"date"}`,
+			mockErr:     errors.Join(entity.ErrInvalidAttributeDefinition, errors.New("type must be string, number, bool or enum")),
+			wantStatus:  http.StatusBadRequest,
+			wantMsgCode: constants.MsgInvalidAttributeDefinition,
+		},
+		{name: "exists", body: `{"key":"color","type":"string"}`, mockErr: entity.ErrAttributeExists, wantStatus: http.StatusConflict, wantMsgCode: constants.MsgAttributeExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAttributesService{
+				InsertAttributeFunc: func(parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+					return parameter, tt.mockErr
+				},
+			}
+			h, _ := NewAttributesHandler(svc)
+			req := httptest.NewRequest(http.MethodPost, "/category-attributes", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			h.InsertAttribute(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("InsertAttribute() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantMsgCode {
+				t.Errorf("InsertAttribute() message code = %v, want %v", gotBody.MessageCode, tt.wantMsgCode)
+			}
+			if tt.wantStatus == http.StatusBadRequest && gotBody.Error == nil {
+				t.Errorf("InsertAttribute() expected error detail, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAttributesHandler_UpdateAttribute(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+	}{
+		{name: "success", wantStatus: http.StatusOK, wantMsgCode: constants.MsgAttributeUpdated},
+		{name: "not found", mockErr: entity.ErrAttributeNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgAttributeNotFound},
+		{name: "in use", mockErr: entity.ErrAttributeInUse, wantStatus: http.StatusConflict, wantMsgCode: constants.MsgAttributeInUse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got entity.AttributeDefinition
+			svc := &mockAttributesService{
+				UpdateAttributeFunc: func(parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+					got = parameter
+					return parameter, tt.mockErr
+				},
+			}
+			h, _ := NewAttributesHandler(svc)
+			req := httptest.NewRequest(http.MethodPut, "/category-attributes/color", strings.NewReader(`{"key":"ignored","type":"string"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("key", "color")
+			w := httptest.NewRecorder()
+
+			h.UpdateAttribute(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("UpdateAttribute() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got.Key != "color" {
+				t.Errorf("UpdateAttribute() key = %q, want the key of the path", got.Key)
+			}
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantMsgCode {
+				t.Errorf("UpdateAttribute() message code = %v, want %v", gotBody.MessageCode, tt.wantMsgCode)
+			}
+		})
+	}
+}
+
+func TestAttributesHandler_DeleteAttribute(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+	}{
+		{name: "success", wantStatus: http.StatusOK, wantMsgCode: constants.MsgAttributeDeleted},
+		{name: "not found", mockErr: entity.ErrAttributeNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgAttributeNotFound},
+		{name: "in use", mockErr: entity.ErrAttributeInUse, wantStatus: http.StatusConflict, wantMsgCode: constants.MsgAttributeInUse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAttributesService{
+				DeleteAttributeFunc: func(key string) error {
+					return tt.mockErr
+				},
+			}
+			h, _ := NewAttributesHandler(svc)
+			req := httptest.NewRequest(http.MethodDelete, "/category-attributes/color", nil)
+			req.SetPathValue("key", "color")
+			w := httptest.NewRecorder()
+
+			h.DeleteAttribute(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("DeleteAttribute() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantMsgCode {
+				t.Errorf("DeleteAttribute() message code = %v, want %v", gotBody.MessageCode, tt.wantMsgCode)
+			}
+		})
+	}
+}
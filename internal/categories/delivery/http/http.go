@@ -1,17 +1,24 @@
 package http
 
 import (
-	"fmt"
+	"errors"
+	"log"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/i18n"
 	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"golang.org/x/text/language"
 )
 
+// slugPattern matches the slugs generated for categories: lower-case letters and digits separated by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
 // CategoriesHandler serves as an HTTP handler that processes category-related requests with the help of ICategoriesService.
 type CategoriesHandler struct {
 	service service.ICategoriesService
@@ -26,131 +33,138 @@ func NewCategoriesHandler(service service.ICategoriesService) (*CategoriesHandle
 	return delegate, nil
 }
 
-// HealthCheck godoc
-// @Summary Get health status of categories API
-// @Description Memeriksa status kesehatan API kategori
-// @Tags categories
-// @Accept json
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Router /api/v1/categories/health [get]
+// HealthCheck reports whether the categories API is healthy.
 func (d *CategoriesHandler) API(w http.ResponseWriter, r *http.Request) {
 	var result json_wrapper.APIResponse
 	svcHealthCheckResult := d.service.API()
 
 	if svcHealthCheckResult.IsHealthy {
 		result.Code = constants.SuccessCode
-		result.Message = fmt.Sprintf("%s is healthy", svcHealthCheckResult.Name)
-		json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+		result.SetMessage(constants.Messages, r, constants.MsgCategoriesHealthy, svcHealthCheckResult.Name)
+		json_wrapper.WriteResponse(w, r, http.StatusOK, result)
 	} else {
 		result.Code = constants.ErrorCode
-		result.Message = fmt.Sprintf("%s is not healthy", svcHealthCheckResult.Name)
-		json_wrapper.WriteJSONResponse(w, http.StatusServiceUnavailable, result)
+		result.SetMessage(constants.Messages, r, constants.MsgCategoriesUnhealthy, svcHealthCheckResult.Name)
+		json_wrapper.WriteResponse(w, r, http.StatusServiceUnavailable, result)
 	}
 
 	return
 }
 
-// GetAllCategories godoc
-// @Summary Get all categories
-// @Description Mengambil semua data kategori
-// @Tags categories
-// @Accept json
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Router /api/v1/categories/ [get]
+// GetAllCategories answers with every category in order, localized for the client and filtered by the attr.{key}
+// query parameters.
 func (d *CategoriesHandler) GetAllCategories(w http.ResponseWriter, r *http.Request) {
 	var result json_wrapper.APIResponse
 
-	res := d.service.GetAllCategories()
+	preferred := i18n.Preferences(r)
+	filter := entity.AttributeFilterFromQuery(r.URL.Query())
+	res := localizeAll(preferred, filter.Filter(d.service.GetAllCategories(r.Context())))
+	w.Header().Add("Vary", "Accept-Language")
 
 	result.Code = constants.SuccessCode
-	result.Message = "Success get all categories"
+	result.SetMessage(constants.Messages, r, constants.MsgCategoriesListed)
 	result.Data = res
 
-	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
 	return
 }
 
-// GetCategoryByID godoc
-// @Summary Get category by ID
-// @Description Mengambil kategori berdasarkan ID
-// @Tags categories
-// @Accept json
-// @Produce json
-// @Param id path int true "Category ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/v1/categories/{id} [get]
+// GetCategoryByID answers with the category named by the ID or slug in the path. Previous slugs are redirected to
+// the current one.
 func (d *CategoriesHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
 	var result json_wrapper.APIResponse
 
 	idStr := strings.TrimPrefix(r.URL.Path, "/categories/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		result.Code = constants.ErrorCode
-		result.Message = constants.ErrInvalidCategoryID
-		json_wrapper.WriteJSONResponse(w, http.StatusBadRequest, result)
+		d.getCategoryBySlug(w, r, idStr)
 		return
 	}
 
-	category, err := d.service.GetCategoryByID(int64(id))
+	category, err := d.service.GetCategoryByID(r.Context(), int64(id))
 	if err != nil {
 		result.Code = constants.ErrorCode
-		result.Message = err.Error()
-		json_wrapper.WriteJSONResponse(w, http.StatusInternalServerError, result)
+		result.SetMessage(constants.Messages, r, errorMessage(err))
+		json_wrapper.WriteResponse(w, r, http.StatusInternalServerError, result)
 		return
 	}
 
+	category = localize(i18n.Preferences(r), category)
+	setContentLanguage(w, category)
+
 	result.Code = constants.SuccessCode
-	result.Message = "Success get category by id"
+	result.SetMessage(constants.Messages, r, constants.MsgCategoryFound)
 	result.Data = category
-	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
 	return
 }
 
-// InsertCategory godoc
-// @Summary Create a new category
-// @Description Membuat kategori baru
-// @Tags categories
-// @Accept json
-// @Produce json
-// @Param category body entity.Category true "Category Data"
-// @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Router /api/v1/categories [post]
+// getCategoryBySlug answers GetCategoryByID for a slug. A slug the category used before a rename is answered with
+// a 301 whose Location is the current slug, relative to the request path so it works behind any route prefix.
+func (d *CategoriesHandler) getCategoryBySlug(w http.ResponseWriter, r *http.Request, slug string) {
+	var result json_wrapper.APIResponse
+
+	if !slugPattern.MatchString(slug) {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidCategoryID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return
+	}
+
+	category, moved, err := d.service.GetCategoryBySlug(r.Context(), slug)
+	if err != nil {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, errorMessage(err))
+		json_wrapper.WriteResponse(w, r, http.StatusInternalServerError, result)
+		return
+	}
+
+	category = localize(i18n.Preferences(r), category)
+	setContentLanguage(w, category)
+
+	result.Code = constants.SuccessCode
+	result.Data = category
+
+	if moved {
+		location := category.Slug
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		w.Header().Set("Location", location)
+		result.SetMessage(constants.Messages, r, constants.MsgCategoryMoved)
+		json_wrapper.WriteResponse(w, r, http.StatusMovedPermanently, result)
+		return
+	}
+
+	result.SetMessage(constants.Messages, r, constants.MsgCategoryFoundBySlug)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// InsertCategory creates a category from the request body.
 func (d *CategoriesHandler) InsertCategory(w http.ResponseWriter, r *http.Request) {
 	var result json_wrapper.APIResponse
 
 	var categoryNew entity.Category
-	err := json_wrapper.ParseJSON(r, &categoryNew)
+	err := json_wrapper.ParseRequest(r, &categoryNew, json_wrapper.WithDisallowUnknownFields())
 	if err != nil {
-		result.Code = constants.ErrorCode
-		result.Message = constants.ErrInvalidRequest
-		json_wrapper.WriteJSONResponse(w, http.StatusBadRequest, result)
+		writeDecodeError(w, r, constants.MsgInvalidRequest, err)
+		return
+	}
+
+	data, err := d.service.InsertCategory(r.Context(), categoryNew)
+	if err != nil {
+		writeServiceError(w, r, err)
 		return
 	}
 
-	data := d.service.InsertCategory(categoryNew)
 	result.Code = constants.SuccessCode
-	result.Message = "Success insert new category"
+	result.SetMessage(constants.Messages, r, constants.MsgCategoryCreated)
 	result.Data = data
-	json_wrapper.WriteJSONResponse(w, http.StatusCreated, result)
+	json_wrapper.WriteResponse(w, r, http.StatusCreated, result)
 	return
 }
 
-// UpdateCategory godoc
-// @Summary Update category
-// @Description Update kategori berdasarkan ID
-// @Tags categories
-// @Accept json
-// @Produce json
-// @Param id path int true "Category ID"
-// @Param category body entity.Category true "Category Data"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Router /api/v1/categories/{id} [put]
+// UpdateCategory replaces the category with the ID in the path.
 func (d *CategoriesHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	var result json_wrapper.APIResponse
 
@@ -158,45 +172,33 @@ func (d *CategoriesHandler) UpdateCategory(w http.ResponseWriter, r *http.Reques
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		result.Code = constants.ErrorCode
-		result.Message = constants.ErrInvalidCategoryID
-		json_wrapper.WriteJSONResponse(w, http.StatusBadRequest, result)
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidCategoryID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
 		return
 	}
 
 	var categoryExisting entity.Category
-	err = json_wrapper.ParseJSON(r, &categoryExisting)
+	err = json_wrapper.ParseRequest(r, &categoryExisting, json_wrapper.WithDisallowUnknownFields())
 	if err != nil {
-		result.Code = constants.ErrorCode
-		result.Message = constants.ErrInvalidRequest
-		json_wrapper.WriteJSONResponse(w, http.StatusBadRequest, result)
+		writeDecodeError(w, r, constants.MsgInvalidRequest, err)
 		return
 	}
 
 	categoryExisting.ID = int64(id)
-	res, err := d.service.UpdateCategory(categoryExisting)
+	res, err := d.service.UpdateCategory(r.Context(), categoryExisting)
 	if err != nil {
-		result.Code = constants.ErrorCode
-		result.Message = err.Error()
-		json_wrapper.WriteJSONResponse(w, http.StatusInternalServerError, result)
+		writeServiceError(w, r, err)
 		return
 	}
 
 	result.Code = constants.SuccessCode
-	result.Message = "Success update existing category"
+	result.SetMessage(constants.Messages, r, constants.MsgCategoryUpdated)
 	result.Data = res
-	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
 	return
 }
 
-// DeleteCategory godoc
-// @Summary Delete category
-// @Description Menghapus kategori berdasarkan ID
-// @Tags categories
-// @Accept json
-// @Produce json
-// @Param id path int true "Category ID"
-// @Success 200 {object} map[string]interface{}
-// @Router /api/v1/categories/{id} [delete]
+// DeleteCategory removes the category with the ID in the path.
 func (d *CategoriesHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	var result json_wrapper.APIResponse
 
@@ -204,21 +206,231 @@ func (d *CategoriesHandler) DeleteCategory(w http.ResponseWriter, r *http.Reques
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		result.Code = constants.ErrorCode
-		result.Message = constants.ErrInvalidCategoryID
-		json_wrapper.WriteJSONResponse(w, http.StatusBadRequest, result)
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidCategoryID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return
+	}
+
+	res, err := d.service.DeleteCategory(r.Context(), int64(id))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgCategoryDeleted, res)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+	return
+}
+
+// UpsertCategoryTranslation sets the translation of the category with the ID in the path to the locale in the path.
+func (d *CategoriesHandler) UpsertCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidCategoryID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return
+	}
+
+	locale, err := i18n.ParseLocale(r.PathValue("locale"))
+	if err != nil {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidLocale)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return
+	}
+
+	var translation entity.Translation
+	err = json_wrapper.ParseRequest(r, &translation, json_wrapper.WithDisallowUnknownFields())
+	if err != nil {
+		writeDecodeError(w, r, constants.MsgInvalidTranslation, err)
+		return
+	}
+
+	res, err := d.service.UpsertCategoryTranslation(r.Context(), int64(id), locale, translation)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrInvalidTranslation) {
+			status = http.StatusBadRequest
+		}
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, errorMessage(err))
+		json_wrapper.WriteResponse(w, r, status, result)
 		return
 	}
 
-	res, err := d.service.DeleteCategory(int64(id))
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgCategoryTranslationUpdated)
+	result.Data = res.Localize(locale)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+	return
+}
+
+// ReorderCategories moves the categories listed in the request body to the front, in that order.
+func (d *CategoriesHandler) ReorderCategories(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	var order entity.CategoryOrder
+	err := json_wrapper.ParseRequest(r, &order, json_wrapper.WithDisallowUnknownFields())
 	if err != nil {
+		writeDecodeError(w, r, constants.MsgInvalidOrder, err)
+		return
+	}
+
+	res, err := d.service.ReorderCategories(r.Context(), order.IDs)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrInvalidOrder):
+			status = http.StatusBadRequest
+		case errors.Is(err, entity.ErrCategoryNotFound):
+			status = http.StatusNotFound
+		}
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, errorMessage(err))
+		json_wrapper.WriteResponse(w, r, status, result)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgCategoriesReordered)
+	result.Data = localizeAll(i18n.Preferences(r), res)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+	return
+}
+
+// localize returns category with its name and description in the locale that best matches preferred,
+// falling back to entity.DefaultLocale.
+func localize(preferred []language.Tag, category entity.Category) entity.Category {
+	return category.Localize(i18n.Match(preferred, category.Locales(), entity.DefaultLocale))
+}
+
+// localizeAll returns a localized copy of categories. The categories are not changed in place, as the service may
+// hand out the slice it stores.
+func localizeAll(preferred []language.Tag, categories []entity.Category) []entity.Category {
+	localized := make([]entity.Category, len(categories))
+	for i, category := range categories {
+		localized[i] = localize(preferred, category)
+	}
+	return localized
+}
+
+// setContentLanguage announces the locale of a single localized category and that it depends on Accept-Language.
+func setContentLanguage(w http.ResponseWriter, category entity.Category) {
+	w.Header().Set("Content-Language", category.Locale)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+const (
+	// defaultSearchLimit is the number of search results returned when no limit is given.
+	defaultSearchLimit = 20
+
+	// maxSearchLimit is the largest number of search results a client may request.
+	maxSearchLimit = 100
+)
+
+// SearchCategories answers with the categories best matching the q query parameter.
+func (d *CategoriesHandler) SearchCategories(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
 		result.Code = constants.ErrorCode
-		result.Message = err.Error()
-		json_wrapper.WriteJSONResponse(w, http.StatusInternalServerError, result)
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidSearchQuery)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
 		return
 	}
 
+	limit := defaultSearchLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			result.Code = constants.ErrorCode
+			result.SetMessage(constants.Messages, r, constants.MsgInvalidSearchLimit)
+			json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+			return
+		}
+	}
+
+	preferred := i18n.Preferences(r)
+	results := d.service.SearchCategories(r.Context(), query, limit)
+	for i := range results {
+		results[i].Category = localize(preferred, results[i].Category)
+	}
+	w.Header().Add("Vary", "Accept-Language")
+
 	result.Code = constants.SuccessCode
-	result.Message = fmt.Sprintf("Success delete category with id %d", res)
-	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+	result.SetMessage(constants.Messages, r, constants.MsgCategoriesSearched)
+	result.Data = results
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
 	return
 }
+
+// errorMessage returns the message code describing an error returned by the service. Errors that are not part of
+// the API contract are logged and reported as an internal server error so their details do not leak to clients.
+func errorMessage(err error) string {
+	switch {
+	case errors.Is(err, entity.ErrCategoryNotFound):
+		return constants.MsgCategoryNotFound
+	case errors.Is(err, entity.ErrInvalidTranslation):
+		return constants.MsgInvalidTranslation
+	case errors.Is(err, entity.ErrInvalidOrder):
+		return constants.MsgInvalidOrder
+	case errors.Is(err, entity.ErrInvalidAttribute):
+		return constants.MsgInvalidAttribute
+	case errors.Is(err, entity.ErrCategoryInUse):
+		return constants.MsgCategoryInUse
+	}
+
+	log.Printf("categories: %v", err)
+	return constants.MsgInternalServer
+}
+
+// writeServiceError answers r with the error envelope for an error returned by the service when writing a category.
+// Rejected attributes are answered with 400 Bad Request and the offending attribute as error detail, and deletes of
+// categories that still have products with 409 Conflict; any other error keeps the 500 Internal Server Error category
+// writes have always been answered with.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, errorMessage(err))
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, entity.ErrInvalidAttribute):
+		status = http.StatusBadRequest
+	case errors.Is(err, entity.ErrCategoryInUse):
+		status = http.StatusConflict
+	}
+	var attributeErr *entity.AttributeError
+	if errors.As(err, &attributeErr) {
+		result.Error = &json_wrapper.ErrorDetail{
+			Reason:  json_wrapper.ReasonInvalidValue,
+			Field:   "attributes." + attributeErr.Key,
+			Message: attributeErr.Key + " " + attributeErr.Reason,
+		}
+	}
+	json_wrapper.WriteResponse(w, r, status, result)
+}
+
+// writeDecodeError answers r with the error envelope for err, an error returned by json_wrapper.ParseRequest, and the
+// details of the offending field and position. code describes bodies that cannot be decoded into the request.
+func writeDecodeError(w http.ResponseWriter, r *http.Request, code string, err error) {
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	status := result.SetDecodeError(err)
+	switch status {
+	case http.StatusRequestEntityTooLarge:
+		code = constants.MsgRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		code = constants.MsgUnsupportedMediaType
+	}
+	result.SetMessage(constants.Messages, r, code)
+	json_wrapper.WriteResponse(w, r, status, result)
+}
@@ -2,10 +2,14 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
@@ -14,29 +18,45 @@ import (
 )
 
 type mockService struct {
-	GetAllCategoriesFunc func() []entity.Category
-	GetCategoryByIDFunc  func(categoryID int64) (entity.Category, error)
-	InsertCategoryFunc   func(parameter entity.Category) entity.Category
-	UpdateCategoryFunc   func(parameter entity.Category) (entity.Category, error)
-	DeleteCategoryFunc   func(categoryID int64) (int64, error)
-	APIFunc              func() entity.HealthResponse
+	GetAllCategoriesFunc  func() []entity.Category
+	GetCategoryByIDFunc   func(categoryID int64) (entity.Category, error)
+	GetCategoryBySlugFunc func(slug string) (entity.Category, bool, error)
+	InsertCategoryFunc    func(parameter entity.Category) (entity.Category, error)
+	UpdateCategoryFunc    func(parameter entity.Category) (entity.Category, error)
+	DeleteCategoryFunc    func(categoryID int64) (int64, error)
+	SearchCategoriesFunc  func(query string, limit int) []entity.SearchResult
+	UpsertTranslationFunc func(categoryID int64, locale string, translation entity.Translation) (entity.Category, error)
+	ReorderFunc           func(ids []int64) ([]entity.Category, error)
+	APIFunc               func() entity.HealthResponse
 }
 
-func (m *mockService) GetAllCategories() []entity.Category {
+func (m *mockService) GetAllCategories(_ context.Context) []entity.Category {
 	return m.GetAllCategoriesFunc()
 }
-func (m *mockService) GetCategoryByID(categoryID int64) (entity.Category, error) {
+func (m *mockService) GetCategoryByID(_ context.Context, categoryID int64) (entity.Category, error) {
 	return m.GetCategoryByIDFunc(categoryID)
 }
-func (m *mockService) InsertCategory(parameter entity.Category) entity.Category {
+func (m *mockService) GetCategoryBySlug(_ context.Context, slug string) (entity.Category, bool, error) {
+	return m.GetCategoryBySlugFunc(slug)
+}
+func (m *mockService) InsertCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
 	return m.InsertCategoryFunc(parameter)
 }
-func (m *mockService) UpdateCategory(parameter entity.Category) (entity.Category, error) {
+func (m *mockService) UpdateCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
 	return m.UpdateCategoryFunc(parameter)
 }
-func (m *mockService) DeleteCategory(categoryID int64) (int64, error) {
+func (m *mockService) DeleteCategory(_ context.Context, categoryID int64) (int64, error) {
 	return m.DeleteCategoryFunc(categoryID)
 }
+func (m *mockService) SearchCategories(_ context.Context, query string, limit int) []entity.SearchResult {
+	return m.SearchCategoriesFunc(query, limit)
+}
+func (m *mockService) UpsertCategoryTranslation(_ context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	return m.UpsertTranslationFunc(categoryID, locale, translation)
+}
+func (m *mockService) ReorderCategories(_ context.Context, ids []int64) ([]entity.Category, error) {
+	return m.ReorderFunc(ids)
+}
 func (m *mockService) API() entity.HealthResponse {
 	return m.APIFunc()
 }
@@ -68,7 +88,7 @@ func TestCategoriesHandler_API(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantBody: json_wrapper.APIResponse{
 				Code:    constants.SuccessCode,
-				Message: "Service is healthy",
+				Message: "Service dalam kondisi sehat",
 			},
 		},
 		{
@@ -80,7 +100,7 @@ func TestCategoriesHandler_API(t *testing.T) {
 			wantStatus: http.StatusServiceUnavailable,
 			wantBody: json_wrapper.APIResponse{
 				Code:    constants.ErrorCode,
-				Message: "Service is not healthy",
+				Message: "Service tidak dalam kondisi sehat",
 			},
 		},
 	}
@@ -114,8 +134,10 @@ func TestCategoriesHandler_API(t *testing.T) {
 func TestCategoriesHandler_GetAllCategories(t *testing.T) {
 	tests := []struct {
 		name       string
+		target     string
 		mockRes    []entity.Category
 		wantStatus int
+		wantIDs    []int64
 	}{
 		{
 			name: "success",
@@ -123,12 +145,26 @@ func TestCategoriesHandler_GetAllCategories(t *testing.T) {
 				{ID: 1, Name: "Cat 1"},
 			},
 			wantStatus: http.StatusOK,
+			wantIDs:    []int64{1},
 		},
 		{
 			name:       "empty",
 			mockRes:    []entity.Category{},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name:   "attribute filter",
+			target: "/categories?attr.color=red&attr.color=blue&attr.featured=true",
+			mockRes: []entity.Category{
+				{ID: 1, Name: "Cat 1", Attributes: map[string]any{"color": "red", "featured": true}},
+				{ID: 2, Name: "Cat 2", Attributes: map[string]any{"color": "green", "featured": true}},
+				{ID: 3, Name: "Cat 3", Attributes: map[string]any{"color": "blue", "featured": true}},
+				{ID: 4, Name: "Cat 4", Attributes: map[string]any{"color": "blue"}},
+				{ID: 5, Name: "Cat 5"},
+			},
+			wantStatus: http.StatusOK,
+			wantIDs:    []int64{1, 3},
+		},
 	}
 
 	for _, tt := range tests {
@@ -139,7 +175,11 @@ func TestCategoriesHandler_GetAllCategories(t *testing.T) {
 				},
 			}
 			h := &CategoriesHandler{service: svc}
-			req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+			target := tt.target
+			if target == "" {
+				target = "/categories"
+			}
+			req := httptest.NewRequest(http.MethodGet, target, nil)
 			w := httptest.NewRecorder()
 
 			h.GetAllCategories(w, req)
@@ -148,15 +188,50 @@ func TestCategoriesHandler_GetAllCategories(t *testing.T) {
 				t.Errorf("GetAllCategories() status = %v, want %v", w.Code, tt.wantStatus)
 			}
 
-			var gotBody json_wrapper.APIResponse
+			var gotBody struct {
+				Code string            `json:"code"`
+				Data []entity.Category `json:"data"`
+			}
 			json.Unmarshal(w.Body.Bytes(), &gotBody)
 			if gotBody.Code != constants.SuccessCode {
 				t.Errorf("GetAllCategories() code = %v, want %v", gotBody.Code, constants.SuccessCode)
 			}
+			var gotIDs []int64
+			for _, category := range gotBody.Data {
+				gotIDs = append(gotIDs, category.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("GetAllCategories() ids = %v, want %v", gotIDs, tt.wantIDs)
+			}
 		})
 	}
 }
 
+func TestCategoriesHandler_GetAllCategories_DoesNotLocalizeStoredCategories(t *testing.T) {
+	stored := []entity.Category{{
+		ID: 1, Name: "Buku", Description: "Kategori Buku", Locale: entity.DefaultLocale,
+		Translations: map[string]entity.Translation{"en": {Name: "Books", Description: "Books Category"}},
+	}}
+	h := &CategoriesHandler{service: &mockService{
+		GetAllCategoriesFunc: func() []entity.Category { return stored },
+	}}
+
+	w := httptest.NewRecorder()
+	h.GetAllCategories(w, httptest.NewRequest(http.MethodGet, "/categories?lang=en", nil))
+	if !strings.Contains(w.Body.String(), `"name":"Books"`) {
+		t.Errorf("expected the English name, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.GetAllCategories(w, httptest.NewRequest(http.MethodGet, "/categories", nil))
+	if !strings.Contains(w.Body.String(), `"name":"Buku"`) {
+		t.Errorf("expected a later request to get the stored name, got %s", w.Body.String())
+	}
+	if stored[0].Name != "Buku" || stored[0].Description != "Kategori Buku" || stored[0].Locale != entity.DefaultLocale {
+		t.Errorf("expected the stored category to be unchanged, got %+v", stored[0])
+	}
+}
+
 func TestCategoriesHandler_GetCategoryByID(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -171,18 +246,18 @@ func TestCategoriesHandler_GetCategoryByID(t *testing.T) {
 			path:       "/categories/1",
 			mockRes:    entity.Category{ID: 1, Name: "Cat 1"},
 			wantStatus: http.StatusOK,
-			wantMsg:    "Success get category by id",
+			wantMsg:    "Berhasil mengambil kategori berdasarkan id",
 		},
 		{
 			name:       "invalid id",
-			path:       "/categories/abc",
+			path:       "/categories/Bad_Slug",
 			wantStatus: http.StatusBadRequest,
 			wantMsg:    constants.ErrInvalidCategoryID,
 		},
 		{
 			name:       "not found",
 			path:       "/categories/99",
-			mockErr:    errors.New(constants.ErrCategoryNotFound),
+			mockErr:    entity.ErrCategoryNotFound,
 			wantStatus: http.StatusInternalServerError,
 			wantMsg:    constants.ErrCategoryNotFound,
 		},
@@ -214,20 +289,334 @@ func TestCategoriesHandler_GetCategoryByID(t *testing.T) {
 	}
 }
 
+func TestCategoriesHandler_GetCategoryBySlug(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		mockRes      entity.Category
+		mockMoved    bool
+		mockErr      error
+		wantSlug     string
+		wantStatus   int
+		wantMsg      string
+		wantLocation string
+	}{
+		{
+			name:       "current slug",
+			path:       "/categories/handphone",
+			mockRes:    entity.Category{ID: 3, Name: "Handphone", Slug: "handphone"},
+			wantSlug:   "handphone",
+			wantStatus: http.StatusOK,
+			wantMsg:    "Berhasil mengambil kategori berdasarkan slug",
+		},
+		{
+			name:         "previous slug redirects",
+			path:         "/categories/hp?lang=en",
+			mockRes:      entity.Category{ID: 3, Name: "Handphone", Slug: "handphone"},
+			mockMoved:    true,
+			wantSlug:     "hp",
+			wantStatus:   http.StatusMovedPermanently,
+			wantMsg:      "Category moved to a new slug",
+			wantLocation: "handphone?lang=en",
+		},
+		{
+			name:       "unknown slug",
+			path:       "/categories/tidak-ada",
+			mockErr:    entity.ErrCategoryNotFound,
+			wantSlug:   "tidak-ada",
+			wantStatus: http.StatusInternalServerError,
+			wantMsg:    constants.ErrCategoryNotFound,
+		},
+		{
+			name:       "invalid slug",
+			path:       "/categories/-handphone",
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidCategoryID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSlug string
+			svc := &mockService{
+				GetCategoryBySlugFunc: func(slug string) (entity.Category, bool, error) {
+					gotSlug = slug
+					return tt.mockRes, tt.mockMoved, tt.mockErr
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			h.GetCategoryByID(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetCategoryByID() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if gotSlug != tt.wantSlug {
+				t.Errorf("GetCategoryByID() looked up slug %q, want %q", gotSlug, tt.wantSlug)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("GetCategoryByID() Location = %q, want %q", got, tt.wantLocation)
+			}
+
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.Message != tt.wantMsg {
+				t.Errorf("GetCategoryByID() message = %v, want %v", gotBody.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_LocalizedReads(t *testing.T) {
+	category := entity.Category{
+		ID:          1,
+		Name:        "Elektronik",
+		Slug:        "elektronik",
+		Description: "Kategori Elektronik",
+		Translations: map[string]entity.Translation{
+			"en": {Name: "Electronics", Description: "Electronics category"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		wantName       string
+		wantLocale     string
+	}{
+		{name: "default", target: "/categories/1", wantName: "Elektronik", wantLocale: "id"},
+		{name: "accept language", target: "/categories/1", acceptLanguage: "en-US,en;q=0.9", wantName: "Electronics", wantLocale: "en"},
+		{name: "query overrides header", target: "/categories/1?lang=id", acceptLanguage: "en", wantName: "Elektronik", wantLocale: "id"},
+		{name: "untranslated falls back", target: "/categories/elektronik?lang=fr", wantName: "Elektronik", wantLocale: "id"},
+		{name: "by slug", target: "/categories/elektronik?lang=en", wantName: "Electronics", wantLocale: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				GetCategoryByIDFunc: func(categoryID int64) (entity.Category, error) {
+					return category, nil
+				},
+				GetCategoryBySlugFunc: func(slug string) (entity.Category, bool, error) {
+					return category, false, nil
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+
+			h.GetCategoryByID(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("GetCategoryByID() status = %v, want %v", w.Code, http.StatusOK)
+			}
+			if got := w.Header().Get("Content-Language"); got != tt.wantLocale {
+				t.Errorf("GetCategoryByID() Content-Language = %q, want %q", got, tt.wantLocale)
+			}
+
+			var gotBody struct {
+				Data entity.Category `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.Data.Name != tt.wantName || gotBody.Data.Locale != tt.wantLocale {
+				t.Errorf("GetCategoryByID() data = %q (%s), want %q (%s)", gotBody.Data.Name, gotBody.Data.Locale, tt.wantName, tt.wantLocale)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		svc := &mockService{
+			GetAllCategoriesFunc: func() []entity.Category {
+				return []entity.Category{category, {ID: 2, Name: "Komputer"}}
+			},
+		}
+		h := &CategoriesHandler{service: svc}
+		req := httptest.NewRequest(http.MethodGet, "/categories?lang=en", nil)
+		w := httptest.NewRecorder()
+
+		h.GetAllCategories(w, req)
+
+		var gotBody struct {
+			Data []entity.Category `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &gotBody)
+		if len(gotBody.Data) != 2 || gotBody.Data[0].Name != "Electronics" || gotBody.Data[1].Name != "Komputer" {
+			t.Errorf("GetAllCategories() data = %+v, want each category in its best locale", gotBody.Data)
+		}
+	})
+}
+
+func TestCategoriesHandler_UpsertCategoryTranslation(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		locale     string
+		body       string
+		mockErr    error
+		wantLocale string
+		wantStatus int
+		wantMsg    string
+	}{
+		{
+			name:       "success",
+			id:         "1",
+			locale:     "EN-us",
+			body:       `{"name":"Electronics","description":"Electronics category"}`,
+			wantLocale: "en-US",
+			wantStatus: http.StatusOK,
+			wantMsg:    "Berhasil memperbarui terjemahan kategori",
+		},
+		{
+			name:       "invalid id",
+			id:         "abc",
+			locale:     "en",
+			body:       `{"name":"Electronics"}`,
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidCategoryID,
+		},
+		{
+			name:       "invalid locale",
+			id:         "1",
+			locale:     "not a locale",
+			body:       `{"name":"Electronics"}`,
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidLocale,
+		},
+		{
+			name:       "invalid body",
+			id:         "1",
+			locale:     "en",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidTranslation,
+		},
+		{
+			name:       "empty name",
+			id:         "1",
+			locale:     "en",
+			body:       `{"name":""}`,
+			mockErr:    entity.ErrInvalidTranslation,
+			wantLocale: "en",
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidTranslation,
+		},
+		{
+			name:       "not found",
+			id:         "9",
+			locale:     "en",
+			body:       `{"name":"Electronics"}`,
+			mockErr:    entity.ErrCategoryNotFound,
+			wantLocale: "en",
+			wantStatus: http.StatusInternalServerError,
+			wantMsg:    constants.ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLocale string
+			svc := &mockService{
+				UpsertTranslationFunc: func(categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+					gotLocale = locale
+					if tt.mockErr != nil {
+						return entity.Category{}, tt.mockErr
+					}
+					return entity.Category{ID: categoryID, Name: "Elektronik", Translations: map[string]entity.Translation{locale: translation}}, nil
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+			req := httptest.NewRequest(http.MethodPut, "/categories/"+tt.id+"/translations/x", bytes.NewReader([]byte(tt.body)))
+			req.SetPathValue("id", tt.id)
+			req.SetPathValue("locale", tt.locale)
+			w := httptest.NewRecorder()
+
+			h.UpsertCategoryTranslation(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("UpsertCategoryTranslation() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if gotLocale != tt.wantLocale {
+				t.Errorf("UpsertCategoryTranslation() locale = %q, want %q", gotLocale, tt.wantLocale)
+			}
+
+			var gotBody struct {
+				Message string          `json:"message"`
+				Data    entity.Category `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.Message != tt.wantMsg {
+				t.Errorf("UpsertCategoryTranslation() message = %v, want %v", gotBody.Message, tt.wantMsg)
+			}
+			if tt.wantStatus == http.StatusOK && (gotBody.Data.Name != "Electronics" || gotBody.Data.Locale != tt.wantLocale) {
+				t.Errorf("UpsertCategoryTranslation() data = %+v, want the category in %s", gotBody.Data, tt.wantLocale)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_LocalizedMessages(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		mockErr        error
+		wantCode       string
+		wantMsg        string
+	}{
+		{name: "default locale", target: "/categories/1", wantCode: constants.MsgCategoryFound, wantMsg: "Berhasil mengambil kategori berdasarkan id"},
+		{name: "english", target: "/categories/1", acceptLanguage: "en-US", wantCode: constants.MsgCategoryFound, wantMsg: "Success get category by id"},
+		{name: "english error", target: "/categories/1?lang=en", mockErr: entity.ErrCategoryNotFound, wantCode: constants.MsgCategoryNotFound, wantMsg: "category not found"},
+		{name: "unsupported locale", target: "/categories/1", acceptLanguage: "fr", mockErr: entity.ErrCategoryNotFound, wantCode: constants.MsgCategoryNotFound, wantMsg: constants.ErrCategoryNotFound},
+		{name: "unexpected error", target: "/categories/1?lang=en", mockErr: errors.New("disk on fire"), wantCode: constants.MsgInternalServer, wantMsg: "an internal server error occurred"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				GetCategoryByIDFunc: func(id int64) (entity.Category, error) {
+					return entity.Category{ID: id}, tt.mockErr
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+
+			h.GetCategoryByID(w, req)
+
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantCode || gotBody.Message != tt.wantMsg {
+				t.Errorf("GetCategoryByID() message = %s %q, want %s %q", gotBody.MessageCode, gotBody.Message, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
 func TestCategoriesHandler_InsertCategory(t *testing.T) {
 	tests := []struct {
 		name       string
 		body       interface{}
 		mockRes    entity.Category
+		mockErr    error
 		wantStatus int
 		wantMsg    string
+		wantField  string
 	}{
 		{
 			name:       "success",
 			body:       entity.Category{Name: "New Cat"},
 			mockRes:    entity.Category{ID: 1, Name: "New Cat"},
 			wantStatus: http.StatusCreated,
-			wantMsg:    "Success insert new category",
+			wantMsg:    "Berhasil menambahkan kategori baru",
 		},
 		{
 			name:       "invalid json",
@@ -235,13 +624,28 @@ func TestCategoriesHandler_InsertCategory(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantMsg:    constants.ErrInvalidRequest,
 		},
+		{
+			name:       "invalid attribute",
+			body:       entity.Category{Name: "New Cat", Attributes: map[string]any{"color": "green"}},
+			mockErr:    &entity.AttributeError{Key: "color", Reason: "must be one of red, blue"},
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidAttribute,
+			wantField:  "attributes.color",
+		},
+		{
+			name:       "service error",
+			body:       entity.Category{Name: "New Cat"},
+			mockErr:    errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantMsg:    constants.ErrInternalServer,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			svc := &mockService{
-				InsertCategoryFunc: func(c entity.Category) entity.Category {
-					return tt.mockRes
+				InsertCategoryFunc: func(c entity.Category) (entity.Category, error) {
+					return tt.mockRes, tt.mockErr
 				},
 			}
 			h := &CategoriesHandler{service: svc}
@@ -268,6 +672,197 @@ func TestCategoriesHandler_InsertCategory(t *testing.T) {
 			if gotBody.Message != tt.wantMsg {
 				t.Errorf("InsertCategory() message = %v, want %v", gotBody.Message, tt.wantMsg)
 			}
+			if tt.wantField != "" && (gotBody.Error == nil || gotBody.Error.Field != tt.wantField) {
+				t.Errorf("InsertCategory() error = %+v, want field %v", gotBody.Error, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		wantStatus  int
+		wantCode    string
+		wantDetail  json_wrapper.ErrorDetail
+	}{
+		{
+			name:       "unknown field",
+			body:       `{"name":"Buku","colour":"red"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constants.MsgInvalidRequest,
+			wantDetail: json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonUnknownField, Field: "colour", Line: 1, Column: 16},
+		},
+		{
+			name:       "type mismatch",
+			body:       "{\n  \"name\": 42\n}",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constants.MsgInvalidRequest,
+			wantDetail: json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonTypeMismatch, Field: "name", Line: 2, Column: 11},
+		},
+		{
+			name:       "trailing data",
+			body:       `{"name":"Buku"}{"name":"Pena"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constants.MsgInvalidRequest,
+			wantDetail: json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonTrailingData, Line: 1, Column: 16},
+		},
+		{
+			name:        "form content type",
+			body:        `{"name":"Buku"}`,
+			contentType: "application/x-www-form-urlencoded",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantCode:    constants.MsgUnsupportedMediaType,
+			wantDetail:  json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonUnsupportedMediaType},
+		},
+		{
+			name:       "body too large",
+			body:       `{"name":"` + strings.Repeat("a", int(json_wrapper.DefaultMaxBodyBytes)) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   constants.MsgRequestTooLarge,
+			wantDetail: json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonTooLarge},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				InsertCategoryFunc: func(c entity.Category) (entity.Category, error) {
+					t.Errorf("InsertCategory() called the service with %+v", c)
+					return c, nil
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+
+			req := httptest.NewRequest(http.MethodPost, "/categories", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			h.InsertCategory(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("InsertCategory() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantCode {
+				t.Errorf("InsertCategory() message code = %v, want %v", gotBody.MessageCode, tt.wantCode)
+			}
+			if gotBody.Error == nil {
+				t.Fatalf("InsertCategory() returned no error detail")
+			}
+			got := *gotBody.Error
+			got.Message = ""
+			if got != tt.wantDetail {
+				t.Errorf("InsertCategory() error detail = %+v, want %+v", got, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_ContentNegotiation(t *testing.T) {
+	category := entity.Category{ID: 1, Name: "Buku", Slug: "buku", Description: "Buku, majalah", Position: 1024}
+
+	tests := []struct {
+		name            string
+		method          string
+		target          string
+		contentType     string
+		body            string
+		accept          string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+		wantName        string
+	}{
+		{
+			name:            "list as csv",
+			method:          http.MethodGet,
+			target:          "/categories",
+			accept:          "text/csv",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "id,name,slug,description,position,image.content_type,image.size,image.width,image.height,image.checksum,locale\n1,Buku,buku,\"Buku, majalah\",1024,,,,,,id\n",
+		},
+		{
+			name:            "create from xml as xml",
+			method:          http.MethodPost,
+			target:          "/categories",
+			contentType:     "application/xml",
+			body:            "<category><name>Buku</name><description>Buku, majalah</description></category>",
+			accept:          "application/xml",
+			wantStatus:      http.StatusCreated,
+			wantContentType: "application/xml; charset=utf-8",
+			wantBody:        "<data><id>1</id><name>Buku</name><slug>buku</slug><description>Buku, majalah</description><position>1024</position></data>",
+			wantName:        "Buku",
+		},
+		{
+			name:            "xml unknown field",
+			method:          http.MethodPost,
+			target:          "/categories",
+			contentType:     "application/xml",
+			body:            "<category><colour>red</colour></category>",
+			accept:          "application/xml",
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/xml; charset=utf-8",
+			wantBody:        "<error><reason>unknown_field</reason><field>colour</field><line>1</line><column>11</column>",
+		},
+		{
+			name:            "csv body",
+			method:          http.MethodPost,
+			target:          "/categories",
+			contentType:     "text/csv",
+			body:            "name\nBuku\n",
+			wantStatus:      http.StatusUnsupportedMediaType,
+			wantContentType: "application/json",
+			wantBody:        constants.MsgUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got entity.Category
+			svc := &mockService{
+				GetAllCategoriesFunc: func() []entity.Category {
+					return []entity.Category{category}
+				},
+				InsertCategoryFunc: func(c entity.Category) (entity.Category, error) {
+					got = c
+					return category, nil
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			if tt.method == http.MethodGet {
+				h.GetAllCategories(w, req)
+			} else {
+				h.InsertCategory(w, req)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != tt.wantContentType {
+				t.Errorf("Content-Type = %v, want %v", contentType, tt.wantContentType)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.wantBody)
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("service got name %q, want %q", got.Name, tt.wantName)
+			}
 		})
 	}
 }
@@ -288,7 +883,7 @@ func TestCategoriesHandler_UpdateCategory(t *testing.T) {
 			body:       entity.Category{Name: "Updated Cat"},
 			mockRes:    entity.Category{ID: 1, Name: "Updated Cat"},
 			wantStatus: http.StatusOK,
-			wantMsg:    "Success update existing category",
+			wantMsg:    "Berhasil memperbarui kategori",
 		},
 		{
 			name:       "invalid json",
@@ -303,7 +898,7 @@ func TestCategoriesHandler_UpdateCategory(t *testing.T) {
 			body:       entity.Category{Name: "Updated Cat"},
 			mockErr:    errors.New("some error"),
 			wantStatus: http.StatusInternalServerError,
-			wantMsg:    "some error",
+			wantMsg:    constants.ErrInternalServer,
 		},
 	}
 
@@ -356,7 +951,7 @@ func TestCategoriesHandler_DeleteCategory(t *testing.T) {
 			path:       "/categories/1",
 			mockRes:    1,
 			wantStatus: http.StatusOK,
-			wantMsg:    "Success delete category with id 1",
+			wantMsg:    "Berhasil menghapus kategori dengan id 1",
 		},
 		{
 			name:       "invalid id",
@@ -364,12 +959,19 @@ func TestCategoriesHandler_DeleteCategory(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantMsg:    constants.ErrInvalidCategoryID,
 		},
+		{
+			name:       "in use",
+			path:       "/categories/1",
+			mockErr:    fmt.Errorf("%w: 2 products reference category 1", entity.ErrCategoryInUse),
+			wantStatus: http.StatusConflict,
+			wantMsg:    constants.ErrCategoryInUse,
+		},
 		{
 			name:       "service error",
 			path:       "/categories/1",
 			mockErr:    errors.New("some error"),
 			wantStatus: http.StatusInternalServerError,
-			wantMsg:    "some error",
+			wantMsg:    constants.ErrInternalServer,
 		},
 	}
 
@@ -398,3 +1000,162 @@ func TestCategoriesHandler_DeleteCategory(t *testing.T) {
 		})
 	}
 }
+
+func TestCategoriesHandler_ReorderCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		mockErr    error
+		wantStatus int
+		wantCode   string
+		wantIDs    []int64
+	}{
+		{
+			name:       "success",
+			body:       `{"ids":[3,1]}`,
+			wantStatus: http.StatusOK,
+			wantCode:   constants.MsgCategoriesReordered,
+			wantIDs:    []int64{3, 1},
+		},
+		{
+			name:       "invalid order",
+			body:       `{"ids":[]}`,
+			mockErr:    entity.ErrInvalidOrder,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constants.MsgInvalidOrder,
+		},
+		{
+			name:       "unknown category",
+			body:       `{"ids":[9]}`,
+			mockErr:    entity.ErrCategoryNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   constants.MsgCategoryNotFound,
+		},
+		{
+			name:       "malformed body",
+			body:       `{"ids":"3,1"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constants.MsgInvalidOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIDs []int64
+			svc := &mockService{
+				ReorderFunc: func(ids []int64) ([]entity.Category, error) {
+					gotIDs = ids
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return []entity.Category{{ID: 3, Position: 512}, {ID: 1, Position: 1024}}, nil
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+			req := httptest.NewRequest(http.MethodPost, "/categories/reorder", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			h.ReorderCategories(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ReorderCategories() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+
+			var gotBody struct {
+				MessageCode string            `json:"message_code"`
+				Data        []entity.Category `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantCode {
+				t.Errorf("ReorderCategories() message code = %v, want %v", gotBody.MessageCode, tt.wantCode)
+			}
+			if tt.wantIDs == nil {
+				return
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("ReorderCategories() ids = %v, want %v", gotIDs, tt.wantIDs)
+			}
+			if len(gotBody.Data) != 2 || gotBody.Data[0].ID != 3 || gotBody.Data[0].Position != 512 {
+				t.Errorf("ReorderCategories() data = %+v, want the categories in their new order", gotBody.Data)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_SearchCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantQuery  string
+		wantLimit  int
+		wantStatus int
+		wantMsg    string
+	}{
+		{
+			name:       "default limit",
+			path:       "/categories/search?q=hanphone",
+			wantQuery:  "hanphone",
+			wantLimit:  defaultSearchLimit,
+			wantStatus: http.StatusOK,
+			wantMsg:    "Berhasil mencari kategori",
+		},
+		{
+			name:       "explicit limit",
+			path:       "/categories/search?q=+komputer+&limit=5",
+			wantQuery:  "komputer",
+			wantLimit:  5,
+			wantStatus: http.StatusOK,
+			wantMsg:    "Berhasil mencari kategori",
+		},
+		{
+			name:       "missing query",
+			path:       "/categories/search?q=%20",
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidSearchQuery,
+		},
+		{
+			name:       "invalid limit",
+			path:       "/categories/search?q=a&limit=abc",
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidSearchLimit,
+		},
+		{
+			name:       "limit too large",
+			path:       "/categories/search?q=a&limit=101",
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    constants.ErrInvalidSearchLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			var gotLimit int
+			svc := &mockService{
+				SearchCategoriesFunc: func(query string, limit int) []entity.SearchResult {
+					gotQuery, gotLimit = query, limit
+					return []entity.SearchResult{{Category: entity.Category{ID: 3, Name: "Handphone"}, Score: 1}}
+				},
+			}
+			h := &CategoriesHandler{service: svc}
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			h.SearchCategories(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("SearchCategories() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.Message != tt.wantMsg {
+				t.Errorf("SearchCategories() message = %v, want %v", gotBody.Message, tt.wantMsg)
+			}
+			if gotQuery != tt.wantQuery || gotLimit != tt.wantLimit {
+				t.Errorf("SearchCategories() called service with (%q, %d), want (%q, %d)", gotQuery, gotLimit, tt.wantQuery, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+)
+
+const (
+	// ImageFormField is the multipart form field carrying an uploaded category image.
+	ImageFormField = "image"
+
+	// multipartOverhead is the room left in upload bodies for multipart boundaries and part headers on top of the
+	// largest accepted image.
+	multipartOverhead = 64 << 10
+)
+
+// ImagesHandler serves the category image endpoints with the help of IImagesService.
+type ImagesHandler struct {
+	service service.IImagesService
+	maxAge  time.Duration
+}
+
+// NewImagesHandler initializes an ImagesHandler serving images with a Cache-Control max-age of maxAge. Clients
+// revalidate with the image's ETag once it expires, so a replaced image is picked up within maxAge.
+func NewImagesHandler(service service.IImagesService, maxAge time.Duration) (*ImagesHandler, error) {
+	if service == nil {
+		return nil, fmt.Errorf("images service must not be nil")
+	}
+	if maxAge < 0 {
+		return nil, fmt.Errorf("image cache max-age must not be negative, got %s", maxAge)
+	}
+
+	return &ImagesHandler{
+		service: service,
+		maxAge:  maxAge,
+	}, nil
+}
+
+// PutCategoryImage stores the image in the request body as the image of the category with the ID in the path.
+func (d *ImagesHandler) PutCategoryImage(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidCategoryID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, d.service.MaxImageBytes()+multipartOverhead)
+	content, err := imagePart(r)
+	if err != nil {
+		writeImageError(w, r, err)
+		return
+	}
+
+	category, err := d.service.PutCategoryImage(r.Context(), int64(id), content)
+	if err != nil {
+		writeImageError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgCategoryImageUpdated)
+	result.Data = category
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// GetCategoryImage answers with the image of the category with the ID in the path.
+func (d *ImagesHandler) GetCategoryImage(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidCategoryID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return
+	}
+
+	object, image, err := d.service.OpenCategoryImage(r.Context(), int64(id))
+	if err != nil {
+		writeImageError(w, r, err)
+		return
+	}
+	defer object.Close()
+
+	etag := `"` + image.Checksum + `"`
+	w.Header().Set("Content-Type", image.ContentType)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(d.maxAge.Seconds())))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// ServeContent answers conditional and range requests against the ETag set above.
+	if seeker, ok := object.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", time.Time{}, seeker)
+		return
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(image.Size, 10))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		if _, err := io.Copy(w, object); err != nil {
+			log.Printf("category images: serving category %d: %v", id, err)
+		}
+	}
+}
+
+// imagePart returns the content of the image field of the multipart upload r. Uploads that are not multipart or
+// lack the field match ErrInvalidImage.
+func imagePart(r *http.Request) (io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("%w: the upload must be multipart/form-data with an %s field", entity.ErrInvalidImage, ImageFormField)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the upload has no %s field", entity.ErrInvalidImage, ImageFormField)
+		}
+		var tooLargeErr *http.MaxBytesError
+		if errors.As(err, &tooLargeErr) {
+			return nil, fmt.Errorf("%w: %w", entity.ErrImageTooLarge, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: the multipart upload is malformed", entity.ErrInvalidImage)
+		}
+		if part.FormName() == ImageFormField {
+			return part, nil
+		}
+	}
+}
+
+// etagMatches reports whether the If-None-Match header value header lists etag or is "*". Weak validators match
+// their strong counterparts, as the weak comparison of RFC 9110 requires.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeImageError answers r with the error envelope describing an error returned by IImagesService or imagePart;
+// rejected images carry the reason as error detail. Errors that are not part of the API contract are logged and
+// reported as an internal server error so their details do not leak.
+func writeImageError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLargeErr *http.MaxBytesError
+	status, code := http.StatusInternalServerError, constants.MsgInternalServer
+	switch {
+	case errors.Is(err, entity.ErrCategoryNotFound):
+		status, code = http.StatusNotFound, constants.MsgCategoryNotFound
+	case errors.Is(err, entity.ErrImageNotFound):
+		status, code = http.StatusNotFound, constants.MsgImageNotFound
+	case errors.Is(err, entity.ErrImageTooLarge), errors.As(err, &tooLargeErr):
+		status, code = http.StatusRequestEntityTooLarge, constants.MsgImageTooLarge
+	case errors.Is(err, entity.ErrUnsupportedImageType):
+		status, code = http.StatusUnsupportedMediaType, constants.MsgUnsupportedImageType
+	case errors.Is(err, entity.ErrInvalidImage):
+		status, code = http.StatusBadRequest, constants.MsgInvalidImage
+	default:
+		log.Printf("category images: %v", err)
+	}
+
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, code)
+	if status == http.StatusBadRequest || status == http.StatusUnsupportedMediaType {
+		result.Error = &json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonInvalidValue, Field: ImageFormField, Message: err.Error()}
+	}
+	json_wrapper.WriteResponse(w, r, status, result)
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+)
+
+type mockImagesService struct {
+	PutCategoryImageFunc  func(categoryID int64, content io.Reader) (entity.Category, error)
+	OpenCategoryImageFunc func(categoryID int64) (io.ReadCloser, entity.CategoryImage, error)
+	MaxImageBytesFunc     func() int64
+}
+
+func (m *mockImagesService) PutCategoryImage(_ context.Context, categoryID int64, content io.Reader) (entity.Category, error) {
+	return m.PutCategoryImageFunc(categoryID, content)
+}
+func (m *mockImagesService) OpenCategoryImage(_ context.Context, categoryID int64) (io.ReadCloser, entity.CategoryImage, error) {
+	return m.OpenCategoryImageFunc(categoryID)
+}
+func (m *mockImagesService) MaxImageBytes() int64 {
+	if m.MaxImageBytesFunc == nil {
+		return 1 << 10
+	}
+	return m.MaxImageBytesFunc()
+}
+
+// multipartBody returns a multipart/form-data body with a file part named field holding content, and its content type.
+func multipartBody(t *testing.T, field string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("note", "storefront icon"); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	part, err := writer.CreateFormFile(field, "icon.png")
+	if err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+	return &body, writer.FormDataContentType()
+}
+
+func TestNewImagesHandler(t *testing.T) {
+	if _, err := NewImagesHandler(nil, time.Hour); err == nil {
+		t.Errorf("expected an error for a nil service")
+	}
+	if _, err := NewImagesHandler(&mockImagesService{}, -time.Second); err == nil {
+		t.Errorf("expected an error for a negative max-age")
+	}
+	if _, err := NewImagesHandler(&mockImagesService{}, 0); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestImagesHandler_PutCategoryImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		field       string
+		content     string
+		contentType string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+		wantField   string
+	}{
+		{name: "success", id: "1", field: "image", content: "png bytes", wantStatus: http.StatusOK, wantMsgCode: constants.MsgCategoryImageUpdated},
+		{name: "invalid id", id: "abc", field: "image", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidCategoryID},
+		{name: "not multipart", id: "1", contentType: "application/json", content: `{}`, wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidImage, wantField: "image"},
+		{name: "missing field", id: "1", field: "icon", content: "png bytes", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidImage, wantField: "image"},
+		{name: "body too large", id: "1", field: "image", content: strings.Repeat("x", 80<<10), wantStatus: http.StatusRequestEntityTooLarge, wantMsgCode: constants.MsgImageTooLarge},
+		{name: "image too large", id: "1", field: "image", content: "png bytes", mockErr: entity.ErrImageTooLarge, wantStatus: http.StatusRequestEntityTooLarge, wantMsgCode: constants.MsgImageTooLarge},
+		{name: "unsupported type", id: "1", field: "image", content: "<svg/>", mockErr: entity.ErrUnsupportedImageType, wantStatus: http.StatusUnsupportedMediaType, wantMsgCode: constants.MsgUnsupportedImageType, wantField: "image"},
+		{name: "invalid image", id: "1", field: "image", content: "png bytes", mockErr: entity.ErrInvalidImage, wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidImage, wantField: "image"},
+		{name: "not found", id: "99", field: "image", content: "png bytes", mockErr: entity.ErrCategoryNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgCategoryNotFound},
+		{name: "service error", id: "1", field: "image", content: "png bytes", mockErr: errors.New("disk full"), wantStatus: http.StatusInternalServerError, wantMsgCode: constants.MsgInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotContent string
+			svc := &mockImagesService{
+				PutCategoryImageFunc: func(categoryID int64, content io.Reader) (entity.Category, error) {
+					data, err := io.ReadAll(content)
+					if err != nil {
+						return entity.Category{}, err
+					}
+					gotContent = string(data)
+					return entity.Category{ID: categoryID, Image: &entity.CategoryImage{ContentType: "image/png"}}, tt.mockErr
+				},
+			}
+			h, _ := NewImagesHandler(svc, time.Hour)
+
+			var body io.Reader = strings.NewReader(tt.content)
+			contentType := tt.contentType
+			if tt.field != "" {
+				body, contentType = multipartBody(t, tt.field, []byte(tt.content))
+			}
+			req := httptest.NewRequest(http.MethodPut, "/categories/"+tt.id+"/image", body)
+			req.Header.Set("Content-Type", contentType)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			h.PutCategoryImage(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("PutCategoryImage() status = %v, want %v, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var gotBody json_wrapper.APIResponse
+			json.Unmarshal(w.Body.Bytes(), &gotBody)
+			if gotBody.MessageCode != tt.wantMsgCode {
+				t.Errorf("PutCategoryImage() message code = %v, want %v", gotBody.MessageCode, tt.wantMsgCode)
+			}
+			if tt.wantField != "" && (gotBody.Error == nil || gotBody.Error.Field != tt.wantField) {
+				t.Errorf("PutCategoryImage() error = %+v, want field %q", gotBody.Error, tt.wantField)
+			}
+			if tt.wantStatus == http.StatusOK && gotContent != tt.content {
+				t.Errorf("PutCategoryImage() stored %q, want %q", gotContent, tt.content)
+			}
+		})
+	}
+}
+
+// readCloser hides the Seek method of a reader, like blob stores that stream their objects.
+type readCloser struct{ io.Reader }
+
+func (readCloser) Close() error { return nil }
+
+func TestImagesHandler_GetCategoryImage(t *testing.T) {
+	image := entity.CategoryImage{ContentType: "image/png", Size: 9, Width: 32, Height: 32, Checksum: "abc123"}
+
+	tests := []struct {
+		name        string
+		id          string
+		ifNoneMatch string
+		seekable    bool
+		mockErr     error
+		wantStatus  int
+		wantBody    string
+		wantMsgCode string
+	}{
+		{name: "seekable", id: "1", seekable: true, wantStatus: http.StatusOK, wantBody: "png bytes"},
+		{name: "streamed", id: "1", wantStatus: http.StatusOK, wantBody: "png bytes"},
+		{name: "seekable not modified", id: "1", seekable: true, ifNoneMatch: `"abc123"`, wantStatus: http.StatusNotModified},
+		{name: "streamed not modified", id: "1", ifNoneMatch: `"old", W/"abc123"`, wantStatus: http.StatusNotModified},
+		{name: "stale etag", id: "1", ifNoneMatch: `"old"`, wantStatus: http.StatusOK, wantBody: "png bytes"},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidCategoryID},
+		{name: "category not found", id: "99", mockErr: entity.ErrCategoryNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgCategoryNotFound},
+		{name: "image not found", id: "1", mockErr: entity.ErrImageNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgImageNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockImagesService{
+				OpenCategoryImageFunc: func(categoryID int64) (io.ReadCloser, entity.CategoryImage, error) {
+					if tt.mockErr != nil {
+						return nil, entity.CategoryImage{}, tt.mockErr
+					}
+					if tt.seekable {
+						return readSeekCloser{strings.NewReader("png bytes")}, image, nil
+					}
+					return readCloser{strings.NewReader("png bytes")}, image, nil
+				},
+			}
+			h, _ := NewImagesHandler(svc, time.Hour)
+			req := httptest.NewRequest(http.MethodGet, "/categories/"+tt.id+"/image", nil)
+			req.SetPathValue("id", tt.id)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			h.GetCategoryImage(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetCategoryImage() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantMsgCode != "" {
+				var gotBody json_wrapper.APIResponse
+				json.Unmarshal(w.Body.Bytes(), &gotBody)
+				if gotBody.MessageCode != tt.wantMsgCode {
+					t.Errorf("GetCategoryImage() message code = %v, want %v", gotBody.MessageCode, tt.wantMsgCode)
+				}
+				return
+			}
+
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("GetCategoryImage() body = %q, want %q", got, tt.wantBody)
+			}
+			if got := w.Header().Get("ETag"); got != `"abc123"` {
+				t.Errorf("GetCategoryImage() ETag = %q", got)
+			}
+			if got := w.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+				t.Errorf("GetCategoryImage() Cache-Control = %q", got)
+			}
+			if tt.wantStatus == http.StatusOK && w.Header().Get("Content-Type") != "image/png" {
+				t.Errorf("GetCategoryImage() Content-Type = %q", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+// readSeekCloser is a seekable object, like the files of blob.LocalStore.
+type readSeekCloser struct{ io.ReadSeeker }
+
+func (readSeekCloser) Close() error { return nil }
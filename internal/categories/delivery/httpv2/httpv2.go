@@ -0,0 +1,355 @@
+// Package httpv2 serves the categories API under /api/v2. Unlike /api/v1, successful responses are the bare
+// resources, with pagination in headers, and errors are RFC 7807 problem details.
+package httpv2
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/i18n"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/pagination"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
+	"golang.org/x/text/language"
+)
+
+const (
+	// defaultSearchLimit is the number of search results returned when no limit is given.
+	defaultSearchLimit = 20
+
+	// maxSearchLimit is the largest number of search results a client may request.
+	maxSearchLimit = 100
+
+	// TotalCountHeader carries the number of items of a paginated list across all pages.
+	TotalCountHeader = pagination.TotalCountHeader
+)
+
+// slugPattern matches the slugs generated for categories: lower-case letters and digits separated by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// CategoriesHandler serves the v2 category endpoints with the help of ICategoriesService.
+type CategoriesHandler struct {
+	service service.ICategoriesService
+}
+
+// NewCategoriesHandler initializes and returns a new CategoriesHandler instance with the provided ICategoriesService implementation.
+func NewCategoriesHandler(service service.ICategoriesService) (*CategoriesHandler, error) {
+	delegate := &CategoriesHandler{
+		service: service,
+	}
+
+	return delegate, nil
+}
+
+// API reports whether the categories API is healthy.
+func (d *CategoriesHandler) API(w http.ResponseWriter, r *http.Request) {
+	health := d.service.API()
+	if !health.IsHealthy {
+		WriteProblem(w, r, http.StatusServiceUnavailable, constants.MsgCategoriesUnhealthy, "", nil, health.Name)
+		return
+	}
+
+	json_wrapper.WriteResponse(w, r, http.StatusOK, health)
+}
+
+// GetAllCategories answers with a page of categories, localized for the client and filtered by the attr.{key}
+// query parameters.
+func (d *CategoriesHandler) GetAllCategories(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := pagination.FromQuery(r.URL.Query())
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidPagination, err.Error(), nil)
+		return
+	}
+
+	all := entity.AttributeFilterFromQuery(r.URL.Query()).Filter(d.service.GetAllCategories(r.Context()))
+	start := min(pagination.Offset(page, perPage), len(all))
+	end := min(start+perPage, len(all))
+
+	categories := localizeAll(i18n.Preferences(r), all[start:end])
+
+	pagination.SetHeaders(w, r, page, perPage, len(all))
+	w.Header().Add("Vary", "Accept-Language")
+	json_wrapper.WriteResponse(w, r, http.StatusOK, categories)
+}
+
+// GetCategory answers with the category named by the ID or slug in the path. Previous slugs are redirected to the
+// current one.
+func (d *CategoriesHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	idOrSlug := r.PathValue("id")
+
+	var (
+		category entity.Category
+		moved    bool
+		err      error
+	)
+	if id, parseErr := strconv.ParseInt(idOrSlug, 10, 64); parseErr == nil {
+		category, err = d.service.GetCategoryByID(r.Context(), id)
+	} else if slugPattern.MatchString(idOrSlug) {
+		category, moved, err = d.service.GetCategoryBySlug(r.Context(), idOrSlug)
+	} else {
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidCategoryID, fmt.Sprintf("%q is neither a category id nor a slug", idOrSlug), nil)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	category = localize(i18n.Preferences(r), category)
+	setContentLanguage(w, category)
+
+	status := http.StatusOK
+	if moved {
+		location := category.Slug
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		w.Header().Set("Location", location)
+		status = http.StatusMovedPermanently
+	}
+	json_wrapper.WriteResponse(w, r, status, category)
+}
+
+// InsertCategory creates a category from the request body.
+func (d *CategoriesHandler) InsertCategory(w http.ResponseWriter, r *http.Request) {
+	var category entity.Category
+	if err := json_wrapper.ParseRequest(r, &category, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeProblem(w, r, constants.MsgInvalidRequest, err)
+		return
+	}
+
+	created, err := d.service.InsertCategory(r.Context(), category)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	location := requestURL(r)
+	location.Path = strings.TrimSuffix(location.Path, "/") + "/" + strconv.FormatInt(created.ID, 10)
+	location.RawQuery = ""
+	w.Header().Set("Location", location.String())
+	json_wrapper.WriteResponse(w, r, http.StatusCreated, created)
+}
+
+// UpdateCategory replaces the category with the ID in the path.
+func (d *CategoriesHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := categoryID(w, r)
+	if !ok {
+		return
+	}
+
+	var category entity.Category
+	if err := json_wrapper.ParseRequest(r, &category, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeProblem(w, r, constants.MsgInvalidRequest, err)
+		return
+	}
+
+	category.ID = id
+	updated, err := d.service.UpdateCategory(r.Context(), category)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	json_wrapper.WriteResponse(w, r, http.StatusOK, updated)
+}
+
+// DeleteCategory removes the category with the ID in the path.
+func (d *CategoriesHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := categoryID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := d.service.DeleteCategory(r.Context(), id); err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpsertCategoryTranslation sets the translation of the category with the ID in the path to the locale in the path.
+func (d *CategoriesHandler) UpsertCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	id, ok := categoryID(w, r)
+	if !ok {
+		return
+	}
+
+	locale, err := i18n.ParseLocale(r.PathValue("locale"))
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidLocale, err.Error(), nil)
+		return
+	}
+
+	var translation entity.Translation
+	if err := json_wrapper.ParseRequest(r, &translation, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeProblem(w, r, constants.MsgInvalidTranslation, err)
+		return
+	}
+
+	category, err := d.service.UpsertCategoryTranslation(r.Context(), id, locale, translation)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	category = category.Localize(locale)
+	w.Header().Set("Content-Language", category.Locale)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, category)
+}
+
+// ReorderCategories moves the categories listed in the request body to the front, in that order.
+func (d *CategoriesHandler) ReorderCategories(w http.ResponseWriter, r *http.Request) {
+	var order entity.CategoryOrder
+	if err := json_wrapper.ParseRequest(r, &order, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeProblem(w, r, constants.MsgInvalidOrder, err)
+		return
+	}
+
+	categories, err := d.service.ReorderCategories(r.Context(), order.IDs)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+	json_wrapper.WriteResponse(w, r, http.StatusOK, localizeAll(i18n.Preferences(r), categories))
+}
+
+// SearchCategories answers with the categories best matching the q query parameter.
+func (d *CategoriesHandler) SearchCategories(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidSearchQuery, "query parameter q is required", nil)
+		return
+	}
+
+	limit := defaultSearchLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidSearchLimit, fmt.Sprintf("limit must be a number from 1 to %d", maxSearchLimit), nil)
+			return
+		}
+	}
+
+	preferred := i18n.Preferences(r)
+	results := d.service.SearchCategories(r.Context(), query, limit)
+	for i := range results {
+		results[i].Category = localize(preferred, results[i].Category)
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+	json_wrapper.WriteResponse(w, r, http.StatusOK, results)
+}
+
+// categoryID returns the category ID in the path of r, answering r with a problem when it is not a number.
+func categoryID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidCategoryID, fmt.Sprintf("%q is not a category id", r.PathValue("id")), nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// requestURL returns the URL of r as the client sent it, before any route prefix was stripped.
+func requestURL(r *http.Request) *url.URL {
+	if r.RequestURI != "" {
+		if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			return u
+		}
+	}
+	u := *r.URL
+	return &u
+}
+
+// localize returns category with its name and description in the locale that best matches preferred,
+// falling back to entity.DefaultLocale.
+func localize(preferred []language.Tag, category entity.Category) entity.Category {
+	return category.Localize(i18n.Match(preferred, category.Locales(), entity.DefaultLocale))
+}
+
+// localizeAll returns a localized copy of categories. The categories are not changed in place, as the service may
+// hand out the slice it stores.
+func localizeAll(preferred []language.Tag, categories []entity.Category) []entity.Category {
+	localized := make([]entity.Category, len(categories))
+	for i, category := range categories {
+		localized[i] = localize(preferred, category)
+	}
+	return localized
+}
+
+// setContentLanguage announces the locale of a single localized category and that it depends on Accept-Language.
+func setContentLanguage(w http.ResponseWriter, category entity.Category) {
+	w.Header().Set("Content-Language", category.Locale)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+// writeServiceError answers r with the problem describing an error returned by the service. Errors that are not
+// part of the API contract are logged and reported as an internal server error so their details do not leak.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, entity.ErrCategoryNotFound):
+		WriteProblem(w, r, http.StatusNotFound, constants.MsgCategoryNotFound, "", nil)
+	case errors.Is(err, entity.ErrInvalidTranslation):
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidTranslation, err.Error(), nil)
+	case errors.Is(err, entity.ErrInvalidOrder):
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidOrder, err.Error(), nil)
+	case errors.Is(err, entity.ErrCategoryInUse):
+		WriteProblem(w, r, http.StatusConflict, constants.MsgCategoryInUse, err.Error(), nil)
+	case errors.Is(err, entity.ErrInvalidAttribute):
+		var extensions map[string]any
+		var attributeErr *entity.AttributeError
+		if errors.As(err, &attributeErr) {
+			extensions = map[string]any{"reason": json_wrapper.ReasonInvalidValue, "field": "attributes." + attributeErr.Key}
+		}
+		WriteProblem(w, r, http.StatusBadRequest, constants.MsgInvalidAttribute, err.Error(), extensions)
+	default:
+		log.Printf("categories: %v", err)
+		WriteProblem(w, r, http.StatusInternalServerError, constants.MsgInternalServer, "", nil)
+	}
+}
+
+// writeDecodeProblem answers r with the problem for err, an error returned by json_wrapper.ParseRequest, with the
+// offending field and position as extensions. code describes bodies that cannot be decoded into the request.
+func writeDecodeProblem(w http.ResponseWriter, r *http.Request, code string, err error) {
+	var result json_wrapper.APIResponse
+	status := result.SetDecodeError(err)
+	switch status {
+	case http.StatusRequestEntityTooLarge:
+		code = constants.MsgRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		code = constants.MsgUnsupportedMediaType
+	}
+
+	extensions := map[string]any{}
+	if detail := result.Error; detail != nil {
+		extensions["reason"] = detail.Reason
+		if detail.Field != "" {
+			extensions["field"] = detail.Field
+		}
+		if detail.Line != 0 {
+			extensions["line"] = detail.Line
+			extensions["column"] = detail.Column
+		}
+	}
+	WriteProblem(w, r, status, code, err.Error(), extensions)
+}
+
+// WriteProblem answers r with the problem for code, whose localized message is the title, and the given detail.
+// The message code is added to extensions as "code"; args format the title.
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string, extensions map[string]any, args ...any) {
+	problem.WriteMessage(w, r, constants.Messages, status, code, detail, extensions, args...)
+}
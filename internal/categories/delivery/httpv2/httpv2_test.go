@@ -0,0 +1,627 @@
+package httpv2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
+)
+
+type mockService struct {
+	GetAllCategoriesFunc  func() []entity.Category
+	GetCategoryByIDFunc   func(categoryID int64) (entity.Category, error)
+	GetCategoryBySlugFunc func(slug string) (entity.Category, bool, error)
+	InsertCategoryFunc    func(parameter entity.Category) (entity.Category, error)
+	UpdateCategoryFunc    func(parameter entity.Category) (entity.Category, error)
+	DeleteCategoryFunc    func(categoryID int64) (int64, error)
+	SearchCategoriesFunc  func(query string, limit int) []entity.SearchResult
+	UpsertTranslationFunc func(categoryID int64, locale string, translation entity.Translation) (entity.Category, error)
+	ReorderFunc           func(ids []int64) ([]entity.Category, error)
+	APIFunc               func() entity.HealthResponse
+}
+
+func (m *mockService) GetAllCategories(_ context.Context) []entity.Category {
+	return m.GetAllCategoriesFunc()
+}
+func (m *mockService) GetCategoryByID(_ context.Context, categoryID int64) (entity.Category, error) {
+	return m.GetCategoryByIDFunc(categoryID)
+}
+func (m *mockService) GetCategoryBySlug(_ context.Context, slug string) (entity.Category, bool, error) {
+	return m.GetCategoryBySlugFunc(slug)
+}
+func (m *mockService) InsertCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
+	return m.InsertCategoryFunc(parameter)
+}
+func (m *mockService) UpdateCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
+	return m.UpdateCategoryFunc(parameter)
+}
+func (m *mockService) DeleteCategory(_ context.Context, categoryID int64) (int64, error) {
+	return m.DeleteCategoryFunc(categoryID)
+}
+func (m *mockService) SearchCategories(_ context.Context, query string, limit int) []entity.SearchResult {
+	return m.SearchCategoriesFunc(query, limit)
+}
+func (m *mockService) UpsertCategoryTranslation(_ context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	return m.UpsertTranslationFunc(categoryID, locale, translation)
+}
+func (m *mockService) ReorderCategories(_ context.Context, ids []int64) ([]entity.Category, error) {
+	return m.ReorderFunc(ids)
+}
+func (m *mockService) API() entity.HealthResponse {
+	return m.APIFunc()
+}
+
+// serve routes req to h the way the router mounts it under /api/v2, so path values and the original request URI
+// are both available to the handler.
+func serve(h *CategoriesHandler, req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /categories/health", h.API)
+	mux.HandleFunc("GET /categories", h.GetAllCategories)
+	mux.HandleFunc("POST /categories", h.InsertCategory)
+	mux.HandleFunc("POST /categories/reorder", h.ReorderCategories)
+	mux.HandleFunc("GET /categories/search", h.SearchCategories)
+	mux.HandleFunc("GET /categories/{id}", h.GetCategory)
+	mux.HandleFunc("PUT /categories/{id}", h.UpdateCategory)
+	mux.HandleFunc("DELETE /categories/{id}", h.DeleteCategory)
+	mux.HandleFunc("PUT /categories/{id}/translations/{locale}", h.UpsertCategoryTranslation)
+
+	w := httptest.NewRecorder()
+	http.StripPrefix("/api/v2", mux).ServeHTTP(w, req)
+	return w
+}
+
+// decodeProblem decodes the problem details answered in w, failing the test when w is not a problem response.
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) problem.Problem {
+	t.Helper()
+
+	if got := w.Header().Get("Content-Type"); got != problem.MediaType {
+		t.Fatalf("expected content type %s, got %s", problem.MediaType, got)
+	}
+	var got problem.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error decoding %s: %v", w.Body.String(), err)
+	}
+	if got.Status != w.Code {
+		t.Errorf("expected problem status %d to match the response status, got %d", w.Code, got.Status)
+	}
+	return got
+}
+
+func TestNewCategoriesHandler(t *testing.T) {
+	svc := &mockService{}
+	handler, err := NewCategoriesHandler(svc)
+	if err != nil {
+		t.Errorf("NewCategoriesHandler() error = %v, wantErr nil", err)
+	}
+	if handler.service != svc {
+		t.Errorf("NewCategoriesHandler() handler.service = %v, want %v", handler.service, svc)
+	}
+}
+
+func TestCategoriesHandler_API(t *testing.T) {
+	tests := []struct {
+		name       string
+		healthy    bool
+		wantStatus int
+	}{
+		{name: "healthy", healthy: true, wantStatus: http.StatusOK},
+		{name: "unhealthy", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &CategoriesHandler{service: &mockService{
+				APIFunc: func() entity.HealthResponse {
+					return entity.HealthResponse{Name: "Service", IsHealthy: tt.healthy}
+				},
+			}}
+
+			w := serve(h, httptest.NewRequest(http.MethodGet, "/api/v2/categories/health", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if !tt.healthy {
+				if got := decodeProblem(t, w); got.Title != "Service tidak dalam kondisi sehat" {
+					t.Errorf("unexpected title %q", got.Title)
+				}
+				return
+			}
+			var got entity.HealthResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got.Name != "Service" {
+				t.Errorf("expected the bare health response, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_GetAllCategories(t *testing.T) {
+	stored := []entity.Category{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "C"}}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantIDs    []int64
+		wantLink   string
+	}{
+		{
+			name: "first page by default", target: "/api/v2/categories",
+			wantStatus: http.StatusOK, wantIDs: []int64{1, 2, 3},
+			wantLink: `</api/v2/categories?page=1&per_page=20>; rel="first", </api/v2/categories?page=1&per_page=20>; rel="last"`,
+		},
+		{
+			name: "middle page", target: "/api/v2/categories?page=2&per_page=1&lang=en",
+			wantStatus: http.StatusOK, wantIDs: []int64{2},
+			wantLink: `</api/v2/categories?lang=en&page=1&per_page=1>; rel="first", ` +
+				`</api/v2/categories?lang=en&page=1&per_page=1>; rel="prev", ` +
+				`</api/v2/categories?lang=en&page=3&per_page=1>; rel="next", ` +
+				`</api/v2/categories?lang=en&page=3&per_page=1>; rel="last"`,
+		},
+		{
+			name: "past the last page", target: "/api/v2/categories?page=5&per_page=2",
+			wantStatus: http.StatusOK, wantIDs: []int64{},
+			wantLink: `</api/v2/categories?page=1&per_page=2>; rel="first", ` +
+				`</api/v2/categories?page=2&per_page=2>; rel="prev", ` +
+				`</api/v2/categories?page=2&per_page=2>; rel="last"`,
+		},
+		{name: "zero page", target: "/api/v2/categories?page=0", wantStatus: http.StatusBadRequest},
+		{name: "page size too large", target: "/api/v2/categories?per_page=101", wantStatus: http.StatusBadRequest},
+		{name: "page size not a number", target: "/api/v2/categories?per_page=ten", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &CategoriesHandler{service: &mockService{
+				GetAllCategoriesFunc: func() []entity.Category { return stored },
+			}}
+
+			w := serve(h, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				got := decodeProblem(t, w)
+				if got.Type != problem.TypeURI(constants.MsgInvalidPagination) || got.Extensions["code"] != constants.MsgInvalidPagination {
+					t.Errorf("expected an invalid pagination problem, got %+v", got)
+				}
+				return
+			}
+
+			var got []entity.Category
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("expected a bare list, got %s", w.Body.String())
+			}
+			ids := []int64{}
+			for _, category := range got {
+				ids = append(ids, category.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("expected ids %v, got %v", tt.wantIDs, ids)
+			}
+			if got := w.Header().Get(TotalCountHeader); got != "3" {
+				t.Errorf("expected %s: 3, got %q", TotalCountHeader, got)
+			}
+			if got := w.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("expected Link %s, got %s", tt.wantLink, got)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_GetAllCategories_FiltersByAttributes(t *testing.T) {
+	stored := []entity.Category{
+		{ID: 1, Name: "A", Attributes: map[string]any{"commission": 2.5}},
+		{ID: 2, Name: "B", Attributes: map[string]any{"commission": 5.0}},
+		{ID: 3, Name: "C", Attributes: map[string]any{"commission": 2.5}},
+		{ID: 4, Name: "D"},
+	}
+	h := &CategoriesHandler{service: &mockService{
+		GetAllCategoriesFunc: func() []entity.Category { return stored },
+	}}
+
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/api/v2/categories?attr.commission=2.50&per_page=1", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var got []entity.Category
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("expected the first matching category, got %s", w.Body.String())
+	}
+	if got := w.Header().Get(TotalCountHeader); got != "2" {
+		t.Errorf("expected %s to count the matching categories, got %q", TotalCountHeader, got)
+	}
+	if want := `</api/v2/categories?attr.commission=2.50&page=2&per_page=1>; rel="next"`; !strings.Contains(w.Header().Get("Link"), want) {
+		t.Errorf("expected Link to keep the filter, got %s", w.Header().Get("Link"))
+	}
+}
+
+func TestCategoriesHandler_GetAllCategories_DoesNotLocalizeStoredCategories(t *testing.T) {
+	stored := []entity.Category{{
+		ID: 1, Name: "Buku", Locale: entity.DefaultLocale,
+		Translations: map[string]entity.Translation{"en": {Name: "Books"}},
+	}}
+	h := &CategoriesHandler{service: &mockService{
+		GetAllCategoriesFunc: func() []entity.Category { return stored },
+	}}
+
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/api/v2/categories?lang=en", nil))
+
+	if !strings.Contains(w.Body.String(), `"name":"Books"`) {
+		t.Errorf("expected the English name, got %s", w.Body.String())
+	}
+	if stored[0].Name != "Buku" {
+		t.Errorf("expected the stored category to keep its name, got %q", stored[0].Name)
+	}
+}
+
+func TestCategoriesHandler_GetCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		byID         func(int64) (entity.Category, error)
+		bySlug       func(string) (entity.Category, bool, error)
+		wantStatus   int
+		wantLocation string
+		wantCode     string
+	}{
+		{
+			name: "by id", target: "/api/v2/categories/1",
+			byID:       func(id int64) (entity.Category, error) { return entity.Category{ID: id, Slug: "buku"}, nil },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "by slug", target: "/api/v2/categories/buku",
+			bySlug: func(slug string) (entity.Category, bool, error) {
+				return entity.Category{ID: 1, Slug: slug}, false, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "renamed slug", target: "/api/v2/categories/buku-lama?lang=en",
+			bySlug:       func(string) (entity.Category, bool, error) { return entity.Category{ID: 1, Slug: "buku"}, true, nil },
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "buku?lang=en",
+		},
+		{
+			name: "not found", target: "/api/v2/categories/9",
+			byID:       func(int64) (entity.Category, error) { return entity.Category{}, entity.ErrCategoryNotFound },
+			wantStatus: http.StatusNotFound, wantCode: constants.MsgCategoryNotFound,
+		},
+		{
+			name: "invalid id", target: "/api/v2/categories/Bad_Slug",
+			wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidCategoryID,
+		},
+		{
+			name: "internal error", target: "/api/v2/categories/1",
+			byID:       func(int64) (entity.Category, error) { return entity.Category{}, errors.New("disk on fire") },
+			wantStatus: http.StatusInternalServerError, wantCode: constants.MsgInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &CategoriesHandler{service: &mockService{GetCategoryByIDFunc: tt.byID, GetCategoryBySlugFunc: tt.bySlug}}
+
+			w := serve(h, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("expected Location %q, got %q", tt.wantLocation, got)
+			}
+			if tt.wantCode == "" {
+				var got entity.Category
+				if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got.ID != 1 {
+					t.Errorf("expected the bare category, got %s", w.Body.String())
+				}
+				return
+			}
+
+			got := decodeProblem(t, w)
+			if got.Type != problem.TypeURI(tt.wantCode) || got.Extensions["code"] != tt.wantCode {
+				t.Errorf("expected a %s problem, got %+v", tt.wantCode, got)
+			}
+			if got.Instance != strings.SplitN(tt.target, "?", 2)[0] {
+				t.Errorf("expected instance %s, got %s", tt.target, got.Instance)
+			}
+			if strings.Contains(w.Body.String(), "disk on fire") {
+				t.Errorf("expected internal errors to stay hidden, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_InsertCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  string
+		body         string
+		wantStatus   int
+		wantLocation string
+		wantCode     string
+		wantReason   string
+		wantField    string
+	}{
+		{
+			name: "created", contentType: "application/json", body: `{"name":"Buku"}`,
+			wantStatus: http.StatusCreated, wantLocation: "/api/v2/categories/7",
+		},
+		{
+			name: "invalid attribute", contentType: "application/json", body: `{"name":"Buku","attributes":{"color":"green"}}`,
+			wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidAttribute, wantReason: "invalid_value", wantField: "attributes.color",
+		},
+		{
+			name: "unknown field", contentType: "application/json", body: `{"name":"Buku","colour":"red"}`,
+			wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidRequest, wantReason: "unknown_field",
+		},
+		{
+			name: "unsupported media type", contentType: "text/plain", body: `name=Buku`,
+			wantStatus: http.StatusUnsupportedMediaType, wantCode: constants.MsgUnsupportedMediaType, wantReason: "unsupported_media_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &CategoriesHandler{service: &mockService{
+				InsertCategoryFunc: func(category entity.Category) (entity.Category, error) {
+					if _, ok := category.Attributes["color"]; ok {
+						return entity.Category{}, &entity.AttributeError{Key: "color", Reason: "must be one of red, blue"}
+					}
+					category.ID = 7
+					return category, nil
+				},
+			}}
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/categories", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			w := serve(h, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("expected Location %q, got %q", tt.wantLocation, got)
+			}
+			if tt.wantCode == "" {
+				if got := strings.TrimSpace(w.Body.String()); !strings.HasPrefix(got, `{"id":7,`) {
+					t.Errorf("expected the bare category, got %s", got)
+				}
+				return
+			}
+
+			got := decodeProblem(t, w)
+			if got.Extensions["code"] != tt.wantCode || got.Extensions["reason"] != tt.wantReason {
+				t.Errorf("expected code %s and reason %s, got %+v", tt.wantCode, tt.wantReason, got.Extensions)
+			}
+			if tt.wantField != "" && got.Extensions["field"] != tt.wantField {
+				t.Errorf("expected field %s, got %+v", tt.wantField, got.Extensions)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_UpdateCategory(t *testing.T) {
+	h := &CategoriesHandler{service: &mockService{
+		UpdateCategoryFunc: func(category entity.Category) (entity.Category, error) {
+			if category.ID != 1 {
+				return entity.Category{}, entity.ErrCategoryNotFound
+			}
+			return category, nil
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{name: "updated", target: "/api/v2/categories/1", wantStatus: http.StatusOK},
+		{name: "not found", target: "/api/v2/categories/2", wantStatus: http.StatusNotFound},
+		{name: "invalid id", target: "/api/v2/categories/abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h, httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(`{"name":"Buku"}`)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				decodeProblem(t, w)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_DeleteCategory(t *testing.T) {
+	h := &CategoriesHandler{service: &mockService{
+		DeleteCategoryFunc: func(id int64) (int64, error) {
+			switch id {
+			case 1:
+				return id, nil
+			case 3:
+				return 0, entity.ErrCategoryInUse
+			}
+			return 0, entity.ErrCategoryNotFound
+		},
+	}}
+
+	w := serve(h, httptest.NewRequest(http.MethodDelete, "/api/v2/categories/1", nil))
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("expected an empty 204, got %d %q", w.Code, w.Body.String())
+	}
+
+	w = serve(h, httptest.NewRequest(http.MethodDelete, "/api/v2/categories/2", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+	decodeProblem(t, w)
+
+	w = serve(h, httptest.NewRequest(http.MethodDelete, "/api/v2/categories/3", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", w.Code)
+	}
+	if got := decodeProblem(t, w); got.Type != problem.TypeURI(constants.MsgCategoryInUse) {
+		t.Errorf("expected a %s problem, got %s", constants.MsgCategoryInUse, got.Type)
+	}
+}
+
+func TestCategoriesHandler_UpsertCategoryTranslation(t *testing.T) {
+	h := &CategoriesHandler{service: &mockService{
+		UpsertTranslationFunc: func(id int64, locale string, translation entity.Translation) (entity.Category, error) {
+			if translation.Name == "" {
+				return entity.Category{}, entity.ErrInvalidTranslation
+			}
+			return entity.Category{
+				ID: id, Name: "Buku", Locale: entity.DefaultLocale,
+				Translations: map[string]entity.Translation{locale: translation},
+			}, nil
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "translated", target: "/api/v2/categories/1/translations/en", body: `{"name":"Books"}`, wantStatus: http.StatusOK},
+		{name: "invalid locale", target: "/api/v2/categories/1/translations/!!", body: `{"name":"Books"}`, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidLocale},
+		{name: "invalid translation", target: "/api/v2/categories/1/translations/en", body: `{}`, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidTranslation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h, httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				if got := w.Header().Get("Content-Language"); got != "en" {
+					t.Errorf("expected Content-Language en, got %q", got)
+				}
+				return
+			}
+			if got := decodeProblem(t, w); got.Extensions["code"] != tt.wantCode {
+				t.Errorf("expected code %s, got %+v", tt.wantCode, got.Extensions)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_ReorderCategories(t *testing.T) {
+	h := &CategoriesHandler{service: &mockService{
+		ReorderFunc: func(ids []int64) ([]entity.Category, error) {
+			if len(ids) == 0 {
+				return nil, entity.ErrInvalidOrder
+			}
+			if ids[0] == 9 {
+				return nil, entity.ErrCategoryNotFound
+			}
+			return []entity.Category{{ID: ids[0], Position: 512}}, nil
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "reordered", body: `{"ids":[2]}`, wantStatus: http.StatusOK},
+		{name: "empty order", body: `{"ids":[]}`, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidOrder},
+		{name: "unknown category", body: `{"ids":[9]}`, wantStatus: http.StatusNotFound, wantCode: constants.MsgCategoryNotFound},
+		{name: "unknown field", body: `{"order":[2]}`, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h, httptest.NewRequest(http.MethodPost, "/api/v2/categories/reorder", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				if !strings.HasPrefix(w.Body.String(), `[{"id":2`) {
+					t.Errorf("expected a bare list, got %s", w.Body.String())
+				}
+				return
+			}
+			if got := decodeProblem(t, w); got.Extensions["code"] != tt.wantCode {
+				t.Errorf("expected code %s, got %+v", tt.wantCode, got.Extensions)
+			}
+		})
+	}
+}
+
+func TestCategoriesHandler_SearchCategories(t *testing.T) {
+	h := &CategoriesHandler{service: &mockService{
+		SearchCategoriesFunc: func(query string, limit int) []entity.SearchResult {
+			return []entity.SearchResult{{Category: entity.Category{ID: 1, Name: query}}}
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "found", target: "/api/v2/categories/search?q=buku", wantStatus: http.StatusOK},
+		{name: "missing query", target: "/api/v2/categories/search", wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidSearchQuery},
+		{name: "limit too large", target: "/api/v2/categories/search?q=buku&limit=500", wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidSearchLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				if !strings.HasPrefix(w.Body.String(), "[") {
+					t.Errorf("expected a bare list, got %s", w.Body.String())
+				}
+				return
+			}
+			if got := decodeProblem(t, w); got.Extensions["code"] != tt.wantCode {
+				t.Errorf("expected code %s, got %+v", tt.wantCode, got.Extensions)
+			}
+		})
+	}
+}
+
+func TestWriteProblem_Localized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/categories/9?lang=en", nil)
+	w := httptest.NewRecorder()
+
+	WriteProblem(w, req, http.StatusNotFound, constants.MsgCategoryNotFound, "", nil)
+
+	got := decodeProblem(t, w)
+	want := problem.Problem{
+		Type:       "urn:problem-type:categories-api:category-not-found",
+		Title:      constants.Messages.Localize(req, constants.MsgCategoryNotFound),
+		Status:     http.StatusNotFound,
+		Instance:   "/api/v2/categories/9",
+		Extensions: map[string]any{"code": constants.MsgCategoryNotFound},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
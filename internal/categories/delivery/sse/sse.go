@@ -0,0 +1,144 @@
+package sse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/stream"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+const (
+	// EventReset is sent instead of a replay when the requested Last-Event-ID is no longer buffered;
+	// clients should reload the category list before applying further events.
+	EventReset = "reset"
+
+	// retryInterval is the reconnection delay suggested to clients, in milliseconds.
+	retryInterval = 3000
+)
+
+// StreamHandler streams category changes to clients as Server-Sent Events.
+type StreamHandler struct {
+	broker    *stream.Broker
+	heartbeat time.Duration
+}
+
+// NewStreamHandler initializes a StreamHandler reading from broker and writing a heartbeat comment every heartbeat
+// so proxies keep idle connections open and disconnected clients are detected.
+func NewStreamHandler(broker *stream.Broker, heartbeat time.Duration) (*StreamHandler, error) {
+	if broker == nil {
+		return nil, fmt.Errorf("stream broker must not be nil")
+	}
+	if heartbeat <= 0 {
+		return nil, fmt.Errorf("stream heartbeat must be positive, got %s", heartbeat)
+	}
+
+	return &StreamHandler{
+		broker:    broker,
+		heartbeat: heartbeat,
+	}, nil
+}
+
+// Stream sends every category change of the tenant to the client as a Server-Sent Event until it disconnects,
+// replaying the events missed since Last-Event-ID first.
+func (d *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidLastEventID)
+		json_wrapper.WriteJSONResponse(w, http.StatusBadRequest, result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); errors.Is(err, http.ErrNotSupported) {
+		w.Header().Del("Content-Type")
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgStreamingUnsupported)
+		json_wrapper.WriteJSONResponse(w, http.StatusInternalServerError, result)
+		return
+	}
+	// The stream outlives any server write timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub, missed, complete := d.broker.Subscribe(tenant.FromContext(r.Context()), lastEventID)
+	defer sub.Close()
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", retryInterval); err != nil {
+		return
+	}
+	if complete {
+		for _, msg := range missed {
+			if err := writeEvent(w, msg); err != nil {
+				return
+			}
+		}
+	} else {
+		// The reloaded state already covers every event up to StartID, so the partial replay is skipped.
+		if err := writeEvent(w, stream.Message{ID: sub.StartID, Type: EventReset, Data: []byte("{}")}); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(d.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and resumes from its Last-Event-ID.
+				return
+			}
+			if err := writeEvent(w, msg); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseLastEventID reads the ID to resume from the Last-Event-ID header, falling back to the lastEventId query
+// parameter for clients that cannot set headers. A missing ID yields 0.
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// writeEvent writes msg in the text/event-stream format.
+func writeEvent(w io.Writer, msg stream.Message) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, msg.Data)
+	return err
+}
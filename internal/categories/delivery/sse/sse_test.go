@@ -0,0 +1,210 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/stream"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// readFrames reads SSE frames from the response body in the background, sending each raw frame on the returned channel.
+func readFrames(t *testing.T, resp *http.Response) <-chan string {
+	t.Helper()
+
+	frames := make(chan string)
+	go func() {
+		defer close(frames)
+		scanner := bufio.NewScanner(resp.Body)
+		var frame strings.Builder
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				frames <- frame.String()
+				frame.Reset()
+				continue
+			}
+			frame.WriteString(line + "\n")
+		}
+	}()
+	return frames
+}
+
+func nextFrame(t *testing.T, frames <-chan string) string {
+	t.Helper()
+
+	select {
+	case frame, ok := <-frames:
+		if !ok {
+			t.Fatalf("stream closed")
+		}
+		return frame
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for frame")
+	}
+	return ""
+}
+
+func openStream(t *testing.T, url, lastEventID string) (*http.Response, context.CancelFunc) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, cancel
+}
+
+func TestNewStreamHandler(t *testing.T) {
+	broker, _ := stream.NewBroker(4)
+
+	if _, err := NewStreamHandler(nil, time.Second); err == nil {
+		t.Fatalf("expected error for nil broker")
+	}
+	if _, err := NewStreamHandler(broker, 0); err == nil {
+		t.Fatalf("expected error for non-positive heartbeat")
+	}
+	if _, err := NewStreamHandler(broker, time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStreamHandler_Stream(t *testing.T) {
+	broker, _ := stream.NewBroker(4)
+	handler, _ := NewStreamHandler(broker, time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(handler.Stream))
+	defer server.Close()
+
+	resp, cancel := openStream(t, server.URL, "")
+	defer cancel()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", got)
+	}
+
+	frames := readFrames(t, resp)
+	if frame := nextFrame(t, frames); frame != "retry: 3000\n" {
+		t.Fatalf("unexpected first frame %q", frame)
+	}
+
+	broker.Publish(entity.CategoryEvent{Type: entity.EventCategoryCreated, TenantID: tenant.DefaultID, Category: entity.Category{ID: 1, Name: "Susu"}})
+
+	frame := nextFrame(t, frames)
+	if !strings.HasPrefix(frame, "id: 1\nevent: category.created\ndata: {") || !strings.Contains(frame, `"name":"Susu"`) {
+		t.Fatalf("unexpected event frame %q", frame)
+	}
+
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for broker.Subscribers() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected subscription to be released after disconnect")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStreamHandler_Resume(t *testing.T) {
+	tests := []struct {
+		name        string
+		published   int
+		lastEventID string
+		wantFrames  []string
+	}{
+		{name: "replay", published: 3, lastEventID: "2", wantFrames: []string{"id: 3\nevent: category.deleted\n"}},
+		{name: "gap", published: 6, lastEventID: "1", wantFrames: []string{"id: 6\nevent: reset\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker, _ := stream.NewBroker(4)
+			eventTypes := []string{entity.EventCategoryCreated, entity.EventCategoryUpdated, entity.EventCategoryDeleted}
+			for i := 0; i < tt.published; i++ {
+				broker.Publish(entity.CategoryEvent{Type: eventTypes[i%len(eventTypes)], TenantID: tenant.DefaultID, Category: entity.Category{ID: int64(i + 1)}})
+			}
+
+			handler, _ := NewStreamHandler(broker, time.Hour)
+			server := httptest.NewServer(http.HandlerFunc(handler.Stream))
+			defer server.Close()
+
+			resp, cancel := openStream(t, server.URL, tt.lastEventID)
+			defer cancel()
+
+			frames := readFrames(t, resp)
+			nextFrame(t, frames)
+			for _, want := range tt.wantFrames {
+				if frame := nextFrame(t, frames); !strings.HasPrefix(frame, want) {
+					t.Fatalf("expected frame starting with %q, got %q", want, frame)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamHandler_TenantIsolation(t *testing.T) {
+	broker, _ := stream.NewBroker(4)
+	handler, _ := NewStreamHandler(broker, time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.Stream(w, r.WithContext(tenant.WithID(r.Context(), "acme")))
+	}))
+	defer server.Close()
+
+	resp, cancel := openStream(t, server.URL, "")
+	defer cancel()
+
+	frames := readFrames(t, resp)
+	nextFrame(t, frames)
+
+	broker.Publish(entity.CategoryEvent{Type: entity.EventCategoryCreated, TenantID: tenant.DefaultID, Category: entity.Category{ID: 1, Name: "Susu"}})
+	broker.Publish(entity.CategoryEvent{Type: entity.EventCategoryCreated, TenantID: "acme", Category: entity.Category{ID: 1, Name: "Kopi"}})
+
+	if frame := nextFrame(t, frames); !strings.HasPrefix(frame, "id: 2\n") || !strings.Contains(frame, `"name":"Kopi"`) {
+		t.Fatalf("expected only acme's event, got %q", frame)
+	}
+}
+
+func TestStreamHandler_Heartbeat(t *testing.T) {
+	broker, _ := stream.NewBroker(4)
+	handler, _ := NewStreamHandler(broker, 10*time.Millisecond)
+	server := httptest.NewServer(http.HandlerFunc(handler.Stream))
+	defer server.Close()
+
+	resp, cancel := openStream(t, server.URL, "")
+	defer cancel()
+
+	frames := readFrames(t, resp)
+	nextFrame(t, frames)
+	if frame := nextFrame(t, frames); frame != ": heartbeat\n" {
+		t.Fatalf("expected heartbeat, got %q", frame)
+	}
+}
+
+func TestStreamHandler_InvalidLastEventID(t *testing.T) {
+	broker, _ := stream.NewBroker(4)
+	handler, _ := NewStreamHandler(broker, time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/stream?lastEventId=abc", nil)
+	w := httptest.NewRecorder()
+	handler.Stream(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	if broker.Subscribers() != 0 {
+		t.Fatalf("expected no subscription for a rejected request")
+	}
+}
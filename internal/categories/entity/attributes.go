@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AttributeType is the type of the values of a category attribute.
+type AttributeType string
+
+// The types a category attribute can have. Number values are stored as float64, bool values as bool and string and
+// enum values as string.
+const (
+	AttributeString AttributeType = "string"
+	AttributeNumber AttributeType = "number"
+	AttributeBool   AttributeType = "bool"
+	AttributeEnum   AttributeType = "enum"
+)
+
+// AttributeDefinition describes an attribute categories of a tenant may carry in Category.Attributes, e.g. an icon,
+// a color or a commission rate, so metadata can be added without changing the Category type.
+// Key names the attribute in Category.Attributes: lower-case letters, digits and underscores, starting with a letter.
+// Values lists the allowed values of an enum attribute and is empty for other types. A required attribute must be
+// given a value unless it has a Default, which is stored for categories written without a value for the attribute.
+type AttributeDefinition struct {
+	Key      string        `json:"key"`
+	Type     AttributeType `json:"type"`
+	Required bool          `json:"required"`
+	Default  any           `json:"default,omitempty"`
+	Values   []string      `json:"values,omitempty"`
+}
+
+// AttributeFilter selects categories by attribute value: a category matches when, for every key, its value of the
+// attribute equals one of the given values. Values are given as text and compared according to the type of the
+// stored value, so "1.50" matches the number 1.5 and "TRUE" matches true.
+type AttributeFilter map[string][]string
+
+// AttributeFilterPrefix prefixes the query parameters that filter category lists by attribute, e.g.
+// ?attr.color=red&attr.color=blue&attr.featured=true.
+const AttributeFilterPrefix = "attr."
+
+// AttributeFilterFromQuery returns the filter given by the query parameters named AttributeFilterPrefix followed by
+// an attribute key. Other parameters are ignored.
+func AttributeFilterFromQuery(query url.Values) AttributeFilter {
+	var filter AttributeFilter
+	for name, values := range query {
+		key, ok := strings.CutPrefix(name, AttributeFilterPrefix)
+		if !ok || key == "" {
+			continue
+		}
+		if filter == nil {
+			filter = AttributeFilter{}
+		}
+		filter[key] = values
+	}
+	return filter
+}
+
+// Match reports whether category matches the filter. An empty filter matches every category.
+func (f AttributeFilter) Match(category Category) bool {
+	for key, values := range f {
+		value, ok := category.Attributes[key]
+		if !ok {
+			return false
+		}
+		if !slices.ContainsFunc(values, func(text string) bool { return attributeEquals(value, text) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the categories matching the filter, keeping their order.
+func (f AttributeFilter) Filter(categories []Category) []Category {
+	if len(f) == 0 {
+		return categories
+	}
+
+	matched := make([]Category, 0, len(categories))
+	for _, category := range categories {
+		if f.Match(category) {
+			matched = append(matched, category)
+		}
+	}
+	return matched
+}
+
+// attributeEquals reports whether the stored attribute value equals text.
+func attributeEquals(value any, text string) bool {
+	switch value := value.(type) {
+	case string:
+		return value == text
+	case float64:
+		number, err := strconv.ParseFloat(text, 64)
+		return err == nil && number == value
+	case bool:
+		b, err := strconv.ParseBool(text)
+		return err == nil && b == value
+	}
+	return fmt.Sprint(value) == text
+}
@@ -1,14 +1,82 @@
 package entity
 
+import (
+	"sort"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+// DefaultLocale is the locale Category.Name and Category.Description are written in. It is served whenever a
+// client asks for a locale the category has no translation for.
+const DefaultLocale = constants.DefaultLocale
+
 // Category represents a grouping of certain entities, containing an ID, name, and a description.
+// Slug is a unique, URL-safe identifier generated from the name, e.g. "handphone".
+// Position is the rank of the category in the merchandiser-chosen order categories are listed in, lowest first.
+// Ranks are spaced apart so a category can usually be moved by changing its own rank only. Positions are only changed
+// by reordering: new categories are placed last and updates keep the position.
+// Attributes holds the values of the attributes defined for the tenant's categories, keyed by AttributeDefinition.Key.
+// Image describes the category's uploaded image, or is nil when it has none. It is only set through image uploads.
+// Translations holds the name and description in locales other than DefaultLocale, keyed by BCP 47 tag, and is
+// managed separately from the category itself. Locale is set on localized reads to the locale Name and
+// Description are returned in.
 type Category struct {
-	ID          int64  `json:"id"`
+	ID           int64                  `json:"id"`
+	Name         string                 `json:"name"`
+	Slug         string                 `json:"slug"`
+	Description  string                 `json:"description"`
+	Position     int64                  `json:"position"`
+	Attributes   map[string]any         `json:"attributes,omitempty"`
+	Image        *CategoryImage         `json:"image,omitempty"`
+	Locale       string                 `json:"locale,omitempty"`
+	Translations map[string]Translation `json:"translations,omitempty"`
+}
+
+// Translation is a category's name and description in one locale.
+type Translation struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
 
+// Locales returns DefaultLocale followed by every locale the category has a translation for, in sorted order.
+func (c Category) Locales() []string {
+	locales := make([]string, 0, len(c.Translations)+1)
+	locales = append(locales, DefaultLocale)
+	for locale := range c.Translations {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// Localize returns the category with Name and Description replaced by their translation in locale and Locale set
+// accordingly. Locales without a translation keep the DefaultLocale text.
+func (c Category) Localize(locale string) Category {
+	c.Locale = DefaultLocale
+	if translation, ok := c.Translations[locale]; ok && locale != DefaultLocale {
+		c.Name = translation.Name
+		c.Description = translation.Description
+		c.Locale = locale
+	}
+	return c
+}
+
+// CategoryOrder is the order categories should be listed in: the categories with IDs come first, in that order,
+// followed by all other categories in their current order.
+type CategoryOrder struct {
+	IDs []int64 `json:"ids"`
+}
+
 // HealthResponse represents the health status of a service or component with its name and health condition.
 type HealthResponse struct {
 	Name      string `json:"name"`
 	IsHealthy bool   `json:"is_healthy"`
 }
+
+// SearchResult is a category matched by a search query together with its relevance score; higher is more relevant.
+type SearchResult struct {
+	Category Category `json:"category"`
+	Score    float64  `json:"score"`
+}
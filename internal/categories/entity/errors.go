@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+// ErrCategoryNotFound is returned when a category does not exist in the data source.
+// Callers should match it with errors.Is rather than comparing messages.
+var ErrCategoryNotFound = errors.New(constants.ErrCategoryNotFound)
+
+// ErrInvalidTranslation is returned when a category translation has no name.
+var ErrInvalidTranslation = errors.New(constants.ErrInvalidTranslation)
+
+// ErrInvalidOrder is returned when a category order is empty or names a category more than once.
+var ErrInvalidOrder = errors.New(constants.ErrInvalidOrder)
+
+// ErrInvalidAttribute is returned when the attributes of a category do not match the attribute definitions. The
+// returned error is an *AttributeError naming the offending attribute.
+var ErrInvalidAttribute = errors.New(constants.ErrInvalidAttribute)
+
+// ErrAttributeNotFound is returned when no category attribute is defined with a key.
+var ErrAttributeNotFound = errors.New(constants.ErrAttributeNotFound)
+
+// ErrAttributeExists is returned when a category attribute is defined with a key that is already taken.
+var ErrAttributeExists = errors.New(constants.ErrAttributeExists)
+
+// ErrInvalidAttributeDefinition is returned when a category attribute definition fails validation.
+var ErrInvalidAttributeDefinition = errors.New(constants.ErrInvalidAttributeDefinition)
+
+// ErrAttributeInUse is returned when changing or deleting an attribute definition would leave categories holding
+// values that no longer match it.
+var ErrAttributeInUse = errors.New(constants.ErrAttributeInUse)
+
+// ErrInvalidImage is returned when an uploaded category image cannot be decoded or its dimensions are out of range.
+var ErrInvalidImage = errors.New(constants.ErrInvalidImage)
+
+// ErrUnsupportedImageType is returned when the content of an uploaded category image is not in a supported format.
+var ErrUnsupportedImageType = errors.New(constants.ErrUnsupportedImageType)
+
+// ErrImageTooLarge is returned when an uploaded category image exceeds the maximum size.
+var ErrImageTooLarge = errors.New(constants.ErrImageTooLarge)
+
+// ErrImageNotFound is returned when a category has no image.
+var ErrImageNotFound = errors.New(constants.ErrImageNotFound)
+
+// ErrCategoryInUse is returned when a category cannot be deleted because products are still classified by it.
+var ErrCategoryInUse = errors.New(constants.ErrCategoryInUse)
+
+// AttributeError describes why the value of the category attribute Key was rejected. It matches ErrInvalidAttribute.
+type AttributeError struct {
+	Key    string
+	Reason string
+}
+
+// Error returns the message of ErrInvalidAttribute followed by the key and the reason.
+func (e *AttributeError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrInvalidAttribute, e.Key, e.Reason)
+}
+
+// Unwrap returns ErrInvalidAttribute.
+func (e *AttributeError) Unwrap() error {
+	return ErrInvalidAttribute
+}
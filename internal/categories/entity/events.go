@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+const (
+	// EventCategoryCreated is emitted after a category has been inserted.
+	EventCategoryCreated = "category.created"
+
+	// EventCategoryUpdated is emitted after an existing category has been updated.
+	EventCategoryUpdated = "category.updated"
+
+	// EventCategoryDeleted is emitted after a category has been deleted.
+	EventCategoryDeleted = "category.deleted"
+)
+
+// CategoryEvent describes a change made to a category, carrying the category as it was after the change
+// (or right before it, for deletions), and the tenant the category belongs to.
+type CategoryEvent struct {
+	Type       string    `json:"type"`
+	TenantID   string    `json:"tenant_id"`
+	Category   Category  `json:"category"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+// CategoryImage describes the icon or image stored for a category. The image itself is kept in blob storage under
+// Key, which is not exposed to clients; they fetch it from the category's image endpoint. Checksum is the
+// hex-encoded SHA-256 of the image and changes whenever a different image is stored.
+type CategoryImage struct {
+	Key         string `json:"-"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Checksum    string `json:"checksum"`
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// IAttributesRepository defines an abstraction for storing the attribute definitions of categories. Like
+// ICategoriesRepository, every operation is scoped to the tenant carried by ctx.
+type IAttributesRepository interface {
+	GetAllAttributes(ctx context.Context) []entity.AttributeDefinition
+	GetAttributeByKey(ctx context.Context, key string) (entity.AttributeDefinition, error)
+	InsertAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error)
+	UpdateAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error)
+	DeleteAttribute(ctx context.Context, key string) error
+}
+
+// AttributesRepository keeps the attribute definitions of every tenant in memory. It is safe for concurrent use.
+type AttributesRepository struct {
+	mu         sync.RWMutex
+	attributes map[string]map[string]entity.AttributeDefinition
+}
+
+// NewAttributesRepository initializes an empty AttributesRepository.
+func NewAttributesRepository() (*AttributesRepository, error) {
+	return &AttributesRepository{attributes: map[string]map[string]entity.AttributeDefinition{}}, nil
+}
+
+// GetAllAttributes returns every attribute definition of the tenant ordered by key.
+func (r *AttributesRepository) GetAllAttributes(ctx context.Context) []entity.AttributeDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := r.attributes[tenant.FromContext(ctx)]
+	attributes := make([]entity.AttributeDefinition, 0, len(definitions))
+	for _, definition := range definitions {
+		attributes = append(attributes, cloneDefinition(definition))
+	}
+	sort.Slice(attributes, func(i, j int) bool { return attributes[i].Key < attributes[j].Key })
+
+	return attributes
+}
+
+// GetAttributeByKey returns the attribute definition of the tenant with the given key or ErrAttributeNotFound.
+func (r *AttributesRepository) GetAttributeByKey(ctx context.Context, key string) (entity.AttributeDefinition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definition, ok := r.attributes[tenant.FromContext(ctx)][key]
+	if !ok {
+		return entity.AttributeDefinition{}, entity.ErrAttributeNotFound
+	}
+
+	return cloneDefinition(definition), nil
+}
+
+// InsertAttribute stores a new attribute definition for the tenant, or returns ErrAttributeExists when its key is taken.
+func (r *AttributesRepository) InsertAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	if _, ok := r.attributes[tenantID][parameter.Key]; ok {
+		return entity.AttributeDefinition{}, entity.ErrAttributeExists
+	}
+	if r.attributes[tenantID] == nil {
+		r.attributes[tenantID] = map[string]entity.AttributeDefinition{}
+	}
+
+	r.attributes[tenantID][parameter.Key] = cloneDefinition(parameter)
+	return parameter, nil
+}
+
+// UpdateAttribute replaces the attribute definition of the tenant with the key of parameter, or returns
+// ErrAttributeNotFound when there is none.
+func (r *AttributesRepository) UpdateAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	definitions := r.attributes[tenant.FromContext(ctx)]
+	if _, ok := definitions[parameter.Key]; !ok {
+		return entity.AttributeDefinition{}, entity.ErrAttributeNotFound
+	}
+
+	definitions[parameter.Key] = cloneDefinition(parameter)
+	return parameter, nil
+}
+
+// DeleteAttribute removes the attribute definition of the tenant with the given key, or returns ErrAttributeNotFound
+// when there is none.
+func (r *AttributesRepository) DeleteAttribute(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	definitions := r.attributes[tenant.FromContext(ctx)]
+	if _, ok := definitions[key]; !ok {
+		return entity.ErrAttributeNotFound
+	}
+
+	delete(definitions, key)
+	return nil
+}
+
+// cloneDefinition returns a copy of definition that shares no slice with it.
+func cloneDefinition(definition entity.AttributeDefinition) entity.AttributeDefinition {
+	definition.Values = slices.Clone(definition.Values)
+	return definition
+}
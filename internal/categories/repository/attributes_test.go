@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+func TestAttributesRepository(t *testing.T) {
+	repo, err := NewAttributesRepository()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := t.Context()
+
+	color := entity.AttributeDefinition{Key: "color", Type: entity.AttributeEnum, Values: []string{"red", "blue"}}
+	if _, err := repo.InsertAttribute(ctx, color); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.InsertAttribute(ctx, entity.AttributeDefinition{Key: "color", Type: entity.AttributeString}); !errors.Is(err, entity.ErrAttributeExists) {
+		t.Errorf("expected ErrAttributeExists, got %v", err)
+	}
+	if _, err := repo.InsertAttribute(ctx, entity.AttributeDefinition{Key: "active", Type: entity.AttributeBool}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Stored definitions must not share the values slice with the caller.
+	color.Values[0] = "green"
+	got, err := repo.GetAttributeByKey(ctx, "color")
+	if err != nil || !reflect.DeepEqual(got.Values, []string{"red", "blue"}) {
+		t.Errorf("expected the stored color definition, got %+v, %v", got, err)
+	}
+
+	var keys []string
+	for _, attribute := range repo.GetAllAttributes(ctx) {
+		keys = append(keys, attribute.Key)
+	}
+	if want := []string{"active", "color"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected attributes %v, got %v", want, keys)
+	}
+
+	if _, err := repo.UpdateAttribute(ctx, entity.AttributeDefinition{Key: "active", Type: entity.AttributeBool, Required: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := repo.GetAttributeByKey(ctx, "active"); !got.Required {
+		t.Errorf("expected the updated definition, got %+v", got)
+	}
+	if _, err := repo.UpdateAttribute(ctx, entity.AttributeDefinition{Key: "size", Type: entity.AttributeNumber}); !errors.Is(err, entity.ErrAttributeNotFound) {
+		t.Errorf("expected ErrAttributeNotFound, got %v", err)
+	}
+
+	if err := repo.DeleteAttribute(ctx, "active"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetAttributeByKey(ctx, "active"); !errors.Is(err, entity.ErrAttributeNotFound) {
+		t.Errorf("expected ErrAttributeNotFound, got %v", err)
+	}
+	if err := repo.DeleteAttribute(ctx, "active"); !errors.Is(err, entity.ErrAttributeNotFound) {
+		t.Errorf("expected ErrAttributeNotFound, got %v", err)
+	}
+}
+
+func TestAttributesRepository_TenantIsolation(t *testing.T) {
+	repo, _ := NewAttributesRepository()
+	acme := tenant.WithID(t.Context(), "acme")
+	globex := tenant.WithID(t.Context(), "globex")
+
+	if _, err := repo.InsertAttribute(acme, entity.AttributeDefinition{Key: "color", Type: entity.AttributeString}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.InsertAttribute(globex, entity.AttributeDefinition{Key: "color", Type: entity.AttributeNumber}); err != nil {
+		t.Fatalf("expected the key to be free in another tenant, got %v", err)
+	}
+
+	if got, _ := repo.GetAttributeByKey(acme, "color"); got.Type != entity.AttributeString {
+		t.Errorf("expected acme's definition, got %+v", got)
+	}
+	if got := repo.GetAllAttributes(t.Context()); len(got) != 0 {
+		t.Errorf("expected no definitions in the default tenant, got %+v", got)
+	}
+	if err := repo.DeleteAttribute(t.Context(), "color"); !errors.Is(err, entity.ErrAttributeNotFound) {
+		t.Errorf("expected ErrAttributeNotFound in the default tenant, got %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// allCategoriesKey is the cache key under which the full category list is stored.
+	allCategoriesKey = "all"
+
+	// slugKeyPrefix prefixes the cache keys of slug lookups.
+	slugKeyPrefix = "slug:"
+)
+
+// CacheStats reports the effectiveness of a CachedCategoriesRepository since it was created.
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
+
+// cacheEntry is a single LRU element holding a cached value and the moment it stops being valid.
+type cacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// CachedCategoriesRepository is a read-through caching decorator for any ICategoriesRepository.
+// Reads are served from a bounded LRU cache with a TTL, concurrent misses for the same key are collapsed
+// with singleflight, and every write invalidates the entries it can affect. Entries are keyed by tenant, so
+// tenants share the capacity but never each other's entries.
+type CachedCategoriesRepository struct {
+	repo     ICategoriesRepository
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List
+	generation uint64
+	stats      CacheStats
+
+	group singleflight.Group
+}
+
+// NewCachedCategoriesRepository wraps repo with an LRU cache holding at most capacity entries, each valid for ttl.
+func NewCachedCategoriesRepository(repo ICategoriesRepository, capacity int, ttl time.Duration) (*CachedCategoriesRepository, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("repository must not be nil")
+	}
+
+	if capacity <= 0 {
+		return nil, fmt.Errorf("cache capacity must be greater than zero")
+	}
+
+	if ttl <= 0 {
+		return nil, fmt.Errorf("cache ttl must be greater than zero")
+	}
+
+	return &CachedCategoriesRepository{
+		repo:     repo,
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}, nil
+}
+
+// GetAllCategories returns the cached category list, loading it from the wrapped repository on a miss.
+func (r *CachedCategoriesRepository) GetAllCategories(ctx context.Context) []entity.Category {
+	value := r.load(tenantKey(ctx, allCategoriesKey), func() interface{} {
+		return cloneCategories(r.repo.GetAllCategories(ctx))
+	})
+
+	return cloneCategories(value.([]entity.Category))
+}
+
+// GetCategoryByID returns the cached category for categoryID, loading it from the wrapped repository on a miss.
+// Empty results are cached as well so repeated lookups of a missing ID do not reach the wrapped repository.
+func (r *CachedCategoriesRepository) GetCategoryByID(ctx context.Context, categoryID int64) entity.Category {
+	value := r.load(tenantKey(ctx, categoryKey(categoryID)), func() interface{} {
+		return r.repo.GetCategoryByID(ctx, categoryID)
+	})
+
+	return value.(entity.Category)
+}
+
+// slugLookup is the cached result of GetCategoryBySlug.
+type slugLookup struct {
+	category entity.Category
+	moved    bool
+}
+
+// GetCategoryBySlug returns the cached slug lookup, loading it from the wrapped repository on a miss.
+func (r *CachedCategoriesRepository) GetCategoryBySlug(ctx context.Context, slug string) (entity.Category, bool) {
+	value := r.load(tenantKey(ctx, slugKeyPrefix+slug), func() interface{} {
+		category, moved := r.repo.GetCategoryBySlug(ctx, slug)
+		return slugLookup{category: category, moved: moved}
+	})
+
+	lookup := value.(slugLookup)
+	return lookup.category, lookup.moved
+}
+
+// InsertCategory inserts through the wrapped repository and invalidates the list, the new category's entry and slug lookups.
+func (r *CachedCategoriesRepository) InsertCategory(ctx context.Context, parameter entity.Category) entity.Category {
+	cat := r.repo.InsertCategory(ctx, parameter)
+	r.invalidate(ctx, allCategoriesKey, categoryKey(cat.ID))
+	return cat
+}
+
+// UpdateCategory updates through the wrapped repository and invalidates the list, the updated category's entry and slug lookups.
+func (r *CachedCategoriesRepository) UpdateCategory(ctx context.Context, parameter entity.Category) (entity.Category, error) {
+	cat, err := r.repo.UpdateCategory(ctx, parameter)
+	r.invalidate(ctx, allCategoriesKey, categoryKey(parameter.ID))
+	return cat, err
+}
+
+// DeleteCategory deletes through the wrapped repository and invalidates the list, the deleted category's entry and slug lookups.
+func (r *CachedCategoriesRepository) DeleteCategory(ctx context.Context, categoryID int64) (int64, error) {
+	id, err := r.repo.DeleteCategory(ctx, categoryID)
+	r.invalidate(ctx, allCategoriesKey, categoryKey(categoryID))
+	return id, err
+}
+
+// UpsertTranslation stores the translation through the wrapped repository and invalidates the list, the category's entry and slug lookups.
+func (r *CachedCategoriesRepository) UpsertTranslation(ctx context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	cat, err := r.repo.UpsertTranslation(ctx, categoryID, locale, translation)
+	r.invalidate(ctx, allCategoriesKey, categoryKey(categoryID))
+	return cat, err
+}
+
+// ReorderCategories reorders through the wrapped repository and invalidates the list, the entry of every category
+// and slug lookups, since any category may have moved.
+func (r *CachedCategoriesRepository) ReorderCategories(ctx context.Context, ids []int64) ([]entity.Category, error) {
+	categories, err := r.repo.ReorderCategories(ctx, ids)
+
+	keys := []string{allCategoriesKey}
+	for _, category := range categories {
+		keys = append(keys, categoryKey(category.ID))
+	}
+	r.invalidate(ctx, keys...)
+
+	return categories, err
+}
+
+// SetCategoryImage stores the image through the wrapped repository and invalidates the list, the category's entry and slug lookups.
+func (r *CachedCategoriesRepository) SetCategoryImage(ctx context.Context, categoryID int64, image *entity.CategoryImage) (entity.Category, error) {
+	cat, err := r.repo.SetCategoryImage(ctx, categoryID, image)
+	r.invalidate(ctx, allCategoriesKey, categoryKey(categoryID))
+	return cat, err
+}
+
+// Stats returns a snapshot of the cache hit, miss and eviction counters together with the current number of entries.
+func (r *CachedCategoriesRepository) Stats() CacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.stats
+	stats.Size = r.order.Len()
+	return stats
+}
+
+// Purge drops every cached entry without resetting the statistics.
+func (r *CachedCategoriesRepository) Purge() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.items = make(map[string]*list.Element)
+	r.order.Init()
+}
+
+// load returns the value cached under key or, on a miss, runs fetch once for all concurrent callers and caches the result.
+// A result is only stored if no write invalidated the cache while fetch was running, so stale reads are never cached.
+func (r *CachedCategoriesRepository) load(key string, fetch func() interface{}) interface{} {
+	r.mu.Lock()
+	if value, ok := r.get(key); ok {
+		r.stats.Hits++
+		r.mu.Unlock()
+		return value
+	}
+	r.stats.Misses++
+	generation := r.generation
+	r.mu.Unlock()
+
+	value, _, _ := r.group.Do(key, func() (interface{}, error) {
+		r.mu.Lock()
+		if value, ok := r.get(key); ok {
+			r.mu.Unlock()
+			return value, nil
+		}
+		r.mu.Unlock()
+
+		value := fetch()
+
+		r.mu.Lock()
+		if r.generation == generation {
+			r.set(key, value)
+		}
+		r.mu.Unlock()
+
+		return value, nil
+	})
+
+	return value
+}
+
+// get returns the live entry for key, dropping it if it has expired. The caller must hold r.mu.
+func (r *CachedCategoriesRepository) get(key string) (interface{}, bool) {
+	elem, ok := r.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !r.now().Before(entry.expiresAt) {
+		r.removeElement(elem)
+		return nil, false
+	}
+
+	r.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// set stores value under key as the most recently used entry, evicting the least recently used one when full.
+// The caller must hold r.mu.
+func (r *CachedCategoriesRepository) set(key string, value interface{}) {
+	expiresAt := r.now().Add(r.ttl)
+
+	if elem, ok := r.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		r.order.MoveToFront(elem)
+		return
+	}
+
+	r.items[key] = r.order.PushFront(&cacheEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for r.order.Len() > r.capacity {
+		r.removeElement(r.order.Back())
+		r.stats.Evictions++
+	}
+}
+
+// invalidate drops the given keys and every slug lookup of the tenant carried by ctx, and bumps the generation so
+// in-flight loads do not repopulate stale data. Slug lookups are always dropped because any write can claim, free or
+// retire a slug.
+func (r *CachedCategoriesRepository) invalidate(ctx context.Context, keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	for _, key := range keys {
+		if elem, ok := r.items[tenantKey(ctx, key)]; ok {
+			r.removeElement(elem)
+		}
+	}
+
+	slugs := tenantKey(ctx, slugKeyPrefix)
+	for key, elem := range r.items {
+		if strings.HasPrefix(key, slugs) {
+			r.removeElement(elem)
+		}
+	}
+}
+
+// removeElement unlinks elem from both the LRU list and the key index. The caller must hold r.mu.
+func (r *CachedCategoriesRepository) removeElement(elem *list.Element) {
+	r.order.Remove(elem)
+	delete(r.items, elem.Value.(*cacheEntry).key)
+}
+
+// tenantKey scopes key to the tenant carried by ctx. Tenant IDs never contain "/", so keys of different tenants
+// cannot collide.
+func tenantKey(ctx context.Context, key string) string {
+	return tenant.FromContext(ctx) + "/" + key
+}
+
+// categoryKey returns the cache key for a single category.
+func categoryKey(categoryID int64) string {
+	return "id:" + strconv.FormatInt(categoryID, 10)
+}
+
+// cloneCategories returns a copy of categories so cached slices are never shared with callers.
+func cloneCategories(categories []entity.Category) []entity.Category {
+	if categories == nil {
+		return nil
+	}
+
+	return append([]entity.Category(nil), categories...)
+}
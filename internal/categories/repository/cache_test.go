@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+type countingRepository struct {
+	mu         sync.Mutex
+	categories []entity.Category
+	getAll     atomic.Int64
+	getByID    atomic.Int64
+	getBySlug  atomic.Int64
+	block      chan struct{}
+}
+
+func (c *countingRepository) GetAllCategories(_ context.Context) []entity.Category {
+	c.getAll.Add(1)
+	if c.block != nil {
+		<-c.block
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]entity.Category(nil), c.categories...)
+}
+
+func (c *countingRepository) GetCategoryByID(_ context.Context, categoryID int64) entity.Category {
+	c.getByID.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cat := range c.categories {
+		if cat.ID == categoryID {
+			return cat
+		}
+	}
+	return entity.Category{}
+}
+
+func (c *countingRepository) GetCategoryBySlug(_ context.Context, slug string) (entity.Category, bool) {
+	c.getBySlug.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cat := range c.categories {
+		if cat.Slug == slug {
+			return cat, false
+		}
+	}
+	return entity.Category{}, false
+}
+
+func (c *countingRepository) InsertCategory(_ context.Context, parameter entity.Category) entity.Category {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.categories = append(c.categories, parameter)
+	return parameter
+}
+
+func (c *countingRepository) UpdateCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, cat := range c.categories {
+		if cat.ID == parameter.ID {
+			c.categories[i] = parameter
+		}
+	}
+	return parameter, nil
+}
+
+func (c *countingRepository) DeleteCategory(_ context.Context, categoryID int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, cat := range c.categories {
+		if cat.ID == categoryID {
+			c.categories = append(c.categories[:i], c.categories[i+1:]...)
+		}
+	}
+	return categoryID, nil
+}
+
+func (c *countingRepository) UpsertTranslation(_ context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, cat := range c.categories {
+		if cat.ID == categoryID {
+			cat.Translations = map[string]entity.Translation{locale: translation}
+			c.categories[i] = cat
+			return cat, nil
+		}
+	}
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+func (c *countingRepository) ReorderCategories(_ context.Context, ids []int64) ([]entity.Category, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for position, id := range ids {
+		for i, cat := range c.categories {
+			if cat.ID == id {
+				c.categories[i].Position = int64(position + 1)
+			}
+		}
+	}
+	return append([]entity.Category(nil), c.categories...), nil
+}
+
+func (c *countingRepository) SetCategoryImage(_ context.Context, categoryID int64, image *entity.CategoryImage) (entity.Category, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, cat := range c.categories {
+		if cat.ID == categoryID {
+			c.categories[i].Image = image
+			return c.categories[i], nil
+		}
+	}
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+func TestNewCachedCategoriesRepository(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     ICategoriesRepository
+		capacity int
+		ttl      time.Duration
+		wantErr  bool
+	}{
+		{name: "ok", repo: &countingRepository{}, capacity: 1, ttl: time.Second},
+		{name: "nil repo", repo: nil, capacity: 1, ttl: time.Second, wantErr: true},
+		{name: "zero capacity", repo: &countingRepository{}, capacity: 0, ttl: time.Second, wantErr: true},
+		{name: "zero ttl", repo: &countingRepository{}, capacity: 1, ttl: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCachedCategoriesRepository(tt.repo, tt.capacity, tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && got == nil {
+				t.Fatalf("expected repository instance")
+			}
+		})
+	}
+}
+
+func TestCachedCategoriesRepository_ReadThrough(t *testing.T) {
+	base := &countingRepository{categories: []entity.Category{{ID: 1, Name: "A"}}}
+	repo, err := NewCachedCategoriesRepository(base, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := repo.GetAllCategories(t.Context()); !reflect.DeepEqual(got, base.categories) {
+			t.Fatalf("expected %v, got %v", base.categories, got)
+		}
+		if got := repo.GetCategoryByID(t.Context(), 1); got.Name != "A" {
+			t.Fatalf("expected category A, got %v", got)
+		}
+	}
+
+	if base.getAll.Load() != 1 || base.getByID.Load() != 1 {
+		t.Fatalf("expected one backend call each, got getAll=%d getByID=%d", base.getAll.Load(), base.getByID.Load())
+	}
+
+	stats := repo.Stats()
+	if stats.Hits != 4 || stats.Misses != 2 || stats.Size != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCachedCategoriesRepository_ReturnsCopies(t *testing.T) {
+	base := &countingRepository{categories: []entity.Category{{ID: 1, Name: "A"}}}
+	repo, _ := NewCachedCategoriesRepository(base, 10, time.Minute)
+
+	got := repo.GetAllCategories(t.Context())
+	got[0].Name = "mutated"
+
+	if again := repo.GetAllCategories(t.Context()); again[0].Name != "A" {
+		t.Fatalf("expected cached list to be isolated from callers, got %v", again)
+	}
+}
+
+func TestCachedCategoriesRepository_TTL(t *testing.T) {
+	base := &countingRepository{categories: []entity.Category{{ID: 1, Name: "A"}}}
+	repo, _ := NewCachedCategoriesRepository(base, 10, time.Minute)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+
+	repo.GetCategoryByID(t.Context(), 1)
+	now = now.Add(59 * time.Second)
+	repo.GetCategoryByID(t.Context(), 1)
+	now = now.Add(time.Second)
+	repo.GetCategoryByID(t.Context(), 1)
+
+	if base.getByID.Load() != 2 {
+		t.Fatalf("expected entry to expire after ttl, got %d backend calls", base.getByID.Load())
+	}
+}
+
+func TestCachedCategoriesRepository_LRUEviction(t *testing.T) {
+	base := &countingRepository{categories: []entity.Category{{ID: 1}, {ID: 2}, {ID: 3}}}
+	repo, _ := NewCachedCategoriesRepository(base, 2, time.Minute)
+
+	repo.GetCategoryByID(t.Context(), 1)
+	repo.GetCategoryByID(t.Context(), 2)
+	repo.GetCategoryByID(t.Context(), 1)
+	repo.GetCategoryByID(t.Context(), 3)
+
+	if stats := repo.Stats(); stats.Evictions != 1 || stats.Size != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	calls := base.getByID.Load()
+	repo.GetCategoryByID(t.Context(), 1)
+	if base.getByID.Load() != calls {
+		t.Fatalf("expected recently used entry to survive eviction")
+	}
+	repo.GetCategoryByID(t.Context(), 2)
+	if base.getByID.Load() != calls+1 {
+		t.Fatalf("expected least recently used entry to be evicted")
+	}
+}
+
+func TestCachedCategoriesRepository_WritesInvalidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		write        func(r *CachedCategoriesRepository)
+		want         []entity.Category
+		wantReloadID bool
+	}{
+		{
+			name: "insert",
+			write: func(r *CachedCategoriesRepository) {
+				r.InsertCategory(t.Context(), entity.Category{ID: 2, Name: "B"})
+			},
+			want: []entity.Category{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}},
+		},
+		{
+			name: "update",
+			write: func(r *CachedCategoriesRepository) {
+				_, _ = r.UpdateCategory(t.Context(), entity.Category{ID: 1, Name: "A2"})
+			},
+			want:         []entity.Category{{ID: 1, Name: "A2"}},
+			wantReloadID: true,
+		},
+		{
+			name: "delete",
+			write: func(r *CachedCategoriesRepository) {
+				_, _ = r.DeleteCategory(t.Context(), 1)
+			},
+			want:         nil,
+			wantReloadID: true,
+		},
+		{
+			name: "reorder",
+			write: func(r *CachedCategoriesRepository) {
+				_, _ = r.ReorderCategories(t.Context(), []int64{1})
+			},
+			want:         []entity.Category{{ID: 1, Name: "A", Position: 1}},
+			wantReloadID: true,
+		},
+		{
+			name: "set image",
+			write: func(r *CachedCategoriesRepository) {
+				_, _ = r.SetCategoryImage(t.Context(), 1, &entity.CategoryImage{Checksum: "abc"})
+			},
+			want:         []entity.Category{{ID: 1, Name: "A", Image: &entity.CategoryImage{Checksum: "abc"}}},
+			wantReloadID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &countingRepository{categories: []entity.Category{{ID: 1, Name: "A"}}}
+			repo, _ := NewCachedCategoriesRepository(base, 10, time.Minute)
+
+			repo.GetAllCategories(t.Context())
+			repo.GetCategoryByID(t.Context(), 1)
+			tt.write(repo)
+
+			if got := repo.GetAllCategories(t.Context()); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			if base.getAll.Load() != 2 || base.getByID.Load() != 1 {
+				t.Fatalf("expected list to be reloaded once, got getAll=%d", base.getAll.Load())
+			}
+
+			var wantByID entity.Category
+			for _, cat := range tt.want {
+				if cat.ID == 1 {
+					wantByID = cat
+				}
+			}
+			if got := repo.GetCategoryByID(t.Context(), 1); !reflect.DeepEqual(got, wantByID) {
+				t.Fatalf("expected %v, got %v", wantByID, got)
+			}
+			wantCalls := int64(1)
+			if tt.wantReloadID {
+				wantCalls = 2
+			}
+			if base.getByID.Load() != wantCalls {
+				t.Fatalf("expected %d backend lookups, got %d", wantCalls, base.getByID.Load())
+			}
+		})
+	}
+}
+
+func TestCachedCategoriesRepository_SlugLookups(t *testing.T) {
+	base := &countingRepository{categories: []entity.Category{{ID: 1, Name: "A", Slug: "a"}}}
+	repo, _ := NewCachedCategoriesRepository(base, 10, time.Minute)
+
+	if got, _ := repo.GetCategoryBySlug(t.Context(), "a"); got.ID != 1 {
+		t.Fatalf("expected category 1, got %v", got)
+	}
+	if got, _ := repo.GetCategoryBySlug(t.Context(), "b"); got.ID != 0 {
+		t.Fatalf("expected no category, got %v", got)
+	}
+	repo.GetCategoryBySlug(t.Context(), "a")
+	repo.GetCategoryBySlug(t.Context(), "b")
+	if base.getBySlug.Load() != 2 {
+		t.Fatalf("expected slug lookups to be cached, got %d backend lookups", base.getBySlug.Load())
+	}
+
+	// Any write may claim a slug, so even a miss for an unrelated slug must be reloaded.
+	repo.InsertCategory(t.Context(), entity.Category{ID: 2, Name: "B", Slug: "b"})
+
+	if got, _ := repo.GetCategoryBySlug(t.Context(), "b"); got.ID != 2 {
+		t.Fatalf("expected category 2 after insert, got %v", got)
+	}
+	repo.GetCategoryBySlug(t.Context(), "a")
+	if base.getBySlug.Load() != 4 {
+		t.Fatalf("expected every slug lookup to be invalidated, got %d backend lookups", base.getBySlug.Load())
+	}
+}
+
+func TestCachedCategoriesRepository_Singleflight(t *testing.T) {
+	base := &countingRepository{
+		categories: []entity.Category{{ID: 1, Name: "A"}},
+		block:      make(chan struct{}),
+	}
+	repo, _ := NewCachedCategoriesRepository(base, 10, time.Minute)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			repo.GetAllCategories(t.Context())
+		}()
+	}
+
+	for repo.Stats().Misses < callers {
+		time.Sleep(time.Millisecond)
+	}
+	close(base.block)
+	wg.Wait()
+
+	if base.getAll.Load() != 1 {
+		t.Fatalf("expected concurrent misses to share one backend call, got %d", base.getAll.Load())
+	}
+}
+
+func TestCachedCategoriesRepository_Purge(t *testing.T) {
+	base := &countingRepository{categories: []entity.Category{{ID: 1, Name: "A"}}}
+	repo, _ := NewCachedCategoriesRepository(base, 10, time.Minute)
+
+	repo.GetAllCategories(t.Context())
+	repo.Purge()
+	repo.GetAllCategories(t.Context())
+
+	if base.getAll.Load() != 2 {
+		t.Fatalf("expected purge to drop cached entries")
+	}
+	if stats := repo.Stats(); stats.Misses != 2 {
+		t.Fatalf("expected stats to survive purge, got %+v", stats)
+	}
+}
+
+func TestCachedCategoriesRepository_TenantIsolation(t *testing.T) {
+	base, _ := NewCategoriesRepository()
+	repo, _ := NewCachedCategoriesRepository(base, 10, time.Minute)
+	acme := tenant.WithID(t.Context(), "acme")
+
+	// Warm the default tenant's entries, then write the same ID and slug in acme.
+	defaultList := repo.GetAllCategories(t.Context())
+	defaultCategory := repo.GetCategoryByID(t.Context(), 1)
+	defaultBySlug, _ := repo.GetCategoryBySlug(t.Context(), defaultCategory.Slug)
+	repo.InsertCategory(acme, entity.Category{ID: 1, Name: "Acme", Slug: defaultCategory.Slug})
+
+	if got := repo.GetCategoryByID(acme, 1); got.Name != "Acme" {
+		t.Errorf("expected acme's category, got %v", got)
+	}
+	if got, _ := repo.GetCategoryBySlug(acme, defaultCategory.Slug); got.Name != "Acme" {
+		t.Errorf("expected acme's slug lookup, got %v", got)
+	}
+	if got := repo.GetAllCategories(acme); len(got) != 1 {
+		t.Errorf("expected acme to list only its own category, got %v", got)
+	}
+
+	misses := repo.Stats().Misses
+	if got := repo.GetAllCategories(t.Context()); !reflect.DeepEqual(got, defaultList) {
+		t.Errorf("expected the default list, got %v", got)
+	}
+	if got := repo.GetCategoryByID(t.Context(), 1); !reflect.DeepEqual(got, defaultCategory) {
+		t.Errorf("expected the default category, got %v", got)
+	}
+	if got, _ := repo.GetCategoryBySlug(t.Context(), defaultCategory.Slug); !reflect.DeepEqual(got, defaultBySlug) {
+		t.Errorf("expected the default slug lookup, got %v", got)
+	}
+	if got := repo.Stats().Misses; got != misses {
+		t.Errorf("expected acme's write not to invalidate the default tenant's entries, got %d new misses", got-misses)
+	}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/search"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// ISearchableRepository is implemented by repositories that can answer full-text queries over categories.
+type ISearchableRepository interface {
+	ICategoriesRepository
+	SearchCategories(ctx context.Context, query string, limit int) []entity.SearchResult
+}
+
+// IndexedCategoriesRepository decorates an ICategoriesRepository with a full-text search index over category
+// names and descriptions, including their translations. Every tenant has an index of its own, built from the
+// wrapped repository on the tenant's first use and updated on every write made through the decorator.
+type IndexedCategoriesRepository struct {
+	repo ICategoriesRepository
+
+	mu      sync.Mutex
+	indexes map[string]*tenantIndex
+}
+
+// tenantIndex is the search index of one tenant.
+type tenantIndex struct {
+	*search.Index
+
+	// mu serializes the writes of the tenant together with their indexing, so the index applies them in the order
+	// the wrapped repository did and never keeps an older version or a deleted category.
+	mu sync.Mutex
+}
+
+// NewIndexedCategoriesRepository wraps repo and indexes the categories the default tenant currently holds.
+func NewIndexedCategoriesRepository(repo ICategoriesRepository) (*IndexedCategoriesRepository, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("repository must not be nil")
+	}
+
+	r := &IndexedCategoriesRepository{
+		repo:    repo,
+		indexes: make(map[string]*tenantIndex),
+	}
+	r.index(context.Background())
+
+	return r, nil
+}
+
+// GetAllCategories delegates to the wrapped repository.
+func (r *IndexedCategoriesRepository) GetAllCategories(ctx context.Context) []entity.Category {
+	return r.repo.GetAllCategories(ctx)
+}
+
+// GetCategoryByID delegates to the wrapped repository.
+func (r *IndexedCategoriesRepository) GetCategoryByID(ctx context.Context, categoryID int64) entity.Category {
+	return r.repo.GetCategoryByID(ctx, categoryID)
+}
+
+// GetCategoryBySlug delegates to the wrapped repository.
+func (r *IndexedCategoriesRepository) GetCategoryBySlug(ctx context.Context, slug string) (entity.Category, bool) {
+	return r.repo.GetCategoryBySlug(ctx, slug)
+}
+
+// InsertCategory stores the category in the wrapped repository and indexes it.
+func (r *IndexedCategoriesRepository) InsertCategory(ctx context.Context, parameter entity.Category) entity.Category {
+	index := r.lock(ctx)
+	defer index.mu.Unlock()
+
+	cat := r.repo.InsertCategory(ctx, parameter)
+	index.Add(cat)
+	return cat
+}
+
+// UpdateCategory updates the category in the wrapped repository and re-indexes it.
+func (r *IndexedCategoriesRepository) UpdateCategory(ctx context.Context, parameter entity.Category) (entity.Category, error) {
+	index := r.lock(ctx)
+	defer index.mu.Unlock()
+
+	cat, err := r.repo.UpdateCategory(ctx, parameter)
+	if err != nil {
+		return cat, err
+	}
+
+	index.Add(cat)
+	return cat, nil
+}
+
+// UpsertTranslation stores the translation in the wrapped repository and re-indexes the category so it can be
+// found by its translated name and description.
+func (r *IndexedCategoriesRepository) UpsertTranslation(ctx context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	index := r.lock(ctx)
+	defer index.mu.Unlock()
+
+	cat, err := r.repo.UpsertTranslation(ctx, categoryID, locale, translation)
+	if err != nil {
+		return cat, err
+	}
+
+	index.Add(cat)
+	return cat, nil
+}
+
+// DeleteCategory removes the category from the wrapped repository and from the index.
+func (r *IndexedCategoriesRepository) DeleteCategory(ctx context.Context, categoryID int64) (int64, error) {
+	index := r.lock(ctx)
+	defer index.mu.Unlock()
+
+	id, err := r.repo.DeleteCategory(ctx, categoryID)
+	if err != nil {
+		return id, err
+	}
+
+	index.Remove(id)
+	return id, nil
+}
+
+// ReorderCategories reorders the categories in the wrapped repository and re-indexes them, so search results carry
+// their new positions.
+func (r *IndexedCategoriesRepository) ReorderCategories(ctx context.Context, ids []int64) ([]entity.Category, error) {
+	index := r.lock(ctx)
+	defer index.mu.Unlock()
+
+	categories, err := r.repo.ReorderCategories(ctx, ids)
+	if err != nil {
+		return categories, err
+	}
+
+	for _, category := range categories {
+		index.Add(category)
+	}
+	return categories, nil
+}
+
+// SetCategoryImage stores the image in the wrapped repository and re-indexes the category, so search results carry it.
+func (r *IndexedCategoriesRepository) SetCategoryImage(ctx context.Context, categoryID int64, image *entity.CategoryImage) (entity.Category, error) {
+	index := r.lock(ctx)
+	defer index.mu.Unlock()
+
+	cat, err := r.repo.SetCategoryImage(ctx, categoryID, image)
+	if err != nil {
+		return cat, err
+	}
+
+	index.Add(cat)
+	return cat, nil
+}
+
+// SearchCategories returns up to limit categories of the tenant matching query, most relevant first.
+func (r *IndexedCategoriesRepository) SearchCategories(ctx context.Context, query string, limit int) []entity.SearchResult {
+	return r.index(ctx).Search(query, limit)
+}
+
+// index returns the index of the tenant carried by ctx, building it from the wrapped repository on first use.
+func (r *IndexedCategoriesRepository) index(ctx context.Context) *tenantIndex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	index, ok := r.indexes[tenantID]
+	if !ok {
+		index = &tenantIndex{Index: search.NewIndex()}
+		index.Reset(r.repo.GetAllCategories(ctx))
+		r.indexes[tenantID] = index
+	}
+
+	return index
+}
+
+// lock returns the index of the tenant carried by ctx with its mu held, so a write can be applied to the wrapped
+// repository and to the index without another write of the tenant in between. The caller must unlock index.mu.
+func (r *IndexedCategoriesRepository) lock(ctx context.Context) *tenantIndex {
+	index := r.index(ctx)
+	index.mu.Lock()
+	return index
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+func searchIDs(t *testing.T, repo *IndexedCategoriesRepository, query string) []int64 {
+	t.Helper()
+	ids := []int64{}
+	for _, result := range repo.SearchCategories(t.Context(), query, 0) {
+		ids = append(ids, result.Category.ID)
+	}
+	return ids
+}
+
+func TestNewIndexedCategoriesRepository(t *testing.T) {
+	if _, err := NewIndexedCategoriesRepository(nil); err == nil {
+		t.Fatalf("expected error for nil repository")
+	}
+}
+
+func TestIndexedCategoriesRepository_KeepsIndexInSync(t *testing.T) {
+	withCategories(t, []entity.Category{
+		{ID: 1, Name: "Elektronik", Description: "Kategori Elektronik"},
+		{ID: 2, Name: "Handphone", Description: "Kategori Handphone"},
+	}, func(base *CategoriesRepository) {
+		repo, err := NewIndexedCategoriesRepository(base)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		steps := []struct {
+			name  string
+			write func()
+			query string
+			want  []int64
+		}{
+			{name: "initial", write: func() {}, query: "hanphone", want: []int64{2}},
+			{
+				name: "insert",
+				write: func() {
+					repo.InsertCategory(t.Context(), entity.Category{Name: "Komputer", Description: "Kategori Komputer"})
+				},
+				query: "komputr",
+				want:  []int64{3},
+			},
+			{
+				name: "update",
+				write: func() {
+					if _, err := repo.UpdateCategory(t.Context(), entity.Category{ID: 2, Name: "Telepon", Description: "Kategori Telepon"}); err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				},
+				query: "handphone",
+				want:  []int64{},
+			},
+			{
+				name: "translate",
+				write: func() {
+					if _, err := repo.UpsertTranslation(t.Context(), 2, "en", entity.Translation{Name: "Mobile Phone"}); err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				},
+				query: "mobile",
+				want:  []int64{2},
+			},
+			{
+				name: "delete",
+				write: func() {
+					if _, err := repo.DeleteCategory(t.Context(), 1); err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				},
+				query: "elektronik",
+				want:  []int64{},
+			},
+			{name: "remaining", write: func() {}, query: "kategori", want: []int64{2, 3}},
+		}
+
+		for _, step := range steps {
+			step.write()
+			if got := searchIDs(t, repo, step.query); !reflect.DeepEqual(got, step.want) {
+				t.Fatalf("%s: expected %v for %q, got %v", step.name, step.want, step.query, got)
+			}
+		}
+
+		if _, err := repo.UpdateCategory(t.Context(), entity.Category{ID: 42, Name: "Hantu"}); !errors.Is(err, entity.ErrCategoryNotFound) {
+			t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+		}
+		if got := searchIDs(t, repo, "hantu"); len(got) != 0 {
+			t.Fatalf("expected failed update not to be indexed, got %v", got)
+		}
+	})
+}
+
+func TestIndexedCategoriesRepository_ReorderUpdatesPositions(t *testing.T) {
+	withCategories(t, ranked(1, 1024, 2, 2048), func(base *CategoriesRepository) {
+		repo, err := NewIndexedCategoriesRepository(base)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := repo.UpdateCategory(t.Context(), entity.Category{ID: 2, Name: "Handphone"}); err != nil {
+			t.Fatalf("unexpected update error: %v", err)
+		}
+
+		if _, err := repo.ReorderCategories(t.Context(), []int64{2}); err != nil {
+			t.Fatalf("unexpected reorder error: %v", err)
+		}
+
+		results := repo.SearchCategories(t.Context(), "handphone", 0)
+		if len(results) != 1 || results[0].Category.Position != 512 {
+			t.Fatalf("expected the search result to carry the new position, got %+v", results)
+		}
+	})
+}
+
+func TestIndexedCategoriesRepository_TenantIsolation(t *testing.T) {
+	base, _ := NewCategoriesRepository()
+	repo, err := NewIndexedCategoriesRepository(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	acme := tenant.WithID(t.Context(), "acme")
+
+	repo.InsertCategory(acme, entity.Category{Name: "Komputer Acme"})
+
+	if got := repo.SearchCategories(acme, "komputer", 0); len(got) != 1 || got[0].Category.Name != "Komputer Acme" {
+		t.Errorf("expected only acme's category, got %v", got)
+	}
+	for _, result := range repo.SearchCategories(t.Context(), "acme", 0) {
+		t.Errorf("expected acme's category not to be found by the default tenant, got %v", result)
+	}
+	if got := repo.SearchCategories(tenant.WithID(t.Context(), "globex"), "kategori", 0); len(got) != 0 {
+		t.Errorf("expected a new tenant to find nothing, got %v", got)
+	}
+}
+
+// pausingRepository is a CategoriesRepository that, after updating a category, reports the update on updated and
+// waits before returning, so writes of other goroutines can run between the update and its indexing.
+type pausingRepository struct {
+	*CategoriesRepository
+	updated chan struct{}
+}
+
+func (r *pausingRepository) UpdateCategory(ctx context.Context, parameter entity.Category) (entity.Category, error) {
+	cat, err := r.CategoriesRepository.UpdateCategory(ctx, parameter)
+	close(r.updated)
+	time.Sleep(20 * time.Millisecond)
+	return cat, err
+}
+
+func TestIndexedCategoriesRepository_ConcurrentUpdateAndDelete(t *testing.T) {
+	t.Run("delete during update", func(t *testing.T) {
+		withCategories(t, []entity.Category{{ID: 1, Name: "Elektronik"}}, func(base *CategoriesRepository) {
+			pausing := &pausingRepository{CategoriesRepository: base, updated: make(chan struct{})}
+			repo, _ := NewIndexedCategoriesRepository(pausing)
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = repo.UpdateCategory(t.Context(), entity.Category{ID: 1, Name: "Komputer"})
+			}()
+
+			<-pausing.updated
+			if _, err := repo.DeleteCategory(t.Context(), 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			wg.Wait()
+
+			if got := searchIDs(t, repo, "komputer"); len(got) != 0 {
+				t.Fatalf("expected the deleted category not to be found, got %v", got)
+			}
+		})
+	})
+
+	t.Run("racing writes", func(t *testing.T) {
+		base, _ := NewCategoriesRepository()
+		repo, _ := NewIndexedCategoriesRepository(base)
+
+		for i := 0; i < 50; i++ {
+			cat := repo.InsertCategory(t.Context(), entity.Category{Name: "Sementara"})
+
+			var wg sync.WaitGroup
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				_, _ = repo.UpdateCategory(t.Context(), entity.Category{ID: cat.ID, Name: "Sementara Baru"})
+			}()
+			go func() {
+				defer wg.Done()
+				_, _ = repo.UpdateCategory(t.Context(), entity.Category{ID: cat.ID, Name: "Sementara Lama"})
+			}()
+			go func() {
+				defer wg.Done()
+				_, _ = repo.DeleteCategory(t.Context(), cat.ID)
+			}()
+			wg.Wait()
+		}
+
+		if got := searchIDs(t, repo, "sementara"); len(got) != 0 {
+			t.Fatalf("expected every deleted category to be gone from the index, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"slices"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+// positionGap is the distance between the positions of neighbouring categories after they are spread out, leaving
+// room to move categories between them without touching any other category.
+const positionGap int64 = 1024
+
+// reorder implements ReorderCategories within the partition.
+func (p *partition) reorder(ids []int64) ([]entity.Category, error) {
+	byID := make(map[int64]entity.Category, len(p.categories))
+	for _, category := range p.categories {
+		byID[category.ID] = category
+	}
+
+	ordered := make([]entity.Category, 0, len(p.categories))
+	listed := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		category, ok := byID[id]
+		if !ok {
+			return nil, entity.ErrCategoryNotFound
+		}
+		if listed[id] {
+			return nil, entity.ErrInvalidOrder
+		}
+		listed[id] = true
+		ordered = append(ordered, category)
+	}
+	for _, category := range p.categories {
+		if !listed[category.ID] {
+			ordered = append(ordered, category)
+		}
+	}
+
+	rank(ordered)
+	p.categories = ordered
+
+	return slices.Clone(ordered), nil
+}
+
+// nextPosition returns the position of a category placed after every category of the partition, whose categories
+// are kept sorted by position.
+func (p *partition) nextPosition() int64 {
+	if len(p.categories) == 0 {
+		return positionGap
+	}
+	return p.categories[len(p.categories)-1].Position + positionGap
+}
+
+// rank gives categories strictly increasing positions in slice order while changing as few positions as possible:
+// the longest run of categories already in increasing order keeps its positions, and the others are spread evenly
+// over the gap between their kept neighbours. Only when a gap is too narrow are all positions spread out again,
+// positionGap apart.
+func rank(categories []entity.Category) {
+	keep := increasingRun(categories)
+
+	var lower int64
+	for i := 0; i < len(categories); {
+		if keep[i] {
+			lower = categories[i].Position
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(categories) && !keep[j] {
+			j++
+		}
+
+		slots := int64(j - i + 1)
+		upper := lower + slots*positionGap
+		if j < len(categories) {
+			upper = categories[j].Position
+		}
+
+		step := (upper - lower) / slots
+		if step <= 0 {
+			spread(categories)
+			return
+		}
+		for k := i; k < j; k++ {
+			lower += step
+			categories[k].Position = lower
+		}
+		i = j
+	}
+}
+
+// spread assigns the positions positionGap, 2*positionGap, ... to categories in slice order.
+func spread(categories []entity.Category) {
+	for i := range categories {
+		categories[i].Position = int64(i+1) * positionGap
+	}
+}
+
+// increasingRun marks the categories forming a longest subsequence with strictly increasing, positive positions.
+func increasingRun(categories []entity.Category) []bool {
+	// tails[n] is the index of the category ending the best subsequence of length n+1 found so far, and prev links
+	// every category to its predecessor in the subsequence it ends.
+	tails := make([]int, 0, len(categories))
+	prev := make([]int, len(categories))
+
+	for i, category := range categories {
+		if category.Position <= 0 {
+			prev[i] = -1
+			continue
+		}
+
+		n, _ := slices.BinarySearchFunc(tails, category.Position, func(tail int, position int64) int {
+			switch {
+			case categories[tail].Position < position:
+				return -1
+			case categories[tail].Position > position:
+				return 1
+			}
+			return 0
+		})
+
+		prev[i] = -1
+		if n > 0 {
+			prev[i] = tails[n-1]
+		}
+		if n == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[n] = i
+		}
+	}
+
+	keep := make([]bool, len(categories))
+	if len(tails) == 0 {
+		return keep
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		keep[i] = true
+	}
+
+	return keep
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+// ranked returns categories with the given IDs and positions, pairwise.
+func ranked(pairs ...int64) []entity.Category {
+	categories := make([]entity.Category, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		categories = append(categories, entity.Category{ID: pairs[i], Position: pairs[i+1]})
+	}
+	return categories
+}
+
+// positions returns the ID and position of every category, pairwise, in slice order.
+func positions(categories []entity.Category) []int64 {
+	pairs := make([]int64, 0, 2*len(categories))
+	for _, category := range categories {
+		pairs = append(pairs, category.ID, category.Position)
+	}
+	return pairs
+}
+
+func TestCategoriesRepository_ReorderCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []entity.Category
+		ids        []int64
+		want       []int64
+		wantErr    error
+	}{
+		{
+			name:       "move last to front",
+			categories: ranked(1, 1024, 2, 2048, 3, 3072),
+			ids:        []int64{3},
+			want:       []int64{3, 512, 1, 1024, 2, 2048},
+		},
+		{
+			name:       "move into a gap",
+			categories: ranked(1, 1024, 2, 2048, 3, 3072),
+			ids:        []int64{1, 3, 2},
+			want:       []int64{1, 1024, 3, 1536, 2, 2048},
+		},
+		{
+			name:       "move first to end",
+			categories: ranked(1, 1024, 2, 2048, 3, 3072),
+			ids:        []int64{2, 3, 1},
+			want:       []int64{2, 2048, 3, 3072, 1, 4096},
+		},
+		{
+			name:       "unchanged order",
+			categories: ranked(1, 1024, 2, 2048, 3, 3072),
+			ids:        []int64{1, 2, 3},
+			want:       []int64{1, 1024, 2, 2048, 3, 3072},
+		},
+		{
+			name:       "full reversal",
+			categories: ranked(1, 1024, 2, 2048, 3, 3072),
+			ids:        []int64{3, 2, 1},
+			want:       []int64{3, 341, 2, 682, 1, 1024},
+		},
+		{
+			name:       "gap exhausted",
+			categories: ranked(1, 1, 2, 2, 3, 3),
+			ids:        []int64{3},
+			want:       []int64{3, 1024, 1, 2048, 2, 3072},
+		},
+		{
+			name:       "unranked categories",
+			categories: ranked(1, 0, 2, 0),
+			ids:        []int64{2},
+			want:       []int64{2, 1024, 1, 2048},
+		},
+		{
+			name:       "unknown id",
+			categories: ranked(1, 1024, 2, 2048),
+			ids:        []int64{2, 9},
+			want:       []int64{1, 1024, 2, 2048},
+			wantErr:    entity.ErrCategoryNotFound,
+		},
+		{
+			name:       "repeated id",
+			categories: ranked(1, 1024, 2, 2048),
+			ids:        []int64{2, 2},
+			want:       []int64{1, 1024, 2, 2048},
+			wantErr:    entity.ErrInvalidOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withCategories(t, tt.categories, func(repo *CategoriesRepository) {
+				got, err := repo.ReorderCategories(t.Context(), tt.ids)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if err == nil && !reflect.DeepEqual(positions(got), tt.want) {
+					t.Fatalf("expected %v, got %v", tt.want, positions(got))
+				}
+				if list := positions(repo.GetAllCategories(t.Context())); !reflect.DeepEqual(list, tt.want) {
+					t.Fatalf("expected list %v, got %v", tt.want, list)
+				}
+			})
+		})
+	}
+}
+
+func TestCategoriesRepository_InsertAfterReorder(t *testing.T) {
+	withCategories(t, ranked(1, 1024, 2, 2048), func(repo *CategoriesRepository) {
+		if _, err := repo.ReorderCategories(t.Context(), []int64{2}); err != nil {
+			t.Fatalf("unexpected reorder error: %v", err)
+		}
+
+		created := repo.InsertCategory(t.Context(), entity.Category{Name: "Baru"})
+		if created.Position != 2048 {
+			t.Fatalf("expected the new category after the last one, got position %d", created.Position)
+		}
+
+		want := []int64{2, 512, 1, 1024, 3, 2048}
+		if list := positions(repo.GetAllCategories(t.Context())); !reflect.DeepEqual(list, want) {
+			t.Fatalf("expected list %v, got %v", want, list)
+		}
+	})
+}
+
+func TestIncreasingRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []entity.Category
+		want       []bool
+	}{
+		{name: "empty", categories: nil, want: []bool{}},
+		{name: "sorted", categories: ranked(1, 1, 2, 2, 3, 3), want: []bool{true, true, true}},
+		{name: "one moved", categories: ranked(3, 3, 1, 1, 2, 2), want: []bool{false, true, true}},
+		{name: "equal positions", categories: ranked(1, 5, 2, 5), want: []bool{false, true}},
+		{name: "unranked", categories: ranked(1, 0, 2, 7), want: []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := increasingRun(tt.categories); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,61 +1,224 @@
 package repository
 
 import (
-	"fmt"
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/utils"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
 )
 
-// ICategoriesRepository defines an abstraction for performing CRUD operations on Category entities.
+// ICategoriesRepository defines an abstraction for performing CRUD operations on Category entities. Every
+// operation is scoped to the tenant carried by ctx (see tenant.FromContext): a tenant never sees, changes or
+// collides with the categories of another, and category IDs are sequenced per tenant.
 type ICategoriesRepository interface {
-	GetAllCategories() []entity.Category
-	GetCategoryByID(categoryID int64) entity.Category
-	InsertCategory(parameter entity.Category) entity.Category
-	UpdateCategory(parameter entity.Category) (entity.Category, error)
-	DeleteCategory(categoryID int64) (int64, error)
+	GetAllCategories(ctx context.Context) []entity.Category
+	GetCategoryByID(ctx context.Context, categoryID int64) entity.Category
+	GetCategoryBySlug(ctx context.Context, slug string) (entity.Category, bool)
+	InsertCategory(ctx context.Context, parameter entity.Category) entity.Category
+	UpdateCategory(ctx context.Context, parameter entity.Category) (entity.Category, error)
+	DeleteCategory(ctx context.Context, categoryID int64) (int64, error)
+	UpsertTranslation(ctx context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error)
+	ReorderCategories(ctx context.Context, ids []int64) ([]entity.Category, error)
+	SetCategoryImage(ctx context.Context, categoryID int64, image *entity.CategoryImage) (entity.Category, error)
 }
 
-// categoriesList holds a predefined list of categories represented as entity.Category objects for initial data setup.
-var categoriesList = []entity.Category{
+// defaultCategories holds the predefined categories the default tenant starts with. Other tenants start empty.
+var defaultCategories = []entity.Category{
 	{
 		ID:          1,
 		Name:        "Elektronik",
+		Slug:        "elektronik",
 		Description: "Kategori Elektronik",
+		Position:    1 * positionGap,
 	},
 	{
 		ID:          2,
 		Name:        "Komputer",
+		Slug:        "komputer",
 		Description: " Kategori Komputer",
+		Position:    2 * positionGap,
 	},
 	{
 		ID:          3,
 		Name:        "Handphone",
+		Slug:        "handphone",
 		Description: "Kategori Handphone",
+		Position:    3 * positionGap,
 	},
 }
 
-// CategoriesRepository manages CRUD operations for Category entity.
-type CategoriesRepository struct{}
+// fallbackSlug is used when a category name contains no characters that can appear in a slug.
+const fallbackSlug = "kategori"
+
+// reservedSlugs are the path segments of the static routes under /categories. A category with one of them as slug
+// could never be looked up by it, so they are prefixed with fallbackSlug like slugs made of digits.
+var reservedSlugs = map[string]bool{
+	"docs":    true,
+	"health":  true,
+	"reorder": true,
+	"search":  true,
+	"stream":  true,
+}
+
+// partition holds the categories of one tenant.
+type partition struct {
+	// categories is sorted by position, so it lists the categories in their display order.
+	categories []entity.Category
+
+	// slugHistory maps slugs that categories no longer use to the ID of the category that last used them,
+	// so links to a renamed category keep resolving.
+	slugHistory map[string]int64
+}
+
+// newPartition returns a partition holding a copy of categories.
+func newPartition(categories []entity.Category) *partition {
+	return &partition{
+		categories:  slices.Clone(categories),
+		slugHistory: map[string]int64{},
+	}
+}
+
+// CategoriesRepository manages CRUD operations for Category entity, keeping the categories of every tenant in a
+// partition of their own. It is safe for concurrent use.
+type CategoriesRepository struct {
+	mu         sync.RWMutex
+	partitions map[string]*partition
+}
 
 // NewCategoriesRepository initializes and returns a new instance of CategoriesRepository or an error if creation fails.
+// The default tenant starts with the predefined categories.
 func NewCategoriesRepository() (*CategoriesRepository, error) {
-	r := &CategoriesRepository{}
+	r := &CategoriesRepository{
+		partitions: map[string]*partition{
+			tenant.DefaultID: newPartition(defaultCategories),
+		},
+	}
 	return r, nil
 }
 
-// GetAllCategories retrieves all categories from the repository and returns them as a slice of entity.Category.
-func (r *CategoriesRepository) GetAllCategories() []entity.Category {
-	categories := categoriesList
-	return categories
+// GetAllCategories retrieves all categories of the tenant, ordered by position, and returns them as a slice of
+// entity.Category. The slice is a copy, so callers may modify it.
+func (r *CategoriesRepository) GetAllCategories(ctx context.Context) []entity.Category {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return nil
+	}
+
+	return slices.Clone(p.categories)
+}
+
+// GetCategoryByID retrieves a category of the tenant based on the provided category ID. Returns an empty category if not found.
+func (r *CategoriesRepository) GetCategoryByID(ctx context.Context, categoryID int64) entity.Category {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return entity.Category{}
+	}
+
+	return p.getByID(categoryID)
+}
+
+// GetCategoryBySlug retrieves a category of the tenant by its current slug or, failing that, by a slug it used before.
+// moved reports that slug is a previous slug, so callers can redirect to the current one.
+// Returns an empty category if no category ever used the slug.
+func (r *CategoriesRepository) GetCategoryBySlug(ctx context.Context, slug string) (category entity.Category, moved bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return entity.Category{}, false
+	}
+
+	return p.getBySlug(slug)
+}
+
+// InsertCategory adds a new category after all other categories of the tenant. It assigns the tenant's next ID if the
+// given ID is 0 and returns the category. The slug is generated from the given slug, or from the name when none is given, and made
+// unique within the tenant with a numeric suffix.
+func (r *CategoriesRepository) InsertCategory(ctx context.Context, parameter entity.Category) entity.Category {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.partition(ctx).insert(parameter)
+}
+
+// UpdateCategory updates an existing category of the tenant with new data or returns an error if the category is not found.
+// A given slug replaces the current one; without one, the slug is regenerated only when the name changes.
+// The replaced slug keeps resolving to the category through GetCategoryBySlug. Translations and the position are
+// kept as they are, and so are the attributes unless new ones are given. The image is only changed by SetCategoryImage.
+func (r *CategoriesRepository) UpdateCategory(ctx context.Context, parameter entity.Category) (entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.partition(ctx).update(parameter)
+}
+
+// UpsertTranslation stores the category's name and description in locale, replacing any existing translation for it.
+// Returns the updated category or an error if the category is not found.
+func (r *CategoriesRepository) UpsertTranslation(ctx context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.partition(ctx).upsertTranslation(categoryID, locale, translation)
+}
+
+// DeleteCategory removes a category of the tenant by its ID and returns the ID of the deleted category or an error if not found.
+func (r *CategoriesRepository) DeleteCategory(ctx context.Context, categoryID int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.partition(ctx).delete(categoryID)
+}
+
+// ReorderCategories moves the categories with the given IDs, in that order, before all other categories of the
+// tenant, which keep their relative order. Positions are only rewritten where the new order requires it. Returns every
+// category of the tenant in the new order, ErrCategoryNotFound if an ID is unknown or ErrInvalidOrder if an ID is
+// repeated; the order is left unchanged on error.
+func (r *CategoriesRepository) ReorderCategories(ctx context.Context, ids []int64) ([]entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.partition(ctx).reorder(ids)
+}
+
+// SetCategoryImage replaces the image of a category of the tenant, or removes it when image is nil. Returns the updated
+// category or ErrCategoryNotFound.
+func (r *CategoriesRepository) SetCategoryImage(ctx context.Context, categoryID int64, image *entity.CategoryImage) (entity.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.partition(ctx).setImage(categoryID, image)
 }
 
-// GetCategoryByID retrieves a category from the list based on the provided category ID. Returns an empty category if not found.
-func (r *CategoriesRepository) GetCategoryByID(categoryID int64) entity.Category {
-	categories := categoriesList
+// partition returns the partition of the tenant carried by ctx, creating an empty one on its first write.
+// The caller must hold r.mu for writing.
+func (r *CategoriesRepository) partition(ctx context.Context) *partition {
+	tenantID := tenant.FromContext(ctx)
+
+	p, ok := r.partitions[tenantID]
+	if !ok {
+		p = newPartition(nil)
+		r.partitions[tenantID] = p
+	}
+
+	return p
+}
 
-	for _, category := range categories {
+// getByID returns the category with the given ID, or an empty category.
+func (p *partition) getByID(categoryID int64) entity.Category {
+	for _, category := range p.categories {
 		if category.ID == categoryID {
 			return category
 		}
@@ -64,53 +227,158 @@ func (r *CategoriesRepository) GetCategoryByID(categoryID int64) entity.Category
 	return entity.Category{}
 }
 
-// InsertCategory adds a new category to the categories list. It assigns a new ID if the given ID is 0 and returns the category.
-func (r *CategoriesRepository) InsertCategory(parameter entity.Category) entity.Category {
+// getBySlug returns the category currently using slug or, failing that, the category that used it last.
+func (p *partition) getBySlug(slug string) (entity.Category, bool) {
+	for _, category := range p.categories {
+		if category.Slug == slug {
+			return category, false
+		}
+	}
+
+	if categoryID, ok := p.slugHistory[slug]; ok {
+		if category := p.getByID(categoryID); category.ID != 0 {
+			return category, true
+		}
+	}
+
+	return entity.Category{}, false
+}
+
+// insert implements InsertCategory within the partition.
+func (p *partition) insert(parameter entity.Category) entity.Category {
 	var cat entity.Category
 	if parameter.ID == 0 {
-		cat.ID = int64(utils.GetMaxID(categoriesList)) + 1
+		cat.ID = utils.GetMaxID(p.categories) + 1
 	} else {
 		cat.ID = parameter.ID
 	}
 
 	cat.Name = parameter.Name
 	cat.Description = parameter.Description
+	cat.Slug = p.uniqueSlug(slugSource(parameter), cat.ID)
+	cat.Position = p.nextPosition()
+	cat.Attributes = maps.Clone(parameter.Attributes)
+	delete(p.slugHistory, cat.Slug)
 
-	categories := categoriesList
-	categories = append(categories, cat)
-	categoriesList = categories
+	p.categories = append(p.categories, cat)
 
 	return cat
 }
 
-// UpdateCategory updates an existing category with new data or returns an error if the category is not found.
-func (r *CategoriesRepository) UpdateCategory(parameter entity.Category) (entity.Category, error) {
+// update implements UpdateCategory within the partition.
+func (p *partition) update(parameter entity.Category) (entity.Category, error) {
 	var cat entity.Category
 	cat.ID = parameter.ID
 	cat.Name = parameter.Name
 	cat.Description = parameter.Description
 
-	categories := categoriesList
-	for i, category := range categories {
+	for i, category := range p.categories {
 		if category.ID == parameter.ID {
-			categories[i] = cat
+			cat.Translations = category.Translations
+			cat.Position = category.Position
+			cat.Attributes = category.Attributes
+			cat.Image = category.Image
+			if parameter.Attributes != nil {
+				cat.Attributes = maps.Clone(parameter.Attributes)
+			}
+			cat.Slug = category.Slug
+			if parameter.Slug != "" || parameter.Name != category.Name {
+				cat.Slug = p.uniqueSlug(slugSource(parameter), cat.ID)
+			}
+			if cat.Slug != category.Slug {
+				p.slugHistory[category.Slug] = cat.ID
+				delete(p.slugHistory, cat.Slug)
+			}
+
+			p.categories[i] = cat
 			return cat, nil
 		}
 	}
 
-	return entity.Category{}, fmt.Errorf(constants.ErrCategoryNotFound)
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+// upsertTranslation implements UpsertTranslation within the partition.
+func (p *partition) upsertTranslation(categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	for i, category := range p.categories {
+		if category.ID == categoryID {
+			// Copy the map so categories handed out earlier never see the change.
+			translations := maps.Clone(category.Translations)
+			if translations == nil {
+				translations = make(map[string]entity.Translation, 1)
+			}
+			translations[locale] = translation
+
+			category.Translations = translations
+			p.categories[i] = category
+			return category, nil
+		}
+	}
+
+	return entity.Category{}, entity.ErrCategoryNotFound
+}
+
+// setImage implements SetCategoryImage within the partition.
+func (p *partition) setImage(categoryID int64, image *entity.CategoryImage) (entity.Category, error) {
+	for i, category := range p.categories {
+		if category.ID == categoryID {
+			// Store a copy so the caller cannot change the image of the stored category.
+			category.Image = nil
+			if image != nil {
+				stored := *image
+				category.Image = &stored
+			}
+			p.categories[i] = category
+			return category, nil
+		}
+	}
+
+	return entity.Category{}, entity.ErrCategoryNotFound
 }
 
-// DeleteCategory removes a category by its ID and returns the ID of the deleted category or an error if not found.
-func (r *CategoriesRepository) DeleteCategory(categoryID int64) (int64, error) {
-	categories := categoriesList
-	for i, category := range categories {
+// delete implements DeleteCategory within the partition.
+func (p *partition) delete(categoryID int64) (int64, error) {
+	for i, category := range p.categories {
 		if category.ID == categoryID {
-			categories = append(categories[:i], categories[i+1:]...)
-			categoriesList = categories
+			p.categories = slices.Delete(p.categories, i, i+1)
 			return category.ID, nil
 		}
 	}
 
-	return 0, fmt.Errorf(constants.ErrCategoryNotFound)
+	return 0, entity.ErrCategoryNotFound
+}
+
+// slugSource returns the text a category's slug is generated from: its explicit slug, or its name.
+func slugSource(category entity.Category) string {
+	if category.Slug != "" {
+		return category.Slug
+	}
+	return category.Name
+}
+
+// uniqueSlug slugifies text and appends "-2", "-3", ... until no category of the partition other than categoryID
+// uses the slug. Slugs made of digits only are prefixed with fallbackSlug, as a path segment that is a number is
+// looked up as an ID, and so are reservedSlugs, which are served by other routes.
+func (p *partition) uniqueSlug(text string, categoryID int64) string {
+	base := utils.Slugify(text)
+	switch {
+	case base == "":
+		base = fallbackSlug
+	case strings.Trim(base, "0123456789") == "", reservedSlugs[base]:
+		base = fallbackSlug + "-" + base
+	}
+
+	taken := make(map[string]bool, len(p.categories))
+	for _, category := range p.categories {
+		if category.ID != categoryID {
+			taken[category.Slug] = true
+		}
+	}
+
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+
+	return slug
 }
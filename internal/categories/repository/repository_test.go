@@ -1,21 +1,20 @@
 package repository
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
 )
 
-func withCategories(t *testing.T, categories []entity.Category, fn func()) {
+func withCategories(t *testing.T, categories []entity.Category, fn func(repo *CategoriesRepository)) {
 	t.Helper()
-	original := append([]entity.Category(nil), categoriesList...)
-	categoriesList = append([]entity.Category(nil), categories...)
-	defer func() {
-		categoriesList = original
-	}()
-	fn()
+	fn(&CategoriesRepository{
+		partitions: map[string]*partition{tenant.DefaultID: newPartition(categories)},
+	})
 }
 
 func TestNewCategoriesRepository(t *testing.T) {
@@ -48,9 +47,8 @@ func TestCategoriesRepository_GetAllCategories(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			withCategories(t, tt.categories, func() {
-				repo := &CategoriesRepository{}
-				got := repo.GetAllCategories()
+			withCategories(t, tt.categories, func(repo *CategoriesRepository) {
+				got := repo.GetAllCategories(t.Context())
 				if !reflect.DeepEqual(got, tt.categories) {
 					t.Fatalf("expected %v, got %v", tt.categories, got)
 				}
@@ -91,9 +89,8 @@ func TestCategoriesRepository_GetCategoryByID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			withCategories(t, tt.categories, func() {
-				repo := &CategoriesRepository{}
-				got := repo.GetCategoryByID(tt.id)
+			withCategories(t, tt.categories, func(repo *CategoriesRepository) {
+				got := repo.GetCategoryByID(t.Context(), tt.id)
 				if !reflect.DeepEqual(got, tt.want) {
 					t.Fatalf("expected %v, got %v", tt.want, got)
 				}
@@ -114,40 +111,105 @@ func TestCategoriesRepository_InsertCategory(t *testing.T) {
 			name:       "autoEmpty",
 			categories: nil,
 			input:      entity.Category{Name: "A", Description: "D1"},
-			want:       entity.Category{ID: 1, Name: "A", Description: "D1"},
-			wantList:   []entity.Category{{ID: 1, Name: "A", Description: "D1"}},
+			want:       entity.Category{ID: 1, Name: "A", Slug: "a", Description: "D1", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "A", Slug: "a", Description: "D1", Position: positionGap}},
 		},
 		{
 			name: "autoExisting",
 			categories: []entity.Category{
-				{ID: 2, Name: "A", Description: "D1"},
-				{ID: 5, Name: "B", Description: "D2"},
+				{ID: 2, Name: "A", Slug: "a", Description: "D1"},
+				{ID: 5, Name: "B", Slug: "b", Description: "D2"},
 			},
 			input:    entity.Category{Name: "C", Description: "D3"},
-			want:     entity.Category{ID: 6, Name: "C", Description: "D3"},
-			wantList: []entity.Category{{ID: 2, Name: "A", Description: "D1"}, {ID: 5, Name: "B", Description: "D2"}, {ID: 6, Name: "C", Description: "D3"}},
+			want:     entity.Category{ID: 6, Name: "C", Slug: "c", Description: "D3", Position: positionGap},
+			wantList: []entity.Category{{ID: 2, Name: "A", Slug: "a", Description: "D1"}, {ID: 5, Name: "B", Slug: "b", Description: "D2"}, {ID: 6, Name: "C", Slug: "c", Description: "D3", Position: positionGap}},
 		},
 		{
 			name: "givenID",
 			categories: []entity.Category{
-				{ID: 1, Name: "A", Description: "D1"},
+				{ID: 1, Name: "A", Slug: "a", Description: "D1"},
 			},
 			input:    entity.Category{ID: 10, Name: "B", Description: "D2"},
-			want:     entity.Category{ID: 10, Name: "B", Description: "D2"},
-			wantList: []entity.Category{{ID: 1, Name: "A", Description: "D1"}, {ID: 10, Name: "B", Description: "D2"}},
+			want:     entity.Category{ID: 10, Name: "B", Slug: "b", Description: "D2", Position: positionGap},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "a", Description: "D1"}, {ID: 10, Name: "B", Slug: "b", Description: "D2", Position: positionGap}},
+		},
+		{
+			name: "duplicateSlug",
+			categories: []entity.Category{
+				{ID: 1, Name: "Handphone", Slug: "handphone"},
+				{ID: 2, Name: "Handphone Bekas", Slug: "handphone-2"},
+			},
+			input:    entity.Category{Name: "  HANDPHONE "},
+			want:     entity.Category{ID: 3, Name: "  HANDPHONE ", Slug: "handphone-3", Position: positionGap},
+			wantList: []entity.Category{{ID: 1, Name: "Handphone", Slug: "handphone"}, {ID: 2, Name: "Handphone Bekas", Slug: "handphone-2"}, {ID: 3, Name: "  HANDPHONE ", Slug: "handphone-3", Position: positionGap}},
+		},
+		{
+			name:       "givenSlug",
+			categories: nil,
+			input:      entity.Category{Name: "Handphone", Slug: "HP Murah"},
+			want:       entity.Category{ID: 1, Name: "Handphone", Slug: "hp-murah", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "Handphone", Slug: "hp-murah", Position: positionGap}},
+		},
+		{
+			name:       "unsluggableName",
+			categories: nil,
+			input:      entity.Category{Name: "!!!"},
+			want:       entity.Category{ID: 1, Name: "!!!", Slug: "kategori", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "!!!", Slug: "kategori", Position: positionGap}},
+		},
+		{
+			name:       "numericName",
+			categories: []entity.Category{{ID: 1, Name: "2024", Slug: "kategori-2024"}},
+			input:      entity.Category{Name: "2024!"},
+			want:       entity.Category{ID: 2, Name: "2024!", Slug: "kategori-2024-2", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "2024", Slug: "kategori-2024"}, {ID: 2, Name: "2024!", Slug: "kategori-2024-2", Position: positionGap}},
+		},
+		{
+			name:       "numericSlug",
+			categories: nil,
+			input:      entity.Category{Name: "Promo", Slug: "17"},
+			want:       entity.Category{ID: 1, Name: "Promo", Slug: "kategori-17", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "Promo", Slug: "kategori-17", Position: positionGap}},
+		},
+		{
+			name:       "reservedName",
+			categories: nil,
+			input:      entity.Category{Name: "Search"},
+			want:       entity.Category{ID: 1, Name: "Search", Slug: "kategori-search", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "Search", Slug: "kategori-search", Position: positionGap}},
+		},
+		{
+			name:       "reservedSlug",
+			categories: []entity.Category{{ID: 1, Name: "Docs", Slug: "kategori-docs"}},
+			input:      entity.Category{Name: "Dokumen", Slug: "DOCS"},
+			want:       entity.Category{ID: 2, Name: "Dokumen", Slug: "kategori-docs-2", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "Docs", Slug: "kategori-docs"}, {ID: 2, Name: "Dokumen", Slug: "kategori-docs-2", Position: positionGap}},
+		},
+		{
+			name:       "reservedPrefix",
+			categories: nil,
+			input:      entity.Category{Name: "Health Food"},
+			want:       entity.Category{ID: 1, Name: "Health Food", Slug: "health-food", Position: positionGap},
+			wantList:   []entity.Category{{ID: 1, Name: "Health Food", Slug: "health-food", Position: positionGap}},
+		},
+		{
+			name:       "attributes",
+			categories: nil,
+			input:      entity.Category{Name: "A", Attributes: map[string]any{"color": "red"}},
+			want:       entity.Category{ID: 1, Name: "A", Slug: "a", Position: positionGap, Attributes: map[string]any{"color": "red"}},
+			wantList:   []entity.Category{{ID: 1, Name: "A", Slug: "a", Position: positionGap, Attributes: map[string]any{"color": "red"}}},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			withCategories(t, tt.categories, func() {
-				repo := &CategoriesRepository{}
-				got := repo.InsertCategory(tt.input)
+			withCategories(t, tt.categories, func(repo *CategoriesRepository) {
+				got := repo.InsertCategory(t.Context(), tt.input)
 				if !reflect.DeepEqual(got, tt.want) {
 					t.Fatalf("expected %v, got %v", tt.want, got)
 				}
-				if !reflect.DeepEqual(categoriesList, tt.wantList) {
-					t.Fatalf("expected list %v, got %v", tt.wantList, categoriesList)
+				if list := repo.GetAllCategories(t.Context()); !reflect.DeepEqual(list, tt.wantList) {
+					t.Fatalf("expected list %v, got %v", tt.wantList, list)
 				}
 			})
 		})
@@ -166,12 +228,76 @@ func TestCategoriesRepository_UpdateCategory(t *testing.T) {
 		{
 			name: "found",
 			categories: []entity.Category{
-				{ID: 1, Name: "A", Description: "D1"},
-				{ID: 2, Name: "B", Description: "D2"},
+				{ID: 1, Name: "A", Slug: "a", Description: "D1"},
+				{ID: 2, Name: "B", Slug: "b", Description: "D2"},
 			},
 			input:    entity.Category{ID: 2, Name: "BB", Description: "DD"},
-			want:     entity.Category{ID: 2, Name: "BB", Description: "DD"},
-			wantList: []entity.Category{{ID: 1, Name: "A", Description: "D1"}, {ID: 2, Name: "BB", Description: "DD"}},
+			want:     entity.Category{ID: 2, Name: "BB", Slug: "bb", Description: "DD"},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "a", Description: "D1"}, {ID: 2, Name: "BB", Slug: "bb", Description: "DD"}},
+		},
+		{
+			name: "sameNameKeepsSlug",
+			categories: []entity.Category{
+				{ID: 1, Name: "A", Slug: "custom", Description: "D1"},
+			},
+			input:    entity.Category{ID: 1, Name: "A", Description: "DD"},
+			want:     entity.Category{ID: 1, Name: "A", Slug: "custom", Description: "DD"},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "custom", Description: "DD"}},
+		},
+		{
+			name: "givenSlug",
+			categories: []entity.Category{
+				{ID: 1, Name: "A", Slug: "a"},
+				{ID: 2, Name: "B", Slug: "b"},
+			},
+			input:    entity.Category{ID: 2, Name: "B", Slug: "a"},
+			want:     entity.Category{ID: 2, Name: "B", Slug: "a-2"},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "a"}, {ID: 2, Name: "B", Slug: "a-2"}},
+		},
+		{
+			name: "renamedToReserved",
+			categories: []entity.Category{
+				{ID: 1, Name: "Siaran", Slug: "siaran"},
+			},
+			input:    entity.Category{ID: 1, Name: "Stream"},
+			want:     entity.Category{ID: 1, Name: "Stream", Slug: "kategori-stream"},
+			wantList: []entity.Category{{ID: 1, Name: "Stream", Slug: "kategori-stream"}},
+		},
+		{
+			name: "keepsImage",
+			categories: []entity.Category{
+				{ID: 1, Name: "A", Slug: "a", Image: &entity.CategoryImage{Checksum: "abc"}},
+			},
+			input:    entity.Category{ID: 1, Name: "A", Image: &entity.CategoryImage{Checksum: "forged"}},
+			want:     entity.Category{ID: 1, Name: "A", Slug: "a", Image: &entity.CategoryImage{Checksum: "abc"}},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "a", Image: &entity.CategoryImage{Checksum: "abc"}}},
+		},
+		{
+			name: "keepsTranslations",
+			categories: []entity.Category{
+				{ID: 1, Name: "A", Slug: "a", Translations: map[string]entity.Translation{"en": {Name: "En"}}},
+			},
+			input:    entity.Category{ID: 1, Name: "A", Description: "DD", Translations: map[string]entity.Translation{"fr": {Name: "Fr"}}},
+			want:     entity.Category{ID: 1, Name: "A", Slug: "a", Description: "DD", Translations: map[string]entity.Translation{"en": {Name: "En"}}},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "a", Description: "DD", Translations: map[string]entity.Translation{"en": {Name: "En"}}}},
+		},
+		{
+			name: "keepsAttributes",
+			categories: []entity.Category{
+				{ID: 1, Name: "A", Slug: "a", Attributes: map[string]any{"color": "red"}},
+			},
+			input:    entity.Category{ID: 1, Name: "A"},
+			want:     entity.Category{ID: 1, Name: "A", Slug: "a", Attributes: map[string]any{"color": "red"}},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "a", Attributes: map[string]any{"color": "red"}}},
+		},
+		{
+			name: "replacesAttributes",
+			categories: []entity.Category{
+				{ID: 1, Name: "A", Slug: "a", Attributes: map[string]any{"color": "red"}},
+			},
+			input:    entity.Category{ID: 1, Name: "A", Attributes: map[string]any{"rate": 2.5}},
+			want:     entity.Category{ID: 1, Name: "A", Slug: "a", Attributes: map[string]any{"rate": 2.5}},
+			wantList: []entity.Category{{ID: 1, Name: "A", Slug: "a", Attributes: map[string]any{"rate": 2.5}}},
 		},
 		{
 			name: "missing",
@@ -187,9 +313,8 @@ func TestCategoriesRepository_UpdateCategory(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			withCategories(t, tt.categories, func() {
-				repo := &CategoriesRepository{}
-				got, err := repo.UpdateCategory(tt.input)
+			withCategories(t, tt.categories, func(repo *CategoriesRepository) {
+				got, err := repo.UpdateCategory(t.Context(), tt.input)
 				if tt.wantErr == "" && err != nil {
 					t.Fatalf("unexpected error: %v", err)
 				}
@@ -204,14 +329,129 @@ func TestCategoriesRepository_UpdateCategory(t *testing.T) {
 				if !reflect.DeepEqual(got, tt.want) {
 					t.Fatalf("expected %v, got %v", tt.want, got)
 				}
-				if !reflect.DeepEqual(categoriesList, tt.wantList) {
-					t.Fatalf("expected list %v, got %v", tt.wantList, categoriesList)
+				if list := repo.GetAllCategories(t.Context()); !reflect.DeepEqual(list, tt.wantList) {
+					t.Fatalf("expected list %v, got %v", tt.wantList, list)
 				}
 			})
 		})
 	}
 }
 
+func TestCategoriesRepository_UpsertTranslation(t *testing.T) {
+	tests := []struct {
+		name             string
+		categories       []entity.Category
+		id               int64
+		locale           string
+		translation      entity.Translation
+		wantTranslations map[string]entity.Translation
+		wantErr          string
+	}{
+		{
+			name:             "first translation",
+			categories:       []entity.Category{{ID: 1, Name: "Elektronik"}},
+			id:               1,
+			locale:           "en",
+			translation:      entity.Translation{Name: "Electronics", Description: "Electronics category"},
+			wantTranslations: map[string]entity.Translation{"en": {Name: "Electronics", Description: "Electronics category"}},
+		},
+		{
+			name: "replaces existing",
+			categories: []entity.Category{{ID: 1, Name: "Elektronik", Translations: map[string]entity.Translation{
+				"en": {Name: "Electronic"},
+				"ja": {Name: "電子機器"},
+			}}},
+			id:          1,
+			locale:      "en",
+			translation: entity.Translation{Name: "Electronics"},
+			wantTranslations: map[string]entity.Translation{
+				"en": {Name: "Electronics"},
+				"ja": {Name: "電子機器"},
+			},
+		},
+		{
+			name:        "missing",
+			categories:  []entity.Category{{ID: 1, Name: "Elektronik"}},
+			id:          2,
+			locale:      "en",
+			translation: entity.Translation{Name: "Electronics"},
+			wantErr:     constants.ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withCategories(t, tt.categories, func(repo *CategoriesRepository) {
+				before := repo.GetCategoryByID(t.Context(), tt.id)
+
+				got, err := repo.UpsertTranslation(t.Context(), tt.id, tt.locale, tt.translation)
+				if tt.wantErr != "" {
+					if err == nil || err.Error() != tt.wantErr {
+						t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(got.Translations, tt.wantTranslations) {
+					t.Fatalf("expected translations %v, got %v", tt.wantTranslations, got.Translations)
+				}
+				if stored := repo.GetCategoryByID(t.Context(), tt.id); !reflect.DeepEqual(stored, got) {
+					t.Fatalf("expected stored category %v, got %v", got, stored)
+				}
+				if reflect.DeepEqual(before.Translations, got.Translations) {
+					t.Fatalf("expected a category read earlier to keep its translations, got %v", before.Translations)
+				}
+			})
+		})
+	}
+}
+
+func TestCategoriesRepository_GetCategoryBySlug(t *testing.T) {
+	withCategories(t, []entity.Category{
+		{ID: 1, Name: "Handphone", Slug: "handphone"},
+		{ID: 2, Name: "Komputer", Slug: "komputer"},
+	}, func(repo *CategoriesRepository) {
+		if _, err := repo.UpdateCategory(t.Context(), entity.Category{ID: 1, Name: "Telepon Genggam"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		tests := []struct {
+			name      string
+			slug      string
+			wantID    int64
+			wantMoved bool
+		}{
+			{name: "current", slug: "telepon-genggam", wantID: 1},
+			{name: "previous", slug: "handphone", wantID: 1, wantMoved: true},
+			{name: "other", slug: "komputer", wantID: 2},
+			{name: "unknown", slug: "laptop"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, moved := repo.GetCategoryBySlug(t.Context(), tt.slug)
+				if got.ID != tt.wantID || moved != tt.wantMoved {
+					t.Fatalf("expected (%d, %v), got (%d, %v)", tt.wantID, tt.wantMoved, got.ID, moved)
+				}
+			})
+		}
+
+		reused := repo.InsertCategory(t.Context(), entity.Category{Name: "Handphone"})
+		if got, moved := repo.GetCategoryBySlug(t.Context(), "handphone"); got.ID != reused.ID || moved {
+			t.Fatalf("expected a new category to reclaim a retired slug, got (%d, %v)", got.ID, moved)
+		}
+
+		if _, err := repo.DeleteCategory(t.Context(), 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, _ := repo.GetCategoryBySlug(t.Context(), "telepon-genggam"); got.ID != 0 {
+			t.Fatalf("expected deleted category not to resolve, got %d", got.ID)
+		}
+	})
+}
+
 func TestCategoriesRepository_DeleteCategory(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -245,9 +485,8 @@ func TestCategoriesRepository_DeleteCategory(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			withCategories(t, tt.categories, func() {
-				repo := &CategoriesRepository{}
-				gotID, err := repo.DeleteCategory(tt.id)
+			withCategories(t, tt.categories, func(repo *CategoriesRepository) {
+				gotID, err := repo.DeleteCategory(t.Context(), tt.id)
 				if tt.wantErr == "" && err != nil {
 					t.Fatalf("unexpected error: %v", err)
 				}
@@ -262,10 +501,108 @@ func TestCategoriesRepository_DeleteCategory(t *testing.T) {
 				if gotID != tt.wantID {
 					t.Fatalf("expected id %d, got %d", tt.wantID, gotID)
 				}
-				if !reflect.DeepEqual(categoriesList, tt.wantList) {
-					t.Fatalf("expected list %v, got %v", tt.wantList, categoriesList)
+				if list := repo.GetAllCategories(t.Context()); !reflect.DeepEqual(list, tt.wantList) {
+					t.Fatalf("expected list %v, got %v", tt.wantList, list)
+				}
+			})
+		})
+	}
+}
+
+func TestCategoriesRepository_SetCategoryImage(t *testing.T) {
+	image := &entity.CategoryImage{Key: "default/1/abc.png", ContentType: "image/png", Size: 10, Width: 64, Height: 64, Checksum: "abc"}
+
+	tests := []struct {
+		name    string
+		id      int64
+		image   *entity.CategoryImage
+		want    *entity.CategoryImage
+		wantErr error
+	}{
+		{name: "set", id: 1, image: image, want: image},
+		{name: "remove", id: 2, image: nil, want: nil},
+		{name: "missing", id: 3, image: image, wantErr: entity.ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories := []entity.Category{
+				{ID: 1, Name: "A", Slug: "a"},
+				{ID: 2, Name: "B", Slug: "b", Image: &entity.CategoryImage{Checksum: "old"}},
+			}
+			withCategories(t, categories, func(repo *CategoriesRepository) {
+				got, err := repo.SetCategoryImage(t.Context(), tt.id, tt.image)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if err != nil {
+					return
+				}
+				if !reflect.DeepEqual(got.Image, tt.want) {
+					t.Fatalf("expected image %+v, got %+v", tt.want, got.Image)
+				}
+				if stored := repo.GetCategoryByID(t.Context(), tt.id); !reflect.DeepEqual(stored.Image, tt.want) {
+					t.Fatalf("expected stored image %+v, got %+v", tt.want, stored.Image)
+				}
+				if tt.image != nil {
+					tt.image.Checksum = "changed"
+					defer func() { tt.image.Checksum = "abc" }()
+					if stored := repo.GetCategoryByID(t.Context(), tt.id); stored.Image.Checksum != "abc" {
+						t.Fatalf("expected the stored image to be a copy")
+					}
 				}
 			})
 		})
 	}
 }
+
+func TestCategoriesRepository_TenantIsolation(t *testing.T) {
+	repo, err := NewCategoriesRepository()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	acme := tenant.WithID(t.Context(), "acme")
+	globex := tenant.WithID(t.Context(), "globex")
+
+	if got := repo.GetAllCategories(acme); len(got) != 0 {
+		t.Fatalf("expected a new tenant to start empty, got %v", got)
+	}
+	if got := repo.GetAllCategories(t.Context()); len(got) != len(defaultCategories) {
+		t.Fatalf("expected the default tenant to hold %d categories, got %d", len(defaultCategories), len(got))
+	}
+
+	// IDs and slugs are sequenced per tenant, so both tenants get ID 1 and the same slug.
+	a := repo.InsertCategory(acme, entity.Category{Name: "Handphone"})
+	g := repo.InsertCategory(globex, entity.Category{Name: "Handphone"})
+	if a.ID != 1 || g.ID != 1 || a.Slug != "handphone" || g.Slug != "handphone" {
+		t.Fatalf("expected ID 1 and slug handphone in both tenants, got %v and %v", a, g)
+	}
+	if _, err := repo.UpdateCategory(acme, entity.Category{ID: 1, Name: "Telepon"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := repo.GetCategoryByID(globex, 1); got.Name != "Handphone" {
+		t.Errorf("expected globex's category to be untouched, got %v", got)
+	}
+	if got, moved := repo.GetCategoryBySlug(globex, "telepon"); got.ID != 0 || moved {
+		t.Errorf("expected acme's slug not to resolve for globex, got %v", got)
+	}
+	if got, moved := repo.GetCategoryBySlug(globex, "handphone"); got.ID != 1 || moved {
+		t.Errorf("expected acme's old slug not to redirect globex, got %v, %v", got, moved)
+	}
+	if got := repo.GetCategoryByID(acme, 6); got.ID != 0 {
+		t.Errorf("expected default categories to be invisible to acme, got %v", got)
+	}
+	if _, err := repo.DeleteCategory(acme, 6); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("expected acme not to delete a default category, got %v", err)
+	}
+	if _, err := repo.UpsertTranslation(acme, 6, "en", entity.Translation{Name: "X"}); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("expected acme not to translate a default category, got %v", err)
+	}
+	if _, err := repo.DeleteCategory(globex, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.GetCategoryByID(acme, 1); got.Name != "Telepon" {
+		t.Errorf("expected acme's category to survive globex's delete, got %v", got)
+	}
+}
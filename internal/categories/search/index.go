@@ -0,0 +1,222 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+const (
+	// nameWeight and descriptionWeight scale a match by the field it was found in.
+	nameWeight        = 3.0
+	descriptionWeight = 1.0
+
+	// exactScore, prefixScore and fuzzyScore rank how a query token matched an indexed term.
+	exactScore  = 1.0
+	prefixScore = 0.6
+	fuzzyScore  = 0.5
+
+	// minPrefixLength is the shortest query token that may match as a prefix.
+	minPrefixLength = 2
+)
+
+// posting records how often a term occurs in each field of one category.
+type posting struct {
+	name        int
+	description int
+}
+
+// Index is an in-memory inverted index over category names and descriptions in every locale. It supports exact, prefix and
+// typo-tolerant matching and is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[int64]entity.Category
+	postings map[string]map[int64]posting
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[int64]entity.Category),
+		postings: make(map[string]map[int64]posting),
+	}
+}
+
+// Add indexes category, replacing any previous version with the same ID.
+func (idx *Index) Add(category entity.Category) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.add(category)
+}
+
+// Remove drops the category with the given ID from the index.
+func (idx *Index) Remove(categoryID int64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(categoryID)
+}
+
+// Reset replaces the indexed categories with categories.
+func (idx *Index) Reset(categories []entity.Category) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs = make(map[int64]entity.Category)
+	idx.postings = make(map[string]map[int64]posting)
+	for _, category := range categories {
+		idx.add(category)
+	}
+}
+
+// Search returns up to limit categories matching query, best match first. Every query token is matched exactly,
+// as a prefix of an indexed term, or within a small edit distance; a category's score sums the best match of each
+// token weighted by field, scaled by the share of query tokens it matched. A limit of 0 or less returns every match.
+func (idx *Index) Search(query string, limit int) []entity.SearchResult {
+	tokens := Tokenize(query)
+	if len(tokens) == 0 {
+		return []entity.SearchResult{}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[int64]float64)
+	matched := make(map[int64]int)
+	for _, token := range tokens {
+		best := make(map[int64]float64)
+		for term, docs := range idx.postings {
+			quality := matchQuality(token, term)
+			if quality == 0 {
+				continue
+			}
+			for id, p := range docs {
+				score := quality * (float64(p.name)*nameWeight + float64(p.description)*descriptionWeight)
+				if score > best[id] {
+					best[id] = score
+				}
+			}
+		}
+		for id, score := range best {
+			scores[id] += score
+			matched[id]++
+		}
+	}
+
+	results := make([]entity.SearchResult, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, entity.SearchResult{
+			Category: idx.docs[id],
+			Score:    score * float64(matched[id]) / float64(len(tokens)),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Category.ID < results[j].Category.ID
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// matchQuality rates how well query token matches indexed term, from 0 (no match) to exactScore.
+func matchQuality(token, term string) float64 {
+	if token == term {
+		return exactScore
+	}
+
+	if utf8.RuneCountInString(token) >= minPrefixLength && strings.HasPrefix(term, token) {
+		return prefixScore
+	}
+
+	maxEdits := allowedEdits(token)
+	if maxEdits == 0 {
+		return 0
+	}
+
+	if d := editDistance([]rune(token), []rune(term), maxEdits); d <= maxEdits {
+		return fuzzyScore / float64(d)
+	}
+
+	return 0
+}
+
+// allowedEdits returns how many typos a query token of this length may contain: none for short tokens,
+// one from four characters and two from eight.
+func allowedEdits(token string) int {
+	switch n := utf8.RuneCountInString(token); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// posting returns the posting of term for categoryID, creating the term's posting list if needed. The caller must hold idx.mu.
+func (idx *Index) posting(term string, categoryID int64) posting {
+	docs, ok := idx.postings[term]
+	if !ok {
+		docs = make(map[int64]posting)
+		idx.postings[term] = docs
+	}
+	return docs[categoryID]
+}
+
+// add indexes category, replacing any previous version. The caller must hold idx.mu.
+func (idx *Index) add(category entity.Category) {
+	idx.remove(category.ID)
+	idx.docs[category.ID] = category
+
+	names, descriptions := fields(category)
+	for _, term := range names {
+		p := idx.posting(term, category.ID)
+		p.name++
+		idx.postings[term][category.ID] = p
+	}
+	for _, term := range descriptions {
+		p := idx.posting(term, category.ID)
+		p.description++
+		idx.postings[term][category.ID] = p
+	}
+}
+
+// remove drops categoryID from the documents and every posting list. The caller must hold idx.mu.
+func (idx *Index) remove(categoryID int64) {
+	category, ok := idx.docs[categoryID]
+	if !ok {
+		return
+	}
+	delete(idx.docs, categoryID)
+
+	names, descriptions := fields(category)
+	for _, term := range append(names, descriptions...) {
+		docs := idx.postings[term]
+		delete(docs, categoryID)
+		if len(docs) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+}
+
+// fields returns the terms of category's name and description in every locale it is available in.
+func fields(category entity.Category) (names, descriptions []string) {
+	names = Tokenize(category.Name)
+	descriptions = Tokenize(category.Description)
+	for _, translation := range category.Translations {
+		names = append(names, Tokenize(translation.Name)...)
+		descriptions = append(descriptions, Tokenize(translation.Description)...)
+	}
+	return names, descriptions
+}
@@ -0,0 +1,121 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	idx.Reset([]entity.Category{
+		{ID: 1, Name: "Elektronik", Description: "Kategori Elektronik"},
+		{ID: 2, Name: "Komputer", Description: " Kategori Komputer"},
+		{ID: 3, Name: "Handphone", Description: "Kategori Handphone"},
+		{ID: 4, Name: "Aksesoris", Description: "Aksesoris untuk handphone dan komputer"},
+	})
+	return idx
+}
+
+func resultIDs(results []entity.SearchResult) []int64 {
+	ids := []int64{}
+	for _, result := range results {
+		ids = append(ids, result.Category.ID)
+	}
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "punctuation and case", text: "Kabel USB-C, Charger & Power-Bank!", want: []string{"kabel", "usb", "c", "charger", "power", "bank"}},
+		{name: "stop-words", text: "Aksesoris untuk handphone dan komputer", want: []string{"aksesoris", "handphone", "komputer"}},
+		{name: "empty", text: "  ", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tokenize(tt.text)
+			if got == nil {
+				got = []string{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{a: "hanphone", b: "handphone", max: 2, want: 1},
+		{a: "komptuer", b: "komputer", max: 2, want: 2},
+		{a: "kitten", b: "sitting", max: 3, want: 3},
+		{a: "elektronik", b: "komputer", max: 2, want: 3},
+		{a: "abc", b: "abcdef", max: 1, want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b), tt.max); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := newTestIndex()
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []int64
+	}{
+		{name: "exact name ranks above description", query: "handphone", want: []int64{3, 4}},
+		{name: "typo", query: "hanphone", want: []int64{3, 4}},
+		{name: "prefix", query: "elek", want: []int64{1}},
+		{name: "case insensitive", query: "KOMPUTER", want: []int64{2, 4}},
+		{name: "multiple terms prefer full coverage", query: "aksesoris komputer", want: []int64{4, 2}},
+		{name: "limit", query: "kategori", limit: 2, want: []int64{1, 2}},
+		{name: "stop-words only", query: "untuk dan", want: []int64{}},
+		{name: "short tokens are not fuzzy", query: "xyz", want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resultIDs(idx.Search(tt.query, tt.limit))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIndex_AddRemove(t *testing.T) {
+	idx := newTestIndex()
+
+	idx.Add(entity.Category{ID: 3, Name: "Telepon", Description: "Kategori Telepon"})
+	if got := resultIDs(idx.Search("handphone", 0)); !reflect.DeepEqual(got, []int64{4}) {
+		t.Fatalf("expected re-indexed category to drop its old terms, got %v", got)
+	}
+	if got := resultIDs(idx.Search("telepon", 0)); !reflect.DeepEqual(got, []int64{3}) {
+		t.Fatalf("expected re-indexed category to be found by its new name, got %v", got)
+	}
+
+	idx.Remove(3)
+	idx.Remove(99)
+	if got := resultIDs(idx.Search("telepon", 0)); len(got) != 0 {
+		t.Fatalf("expected removed category to disappear, got %v", got)
+	}
+	if _, ok := idx.postings["telepon"]; ok {
+		t.Fatalf("expected empty posting list to be dropped")
+	}
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common Indonesian function words that carry no meaning for category search.
+var stopWords = map[string]struct{}{
+	"ada": {}, "adalah": {}, "agar": {}, "akan": {}, "atau": {}, "bagi": {}, "bahwa": {}, "bisa": {},
+	"dalam": {}, "dan": {}, "dapat": {}, "dari": {}, "dengan": {}, "di": {}, "hanya": {}, "ini": {},
+	"itu": {}, "juga": {}, "karena": {}, "ke": {}, "lebih": {}, "oleh": {}, "pada": {}, "para": {},
+	"per": {}, "pun": {}, "saja": {}, "sebagai": {}, "serta": {}, "sudah": {}, "telah": {}, "tersebut": {},
+	"tidak": {}, "untuk": {}, "yang": {},
+}
+
+// Tokenize lower-cases text, splits it on anything that is not a letter or digit and drops stop-words.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := fields[:0]
+	for _, field := range fields {
+		if _, stop := stopWords[field]; stop {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+
+	return tokens
+}
+
+// editDistance returns the Levenshtein distance between a and b, or max+1 once it is known to exceed max.
+func editDistance(a, b []rune, max int) int {
+	if diff := len(a) - len(b); diff > max || -diff > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+)
+
+// attributeKeyPattern matches the keys of attribute definitions: lower-case letters, digits and underscores,
+// starting with a letter, at most 64 characters long.
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// IAttributesService provides methods for managing the attributes categories may carry.
+// GetAllAttributes retrieves every attribute definition.
+// GetAttributeByKey retrieves an attribute definition by its key.
+// InsertAttribute validates and stores a new attribute definition.
+// UpdateAttribute validates and replaces an existing attribute definition.
+// DeleteAttribute removes an attribute definition no category has a value for.
+// Every method works on the attribute definitions of the tenant carried by ctx.
+type IAttributesService interface {
+	GetAllAttributes(ctx context.Context) []entity.AttributeDefinition
+	GetAttributeByKey(ctx context.Context, key string) (entity.AttributeDefinition, error)
+	InsertAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error)
+	UpdateAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error)
+	DeleteAttribute(ctx context.Context, key string) error
+}
+
+// AttributesService manages attribute definitions in an IAttributesRepository. It consults the categories so a
+// definition is never changed or deleted under the values categories hold for it.
+type AttributesService struct {
+	repo       repository.IAttributesRepository
+	categories repository.ICategoriesRepository
+}
+
+// NewAttributesService initializes a new AttributesService with the repository of the attribute definitions and the
+// repository of the categories holding their values.
+func NewAttributesService(repo repository.IAttributesRepository, categories repository.ICategoriesRepository) (*AttributesService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("attributes repository must not be nil")
+	}
+	if categories == nil {
+		return nil, fmt.Errorf("categories repository must not be nil")
+	}
+
+	return &AttributesService{repo: repo, categories: categories}, nil
+}
+
+// GetAllAttributes retrieves every attribute definition ordered by key.
+func (s *AttributesService) GetAllAttributes(ctx context.Context) []entity.AttributeDefinition {
+	return s.repo.GetAllAttributes(ctx)
+}
+
+// GetAttributeByKey retrieves an attribute definition by its key. Returns ErrAttributeNotFound if there is none.
+func (s *AttributesService) GetAttributeByKey(ctx context.Context, key string) (entity.AttributeDefinition, error) {
+	return s.repo.GetAttributeByKey(ctx, key)
+}
+
+// InsertAttribute stores a new attribute definition. Returns ErrInvalidAttributeDefinition when it fails validation
+// and ErrAttributeExists when its key is taken. Categories stored before a required attribute is defined keep having
+// no value for it until their attributes are written again.
+func (s *AttributesService) InsertAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+	definition, err := checkDefinition(parameter)
+	if err != nil {
+		return entity.AttributeDefinition{}, err
+	}
+
+	return s.repo.InsertAttribute(ctx, definition)
+}
+
+// UpdateAttribute replaces the attribute definition with the key of parameter. Returns ErrInvalidAttributeDefinition
+// when it fails validation, ErrAttributeNotFound when there is no definition to replace and ErrAttributeInUse when a
+// category holds a value the new definition does not allow, e.g. a value removed from an enum.
+func (s *AttributesService) UpdateAttribute(ctx context.Context, parameter entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+	definition, err := checkDefinition(parameter)
+	if err != nil {
+		return entity.AttributeDefinition{}, err
+	}
+	if _, err := s.repo.GetAttributeByKey(ctx, definition.Key); err != nil {
+		return entity.AttributeDefinition{}, err
+	}
+
+	for _, category := range s.categories.GetAllCategories(ctx) {
+		value, ok := category.Attributes[definition.Key]
+		if !ok {
+			continue
+		}
+		// Stored values must already be in the form the new definition stores, so e.g. the text "5" of a former
+		// string attribute does not pass as a number.
+		if checked, err := attributeValue(definition, value); err != nil || checked != value {
+			return entity.AttributeDefinition{}, fmt.Errorf("%w: category %d has the value %v", entity.ErrAttributeInUse, category.ID, value)
+		}
+	}
+
+	return s.repo.UpdateAttribute(ctx, definition)
+}
+
+// DeleteAttribute removes the attribute definition with the given key. Returns ErrAttributeNotFound when there is
+// none and ErrAttributeInUse while a category still has a value for it.
+func (s *AttributesService) DeleteAttribute(ctx context.Context, key string) error {
+	if _, err := s.repo.GetAttributeByKey(ctx, key); err != nil {
+		return err
+	}
+
+	for _, category := range s.categories.GetAllCategories(ctx) {
+		if _, ok := category.Attributes[key]; ok {
+			return fmt.Errorf("%w: category %d has a value for %s", entity.ErrAttributeInUse, category.ID, key)
+		}
+	}
+
+	return s.repo.DeleteAttribute(ctx, key)
+}
+
+// checkAttributes checks the attribute values of a category against the attribute definitions of the tenant and
+// returns them in stored form, with the default of every defined attribute that has no value. It returns an
+// *entity.AttributeError for the first attribute, in key order, that is not defined, is missing although required
+// or has a value its definition does not allow.
+func (s *CategoriesService) checkAttributes(ctx context.Context, values map[string]any) (map[string]any, error) {
+	var definitions []entity.AttributeDefinition
+	if s.attributes != nil {
+		definitions = s.attributes.GetAllAttributes(ctx)
+	}
+
+	defined := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		defined[definition.Key] = true
+	}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if !defined[key] {
+			return nil, &entity.AttributeError{Key: key, Reason: "is not defined"}
+		}
+	}
+
+	var checked map[string]any
+	if values != nil {
+		checked = make(map[string]any, len(definitions))
+	}
+	for _, definition := range definitions {
+		value, ok := values[definition.Key]
+		switch {
+		case ok:
+			var err error
+			if value, err = attributeValue(definition, value); err != nil {
+				return nil, &entity.AttributeError{Key: definition.Key, Reason: err.Error()}
+			}
+		case definition.Default != nil:
+			value = definition.Default
+		case definition.Required:
+			return nil, &entity.AttributeError{Key: definition.Key, Reason: "is required"}
+		default:
+			continue
+		}
+
+		if checked == nil {
+			checked = make(map[string]any, len(definitions))
+		}
+		checked[definition.Key] = value
+	}
+
+	return checked, nil
+}
+
+// checkDefinition validates an attribute definition and returns it with its default in stored form. Errors match
+// ErrInvalidAttributeDefinition.
+func checkDefinition(definition entity.AttributeDefinition) (entity.AttributeDefinition, error) {
+	invalid := func(reason string) (entity.AttributeDefinition, error) {
+		return entity.AttributeDefinition{}, fmt.Errorf("%w: %s", entity.ErrInvalidAttributeDefinition, reason)
+	}
+
+	if !attributeKeyPattern.MatchString(definition.Key) {
+		return invalid("key must be 1-64 lower-case letters, digits or underscores, starting with a letter")
+	}
+
+	switch definition.Type {
+	case entity.AttributeString, entity.AttributeNumber, entity.AttributeBool:
+		if len(definition.Values) > 0 {
+			return invalid("values are only allowed for enum attributes")
+		}
+	case entity.AttributeEnum:
+		if len(definition.Values) == 0 {
+			return invalid("enum attributes must list their values")
+		}
+		seen := make(map[string]bool, len(definition.Values))
+		for _, value := range definition.Values {
+			if strings.TrimSpace(value) == "" || seen[value] {
+				return invalid("enum values must be non-empty and unique")
+			}
+			seen[value] = true
+		}
+	default:
+		return invalid("type must be string, number, bool or enum")
+	}
+
+	if definition.Default != nil {
+		value, err := attributeValue(definition, definition.Default)
+		if err != nil {
+			return invalid("default " + err.Error())
+		}
+		definition.Default = value
+	}
+
+	return definition, nil
+}
+
+// attributeValue returns value in the form stored for attributes of the definition, or an error saying why the
+// definition does not allow it. Bodies decoded from XML carry every value as text, so numbers and booleans are also
+// accepted as text, and bodies decoded from MessagePack carry integers, which are stored as float64 like JSON numbers.
+func attributeValue(definition entity.AttributeDefinition, value any) (any, error) {
+	switch definition.Type {
+	case entity.AttributeString:
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+		return nil, errors.New("must be a string")
+	case entity.AttributeNumber:
+		if number, ok := attributeNumber(value); ok && !math.IsNaN(number) && !math.IsInf(number, 0) {
+			return number, nil
+		}
+		return nil, errors.New("must be a number")
+	case entity.AttributeBool:
+		switch value := value.(type) {
+		case bool:
+			return value, nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+				return b, nil
+			}
+		}
+		return nil, errors.New("must be a bool")
+	case entity.AttributeEnum:
+		if text, ok := value.(string); ok && slices.Contains(definition.Values, text) {
+			return text, nil
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(definition.Values, ", "))
+	}
+
+	return nil, fmt.Errorf("has the unknown type %q", definition.Type)
+}
+
+// attributeNumber converts the numeric kinds produced by the request decoders to float64.
+func attributeNumber(value any) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int8:
+		return float64(value), true
+	case int16:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint:
+		return float64(value), true
+	case uint8:
+		return float64(value), true
+	case uint16:
+		return float64(value), true
+	case uint32:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case json.Number:
+		number, err := value.Float64()
+		return number, err == nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+)
+
+// attributeDefinitions are the definitions the attribute tests run against.
+var attributeDefinitions = []entity.AttributeDefinition{
+	{Key: "color", Type: entity.AttributeEnum, Values: []string{"red", "blue"}, Default: "red"},
+	{Key: "commission", Type: entity.AttributeNumber},
+	{Key: "featured", Type: entity.AttributeBool},
+	{Key: "seo_title", Type: entity.AttributeString, Required: true},
+}
+
+// newAttributesFixture returns the repositories of the default categories and of attributeDefinitions.
+func newAttributesFixture(t *testing.T) (*repository.CategoriesRepository, *repository.AttributesRepository) {
+	t.Helper()
+
+	categories, _ := repository.NewCategoriesRepository()
+	attributes, _ := repository.NewAttributesRepository()
+	for _, definition := range attributeDefinitions {
+		if _, err := attributes.InsertAttribute(t.Context(), definition); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return categories, attributes
+}
+
+func TestNewAttributesService(t *testing.T) {
+	categories, attributes := newAttributesFixture(t)
+
+	if _, err := NewAttributesService(nil, categories); err == nil {
+		t.Errorf("expected an error without attributes repository")
+	}
+	if _, err := NewAttributesService(attributes, nil); err == nil {
+		t.Errorf("expected an error without categories repository")
+	}
+	if _, err := NewAttributesService(attributes, categories); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCategoriesService_InsertCategoryAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		want       map[string]any
+		wantKey    string
+	}{
+		{
+			name:       "defaults",
+			attributes: map[string]any{"seo_title": "Phones"},
+			want:       map[string]any{"color": "red", "seo_title": "Phones"},
+		},
+		{
+			name:       "every type",
+			attributes: map[string]any{"color": "blue", "commission": 2.5, "featured": true, "seo_title": "Phones"},
+			want:       map[string]any{"color": "blue", "commission": 2.5, "featured": true, "seo_title": "Phones"},
+		},
+		{
+			name:       "decoded from xml",
+			attributes: map[string]any{"commission": "2.5", "featured": "true", "seo_title": "Phones"},
+			want:       map[string]any{"color": "red", "commission": 2.5, "featured": true, "seo_title": "Phones"},
+		},
+		{
+			name:       "decoded from msgpack",
+			attributes: map[string]any{"commission": int8(3), "seo_title": "Phones"},
+			want:       map[string]any{"color": "red", "commission": 3.0, "seo_title": "Phones"},
+		},
+		{name: "required missing", attributes: nil, wantKey: "seo_title"},
+		{name: "undefined", attributes: map[string]any{"icon": "phone", "seo_title": "Phones"}, wantKey: "icon"},
+		{name: "not in enum", attributes: map[string]any{"color": "green", "seo_title": "Phones"}, wantKey: "color"},
+		{name: "not a number", attributes: map[string]any{"commission": "high", "seo_title": "Phones"}, wantKey: "commission"},
+		{name: "not a bool", attributes: map[string]any{"featured": 1.0, "seo_title": "Phones"}, wantKey: "featured"},
+		{name: "not a string", attributes: map[string]any{"seo_title": 42.0}, wantKey: "seo_title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, attributes := newAttributesFixture(t)
+			svc, _ := NewCategoriesService(categories, attributes, nil)
+
+			got, err := svc.InsertCategory(t.Context(), entity.Category{Name: "Phones", Attributes: tt.attributes})
+
+			if tt.wantKey != "" {
+				var attributeErr *entity.AttributeError
+				if !errors.As(err, &attributeErr) || attributeErr.Key != tt.wantKey {
+					t.Fatalf("expected an attribute error for %q, got %v", tt.wantKey, err)
+				}
+				if !errors.Is(err, entity.ErrInvalidAttribute) {
+					t.Fatalf("expected the error to match ErrInvalidAttribute, got %v", err)
+				}
+				if n := len(categories.GetAllCategories(t.Context())); n != 3 {
+					t.Fatalf("expected the category not to be stored, got %d categories", n)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Attributes, tt.want) {
+				t.Fatalf("expected attributes %v, got %v", tt.want, got.Attributes)
+			}
+		})
+	}
+}
+
+func TestCategoriesService_InsertCategoryWithoutDefinitions(t *testing.T) {
+	categories, _ := repository.NewCategoriesRepository()
+	svc, _ := NewCategoriesService(categories, nil, nil)
+
+	if got, err := svc.InsertCategory(t.Context(), entity.Category{Name: "Phones"}); err != nil || got.Attributes != nil {
+		t.Fatalf("expected a category without attributes, got %+v, %v", got, err)
+	}
+	if _, err := svc.InsertCategory(t.Context(), entity.Category{Name: "Tablets", Attributes: map[string]any{"color": "red"}}); !errors.Is(err, entity.ErrInvalidAttribute) {
+		t.Fatalf("expected ErrInvalidAttribute, got %v", err)
+	}
+}
+
+func TestCategoriesService_UpdateCategoryAttributes(t *testing.T) {
+	categories, attributes := newAttributesFixture(t)
+	svc, _ := NewCategoriesService(categories, attributes, nil)
+
+	created, err := svc.InsertCategory(t.Context(), entity.Category{Name: "Phones", Attributes: map[string]any{"seo_title": "Phones", "featured": true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kept, err := svc.UpdateCategory(t.Context(), entity.Category{ID: created.ID, Name: "Smartphones"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(kept.Attributes, created.Attributes) {
+		t.Errorf("expected the attributes to be kept, got %v", kept.Attributes)
+	}
+
+	replaced, err := svc.UpdateCategory(t.Context(), entity.Category{ID: created.ID, Name: "Smartphones", Attributes: map[string]any{"seo_title": "Smartphones"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]any{"color": "red", "seo_title": "Smartphones"}; !reflect.DeepEqual(replaced.Attributes, want) {
+		t.Errorf("expected attributes %v, got %v", want, replaced.Attributes)
+	}
+
+	if _, err := svc.UpdateCategory(t.Context(), entity.Category{ID: created.ID, Name: "Smartphones", Attributes: map[string]any{}}); !errors.Is(err, entity.ErrInvalidAttribute) {
+		t.Errorf("expected ErrInvalidAttribute for a missing required attribute, got %v", err)
+	}
+	if got := categories.GetCategoryByID(t.Context(), created.ID); !reflect.DeepEqual(got.Attributes, replaced.Attributes) {
+		t.Errorf("expected a rejected update to keep the attributes, got %v", got.Attributes)
+	}
+}
+
+func TestAttributesService_InsertAttribute(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition entity.AttributeDefinition
+		want       entity.AttributeDefinition
+		wantErr    error
+	}{
+		{
+			name:       "string",
+			definition: entity.AttributeDefinition{Key: "icon", Type: entity.AttributeString, Default: "folder"},
+			want:       entity.AttributeDefinition{Key: "icon", Type: entity.AttributeString, Default: "folder"},
+		},
+		{
+			name:       "number default given as text",
+			definition: entity.AttributeDefinition{Key: "rate", Type: entity.AttributeNumber, Default: "1.5"},
+			want:       entity.AttributeDefinition{Key: "rate", Type: entity.AttributeNumber, Default: 1.5},
+		},
+		{
+			name:       "enum",
+			definition: entity.AttributeDefinition{Key: "size", Type: entity.AttributeEnum, Values: []string{"s", "m"}, Required: true},
+			want:       entity.AttributeDefinition{Key: "size", Type: entity.AttributeEnum, Values: []string{"s", "m"}, Required: true},
+		},
+		{name: "taken key", definition: entity.AttributeDefinition{Key: "color", Type: entity.AttributeString}, wantErr: entity.ErrAttributeExists},
+		{name: "invalid key", definition: entity.AttributeDefinition{Key: "SEO Title", Type: entity.AttributeString}, wantErr: entity.ErrInvalidAttributeDefinition},
+		{name: "unknown type", definition: entity.AttributeDefinition{Key: "icon", Type: "date"}, wantErr: entity.ErrInvalidAttributeDefinition},
+		{name: "enum without values", definition: entity.AttributeDefinition{Key: "size", Type: entity.AttributeEnum}, wantErr: entity.ErrInvalidAttributeDefinition},
+		{name: "repeated enum value", definition: entity.AttributeDefinition{Key: "size", Type: entity.AttributeEnum, Values: []string{"s", "s"}}, wantErr: entity.ErrInvalidAttributeDefinition},
+		{name: "values of a string", definition: entity.AttributeDefinition{Key: "icon", Type: entity.AttributeString, Values: []string{"a"}}, wantErr: entity.ErrInvalidAttributeDefinition},
+		{name: "default of another type", definition: entity.AttributeDefinition{Key: "hot", Type: entity.AttributeBool, Default: "yes"}, wantErr: entity.ErrInvalidAttributeDefinition},
+		{name: "default outside the enum", definition: entity.AttributeDefinition{Key: "size", Type: entity.AttributeEnum, Values: []string{"s"}, Default: "xl"}, wantErr: entity.ErrInvalidAttributeDefinition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, attributes := newAttributesFixture(t)
+			svc, _ := NewAttributesService(attributes, categories)
+
+			got, err := svc.InsertAttribute(t.Context(), tt.definition)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAttributesService_UpdateAttribute(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition entity.AttributeDefinition
+		wantErr    error
+	}{
+		{name: "add enum value", definition: entity.AttributeDefinition{Key: "color", Type: entity.AttributeEnum, Values: []string{"red", "blue", "green"}}},
+		{name: "remove unused enum value", definition: entity.AttributeDefinition{Key: "color", Type: entity.AttributeEnum, Values: []string{"blue"}}},
+		{name: "remove used enum value", definition: entity.AttributeDefinition{Key: "color", Type: entity.AttributeEnum, Values: []string{"red"}}, wantErr: entity.ErrAttributeInUse},
+		{name: "enum to string", definition: entity.AttributeDefinition{Key: "color", Type: entity.AttributeString}},
+		{name: "enum to number", definition: entity.AttributeDefinition{Key: "color", Type: entity.AttributeNumber}, wantErr: entity.ErrAttributeInUse},
+		{name: "unused attribute", definition: entity.AttributeDefinition{Key: "featured", Type: entity.AttributeString}},
+		{name: "unknown key", definition: entity.AttributeDefinition{Key: "icon", Type: entity.AttributeString}, wantErr: entity.ErrAttributeNotFound},
+		{name: "invalid", definition: entity.AttributeDefinition{Key: "color", Type: entity.AttributeEnum}, wantErr: entity.ErrInvalidAttributeDefinition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, attributes := newAttributesFixture(t)
+			categories.InsertCategory(t.Context(), entity.Category{Name: "Phones", Attributes: map[string]any{"color": "blue"}})
+			svc, _ := NewAttributesService(attributes, categories)
+
+			_, err := svc.UpdateAttribute(t.Context(), tt.definition)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			got, _ := attributes.GetAttributeByKey(t.Context(), tt.definition.Key)
+			if err == nil && !reflect.DeepEqual(got, tt.definition) {
+				t.Fatalf("expected the definition %+v to be stored, got %+v", tt.definition, got)
+			}
+			if err != nil && reflect.DeepEqual(got, tt.definition) {
+				t.Fatalf("expected the definition to be left unchanged")
+			}
+		})
+	}
+}
+
+func TestAttributesService_DeleteAttribute(t *testing.T) {
+	categories, attributes := newAttributesFixture(t)
+	created := categories.InsertCategory(t.Context(), entity.Category{Name: "Phones", Attributes: map[string]any{"color": "blue"}})
+	svc, _ := NewAttributesService(attributes, categories)
+
+	if err := svc.DeleteAttribute(t.Context(), "color"); !errors.Is(err, entity.ErrAttributeInUse) {
+		t.Fatalf("expected ErrAttributeInUse, got %v", err)
+	}
+	if err := svc.DeleteAttribute(t.Context(), "icon"); !errors.Is(err, entity.ErrAttributeNotFound) {
+		t.Fatalf("expected ErrAttributeNotFound, got %v", err)
+	}
+
+	if _, err := categories.UpdateCategory(t.Context(), entity.Category{ID: created.ID, Name: "Phones", Attributes: map[string]any{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.DeleteAttribute(t.Context(), "color"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := attributes.GetAttributeByKey(t.Context(), "color"); !errors.Is(err, entity.ErrAttributeNotFound) {
+		t.Fatalf("expected the definition to be deleted, got %v", err)
+	}
+}
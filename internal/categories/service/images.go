@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	// Register the decoders of the supported image formats with the image package.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/blob"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+const (
+	// DefaultMaxImageBytes is the size of the largest category image accepted.
+	DefaultMaxImageBytes = 1 << 20
+
+	// DefaultMinImageDimension is the smallest width and height, in pixels, of an accepted category image.
+	DefaultMinImageDimension = 16
+
+	// DefaultMaxImageDimension is the largest width and height, in pixels, of an accepted category image.
+	DefaultMaxImageDimension = 1024
+
+	// imageCleanupTimeout bounds the removal of the image of a deleted category from blob storage.
+	imageCleanupTimeout = 30 * time.Second
+)
+
+// imageFormats maps the content types of the supported image formats, as sniffed by http.DetectContentType, to the
+// name image.DecodeConfig reports for them and the extension of their blob keys.
+var imageFormats = map[string]struct{ name, extension string }{
+	"image/png":  {name: "png", extension: "png"},
+	"image/jpeg": {name: "jpeg", extension: "jpg"},
+	"image/gif":  {name: "gif", extension: "gif"},
+}
+
+// IImagesService provides methods for managing the images of categories.
+// PutCategoryImage validates an uploaded image and stores it as the image of a category.
+// OpenCategoryImage opens the stored image of a category.
+// MaxImageBytes returns the size of the largest image PutCategoryImage accepts, so callers can bound request bodies.
+// Every method works on the categories of the tenant carried by ctx.
+type IImagesService interface {
+	PutCategoryImage(ctx context.Context, categoryID int64, content io.Reader) (entity.Category, error)
+	OpenCategoryImage(ctx context.Context, categoryID int64) (io.ReadCloser, entity.CategoryImage, error)
+	MaxImageBytes() int64
+}
+
+// ImageOptions limits the images ImagesService accepts. Zero values fall back to the package defaults.
+type ImageOptions struct {
+	MaxBytes     int64
+	MinDimension int
+	MaxDimension int
+}
+
+// ImagesService keeps category images in a blob.Store and records them on the categories. It is also an
+// IEventPublisher: registered with CategoriesService, it removes the image of every deleted category from the store.
+// Uploads to the same category are serialized, so every replaced image is removed exactly once.
+type ImagesService struct {
+	repo       repository.ICategoriesRepository
+	store      blob.Store
+	options    ImageOptions
+	publishers []IEventPublisher
+
+	uploadsMu sync.Mutex
+	uploads   map[string]*uploadLock
+
+	wg sync.WaitGroup
+}
+
+// uploadLock serializes the uploads to one category. waiters counts the uploads holding or waiting for it, so it
+// is dropped once none are left.
+type uploadLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+// NewImagesService initializes a new ImagesService storing images in store and recording them in repo. Every
+// publisher is notified of the categories whose image changed.
+func NewImagesService(repo repository.ICategoriesRepository, store blob.Store, options ImageOptions, publishers ...IEventPublisher) (*ImagesService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("categories repository must not be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("blob store must not be nil")
+	}
+
+	if options.MaxBytes <= 0 {
+		options.MaxBytes = DefaultMaxImageBytes
+	}
+	if options.MinDimension <= 0 {
+		options.MinDimension = DefaultMinImageDimension
+	}
+	if options.MaxDimension <= 0 {
+		options.MaxDimension = DefaultMaxImageDimension
+	}
+	if options.MinDimension > options.MaxDimension {
+		return nil, fmt.Errorf("minimum image dimension %d exceeds the maximum %d", options.MinDimension, options.MaxDimension)
+	}
+
+	return &ImagesService{
+		repo:       repo,
+		store:      store,
+		options:    options,
+		publishers: publishers,
+		uploads:    make(map[string]*uploadLock),
+	}, nil
+}
+
+// MaxImageBytes returns the size of the largest image PutCategoryImage accepts.
+func (s *ImagesService) MaxImageBytes() int64 {
+	return s.options.MaxBytes
+}
+
+// PutCategoryImage reads an image from content and stores it as the image of the category, replacing and removing
+// any previous one. The format is sniffed from the content rather than trusted from the client. Returns
+// ErrCategoryNotFound when the category does not exist, ErrImageTooLarge when content exceeds MaxImageBytes,
+// ErrUnsupportedImageType when it is not a PNG, JPEG or GIF image and ErrInvalidImage when it cannot be decoded or its
+// dimensions are out of range.
+func (s *ImagesService) PutCategoryImage(ctx context.Context, categoryID int64, content io.Reader) (entity.Category, error) {
+	if s.repo.GetCategoryByID(ctx, categoryID).ID == 0 {
+		return entity.Category{}, entity.ErrCategoryNotFound
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, s.options.MaxBytes+1))
+	if err != nil {
+		return entity.Category{}, err
+	}
+	if int64(len(data)) > s.options.MaxBytes {
+		return entity.Category{}, fmt.Errorf("%w: the limit is %d bytes", entity.ErrImageTooLarge, s.options.MaxBytes)
+	}
+
+	img, err := s.checkImage(data)
+	if err != nil {
+		return entity.Category{}, err
+	}
+	img.Key = fmt.Sprintf("%s/%d/%s.%s", tenant.FromContext(ctx), categoryID, img.Checksum, imageFormats[img.ContentType].extension)
+
+	// The previous image is read and replaced under the lock, so a concurrent upload cannot replace it in between
+	// and leave one of the two images behind in the store.
+	unlock := s.lockUpload(ctx, categoryID)
+	defer unlock()
+
+	previous := s.repo.GetCategoryByID(ctx, categoryID)
+	if previous.ID == 0 {
+		return entity.Category{}, entity.ErrCategoryNotFound
+	}
+
+	if err := s.store.Put(ctx, img.Key, bytes.NewReader(data)); err != nil {
+		return entity.Category{}, err
+	}
+
+	cat, err := s.repo.SetCategoryImage(ctx, categoryID, &img)
+	if err != nil {
+		// The category was deleted while the image was stored.
+		s.deleteImage(ctx, img.Key)
+		return entity.Category{}, err
+	}
+
+	if previous.Image != nil && previous.Image.Key != img.Key {
+		s.deleteImage(ctx, previous.Image.Key)
+	}
+
+	s.publish(ctx, cat)
+	return cat, nil
+}
+
+// OpenCategoryImage opens the stored image of the category and returns it with its description. The caller must close
+// the image. Returns ErrCategoryNotFound when the category does not exist and ErrImageNotFound when it has no image.
+func (s *ImagesService) OpenCategoryImage(ctx context.Context, categoryID int64) (io.ReadCloser, entity.CategoryImage, error) {
+	cat := s.repo.GetCategoryByID(ctx, categoryID)
+	if cat.ID == 0 {
+		return nil, entity.CategoryImage{}, entity.ErrCategoryNotFound
+	}
+	if cat.Image == nil {
+		return nil, entity.CategoryImage{}, entity.ErrImageNotFound
+	}
+
+	object, err := s.store.Open(ctx, cat.Image.Key)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, entity.CategoryImage{}, entity.ErrImageNotFound
+	}
+	if err != nil {
+		return nil, entity.CategoryImage{}, err
+	}
+
+	return object, *cat.Image, nil
+}
+
+// Publish removes the image of a deleted category from blob storage in the background. It implements
+// IEventPublisher; other events are ignored.
+func (s *ImagesService) Publish(event entity.CategoryEvent) {
+	if event.Type != entity.EventCategoryDeleted || event.Category.Image == nil {
+		return
+	}
+
+	key := event.Category.Image.Key
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), imageCleanupTimeout)
+		defer cancel()
+		s.deleteImage(ctx, key)
+	}()
+}
+
+// Close waits for the removal of the images of deleted categories to finish.
+func (s *ImagesService) Close() {
+	s.wg.Wait()
+}
+
+// lockUpload locks the uploads to the category of the tenant carried by ctx and returns the function unlocking them.
+func (s *ImagesService) lockUpload(ctx context.Context, categoryID int64) func() {
+	key := fmt.Sprintf("%s/%d", tenant.FromContext(ctx), categoryID)
+
+	s.uploadsMu.Lock()
+	lock, ok := s.uploads[key]
+	if !ok {
+		lock = &uploadLock{}
+		s.uploads[key] = lock
+	}
+	lock.waiters++
+	s.uploadsMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		s.uploadsMu.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(s.uploads, key)
+		}
+		s.uploadsMu.Unlock()
+	}
+}
+
+// checkImage validates the content of an uploaded image and describes it. Errors match ErrUnsupportedImageType or
+// ErrInvalidImage.
+func (s *ImagesService) checkImage(data []byte) (entity.CategoryImage, error) {
+	contentType := http.DetectContentType(data)
+	format, ok := imageFormats[contentType]
+	if !ok {
+		return entity.CategoryImage{}, fmt.Errorf("%w: the content is %s, not PNG, JPEG or GIF", entity.ErrUnsupportedImageType, contentType)
+	}
+
+	config, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || name != format.name {
+		return entity.CategoryImage{}, fmt.Errorf("%w: the %s image cannot be decoded", entity.ErrInvalidImage, format.name)
+	}
+
+	minimum, maximum := s.options.MinDimension, s.options.MaxDimension
+	if config.Width < minimum || config.Height < minimum || config.Width > maximum || config.Height > maximum {
+		return entity.CategoryImage{}, fmt.Errorf("%w: the image is %dx%d pixels, width and height must be between %d and %d",
+			entity.ErrInvalidImage, config.Width, config.Height, minimum, maximum)
+	}
+
+	// The dimensions are bounded now, so decoding the whole image to catch truncated or corrupt data is cheap.
+	if _, _, err := image.Decode(bytes.NewReader(data)); err != nil {
+		return entity.CategoryImage{}, fmt.Errorf("%w: the %s image cannot be decoded", entity.ErrInvalidImage, format.name)
+	}
+
+	checksum := sha256.Sum256(data)
+	return entity.CategoryImage{
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       config.Width,
+		Height:      config.Height,
+		Checksum:    hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// deleteImage removes the image stored under key, logging failures: a leftover image wastes space but is never served.
+func (s *ImagesService) deleteImage(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Printf("category images: deleting %s: %v", key, err)
+	}
+}
+
+// publish notifies every registered publisher that the image of category changed.
+func (s *ImagesService) publish(ctx context.Context, category entity.Category) {
+	event := entity.CategoryEvent{
+		Type:       entity.EventCategoryUpdated,
+		TenantID:   tenant.FromContext(ctx),
+		Category:   category,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	for _, publisher := range s.publishers {
+		publisher.Publish(event)
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/blob"
+)
+
+// encodeImage returns a width x height image encoded with encode.
+func encodeImage(t *testing.T, width, height int, encode func(io.Writer, image.Image) error) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(w io.Writer, img image.Image) error  { return png.Encode(w, img) }
+func encodeJPEG(w io.Writer, img image.Image) error { return jpeg.Encode(w, img, nil) }
+func encodeGIF(w io.Writer, img image.Image) error  { return gif.Encode(w, img, nil) }
+
+// newImagesFixture returns an ImagesService over the default categories and a local store in a temporary directory.
+func newImagesFixture(t *testing.T, options ImageOptions, publishers ...IEventPublisher) (*ImagesService, *repository.CategoriesRepository, *blob.LocalStore) {
+	t.Helper()
+
+	repo, _ := repository.NewCategoriesRepository()
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+	svc, err := NewImagesService(repo, store, options, publishers...)
+	if err != nil {
+		t.Fatalf("unexpected service error: %v", err)
+	}
+	t.Cleanup(svc.Close)
+	return svc, repo, store
+}
+
+func TestNewImagesService(t *testing.T) {
+	repo, _ := repository.NewCategoriesRepository()
+	store, _ := blob.NewLocalStore(t.TempDir())
+
+	tests := []struct {
+		name    string
+		repo    repository.ICategoriesRepository
+		store   blob.Store
+		options ImageOptions
+		want    ImageOptions
+		wantErr bool
+	}{
+		{
+			name: "defaults", repo: repo, store: store,
+			want: ImageOptions{MaxBytes: DefaultMaxImageBytes, MinDimension: DefaultMinImageDimension, MaxDimension: DefaultMaxImageDimension},
+		},
+		{
+			name: "options", repo: repo, store: store,
+			options: ImageOptions{MaxBytes: 512, MinDimension: 8, MaxDimension: 64},
+			want:    ImageOptions{MaxBytes: 512, MinDimension: 8, MaxDimension: 64},
+		},
+		{name: "nil repository", store: store, wantErr: true},
+		{name: "nil store", repo: repo, wantErr: true},
+		{name: "minimum above maximum", repo: repo, store: store, options: ImageOptions{MinDimension: 64, MaxDimension: 32}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := NewImagesService(tt.repo, tt.store, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && svc.options != tt.want {
+				t.Fatalf("expected options %+v, got %+v", tt.want, svc.options)
+			}
+		})
+	}
+}
+
+func TestImagesService_PutCategoryImage(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              int64
+		content         []byte
+		wantContentType string
+		wantErr         error
+	}{
+		{name: "png", id: 1, content: encodeImage(t, 64, 32, encodePNG), wantContentType: "image/png"},
+		{name: "jpeg", id: 1, content: encodeImage(t, 64, 32, encodeJPEG), wantContentType: "image/jpeg"},
+		{name: "gif", id: 1, content: encodeImage(t, 64, 32, encodeGIF), wantContentType: "image/gif"},
+		{name: "unknown category", id: 99, content: encodeImage(t, 64, 32, encodePNG), wantErr: entity.ErrCategoryNotFound},
+		{name: "too large", id: 1, content: bytes.Repeat([]byte{0}, 4097), wantErr: entity.ErrImageTooLarge},
+		{name: "not an image", id: 1, content: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), wantErr: entity.ErrUnsupportedImageType},
+		{name: "empty", id: 1, content: nil, wantErr: entity.ErrUnsupportedImageType},
+		{name: "truncated", id: 1, content: encodeImage(t, 64, 32, encodePNG)[:60], wantErr: entity.ErrInvalidImage},
+		{name: "too small", id: 1, content: encodeImage(t, 8, 32, encodePNG), wantErr: entity.ErrInvalidImage},
+		{name: "too wide", id: 1, content: encodeImage(t, 129, 32, encodePNG), wantErr: entity.ErrInvalidImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			svc, repo, _ := newImagesFixture(t, ImageOptions{MaxBytes: 4096, MinDimension: 16, MaxDimension: 128}, publisher)
+
+			got, err := svc.PutCategoryImage(t.Context(), tt.id, bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				if len(publisher.events) != 0 {
+					t.Errorf("expected no events, got %d", len(publisher.events))
+				}
+				if stored := repo.GetCategoryByID(t.Context(), tt.id); stored.Image != nil {
+					t.Errorf("expected no image to be recorded, got %+v", stored.Image)
+				}
+				return
+			}
+
+			if got.Image == nil || got.Image.ContentType != tt.wantContentType || got.Image.Width != 64 || got.Image.Height != 32 || got.Image.Size != int64(len(tt.content)) {
+				t.Fatalf("unexpected image %+v", got.Image)
+			}
+			if len(publisher.events) != 1 || publisher.events[0].Type != entity.EventCategoryUpdated {
+				t.Errorf("expected one category.updated event, got %+v", publisher.events)
+			}
+
+			object, described, err := svc.OpenCategoryImage(t.Context(), tt.id)
+			if err != nil {
+				t.Fatalf("unexpected open error: %v", err)
+			}
+			defer object.Close()
+			if stored, _ := io.ReadAll(object); !bytes.Equal(stored, tt.content) || described != *got.Image {
+				t.Errorf("expected the uploaded image to be served")
+			}
+		})
+	}
+}
+
+func TestImagesService_ReplacesImage(t *testing.T) {
+	svc, _, store := newImagesFixture(t, ImageOptions{})
+
+	first, err := svc.PutCategoryImage(t.Context(), 1, bytes.NewReader(encodeImage(t, 32, 32, encodePNG)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(first.Image.Key, "default/1/") || !strings.HasSuffix(first.Image.Key, ".png") {
+		t.Errorf("unexpected key %q", first.Image.Key)
+	}
+
+	second, err := svc.PutCategoryImage(t.Context(), 1, bytes.NewReader(encodeImage(t, 48, 48, encodeJPEG)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Image.Checksum == first.Image.Checksum {
+		t.Fatalf("expected a new checksum")
+	}
+	if _, err := store.Open(t.Context(), first.Image.Key); !errors.Is(err, blob.ErrNotFound) {
+		t.Errorf("expected the replaced image to be deleted, got %v", err)
+	}
+
+	again, err := svc.PutCategoryImage(t.Context(), 1, bytes.NewReader(encodeImage(t, 48, 48, encodeJPEG)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	object, err := store.Open(t.Context(), again.Image.Key)
+	if err != nil {
+		t.Fatalf("expected uploading the same image again to keep it, got %v", err)
+	}
+	object.Close()
+}
+
+func TestImagesService_ConcurrentUploadsKeepOnlyTheCurrentImage(t *testing.T) {
+	svc, repo, store := newImagesFixture(t, ImageOptions{})
+
+	const uploads = 8
+	keys := make([]string, uploads)
+	var wg sync.WaitGroup
+	for i := range uploads {
+		data := encodeImage(t, 16+i, 16+i, encodePNG)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cat, err := svc.PutCategoryImage(t.Context(), 1, bytes.NewReader(data))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			keys[i] = cat.Image.Key
+		}()
+	}
+	wg.Wait()
+
+	current := repo.GetCategoryByID(t.Context(), 1).Image.Key
+	for _, key := range keys {
+		object, err := store.Open(t.Context(), key)
+		switch {
+		case key == current && err != nil:
+			t.Errorf("expected the current image %s to be stored, got %v", key, err)
+		case key != current && !errors.Is(err, blob.ErrNotFound):
+			t.Errorf("expected the replaced image %s to be deleted, got %v", key, err)
+		}
+		if err == nil {
+			object.Close()
+		}
+	}
+	if len(svc.uploads) != 0 {
+		t.Errorf("expected the upload locks to be released, got %d", len(svc.uploads))
+	}
+}
+
+func TestImagesService_OpenCategoryImage(t *testing.T) {
+	svc, _, store := newImagesFixture(t, ImageOptions{})
+
+	if _, _, err := svc.OpenCategoryImage(t.Context(), 99); !errors.Is(err, entity.ErrCategoryNotFound) {
+		t.Errorf("expected ErrCategoryNotFound, got %v", err)
+	}
+	if _, _, err := svc.OpenCategoryImage(t.Context(), 1); !errors.Is(err, entity.ErrImageNotFound) {
+		t.Errorf("expected ErrImageNotFound without an image, got %v", err)
+	}
+
+	cat, err := svc.PutCategoryImage(t.Context(), 1, bytes.NewReader(encodeImage(t, 32, 32, encodePNG)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = store.Delete(t.Context(), cat.Image.Key)
+	if _, _, err := svc.OpenCategoryImage(t.Context(), 1); !errors.Is(err, entity.ErrImageNotFound) {
+		t.Errorf("expected ErrImageNotFound for a missing blob, got %v", err)
+	}
+}
+
+func TestImagesService_DeletesImageOfDeletedCategory(t *testing.T) {
+	images, repo, store := newImagesFixture(t, ImageOptions{})
+	categories, _ := NewCategoriesService(repo, nil, nil, images)
+
+	cat, err := images.PutCategoryImage(t.Context(), 2, bytes.NewReader(encodeImage(t, 32, 32, encodePNG)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kept, err := images.PutCategoryImage(t.Context(), 3, bytes.NewReader(encodeImage(t, 32, 32, encodePNG)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := categories.DeleteCategory(t.Context(), 2); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	images.Close()
+
+	if _, err := store.Open(t.Context(), cat.Image.Key); !errors.Is(err, blob.ErrNotFound) {
+		t.Errorf("expected the image of the deleted category to be removed, got %v", err)
+	}
+	object, err := store.Open(t.Context(), kept.Image.Key)
+	if err != nil {
+		t.Fatalf("expected the images of other categories to be kept, got %v", err)
+	}
+	object.Close()
+}
@@ -1,37 +1,72 @@
 package service
 
 import (
-	"fmt"
+	"context"
+	"strings"
+	"time"
 
-	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/search"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
 )
 
 // ICategoriesService provides methods for managing category entities.
 // GetAllCategories retrieves all categories from the storage.
 // GetCategoryByID retrieves a category by its unique identifier.
-// InsertCategory creates a new category in the storage.
+// GetCategoryBySlug retrieves a category by its current or a previous slug.
+// InsertCategory validates and creates a new category in the storage.
 // UpdateCategory updates an existing category's details.
 // DeleteCategory removes a category from storage using its ID.
+// UpsertCategoryTranslation sets a category's name and description in one locale.
+// ReorderCategories changes the order categories are listed in.
+// SearchCategories finds categories by name and description, most relevant first.
+// Every method except API works on the categories of the tenant carried by ctx.
 type ICategoriesService interface {
-	GetAllCategories() []entity.Category
-	GetCategoryByID(categoryID int64) (entity.Category, error)
-	InsertCategory(parameter entity.Category) entity.Category
-	UpdateCategory(parameter entity.Category) (entity.Category, error)
-	DeleteCategory(categoryID int64) (int64, error)
+	GetAllCategories(ctx context.Context) []entity.Category
+	GetCategoryByID(ctx context.Context, categoryID int64) (entity.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (entity.Category, bool, error)
+	InsertCategory(ctx context.Context, parameter entity.Category) (entity.Category, error)
+	UpdateCategory(ctx context.Context, parameter entity.Category) (entity.Category, error)
+	DeleteCategory(ctx context.Context, categoryID int64) (int64, error)
+	UpsertCategoryTranslation(ctx context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error)
+	ReorderCategories(ctx context.Context, ids []int64) ([]entity.Category, error)
+	SearchCategories(ctx context.Context, query string, limit int) []entity.SearchResult
 	API() entity.HealthResponse
 }
 
+// IEventPublisher receives a notification for every category change performed through CategoriesService.
+// Publish is called synchronously after the change succeeds, so implementations must not block.
+type IEventPublisher interface {
+	Publish(event entity.CategoryEvent)
+}
+
+// ICategoryGuard decides whether a category may be deleted, e.g. because other resources still reference it.
+// GuardCategoryDelete calls deleteCategory to perform the delete, or returns an error matching ErrCategoryInUse
+// without calling it. Anything that must not change while the delete is decided, or that has to be cleaned up after
+// it, is handled around that call.
+type ICategoryGuard interface {
+	GuardCategoryDelete(ctx context.Context, categoryID int64, deleteCategory func() (int64, error)) (int64, error)
+}
+
 // CategoriesService provides methods to manage and manipulate category data using the ICategoriesRepository abstraction.
 type CategoriesService struct {
-	repo repository.ICategoriesRepository
+	repo       repository.ICategoriesRepository
+	attributes repository.IAttributesRepository
+	publishers []IEventPublisher
+	guard      ICategoryGuard
 }
 
 // NewCategoriesService initializes a new CategoriesService instance with the provided ICategoriesRepository implementation.
-func NewCategoriesService(repo repository.ICategoriesRepository) (*CategoriesService, error) {
+// Category attributes are validated against the definitions in attributes; without it, no attributes are defined.
+// Every DeleteCategory goes through guard; a nil guard lets every delete through. Every publisher is notified of
+// inserts, updates and deletes.
+func NewCategoriesService(repo repository.ICategoriesRepository, attributes repository.IAttributesRepository, guard ICategoryGuard, publishers ...IEventPublisher) (*CategoriesService, error) {
 	return &CategoriesService{
-		repo: repo,
+		repo:       repo,
+		attributes: attributes,
+		publishers: publishers,
+		guard:      guard,
 	}, nil
 }
 
@@ -43,33 +78,192 @@ func (s *CategoriesService) API() entity.HealthResponse {
 	}
 }
 
-// GetAllCategories retrieves all categories from the repository and returns them as a slice of Category entities.
-func (s *CategoriesService) GetAllCategories() []entity.Category {
-	return s.repo.GetAllCategories()
+// GetAllCategories retrieves all categories from the repository, ordered by position, and returns them as a slice of Category entities.
+func (s *CategoriesService) GetAllCategories(ctx context.Context) []entity.Category {
+	return s.repo.GetAllCategories(ctx)
 }
 
 // GetCategoryByID retrieves a category by its ID from the repository. Returns an error if the category is not found.
-func (s *CategoriesService) GetCategoryByID(categoryID int64) (entity.Category, error) {
-	cat := s.repo.GetCategoryByID(categoryID)
+func (s *CategoriesService) GetCategoryByID(ctx context.Context, categoryID int64) (entity.Category, error) {
+	cat := s.repo.GetCategoryByID(ctx, categoryID)
 
 	if cat.ID == 0 {
-		return entity.Category{}, fmt.Errorf(constants.ErrCategoryNotFound)
+		return entity.Category{}, entity.ErrCategoryNotFound
 	}
 
 	return cat, nil
 }
 
-// InsertCategory adds a new category to the repository and returns the created category.
-func (s *CategoriesService) InsertCategory(parameter entity.Category) entity.Category {
-	return s.repo.InsertCategory(parameter)
+// GetCategoryBySlug retrieves a category by its current slug or a slug it used before a rename; moved reports the
+// latter so callers can redirect to the current slug. Returns ErrCategoryNotFound if no category ever used the slug.
+func (s *CategoriesService) GetCategoryBySlug(ctx context.Context, slug string) (entity.Category, bool, error) {
+	cat, moved := s.repo.GetCategoryBySlug(ctx, slug)
+
+	if cat.ID == 0 {
+		return entity.Category{}, false, entity.ErrCategoryNotFound
+	}
+
+	return cat, moved, nil
+}
+
+// InsertCategory adds a new category to the repository and returns the created category. Its attributes are
+// checked against the attribute definitions and completed with their defaults; an *entity.AttributeError matching
+// ErrInvalidAttribute is returned when they do not match.
+func (s *CategoriesService) InsertCategory(ctx context.Context, parameter entity.Category) (entity.Category, error) {
+	attributes, err := s.checkAttributes(ctx, parameter.Attributes)
+	if err != nil {
+		return entity.Category{}, err
+	}
+	parameter.Attributes = attributes
+
+	cat := s.repo.InsertCategory(ctx, parameter)
+	s.publish(ctx, entity.EventCategoryCreated, cat)
+	return cat, nil
 }
 
 // UpdateCategory updates an existing category in the data source and returns the updated category or an error if any occurs.
-func (s *CategoriesService) UpdateCategory(parameter entity.Category) (entity.Category, error) {
-	return s.repo.UpdateCategory(parameter)
+// Given attributes replace the current ones and are checked like those of InsertCategory; without them, the current
+// attributes are kept.
+func (s *CategoriesService) UpdateCategory(ctx context.Context, parameter entity.Category) (entity.Category, error) {
+	if parameter.Attributes != nil {
+		attributes, err := s.checkAttributes(ctx, parameter.Attributes)
+		if err != nil {
+			return entity.Category{}, err
+		}
+		parameter.Attributes = attributes
+	}
+
+	cat, err := s.repo.UpdateCategory(ctx, parameter)
+	if err != nil {
+		return cat, err
+	}
+
+	s.publish(ctx, entity.EventCategoryUpdated, cat)
+	return cat, nil
 }
 
 // DeleteCategory removes a category by its ID and returns the number of rows affected or an error if the operation fails.
-func (s *CategoriesService) DeleteCategory(categoryID int64) (int64, error) {
-	return s.repo.DeleteCategory(categoryID)
+// With a guard given to NewCategoriesService, the guard may refuse the delete with an error matching ErrCategoryInUse.
+func (s *CategoriesService) DeleteCategory(ctx context.Context, categoryID int64) (int64, error) {
+	if s.guard == nil {
+		return s.deleteCategory(ctx, categoryID)
+	}
+
+	return s.guard.GuardCategoryDelete(ctx, categoryID, func() (int64, error) {
+		return s.deleteCategory(ctx, categoryID)
+	})
+}
+
+// deleteCategory implements DeleteCategory without the guard.
+func (s *CategoriesService) deleteCategory(ctx context.Context, categoryID int64) (int64, error) {
+	if len(s.publishers) == 0 {
+		return s.repo.DeleteCategory(ctx, categoryID)
+	}
+
+	// Capture the category before it disappears so subscribers know what was deleted.
+	cat := s.repo.GetCategoryByID(ctx, categoryID)
+
+	id, err := s.repo.DeleteCategory(ctx, categoryID)
+	if err != nil {
+		return id, err
+	}
+
+	if cat.ID == 0 {
+		cat.ID = id
+	}
+	s.publish(ctx, entity.EventCategoryDeleted, cat)
+	return id, nil
+}
+
+// UpsertCategoryTranslation stores the category's name and description in locale, which must already be in canonical
+// form. Translating into entity.DefaultLocale updates the category itself, just like UpdateCategory.
+// Returns ErrInvalidTranslation when the name is empty and ErrCategoryNotFound when the category does not exist.
+func (s *CategoriesService) UpsertCategoryTranslation(ctx context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	if strings.TrimSpace(translation.Name) == "" {
+		return entity.Category{}, entity.ErrInvalidTranslation
+	}
+
+	if locale == entity.DefaultLocale {
+		return s.UpdateCategory(ctx, entity.Category{
+			ID:          categoryID,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+	}
+
+	cat, err := s.repo.UpsertTranslation(ctx, categoryID, locale, translation)
+	if err != nil {
+		return cat, err
+	}
+
+	s.publish(ctx, entity.EventCategoryUpdated, cat)
+	return cat, nil
+}
+
+// ReorderCategories moves the categories with the given IDs, in that order, before all other categories, which keep
+// their relative order, and returns every category in the new order. Every category whose position changed is
+// published as updated. Returns ErrInvalidOrder when ids is empty or repeats an ID and ErrCategoryNotFound when an ID
+// is unknown.
+func (s *CategoriesService) ReorderCategories(ctx context.Context, ids []int64) ([]entity.Category, error) {
+	if len(ids) == 0 {
+		return nil, entity.ErrInvalidOrder
+	}
+
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, entity.ErrInvalidOrder
+		}
+		seen[id] = true
+	}
+
+	// Remember the current positions so only the categories that actually moved are published.
+	positions := make(map[int64]int64)
+	if len(s.publishers) > 0 {
+		for _, category := range s.repo.GetAllCategories(ctx) {
+			positions[category.ID] = category.Position
+		}
+	}
+
+	categories, err := s.repo.ReorderCategories(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, category := range categories {
+		if position, ok := positions[category.ID]; ok && position != category.Position {
+			s.publish(ctx, entity.EventCategoryUpdated, category)
+		}
+	}
+	return categories, nil
+}
+
+// SearchCategories returns up to limit categories matching query, most relevant first. The repository's index is used
+// when it has one; otherwise the current categories are indexed for this query only.
+func (s *CategoriesService) SearchCategories(ctx context.Context, query string, limit int) []entity.SearchResult {
+	if searchable, ok := s.repo.(repository.ISearchableRepository); ok {
+		return searchable.SearchCategories(ctx, query, limit)
+	}
+
+	index := search.NewIndex()
+	index.Reset(s.repo.GetAllCategories(ctx))
+	return index.Search(query, limit)
+}
+
+// publish notifies every registered publisher about a change to category in the tenant carried by ctx.
+func (s *CategoriesService) publish(ctx context.Context, eventType string, category entity.Category) {
+	if len(s.publishers) == 0 {
+		return
+	}
+
+	event := entity.CategoryEvent{
+		Type:       eventType,
+		TenantID:   tenant.FromContext(ctx),
+		Category:   category,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	for _, publisher := range s.publishers {
+		publisher.Publish(event)
+	}
 }
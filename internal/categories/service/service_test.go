@@ -1,45 +1,68 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
 	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
 )
 
 type mockRepository struct {
-	getAllCategoriesFunc func() []entity.Category
-	getCategoryByIDFunc  func(id int64) entity.Category
-	insertCategoryFunc   func(category entity.Category) entity.Category
-	updateCategoryFunc   func(category entity.Category) (entity.Category, error)
-	deleteCategoryFunc   func(id int64) (int64, error)
+	getAllCategoriesFunc  func() []entity.Category
+	getCategoryByIDFunc   func(id int64) entity.Category
+	getCategoryBySlugFunc func(slug string) (entity.Category, bool)
+	insertCategoryFunc    func(category entity.Category) entity.Category
+	updateCategoryFunc    func(category entity.Category) (entity.Category, error)
+	deleteCategoryFunc    func(id int64) (int64, error)
+	upsertTranslationFunc func(id int64, locale string, translation entity.Translation) (entity.Category, error)
+	reorderCategoriesFunc func(ids []int64) ([]entity.Category, error)
+	setCategoryImageFunc  func(id int64, image *entity.CategoryImage) (entity.Category, error)
 }
 
-func (m *mockRepository) GetAllCategories() []entity.Category {
+func (m *mockRepository) GetAllCategories(_ context.Context) []entity.Category {
 	return m.getAllCategoriesFunc()
 }
 
-func (m *mockRepository) GetCategoryByID(categoryID int64) entity.Category {
+func (m *mockRepository) GetCategoryByID(_ context.Context, categoryID int64) entity.Category {
 	return m.getCategoryByIDFunc(categoryID)
 }
 
-func (m *mockRepository) InsertCategory(parameter entity.Category) entity.Category {
+func (m *mockRepository) GetCategoryBySlug(_ context.Context, slug string) (entity.Category, bool) {
+	return m.getCategoryBySlugFunc(slug)
+}
+
+func (m *mockRepository) InsertCategory(_ context.Context, parameter entity.Category) entity.Category {
 	return m.insertCategoryFunc(parameter)
 }
 
-func (m *mockRepository) UpdateCategory(parameter entity.Category) (entity.Category, error) {
+func (m *mockRepository) UpdateCategory(_ context.Context, parameter entity.Category) (entity.Category, error) {
 	return m.updateCategoryFunc(parameter)
 }
 
-func (m *mockRepository) DeleteCategory(categoryID int64) (int64, error) {
+func (m *mockRepository) DeleteCategory(_ context.Context, categoryID int64) (int64, error) {
 	return m.deleteCategoryFunc(categoryID)
 }
 
+func (m *mockRepository) UpsertTranslation(_ context.Context, categoryID int64, locale string, translation entity.Translation) (entity.Category, error) {
+	return m.upsertTranslationFunc(categoryID, locale, translation)
+}
+
+func (m *mockRepository) ReorderCategories(_ context.Context, ids []int64) ([]entity.Category, error) {
+	return m.reorderCategoriesFunc(ids)
+}
+
+func (m *mockRepository) SetCategoryImage(_ context.Context, categoryID int64, image *entity.CategoryImage) (entity.Category, error) {
+	return m.setCategoryImageFunc(categoryID, image)
+}
+
 func TestNewCategoriesService(t *testing.T) {
 	repo := &mockRepository{}
-	svc, err := NewCategoriesService(repo)
+	svc, err := NewCategoriesService(repo, nil, nil)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -86,7 +109,7 @@ func TestCategoriesService_GetAllCategories(t *testing.T) {
 				},
 			}
 			svc := &CategoriesService{repo: repo}
-			got := svc.GetAllCategories()
+			got := svc.GetAllCategories(t.Context())
 			if !reflect.DeepEqual(got, tt.expected) {
 				t.Errorf("expected %v, got %v", tt.expected, got)
 			}
@@ -126,7 +149,7 @@ func TestCategoriesService_GetCategoryByID(t *testing.T) {
 				},
 			}
 			svc := &CategoriesService{repo: repo}
-			got, err := svc.GetCategoryByID(tt.id)
+			got, err := svc.GetCategoryByID(t.Context(), tt.id)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expectErr %v, got error %v", tt.expectErr, err)
 			}
@@ -150,7 +173,10 @@ func TestCategoriesService_InsertCategory(t *testing.T) {
 		},
 	}
 	svc := &CategoriesService{repo: repo}
-	got := svc.InsertCategory(input)
+	got, err := svc.InsertCategory(t.Context(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if !reflect.DeepEqual(got, expected) {
 		t.Errorf("expected %v, got %v", expected, got)
 	}
@@ -187,7 +213,7 @@ func TestCategoriesService_UpdateCategory(t *testing.T) {
 				},
 			}
 			svc := &CategoriesService{repo: repo}
-			got, err := svc.UpdateCategory(tt.input)
+			got, err := svc.UpdateCategory(t.Context(), tt.input)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expectErr %v, got %v", tt.expectErr, err)
 			}
@@ -198,6 +224,162 @@ func TestCategoriesService_UpdateCategory(t *testing.T) {
 	}
 }
 
+func TestCategoriesService_UpsertCategoryTranslation(t *testing.T) {
+	tests := []struct {
+		name        string
+		locale      string
+		translation entity.Translation
+		repoErr     error
+		want        entity.Category
+		wantErr     error
+		wantUpdate  bool
+		wantUpsert  bool
+	}{
+		{
+			name:        "translation",
+			locale:      "en",
+			translation: entity.Translation{Name: "Electronics"},
+			want:        entity.Category{ID: 1, Name: "Elektronik", Translations: map[string]entity.Translation{"en": {Name: "Electronics"}}},
+			wantUpsert:  true,
+		},
+		{
+			name:        "default locale updates the category",
+			locale:      entity.DefaultLocale,
+			translation: entity.Translation{Name: "Elektronika", Description: "Barang elektronik"},
+			want:        entity.Category{ID: 1, Name: "Elektronika", Description: "Barang elektronik"},
+			wantUpdate:  true,
+		},
+		{
+			name:        "empty name",
+			locale:      "en",
+			translation: entity.Translation{Name: "  "},
+			wantErr:     entity.ErrInvalidTranslation,
+		},
+		{
+			name:        "not found",
+			locale:      "en",
+			translation: entity.Translation{Name: "Electronics"},
+			repoErr:     entity.ErrCategoryNotFound,
+			wantErr:     entity.ErrCategoryNotFound,
+			wantUpsert:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated, upserted bool
+			repo := &mockRepository{
+				updateCategoryFunc: func(c entity.Category) (entity.Category, error) {
+					updated = true
+					return c, nil
+				},
+				upsertTranslationFunc: func(id int64, locale string, translation entity.Translation) (entity.Category, error) {
+					upserted = true
+					if tt.repoErr != nil {
+						return entity.Category{}, tt.repoErr
+					}
+					return entity.Category{ID: id, Name: "Elektronik", Translations: map[string]entity.Translation{locale: translation}}, nil
+				},
+			}
+			publisher := &recordingPublisher{}
+			svc, _ := NewCategoriesService(repo, nil, nil, publisher)
+
+			got, err := svc.UpsertCategoryTranslation(t.Context(), 1, tt.locale, tt.translation)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if updated != tt.wantUpdate || upserted != tt.wantUpsert {
+				t.Fatalf("expected update=%v upsert=%v, got update=%v upsert=%v", tt.wantUpdate, tt.wantUpsert, updated, upserted)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+
+			wantEvents := 0
+			if tt.wantErr == nil {
+				wantEvents = 1
+			}
+			if len(publisher.events) != wantEvents {
+				t.Fatalf("expected %d events, got %d", wantEvents, len(publisher.events))
+			}
+		})
+	}
+}
+
+func TestCategoriesService_ReorderCategories(t *testing.T) {
+	current := []entity.Category{{ID: 1, Position: 1024}, {ID: 2, Position: 2048}, {ID: 3, Position: 3072}}
+
+	tests := []struct {
+		name        string
+		ids         []int64
+		repoErr     error
+		wantErr     error
+		wantReorder bool
+		wantEvents  []int64
+	}{
+		{
+			name:        "publishes moved categories only",
+			ids:         []int64{3},
+			wantReorder: true,
+			wantEvents:  []int64{3},
+		},
+		{
+			name:    "empty order",
+			ids:     nil,
+			wantErr: entity.ErrInvalidOrder,
+		},
+		{
+			name:    "repeated id",
+			ids:     []int64{1, 2, 1},
+			wantErr: entity.ErrInvalidOrder,
+		},
+		{
+			name:        "unknown id",
+			ids:         []int64{9},
+			repoErr:     entity.ErrCategoryNotFound,
+			wantErr:     entity.ErrCategoryNotFound,
+			wantReorder: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reordered bool
+			repo := &mockRepository{
+				getAllCategoriesFunc: func() []entity.Category { return current },
+				reorderCategoriesFunc: func(ids []int64) ([]entity.Category, error) {
+					reordered = true
+					if tt.repoErr != nil {
+						return nil, tt.repoErr
+					}
+					return []entity.Category{{ID: 3, Position: 512}, {ID: 1, Position: 1024}, {ID: 2, Position: 2048}}, nil
+				},
+			}
+			publisher := &recordingPublisher{}
+			svc, _ := NewCategoriesService(repo, nil, nil, publisher)
+
+			_, err := svc.ReorderCategories(t.Context(), tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if reordered != tt.wantReorder {
+				t.Fatalf("expected reorder=%v, got %v", tt.wantReorder, reordered)
+			}
+
+			var events []int64
+			for _, event := range publisher.events {
+				if event.Type != entity.EventCategoryUpdated {
+					t.Fatalf("unexpected event type %q", event.Type)
+				}
+				events = append(events, event.Category.ID)
+			}
+			if !reflect.DeepEqual(events, tt.wantEvents) {
+				t.Fatalf("expected events for %v, got %v", tt.wantEvents, events)
+			}
+		})
+	}
+}
+
 func TestCategoriesService_DeleteCategory(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -229,7 +411,7 @@ func TestCategoriesService_DeleteCategory(t *testing.T) {
 				},
 			}
 			svc := &CategoriesService{repo: repo}
-			got, err := svc.DeleteCategory(tt.id)
+			got, err := svc.DeleteCategory(t.Context(), tt.id)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("expectErr %v, got %v", tt.expectErr, err)
 			}
@@ -239,3 +421,284 @@ func TestCategoriesService_DeleteCategory(t *testing.T) {
 		})
 	}
 }
+
+// guardFunc adapts a function to ICategoryGuard.
+type guardFunc func(categoryID int64, deleteCategory func() (int64, error)) (int64, error)
+
+func (f guardFunc) GuardCategoryDelete(_ context.Context, categoryID int64, deleteCategory func() (int64, error)) (int64, error) {
+	return f(categoryID, deleteCategory)
+}
+
+func TestCategoriesService_DeleteCategoryGuard(t *testing.T) {
+	tests := []struct {
+		name        string
+		guard       guardFunc
+		wantErr     error
+		wantDeleted bool
+		wantEvents  int
+	}{
+		{
+			name: "allowed",
+			guard: func(_ int64, deleteCategory func() (int64, error)) (int64, error) {
+				return deleteCategory()
+			},
+			wantDeleted: true,
+			wantEvents:  1,
+		},
+		{
+			name: "refused",
+			guard: func(categoryID int64, _ func() (int64, error)) (int64, error) {
+				return 0, entity.ErrCategoryInUse
+			},
+			wantErr: entity.ErrCategoryInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			repo := &mockRepository{
+				getCategoryByIDFunc: func(id int64) entity.Category {
+					return entity.Category{ID: id, Name: "Old"}
+				},
+				deleteCategoryFunc: func(id int64) (int64, error) {
+					deleted = true
+					return id, nil
+				},
+			}
+			publisher := &recordingPublisher{}
+			svc, _ := NewCategoriesService(repo, nil, tt.guard, publisher)
+
+			_, err := svc.DeleteCategory(t.Context(), 3)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("expected deleted %v, got %v", tt.wantDeleted, deleted)
+			}
+			if len(publisher.events) != tt.wantEvents {
+				t.Errorf("expected %d events, got %d", tt.wantEvents, len(publisher.events))
+			}
+		})
+	}
+}
+
+type recordingPublisher struct {
+	events []entity.CategoryEvent
+}
+
+func (p *recordingPublisher) Publish(event entity.CategoryEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestCategoriesService_PublishesEvents(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       *mockRepository
+		call       func(ctx context.Context, svc *CategoriesService)
+		wantType   string
+		wantID     int64
+		wantName   string
+		wantEvents int
+	}{
+		{
+			name: "insert",
+			repo: &mockRepository{
+				insertCategoryFunc: func(c entity.Category) entity.Category {
+					c.ID = 5
+					return c
+				},
+			},
+			call: func(ctx context.Context, svc *CategoriesService) {
+				svc.InsertCategory(ctx, entity.Category{Name: "New"})
+			},
+			wantType:   entity.EventCategoryCreated,
+			wantID:     5,
+			wantName:   "New",
+			wantEvents: 1,
+		},
+		{
+			name: "update",
+			repo: &mockRepository{
+				updateCategoryFunc: func(c entity.Category) (entity.Category, error) {
+					return c, nil
+				},
+			},
+			call: func(ctx context.Context, svc *CategoriesService) {
+				_, _ = svc.UpdateCategory(ctx, entity.Category{ID: 2, Name: "Updated"})
+			},
+			wantType:   entity.EventCategoryUpdated,
+			wantID:     2,
+			wantName:   "Updated",
+			wantEvents: 1,
+		},
+		{
+			name: "update error",
+			repo: &mockRepository{
+				updateCategoryFunc: func(c entity.Category) (entity.Category, error) {
+					return entity.Category{}, entity.ErrCategoryNotFound
+				},
+			},
+			call: func(ctx context.Context, svc *CategoriesService) {
+				_, _ = svc.UpdateCategory(ctx, entity.Category{ID: 2})
+			},
+		},
+		{
+			name: "delete",
+			repo: &mockRepository{
+				getCategoryByIDFunc: func(id int64) entity.Category {
+					return entity.Category{ID: id, Name: "Old"}
+				},
+				deleteCategoryFunc: func(id int64) (int64, error) {
+					return id, nil
+				},
+			},
+			call: func(ctx context.Context, svc *CategoriesService) {
+				_, _ = svc.DeleteCategory(ctx, 3)
+			},
+			wantType:   entity.EventCategoryDeleted,
+			wantID:     3,
+			wantName:   "Old",
+			wantEvents: 1,
+		},
+		{
+			name: "delete error",
+			repo: &mockRepository{
+				getCategoryByIDFunc: func(id int64) entity.Category {
+					return entity.Category{}
+				},
+				deleteCategoryFunc: func(id int64) (int64, error) {
+					return 0, entity.ErrCategoryNotFound
+				},
+			},
+			call: func(ctx context.Context, svc *CategoriesService) {
+				_, _ = svc.DeleteCategory(ctx, 3)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			svc, _ := NewCategoriesService(tt.repo, nil, nil, publisher)
+
+			tt.call(tenant.WithID(t.Context(), "acme"), svc)
+
+			if len(publisher.events) != tt.wantEvents {
+				t.Fatalf("expected %d events, got %d", tt.wantEvents, len(publisher.events))
+			}
+			if tt.wantEvents == 0 {
+				return
+			}
+
+			event := publisher.events[0]
+			if event.Type != tt.wantType || event.Category.ID != tt.wantID || event.Category.Name != tt.wantName {
+				t.Fatalf("unexpected event: %+v", event)
+			}
+			if event.TenantID != "acme" {
+				t.Fatalf("expected the event to carry tenant acme, got %q", event.TenantID)
+			}
+			if event.OccurredAt.IsZero() {
+				t.Fatalf("expected occurred_at to be set")
+			}
+		})
+	}
+}
+
+type searchableRepository struct {
+	mockRepository
+	results []entity.SearchResult
+}
+
+func (s *searchableRepository) SearchCategories(_ context.Context, query string, limit int) []entity.SearchResult {
+	return s.results
+}
+
+func TestCategoriesService_SearchCategories(t *testing.T) {
+	categories := []entity.Category{
+		{ID: 1, Name: "Elektronik", Description: "Kategori Elektronik"},
+		{ID: 2, Name: "Handphone", Description: "Kategori Handphone"},
+	}
+
+	tests := []struct {
+		name  string
+		repo  repository.ICategoriesRepository
+		query string
+		want  []int64
+	}{
+		{
+			name:  "uses repository index",
+			repo:  &searchableRepository{results: []entity.SearchResult{{Category: categories[0], Score: 1}}},
+			query: "anything",
+			want:  []int64{1},
+		},
+		{
+			name:  "indexes plain repository on demand",
+			repo:  &mockRepository{getAllCategoriesFunc: func() []entity.Category { return categories }},
+			query: "hanphone",
+			want:  []int64{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &CategoriesService{repo: tt.repo}
+
+			var got []int64
+			for _, result := range s.SearchCategories(t.Context(), tt.query, 10) {
+				got = append(got, result.Category.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCategoriesService_GetCategoryBySlug(t *testing.T) {
+	tests := []struct {
+		name      string
+		repoRes   entity.Category
+		repoMoved bool
+		want      entity.Category
+		wantMoved bool
+		wantErr   error
+	}{
+		{
+			name:    "found",
+			repoRes: entity.Category{ID: 1, Slug: "handphone"},
+			want:    entity.Category{ID: 1, Slug: "handphone"},
+		},
+		{
+			name:      "moved",
+			repoRes:   entity.Category{ID: 1, Slug: "telepon"},
+			repoMoved: true,
+			want:      entity.Category{ID: 1, Slug: "telepon"},
+			wantMoved: true,
+		},
+		{
+			name:    "not found",
+			want:    entity.Category{},
+			wantErr: entity.ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				getCategoryBySlugFunc: func(slug string) (entity.Category, bool) {
+					return tt.repoRes, tt.repoMoved
+				},
+			}
+			s := &CategoriesService{repo: repo}
+
+			got, moved, err := s.GetCategoryBySlug(t.Context(), "handphone")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || moved != tt.wantMoved {
+				t.Fatalf("expected (%v, %v), got (%v, %v)", tt.want, tt.wantMoved, got, moved)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+)
+
+// subscriberBuffer is how many messages may queue for one subscriber before it is considered too slow and dropped.
+const subscriberBuffer = 64
+
+// Message is a category event numbered with a sequence ID, ready to be written as a Server-Sent Event.
+// IDs are shared by all tenants, so the messages of one tenant are increasing but not consecutive.
+type Message struct {
+	ID     uint64
+	Type   string
+	Tenant string
+	Data   []byte
+}
+
+// Subscription receives the messages published after it was opened. C is closed when the subscription is
+// cancelled or when the subscriber fell too far behind; the client should then resume with the last ID it saw.
+type Subscription struct {
+	C <-chan Message
+
+	// StartID is the ID of the last event published before the subscription was opened.
+	StartID uint64
+
+	tenant string
+	ch     chan Message
+	broker *Broker
+	once   sync.Once
+}
+
+// Close cancels the subscription and releases its resources. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broker.unsubscribe(s)
+}
+
+// Broker is an in-process pub/sub for category events. It numbers every event, keeps the most recent ones in a
+// bounded replay buffer so reconnecting clients can resume from a Last-Event-ID, and fans events out to subscribers.
+type Broker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	replay      []Message
+	next        int
+	full        bool
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBroker initializes a Broker that keeps the last replaySize events for resuming clients.
+func NewBroker(replaySize int) (*Broker, error) {
+	if replaySize <= 0 {
+		return nil, fmt.Errorf("stream replay size must be positive, got %d", replaySize)
+	}
+
+	return &Broker{
+		replay:      make([]Message, replaySize),
+		subscribers: make(map[*Subscription]struct{}),
+	}, nil
+}
+
+// Publish numbers event, stores it in the replay buffer and delivers it to every subscriber of the event's tenant.
+// It never blocks: a subscriber whose queue is full is dropped. It implements the categories service's IEventPublisher.
+func (b *Broker) Publish(event entity.CategoryEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("stream: encoding %s event: %v", event.Type, err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	msg := Message{ID: b.lastID, Type: event.Type, Tenant: event.TenantID, Data: data}
+
+	b.replay[b.next] = msg
+	b.next = (b.next + 1) % len(b.replay)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for sub := range b.subscribers {
+		if sub.tenant != msg.Tenant {
+			continue
+		}
+
+		select {
+		case sub.ch <- msg:
+		default:
+			b.drop(sub)
+		}
+	}
+}
+
+// Subscribe opens a subscription to the messages of tenant and returns its buffered messages published after
+// lastEventID. complete is false when lastEventID is older than the replay buffer, meaning some events were lost and
+// the client should reload its state. A lastEventID of 0 replays nothing.
+func (b *Broker) Subscribe(tenant string, lastEventID uint64) (sub *Subscription, missed []Message, complete bool) {
+	ch := make(chan Message, subscriberBuffer)
+	sub = &Subscription{C: ch, tenant: tenant, ch: ch, broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub.StartID = b.lastID
+	b.subscribers[sub] = struct{}{}
+
+	if lastEventID == 0 || lastEventID >= b.lastID {
+		return sub, nil, lastEventID <= b.lastID
+	}
+
+	buffered := b.buffered()
+	complete = len(buffered) > 0 && buffered[0].ID <= lastEventID+1
+	for _, msg := range buffered {
+		if msg.ID > lastEventID && msg.Tenant == tenant {
+			missed = append(missed, msg)
+		}
+	}
+
+	return sub, missed, complete
+}
+
+// Subscribers returns the number of open subscriptions.
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscribers)
+}
+
+// buffered returns the replay buffer oldest first. The caller must hold b.mu.
+func (b *Broker) buffered() []Message {
+	if !b.full {
+		return append([]Message{}, b.replay[:b.next]...)
+	}
+
+	return append(append([]Message{}, b.replay[b.next:]...), b.replay[:b.next]...)
+}
+
+// unsubscribe removes sub from the broker.
+func (b *Broker) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.drop(sub)
+}
+
+// drop removes sub and closes its channel. The caller must hold b.mu.
+func (b *Broker) drop(sub *Subscription) {
+	sub.once.Do(func() {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	})
+}
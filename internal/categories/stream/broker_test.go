@@ -0,0 +1,156 @@
+package stream
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+func publishN(b *Broker, n int) {
+	for i := 1; i <= n; i++ {
+		b.Publish(entity.CategoryEvent{Type: entity.EventCategoryCreated, TenantID: tenant.DefaultID, Category: entity.Category{ID: int64(i)}})
+	}
+}
+
+func ids(msgs []Message) []uint64 {
+	out := []uint64{}
+	for _, msg := range msgs {
+		out = append(out, msg.ID)
+	}
+	return out
+}
+
+func TestNewBroker(t *testing.T) {
+	if _, err := NewBroker(0); err == nil {
+		t.Fatalf("expected error for empty replay buffer")
+	}
+	if _, err := NewBroker(8); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBroker_SubscribeReplay(t *testing.T) {
+	tests := []struct {
+		name         string
+		published    int
+		lastEventID  uint64
+		wantMissed   []uint64
+		wantComplete bool
+	}{
+		{name: "fresh subscriber", published: 3, lastEventID: 0, wantMissed: []uint64{}, wantComplete: true},
+		{name: "up to date", published: 3, lastEventID: 3, wantMissed: []uint64{}, wantComplete: true},
+		{name: "resume", published: 3, lastEventID: 1, wantMissed: []uint64{2, 3}, wantComplete: true},
+		{name: "resume at buffer edge", published: 6, lastEventID: 1, wantMissed: []uint64{2, 3, 4, 5, 6}, wantComplete: true},
+		{name: "gap", published: 8, lastEventID: 1, wantMissed: []uint64{4, 5, 6, 7, 8}, wantComplete: false},
+		{name: "unknown future id", published: 2, lastEventID: 9, wantMissed: []uint64{}, wantComplete: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := NewBroker(5)
+			publishN(b, tt.published)
+
+			sub, missed, complete := b.Subscribe(tenant.DefaultID, tt.lastEventID)
+			defer sub.Close()
+
+			if got := ids(missed); !reflect.DeepEqual(got, tt.wantMissed) {
+				t.Fatalf("expected missed %v, got %v", tt.wantMissed, got)
+			}
+			if complete != tt.wantComplete {
+				t.Fatalf("expected complete %v, got %v", tt.wantComplete, complete)
+			}
+			if sub.StartID != uint64(tt.published) {
+				t.Fatalf("expected start id %d, got %d", tt.published, sub.StartID)
+			}
+		})
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	b, _ := NewBroker(4)
+	sub, _, _ := b.Subscribe(tenant.DefaultID, 0)
+	defer sub.Close()
+
+	b.Publish(entity.CategoryEvent{Type: entity.EventCategoryUpdated, TenantID: tenant.DefaultID, Category: entity.Category{ID: 7, Name: "Susu"}})
+
+	msg := <-sub.C
+	if msg.ID != 1 || msg.Type != entity.EventCategoryUpdated || msg.Tenant != tenant.DefaultID {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	var event entity.CategoryEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		t.Fatalf("unmarshal %s: %v", msg.Data, err)
+	}
+	if event.Category.Name != "Susu" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
+
+func TestBroker_Unsubscribe(t *testing.T) {
+	b, _ := NewBroker(4)
+	sub, _, _ := b.Subscribe(tenant.DefaultID, 0)
+
+	if b.Subscribers() != 1 {
+		t.Fatalf("expected one subscriber")
+	}
+
+	sub.Close()
+	sub.Close()
+
+	if b.Subscribers() != 0 {
+		t.Fatalf("expected subscriber to be removed")
+	}
+	if _, ok := <-sub.C; ok {
+		t.Fatalf("expected channel to be closed")
+	}
+
+	publishN(b, 1)
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	b, _ := NewBroker(4)
+	slow, _, _ := b.Subscribe(tenant.DefaultID, 0)
+
+	publishN(b, subscriberBuffer+1)
+
+	if b.Subscribers() != 0 {
+		t.Fatalf("expected slow subscriber to be dropped")
+	}
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Fatalf("expected %d queued messages before the drop, got %d", subscriberBuffer, received)
+	}
+}
+
+func TestBroker_TenantIsolation(t *testing.T) {
+	b, _ := NewBroker(8)
+	acme, _, _ := b.Subscribe("acme", 0)
+	defer acme.Close()
+
+	b.Publish(entity.CategoryEvent{Type: entity.EventCategoryCreated, TenantID: "globex", Category: entity.Category{ID: 1}})
+	b.Publish(entity.CategoryEvent{Type: entity.EventCategoryCreated, TenantID: "acme", Category: entity.Category{ID: 1}})
+	b.Publish(entity.CategoryEvent{Type: entity.EventCategoryCreated, TenantID: "globex", Category: entity.Category{ID: 2}})
+
+	if msg := <-acme.C; msg.ID != 2 || msg.Tenant != "acme" {
+		t.Fatalf("expected only acme's message, got %+v", msg)
+	}
+	select {
+	case msg := <-acme.C:
+		t.Fatalf("expected globex's messages not to reach acme, got %+v", msg)
+	default:
+	}
+
+	resumed, missed, complete := b.Subscribe("acme", 1)
+	defer resumed.Close()
+	if got := ids(missed); !complete || !reflect.DeepEqual(got, []uint64{2}) {
+		t.Fatalf("expected acme's replay to be [2], got %v (complete %v)", got, complete)
+	}
+}
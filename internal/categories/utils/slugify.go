@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations covers letters that do not decompose into an ASCII base letter plus accents.
+var transliterations = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "ae", "œ", "oe", "Œ", "oe", "ø", "o", "Ø", "o",
+	"đ", "d", "Đ", "d", "ł", "l", "Ł", "l", "&", " dan ",
+)
+
+// Slugify converts text into a lower-case, URL-safe slug: accents are transliterated to ASCII, every run of
+// characters other than letters and digits becomes a single hyphen, and leading or trailing hyphens are dropped.
+// It returns an empty string when text contains no letters or digits.
+func Slugify(text string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	ascii, _, err := transform.String(stripAccents, transliterations.Replace(text))
+	if err != nil {
+		ascii = text
+	}
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(ascii) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package utils
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "simple",
+			text:     "Handphone",
+			expected: "handphone",
+		},
+		{
+			name:     "whitespace collapsed",
+			text:     "  Alat   Tulis \t Kantor ",
+			expected: "alat-tulis-kantor",
+		},
+		{
+			name:     "accents transliterated",
+			text:     "Café Crème Brûlée",
+			expected: "cafe-creme-brulee",
+		},
+		{
+			name:     "special letters",
+			text:     "Straße & Smørrebrød",
+			expected: "strasse-dan-smorrebrod",
+		},
+		{
+			name:     "punctuation",
+			text:     "Kabel USB-C / Charger (2m)!",
+			expected: "kabel-usb-c-charger-2m",
+		},
+		{
+			name:     "non latin dropped",
+			text:     "手机",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.text); got != tt.expected {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	categoriesEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/pagination"
+)
+
+// ProductsHandler serves the product endpoints with the help of IProductsService.
+type ProductsHandler struct {
+	service service.IProductsService
+}
+
+// NewProductsHandler initializes and returns a new ProductsHandler instance with the provided IProductsService implementation.
+func NewProductsHandler(service service.IProductsService) (*ProductsHandler, error) {
+	if service == nil {
+		return nil, fmt.Errorf("products service must not be nil")
+	}
+
+	return &ProductsHandler{
+		service: service,
+	}, nil
+}
+
+// GetAllProducts answers with a page of products ordered by ID.
+func (d *ProductsHandler) GetAllProducts(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	page, perPage, err := pagination.FromQuery(r.URL.Query())
+	if err != nil {
+		writePaginationError(w, r, err)
+		return
+	}
+
+	products, total := d.service.GetAllProducts(r.Context(), pagination.Offset(page, perPage), perPage)
+
+	pagination.SetHeaders(w, r, page, perPage, total)
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgProductsListed)
+	result.Data = products
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// GetCategoryProducts answers with a page of the products of the category with the ID in the path.
+func (d *ProductsHandler) GetCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	categoryID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidCategoryID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return
+	}
+
+	page, perPage, err := pagination.FromQuery(r.URL.Query())
+	if err != nil {
+		writePaginationError(w, r, err)
+		return
+	}
+
+	products, total, err := d.service.GetProductsByCategory(r.Context(), categoryID, pagination.Offset(page, perPage), perPage)
+	if err != nil {
+		writeProductError(w, r, err)
+		return
+	}
+
+	pagination.SetHeaders(w, r, page, perPage, total)
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgProductsListed)
+	result.Data = products
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// GetProductByID answers with the product with the ID in the path.
+func (d *ProductsHandler) GetProductByID(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, ok := productID(w, r)
+	if !ok {
+		return
+	}
+
+	product, err := d.service.GetProductByID(r.Context(), id)
+	if err != nil {
+		writeProductError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgProductFound)
+	result.Data = product
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// InsertProduct creates a product from the request body.
+func (d *ProductsHandler) InsertProduct(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	var product entity.Product
+	if err := json_wrapper.ParseRequest(r, &product, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+
+	created, err := d.service.InsertProduct(r.Context(), product)
+	if err != nil {
+		writeProductError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgProductCreated)
+	result.Data = created
+	json_wrapper.WriteResponse(w, r, http.StatusCreated, result)
+}
+
+// UpdateProduct replaces the product with the ID in the path.
+func (d *ProductsHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, ok := productID(w, r)
+	if !ok {
+		return
+	}
+
+	var product entity.Product
+	if err := json_wrapper.ParseRequest(r, &product, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeError(w, r, err)
+		return
+	}
+	product.ID = id
+
+	updated, err := d.service.UpdateProduct(r.Context(), product)
+	if err != nil {
+		writeProductError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgProductUpdated)
+	result.Data = updated
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// DeleteProduct removes the product with the ID in the path.
+func (d *ProductsHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, ok := productID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := d.service.DeleteProduct(r.Context(), id); err != nil {
+		writeProductError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgProductDeleted, id)
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// productID returns the product ID in the path of r, or answers r with 400 Bad Request when it is not a number.
+func productID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		var result json_wrapper.APIResponse
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidProductID)
+		json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeProductError answers r with the error envelope describing an error returned by IProductsService; rejected
+// products carry the offending field as error detail. Errors that are not part of the API contract are logged and
+// reported as an internal server error so their details do not leak.
+func writeProductError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := http.StatusInternalServerError, constants.MsgInternalServer
+	switch {
+	case errors.Is(err, entity.ErrProductNotFound):
+		status, code = http.StatusNotFound, constants.MsgProductNotFound
+	case errors.Is(err, categoriesEntity.ErrCategoryNotFound):
+		status, code = http.StatusNotFound, constants.MsgCategoryNotFound
+	case errors.Is(err, entity.ErrInvalidProduct):
+		status, code = http.StatusBadRequest, constants.MsgInvalidProduct
+	default:
+		log.Printf("products: %v", err)
+	}
+
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, code)
+	var productErr *entity.ProductError
+	if errors.As(err, &productErr) {
+		result.Error = &json_wrapper.ErrorDetail{
+			Reason:  json_wrapper.ReasonInvalidValue,
+			Field:   productErr.Field,
+			Message: productErr.Field + " " + productErr.Reason,
+		}
+	}
+	json_wrapper.WriteResponse(w, r, status, result)
+}
+
+// writePaginationError answers r with 400 Bad Request for err, an error returned by pagination.FromQuery.
+func writePaginationError(w http.ResponseWriter, r *http.Request, err error) {
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, constants.MsgInvalidPagination)
+	result.Error = &json_wrapper.ErrorDetail{Reason: json_wrapper.ReasonInvalidValue, Message: err.Error()}
+	json_wrapper.WriteResponse(w, r, http.StatusBadRequest, result)
+}
+
+// writeDecodeError answers r with the error envelope for err, an error returned by json_wrapper.ParseRequest, and the
+// details of the offending field and position.
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	status := result.SetDecodeError(err)
+	code := constants.MsgInvalidProduct
+	switch status {
+	case http.StatusRequestEntityTooLarge:
+		code = constants.MsgRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		code = constants.MsgUnsupportedMediaType
+	}
+	result.SetMessage(constants.Messages, r, code)
+	json_wrapper.WriteResponse(w, r, status, result)
+}
@@ -0,0 +1,334 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	categoriesEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/pagination"
+)
+
+type mockService struct {
+	GetAllProductsFunc        func(offset, limit int) ([]entity.Product, int)
+	GetProductsByCategoryFunc func(categoryID int64, offset, limit int) ([]entity.Product, int, error)
+	GetProductByIDFunc        func(productID int64) (entity.Product, error)
+	InsertProductFunc         func(parameter entity.Product) (entity.Product, error)
+	UpdateProductFunc         func(parameter entity.Product) (entity.Product, error)
+	DeleteProductFunc         func(productID int64) error
+}
+
+func (m *mockService) GetAllProducts(_ context.Context, offset, limit int) ([]entity.Product, int) {
+	return m.GetAllProductsFunc(offset, limit)
+}
+func (m *mockService) GetProductsByCategory(_ context.Context, categoryID int64, offset, limit int) ([]entity.Product, int, error) {
+	return m.GetProductsByCategoryFunc(categoryID, offset, limit)
+}
+func (m *mockService) GetProductByID(_ context.Context, productID int64) (entity.Product, error) {
+	return m.GetProductByIDFunc(productID)
+}
+func (m *mockService) InsertProduct(_ context.Context, parameter entity.Product) (entity.Product, error) {
+	return m.InsertProductFunc(parameter)
+}
+func (m *mockService) UpdateProduct(_ context.Context, parameter entity.Product) (entity.Product, error) {
+	return m.UpdateProductFunc(parameter)
+}
+func (m *mockService) DeleteProduct(_ context.Context, productID int64) error {
+	return m.DeleteProductFunc(productID)
+}
+
+// decodeResponse returns the envelope answered in w.
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) json_wrapper.APIResponse {
+	t.Helper()
+
+	var got json_wrapper.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error decoding %s: %v", w.Body.String(), err)
+	}
+	return got
+}
+
+func TestNewProductsHandler(t *testing.T) {
+	if _, err := NewProductsHandler(nil); err == nil {
+		t.Errorf("expected an error for a nil service")
+	}
+	if _, err := NewProductsHandler(&mockService{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestProductsHandler_GetAllProducts(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		wantStatus  int
+		wantMsgCode string
+		wantOffset  int
+		wantLimit   int
+	}{
+		{name: "default page", target: "/products", wantStatus: http.StatusOK, wantMsgCode: constants.MsgProductsListed, wantLimit: pagination.DefaultPerPage},
+		{name: "second page", target: "/products?page=2&per_page=2", wantStatus: http.StatusOK, wantMsgCode: constants.MsgProductsListed, wantOffset: 2, wantLimit: 2},
+		{name: "invalid page", target: "/products?page=0", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidPagination},
+		{name: "page size too large", target: "/products?per_page=500", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidPagination},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOffset, gotLimit int
+			h, _ := NewProductsHandler(&mockService{
+				GetAllProductsFunc: func(offset, limit int) ([]entity.Product, int) {
+					gotOffset, gotLimit = offset, limit
+					return []entity.Product{{ID: 3, CategoryID: 1, Name: "Laptop"}}, 5
+				},
+			})
+			w := httptest.NewRecorder()
+
+			h.GetAllProducts(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetAllProducts() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := decodeResponse(t, w); got.MessageCode != tt.wantMsgCode {
+				t.Errorf("GetAllProducts() message code = %v, want %v", got.MessageCode, tt.wantMsgCode)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if gotOffset != tt.wantOffset || gotLimit != tt.wantLimit {
+				t.Errorf("GetAllProducts() asked for offset %d limit %d, want %d and %d", gotOffset, gotLimit, tt.wantOffset, tt.wantLimit)
+			}
+			if got := w.Header().Get(pagination.TotalCountHeader); got != "5" {
+				t.Errorf("GetAllProducts() %s = %q, want 5", pagination.TotalCountHeader, got)
+			}
+			if got := w.Header().Get("Link"); !strings.Contains(got, `rel="last"`) {
+				t.Errorf("GetAllProducts() Link = %q", got)
+			}
+		})
+	}
+}
+
+func TestProductsHandler_GetCategoryProducts(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		query       string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+	}{
+		{name: "success", id: "1", query: "?per_page=1", wantStatus: http.StatusOK, wantMsgCode: constants.MsgProductsListed},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidCategoryID},
+		{name: "invalid page", id: "1", query: "?page=x", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidPagination},
+		{name: "category not found", id: "99", mockErr: categoriesEntity.ErrCategoryNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCategoryID int64
+			h, _ := NewProductsHandler(&mockService{
+				GetProductsByCategoryFunc: func(categoryID int64, offset, limit int) ([]entity.Product, int, error) {
+					gotCategoryID = categoryID
+					if tt.mockErr != nil {
+						return nil, 0, tt.mockErr
+					}
+					return []entity.Product{{ID: 1, CategoryID: categoryID, Name: "Laptop"}}, 2, nil
+				},
+			})
+			req := httptest.NewRequest(http.MethodGet, "/categories/"+tt.id+"/products"+tt.query, nil)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			h.GetCategoryProducts(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetCategoryProducts() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			got := decodeResponse(t, w)
+			if got.MessageCode != tt.wantMsgCode {
+				t.Errorf("GetCategoryProducts() message code = %v, want %v", got.MessageCode, tt.wantMsgCode)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if gotCategoryID != 1 {
+				t.Errorf("GetCategoryProducts() asked for category %d, want 1", gotCategoryID)
+			}
+			if got := w.Header().Get(pagination.TotalCountHeader); got != "2" {
+				t.Errorf("GetCategoryProducts() %s = %q, want 2", pagination.TotalCountHeader, got)
+			}
+			if got := w.Header().Get("Link"); !strings.Contains(got, `</categories/1/products?page=2&per_page=1>; rel="next"`) {
+				t.Errorf("GetCategoryProducts() Link = %q", got)
+			}
+		})
+	}
+}
+
+func TestProductsHandler_GetProductByID(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+	}{
+		{name: "success", id: "1", wantStatus: http.StatusOK, wantMsgCode: constants.MsgProductFound},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidProductID},
+		{name: "not found", id: "99", mockErr: entity.ErrProductNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgProductNotFound},
+		{name: "service error", id: "1", mockErr: errors.New("disk on fire"), wantStatus: http.StatusInternalServerError, wantMsgCode: constants.MsgInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewProductsHandler(&mockService{
+				GetProductByIDFunc: func(productID int64) (entity.Product, error) {
+					return entity.Product{ID: productID, CategoryID: 1, Name: "Laptop"}, tt.mockErr
+				},
+			})
+			req := httptest.NewRequest(http.MethodGet, "/products/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			h.GetProductByID(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetProductByID() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := decodeResponse(t, w); got.MessageCode != tt.wantMsgCode {
+				t.Errorf("GetProductByID() message code = %v, want %v", got.MessageCode, tt.wantMsgCode)
+			}
+		})
+	}
+}
+
+func TestProductsHandler_InsertProduct(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+		wantField   string
+	}{
+		{name: "success", body: `{"category_id":1,"name":"Laptop","price":1500000}`, wantStatus: http.StatusCreated, wantMsgCode: constants.MsgProductCreated},
+		{name: "malformed", body: `{"name":`, wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidProduct},
+		{name: "unknown field", body: `{"name":"Laptop","stock":3}`, wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidProduct, wantField: "stock"},
+		{
+			name: "unknown category", body: `{"category_id":99,"name":"Laptop"}`,
+			mockErr:    &entity.ProductError{Field: "category_id", Reason: "category 99 does not exist"},
+			wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidProduct, wantField: "category_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewProductsHandler(&mockService{
+				InsertProductFunc: func(parameter entity.Product) (entity.Product, error) {
+					parameter.ID = 1
+					return parameter, tt.mockErr
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			h.InsertProduct(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("InsertProduct() status = %v, want %v, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			got := decodeResponse(t, w)
+			if got.MessageCode != tt.wantMsgCode {
+				t.Errorf("InsertProduct() message code = %v, want %v", got.MessageCode, tt.wantMsgCode)
+			}
+			if tt.wantField != "" && (got.Error == nil || got.Error.Field != tt.wantField) {
+				t.Errorf("InsertProduct() error = %+v, want field %q", got.Error, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestProductsHandler_UpdateProduct(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		body        string
+		mockErr     error
+		wantStatus  int
+		wantMsgCode string
+	}{
+		{name: "success", id: "1", body: `{"category_id":2,"name":"Kaos"}`, wantStatus: http.StatusOK, wantMsgCode: constants.MsgProductUpdated},
+		{name: "invalid id", id: "abc", body: `{}`, wantStatus: http.StatusBadRequest, wantMsgCode: constants.MsgInvalidProductID},
+		{name: "not found", id: "99", body: `{"category_id":2,"name":"Kaos"}`, mockErr: entity.ErrProductNotFound, wantStatus: http.StatusNotFound, wantMsgCode: constants.MsgProductNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got entity.Product
+			h, _ := NewProductsHandler(&mockService{
+				UpdateProductFunc: func(parameter entity.Product) (entity.Product, error) {
+					got = parameter
+					return parameter, tt.mockErr
+				},
+			})
+			req := httptest.NewRequest(http.MethodPut, "/products/"+tt.id, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			h.UpdateProduct(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("UpdateProduct() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if body := decodeResponse(t, w); body.MessageCode != tt.wantMsgCode {
+				t.Errorf("UpdateProduct() message code = %v, want %v", body.MessageCode, tt.wantMsgCode)
+			}
+			if tt.wantStatus == http.StatusOK && (got.ID != 1 || got.CategoryID != 2) {
+				t.Errorf("UpdateProduct() passed %+v, want the product with the ID of the path", got)
+			}
+		})
+	}
+}
+
+func TestProductsHandler_DeleteProduct(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		mockErr    error
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "success", id: "1", wantStatus: http.StatusOK, wantMsg: "Berhasil menghapus produk dengan id 1"},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest, wantMsg: constants.ErrInvalidProductID},
+		{name: "not found", id: "99", mockErr: entity.ErrProductNotFound, wantStatus: http.StatusNotFound, wantMsg: constants.ErrProductNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewProductsHandler(&mockService{
+				DeleteProductFunc: func(productID int64) error {
+					return tt.mockErr
+				},
+			})
+			req := httptest.NewRequest(http.MethodDelete, "/products/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			h.DeleteProduct(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("DeleteProduct() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := decodeResponse(t, w); got.Message != tt.wantMsg {
+				t.Errorf("DeleteProduct() message = %v, want %v", got.Message, tt.wantMsg)
+			}
+		})
+	}
+}
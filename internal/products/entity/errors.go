@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+// ErrProductNotFound is returned when a product does not exist.
+var ErrProductNotFound = errors.New(constants.ErrProductNotFound)
+
+// ErrInvalidProduct is returned when a product fails validation.
+var ErrInvalidProduct = errors.New(constants.ErrInvalidProduct)
+
+// ProductError describes why the product field Field, named as in JSON, was rejected. It matches ErrInvalidProduct.
+type ProductError struct {
+	Field  string
+	Reason string
+}
+
+// Error returns the message of ErrInvalidProduct followed by the field and the reason.
+func (e *ProductError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrInvalidProduct, e.Field, e.Reason)
+}
+
+// Unwrap returns ErrInvalidProduct.
+func (e *ProductError) Unwrap() error {
+	return ErrInvalidProduct
+}
@@ -0,0 +1,11 @@
+package entity
+
+// Product is an item sold in a storefront. It is classified by exactly one category of the same tenant, which
+// cannot be deleted while it still classifies products. Price is in the smallest unit of the storefront's currency.
+type Product struct {
+	ID          int64  `json:"id"`
+	CategoryID  int64  `json:"category_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       int64  `json:"price"`
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// IProductsRepository defines an abstraction for storing products. Like the categories they reference, every
+// operation is scoped to the tenant carried by ctx, and product IDs are sequenced per tenant. Lists are ordered by
+// ID and paged with offset and limit; they also return the number of products on all pages.
+type IProductsRepository interface {
+	GetAllProducts(ctx context.Context, offset, limit int) ([]entity.Product, int)
+	GetProductsByCategory(ctx context.Context, categoryID int64, offset, limit int) ([]entity.Product, int)
+	GetProductByID(ctx context.Context, productID int64) (entity.Product, error)
+	InsertProduct(ctx context.Context, parameter entity.Product) entity.Product
+	UpdateProduct(ctx context.Context, parameter entity.Product) (entity.Product, error)
+	DeleteProduct(ctx context.Context, productID int64) error
+	CountProductsByCategory(ctx context.Context, categoryID int64) int
+	DeleteProductsByCategory(ctx context.Context, categoryID int64) int
+}
+
+// partition holds the products of one tenant.
+type partition struct {
+	// products is sorted by ID.
+	products []entity.Product
+	lastID   int64
+}
+
+// ProductsRepository keeps the products of every tenant in memory. It is safe for concurrent use.
+type ProductsRepository struct {
+	mu         sync.RWMutex
+	partitions map[string]*partition
+}
+
+// NewProductsRepository initializes an empty ProductsRepository.
+func NewProductsRepository() (*ProductsRepository, error) {
+	return &ProductsRepository{partitions: map[string]*partition{}}, nil
+}
+
+// GetAllProducts returns a page of the products of the tenant and the number of products of the tenant.
+func (r *ProductsRepository) GetAllProducts(ctx context.Context, offset, limit int) ([]entity.Product, int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return []entity.Product{}, 0
+	}
+
+	return page(p.products, offset, limit), len(p.products)
+}
+
+// GetProductsByCategory returns a page of the products of the tenant classified by the category and the number of
+// products classified by it.
+func (r *ProductsRepository) GetProductsByCategory(ctx context.Context, categoryID int64, offset, limit int) ([]entity.Product, int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return []entity.Product{}, 0
+	}
+
+	var products []entity.Product
+	for _, product := range p.products {
+		if product.CategoryID == categoryID {
+			products = append(products, product)
+		}
+	}
+
+	return page(products, offset, limit), len(products)
+}
+
+// GetProductByID returns the product of the tenant with the given ID or ErrProductNotFound.
+func (r *ProductsRepository) GetProductByID(ctx context.Context, productID int64) (entity.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return entity.Product{}, entity.ErrProductNotFound
+	}
+
+	i, found := p.find(productID)
+	if !found {
+		return entity.Product{}, entity.ErrProductNotFound
+	}
+
+	return p.products[i], nil
+}
+
+// InsertProduct stores a new product for the tenant under the tenant's next ID, ignoring the given ID, and returns it.
+func (r *ProductsRepository) InsertProduct(ctx context.Context, parameter entity.Product) entity.Product {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenantID := tenant.FromContext(ctx)
+	p, ok := r.partitions[tenantID]
+	if !ok {
+		p = &partition{}
+		r.partitions[tenantID] = p
+	}
+
+	p.lastID++
+	parameter.ID = p.lastID
+	p.products = append(p.products, parameter)
+	return parameter
+}
+
+// UpdateProduct replaces the product of the tenant with the ID of parameter, or returns ErrProductNotFound when
+// there is none.
+func (r *ProductsRepository) UpdateProduct(ctx context.Context, parameter entity.Product) (entity.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return entity.Product{}, entity.ErrProductNotFound
+	}
+
+	i, found := p.find(parameter.ID)
+	if !found {
+		return entity.Product{}, entity.ErrProductNotFound
+	}
+
+	p.products[i] = parameter
+	return parameter, nil
+}
+
+// DeleteProduct removes the product of the tenant with the given ID, or returns ErrProductNotFound when there is none.
+func (r *ProductsRepository) DeleteProduct(ctx context.Context, productID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return entity.ErrProductNotFound
+	}
+
+	i, found := p.find(productID)
+	if !found {
+		return entity.ErrProductNotFound
+	}
+
+	p.products = slices.Delete(p.products, i, i+1)
+	return nil
+}
+
+// CountProductsByCategory returns the number of products of the tenant classified by the category.
+func (r *ProductsRepository) CountProductsByCategory(ctx context.Context, categoryID int64) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return 0
+	}
+
+	count := 0
+	for _, product := range p.products {
+		if product.CategoryID == categoryID {
+			count++
+		}
+	}
+	return count
+}
+
+// DeleteProductsByCategory removes every product of the tenant classified by the category and returns how many
+// were removed.
+func (r *ProductsRepository) DeleteProductsByCategory(ctx context.Context, categoryID int64) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.partitions[tenant.FromContext(ctx)]
+	if !ok {
+		return 0
+	}
+
+	before := len(p.products)
+	p.products = slices.DeleteFunc(p.products, func(product entity.Product) bool {
+		return product.CategoryID == categoryID
+	})
+	return before - len(p.products)
+}
+
+// find returns the index of the product with the given ID and whether it exists.
+func (p *partition) find(productID int64) (int, bool) {
+	return slices.BinarySearchFunc(p.products, productID, func(product entity.Product, id int64) int {
+		return cmp.Compare(product.ID, id)
+	})
+}
+
+// page returns a copy of the products from offset, at most limit of them. A limit of zero or less means no limit.
+func page(products []entity.Product, offset, limit int) []entity.Product {
+	start := min(max(offset, 0), len(products))
+	end := len(products)
+	if limit > 0 {
+		end = min(start+limit, end)
+	}
+	return append([]entity.Product{}, products[start:end]...)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// ids returns the IDs of products in order.
+func ids(products []entity.Product) []int64 {
+	ids := []int64{}
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	return ids
+}
+
+func TestProductsRepository(t *testing.T) {
+	repo, err := NewProductsRepository()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := t.Context()
+
+	for i, categoryID := range []int64{1, 2, 1, 1} {
+		got := repo.InsertProduct(ctx, entity.Product{ID: 99, CategoryID: categoryID, Name: "Produk"})
+		if got.ID != int64(i+1) {
+			t.Fatalf("expected ID %d, got %d", i+1, got.ID)
+		}
+	}
+
+	if got, total := repo.GetAllProducts(ctx, 1, 2); len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 || total != 4 {
+		t.Errorf("expected products 2 and 3 of 4, got %v of %d", ids(got), total)
+	}
+	if got, total := repo.GetProductsByCategory(ctx, 1, 0, 0); len(got) != 3 || total != 3 {
+		t.Errorf("expected the 3 products of category 1, got %v of %d", ids(got), total)
+	}
+	if got, total := repo.GetProductsByCategory(ctx, 1, 5, 10); len(got) != 0 || total != 3 {
+		t.Errorf("expected an empty page past the end, got %v of %d", ids(got), total)
+	}
+	if got := repo.CountProductsByCategory(ctx, 2); got != 1 {
+		t.Errorf("expected 1 product in category 2, got %d", got)
+	}
+
+	if _, err := repo.UpdateProduct(ctx, entity.Product{ID: 2, CategoryID: 1, Name: "Laptop"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := repo.GetProductByID(ctx, 2); got.Name != "Laptop" || got.CategoryID != 1 {
+		t.Errorf("expected the updated product, got %+v", got)
+	}
+	if _, err := repo.UpdateProduct(ctx, entity.Product{ID: 9}); !errors.Is(err, entity.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+
+	if err := repo.DeleteProduct(ctx, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetProductByID(ctx, 3); !errors.Is(err, entity.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound after delete, got %v", err)
+	}
+	if err := repo.DeleteProduct(ctx, 3); !errors.Is(err, entity.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+
+	if got := repo.DeleteProductsByCategory(ctx, 1); got != 3 {
+		t.Errorf("expected 3 products to be removed, got %d", got)
+	}
+	if got, total := repo.GetAllProducts(ctx, 0, 0); total != 0 || len(got) != 0 {
+		t.Errorf("expected no products left, got %v", ids(got))
+	}
+
+	// IDs are not reused after deletes.
+	if got := repo.InsertProduct(ctx, entity.Product{CategoryID: 1}); got.ID != 5 {
+		t.Errorf("expected ID 5, got %d", got.ID)
+	}
+}
+
+func TestProductsRepository_Tenants(t *testing.T) {
+	repo, _ := NewProductsRepository()
+	acme := tenant.WithID(t.Context(), "acme")
+
+	repo.InsertProduct(t.Context(), entity.Product{CategoryID: 1, Name: "Default"})
+	if got := repo.InsertProduct(acme, entity.Product{CategoryID: 1, Name: "Acme"}); got.ID != 1 {
+		t.Errorf("expected IDs to be sequenced per tenant, got %d", got.ID)
+	}
+
+	if got, _ := repo.GetProductByID(acme, 1); got.Name != "Acme" {
+		t.Errorf("expected the product of acme, got %+v", got)
+	}
+	if got := repo.DeleteProductsByCategory(acme, 1); got != 1 {
+		t.Errorf("expected 1 product to be removed, got %d", got)
+	}
+	if got := repo.CountProductsByCategory(t.Context(), 1); got != 1 {
+		t.Errorf("expected the products of other tenants to be kept, got %d", got)
+	}
+	if got, total := repo.GetAllProducts(tenant.WithID(t.Context(), "other"), 0, 10); len(got) != 0 || total != 0 {
+		t.Errorf("expected no products for an unknown tenant, got %v", ids(got))
+	}
+}
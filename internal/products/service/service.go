@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	categoriesEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	categoriesRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/repository"
+)
+
+// DeletePolicy decides what happens to the products of a category that is deleted.
+type DeletePolicy string
+
+const (
+	// DeleteRestrict refuses to delete categories that still classify products.
+	DeleteRestrict DeletePolicy = "restrict"
+
+	// DeleteCascade deletes the products of a category together with it.
+	DeleteCascade DeletePolicy = "cascade"
+)
+
+// Options configures a ProductsService. Zero values fall back to the defaults.
+type Options struct {
+	// CategoryDeletePolicy applies to deletes of categories that still classify products. Empty means DeleteRestrict.
+	CategoryDeletePolicy DeletePolicy
+}
+
+// IProductsService provides methods for managing product entities.
+// GetAllProducts retrieves a page of all products and the number of products.
+// GetProductsByCategory retrieves a page of the products of a category and the number of them.
+// GetProductByID retrieves a product by its unique identifier.
+// InsertProduct validates and creates a new product in the storage.
+// UpdateProduct validates and replaces an existing product.
+// DeleteProduct removes a product from storage using its ID.
+// Every method works on the products of the tenant carried by ctx.
+type IProductsService interface {
+	GetAllProducts(ctx context.Context, offset, limit int) ([]entity.Product, int)
+	GetProductsByCategory(ctx context.Context, categoryID int64, offset, limit int) ([]entity.Product, int, error)
+	GetProductByID(ctx context.Context, productID int64) (entity.Product, error)
+	InsertProduct(ctx context.Context, parameter entity.Product) (entity.Product, error)
+	UpdateProduct(ctx context.Context, parameter entity.Product) (entity.Product, error)
+	DeleteProduct(ctx context.Context, productID int64) error
+}
+
+// ProductsService manages products in an IProductsRepository and keeps them consistent with the categories
+// classifying them. Given to the categories service as its delete guard, it also applies the category delete policy.
+type ProductsService struct {
+	repo       repository.IProductsRepository
+	categories categoriesRepository.ICategoriesRepository
+	options    Options
+
+	// mu serializes the writes that check a category with the deletes of categories, so no product is attached to a
+	// category while it is being deleted.
+	mu sync.Mutex
+}
+
+// NewProductsService initializes a new ProductsService with the repository of the products and the repository of the
+// categories classifying them.
+func NewProductsService(repo repository.IProductsRepository, categories categoriesRepository.ICategoriesRepository, options Options) (*ProductsService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("products repository must not be nil")
+	}
+	if categories == nil {
+		return nil, fmt.Errorf("categories repository must not be nil")
+	}
+
+	switch options.CategoryDeletePolicy {
+	case "":
+		options.CategoryDeletePolicy = DeleteRestrict
+	case DeleteRestrict, DeleteCascade:
+	default:
+		return nil, fmt.Errorf("category delete policy must be %q or %q, got %q", DeleteRestrict, DeleteCascade, options.CategoryDeletePolicy)
+	}
+
+	return &ProductsService{
+		repo:       repo,
+		categories: categories,
+		options:    options,
+	}, nil
+}
+
+// GetAllProducts retrieves the products from offset, at most limit of them, ordered by ID, together with the number
+// of products. A limit of zero or less returns every product from offset.
+func (s *ProductsService) GetAllProducts(ctx context.Context, offset, limit int) ([]entity.Product, int) {
+	return s.repo.GetAllProducts(ctx, offset, limit)
+}
+
+// GetProductsByCategory retrieves a page of the products classified by a category like GetAllProducts, together with
+// the number of products classified by it. Returns ErrCategoryNotFound if the category does not exist.
+func (s *ProductsService) GetProductsByCategory(ctx context.Context, categoryID int64, offset, limit int) ([]entity.Product, int, error) {
+	if s.categories.GetCategoryByID(ctx, categoryID).ID == 0 {
+		return nil, 0, categoriesEntity.ErrCategoryNotFound
+	}
+
+	products, total := s.repo.GetProductsByCategory(ctx, categoryID, offset, limit)
+	return products, total, nil
+}
+
+// GetProductByID retrieves a product by its ID. Returns ErrProductNotFound if there is none.
+func (s *ProductsService) GetProductByID(ctx context.Context, productID int64) (entity.Product, error) {
+	return s.repo.GetProductByID(ctx, productID)
+}
+
+// InsertProduct stores a new product and returns it with its ID. A *entity.ProductError matching ErrInvalidProduct is
+// returned when it fails validation or its category does not exist.
+func (s *ProductsService) InsertProduct(ctx context.Context, parameter entity.Product) (entity.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, err := s.checkProduct(ctx, parameter)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return s.repo.InsertProduct(ctx, product), nil
+}
+
+// UpdateProduct replaces the product with the ID of parameter and returns it. It is validated like by InsertProduct;
+// ErrProductNotFound is returned when there is no such product.
+func (s *ProductsService) UpdateProduct(ctx context.Context, parameter entity.Product) (entity.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, err := s.checkProduct(ctx, parameter)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return s.repo.UpdateProduct(ctx, product)
+}
+
+// DeleteProduct removes a product by its ID. Returns ErrProductNotFound if there is none.
+func (s *ProductsService) DeleteProduct(ctx context.Context, productID int64) error {
+	return s.repo.DeleteProduct(ctx, productID)
+}
+
+// GuardCategoryDelete implements the ICategoryGuard of the categories service. Under DeleteRestrict, a category that
+// still classifies products is not deleted and an error matching ErrCategoryInUse is returned; under DeleteCascade,
+// its products are deleted once the category is.
+func (s *ProductsService) GuardCategoryDelete(ctx context.Context, categoryID int64, deleteCategory func() (int64, error)) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.options.CategoryDeletePolicy == DeleteRestrict {
+		if count := s.repo.CountProductsByCategory(ctx, categoryID); count > 0 {
+			return 0, fmt.Errorf("%w: %d products reference category %d", categoriesEntity.ErrCategoryInUse, count, categoryID)
+		}
+	}
+
+	id, err := deleteCategory()
+	if err != nil {
+		return id, err
+	}
+
+	s.repo.DeleteProductsByCategory(ctx, categoryID)
+	return id, nil
+}
+
+// checkProduct returns parameter with its name trimmed, or a *entity.ProductError naming the first field that is
+// invalid. The category must exist in the tenant of ctx.
+func (s *ProductsService) checkProduct(ctx context.Context, parameter entity.Product) (entity.Product, error) {
+	parameter.Name = strings.TrimSpace(parameter.Name)
+	if parameter.Name == "" {
+		return entity.Product{}, &entity.ProductError{Field: "name", Reason: "must not be empty"}
+	}
+	if parameter.Price < 0 {
+		return entity.Product{}, &entity.ProductError{Field: "price", Reason: "must not be negative"}
+	}
+	if s.categories.GetCategoryByID(ctx, parameter.CategoryID).ID == 0 {
+		return entity.Product{}, &entity.ProductError{Field: "category_id", Reason: fmt.Sprintf("category %d does not exist", parameter.CategoryID)}
+	}
+
+	return parameter, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	categoriesEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	categoriesRepository "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	categoriesService "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/products/repository"
+)
+
+// newProductsFixture returns a ProductsService over the default categories guarding the deletes of a categories
+// service, with one product in category 1 and one in category 2.
+func newProductsFixture(t *testing.T, options Options) (*ProductsService, *categoriesService.CategoriesService) {
+	t.Helper()
+
+	categories, _ := categoriesRepository.NewCategoriesRepository()
+	products, _ := repository.NewProductsRepository()
+	svc, err := NewProductsService(products, categories, options)
+	if err != nil {
+		t.Fatalf("unexpected service error: %v", err)
+	}
+	categoriesSvc, _ := categoriesService.NewCategoriesService(categories, nil, svc)
+
+	for _, categoryID := range []int64{1, 2} {
+		if _, err := svc.InsertProduct(t.Context(), entity.Product{CategoryID: categoryID, Name: "Produk"}); err != nil {
+			t.Fatalf("unexpected insert error: %v", err)
+		}
+	}
+	return svc, categoriesSvc
+}
+
+func TestNewProductsService(t *testing.T) {
+	categories, _ := categoriesRepository.NewCategoriesRepository()
+	products, _ := repository.NewProductsRepository()
+
+	tests := []struct {
+		name       string
+		repo       repository.IProductsRepository
+		categories categoriesRepository.ICategoriesRepository
+		options    Options
+		want       DeletePolicy
+		wantErr    bool
+	}{
+		{name: "defaults", repo: products, categories: categories, want: DeleteRestrict},
+		{name: "cascade", repo: products, categories: categories, options: Options{CategoryDeletePolicy: DeleteCascade}, want: DeleteCascade},
+		{name: "unknown policy", repo: products, categories: categories, options: Options{CategoryDeletePolicy: "nullify"}, wantErr: true},
+		{name: "nil repository", categories: categories, wantErr: true},
+		{name: "nil categories", repo: products, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := NewProductsService(tt.repo, tt.categories, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && svc.options.CategoryDeletePolicy != tt.want {
+				t.Errorf("expected policy %q, got %q", tt.want, svc.options.CategoryDeletePolicy)
+			}
+		})
+	}
+}
+
+func TestProductsService_InsertProduct(t *testing.T) {
+	tests := []struct {
+		name      string
+		product   entity.Product
+		want      entity.Product
+		wantField string
+	}{
+		{
+			name:    "success",
+			product: entity.Product{ID: 9, CategoryID: 1, Name: "  Laptop ", Description: "14 inci", Price: 1500000},
+			want:    entity.Product{ID: 3, CategoryID: 1, Name: "Laptop", Description: "14 inci", Price: 1500000},
+		},
+		{name: "empty name", product: entity.Product{CategoryID: 1, Name: " "}, wantField: "name"},
+		{name: "negative price", product: entity.Product{CategoryID: 1, Name: "Laptop", Price: -1}, wantField: "price"},
+		{name: "unknown category", product: entity.Product{CategoryID: 99, Name: "Laptop"}, wantField: "category_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newProductsFixture(t, Options{})
+
+			got, err := svc.InsertProduct(t.Context(), tt.product)
+			if tt.wantField != "" {
+				var productErr *entity.ProductError
+				if !errors.As(err, &productErr) || productErr.Field != tt.wantField || !errors.Is(err, entity.ErrInvalidProduct) {
+					t.Fatalf("expected a ProductError for %q, got %v", tt.wantField, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProductsService_UpdateProduct(t *testing.T) {
+	svc, _ := newProductsFixture(t, Options{})
+
+	got, err := svc.UpdateProduct(t.Context(), entity.Product{ID: 1, CategoryID: 2, Name: "Kaos", Price: 75000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored, _ := svc.GetProductByID(t.Context(), 1); stored != got || stored.CategoryID != 2 {
+		t.Errorf("expected the product to be replaced, got %+v", stored)
+	}
+
+	if _, err := svc.UpdateProduct(t.Context(), entity.Product{ID: 99, CategoryID: 1, Name: "Kaos"}); !errors.Is(err, entity.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+	if _, err := svc.UpdateProduct(t.Context(), entity.Product{ID: 1, CategoryID: 99, Name: "Kaos"}); !errors.Is(err, entity.ErrInvalidProduct) {
+		t.Errorf("expected ErrInvalidProduct, got %v", err)
+	}
+}
+
+func TestProductsService_GetProductsByCategory(t *testing.T) {
+	svc, _ := newProductsFixture(t, Options{})
+
+	got, total, err := svc.GetProductsByCategory(t.Context(), 1, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].CategoryID != 1 || total != 1 {
+		t.Errorf("expected the product of category 1, got %+v of %d", got, total)
+	}
+
+	if got, total, err := svc.GetProductsByCategory(t.Context(), 3, 0, 10); err != nil || len(got) != 0 || total != 0 {
+		t.Errorf("expected no products for category 3, got %+v of %d, %v", got, total, err)
+	}
+	if _, _, err := svc.GetProductsByCategory(t.Context(), 99, 0, 10); !errors.Is(err, categoriesEntity.ErrCategoryNotFound) {
+		t.Errorf("expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestProductsService_GuardCategoryDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       DeletePolicy
+		categoryID   int64
+		wantErr      error
+		wantExists   bool
+		wantProducts int
+	}{
+		{name: "restrict in use", policy: DeleteRestrict, categoryID: 1, wantErr: categoriesEntity.ErrCategoryInUse, wantExists: true, wantProducts: 2},
+		{name: "restrict unused", policy: DeleteRestrict, categoryID: 3, wantProducts: 2},
+		{name: "cascade", policy: DeleteCascade, categoryID: 1, wantProducts: 1},
+		{name: "unknown category", policy: DeleteCascade, categoryID: 99, wantErr: categoriesEntity.ErrCategoryNotFound, wantProducts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, categories := newProductsFixture(t, Options{CategoryDeletePolicy: tt.policy})
+
+			_, err := categories.DeleteCategory(t.Context(), tt.categoryID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if _, err := categories.GetCategoryByID(t.Context(), tt.categoryID); (err == nil) != tt.wantExists {
+				t.Errorf("expected the category to exist %v, got lookup error %v", tt.wantExists, err)
+			}
+			if _, total := svc.GetAllProducts(t.Context(), 0, 0); total != tt.wantProducts {
+				t.Errorf("expected %d products, got %d", tt.wantProducts, total)
+			}
+		})
+	}
+}
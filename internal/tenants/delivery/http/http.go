@@ -0,0 +1,138 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// AdminTokenHeader is the request header carrying the admin token of the tenant administration endpoints.
+const AdminTokenHeader = "X-Admin-Token"
+
+// TenantsHandler serves the tenant administration endpoints with the help of ITenantsService. Only requests of the
+// default tenant carrying the admin token in AdminTokenHeader may use them, so a tenant can neither list nor register
+// other tenants. Without an admin token the endpoints refuse every request.
+type TenantsHandler struct {
+	service    service.ITenantsService
+	adminToken [sha256.Size]byte
+	enabled    bool
+}
+
+// NewTenantsHandler initializes and returns a new TenantsHandler instance with the provided ITenantsService
+// implementation, admitting requests that carry adminToken.
+func NewTenantsHandler(service service.ITenantsService, adminToken string) (*TenantsHandler, error) {
+	delegate := &TenantsHandler{
+		service:    service,
+		adminToken: sha256.Sum256([]byte(adminToken)),
+		enabled:    adminToken != "",
+	}
+
+	return delegate, nil
+}
+
+// TenantRequest is the body accepted when registering a tenant.
+type TenantRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetAllTenants answers with every registered tenant.
+func (d *TenantsHandler) GetAllTenants(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	if !d.authorize(w, r) {
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgTenantsListed)
+	result.Data = d.service.GetAllTenants()
+	json_wrapper.WriteResponse(w, r, http.StatusOK, result)
+}
+
+// InsertTenant registers a tenant from the request body.
+func (d *TenantsHandler) InsertTenant(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	if !d.authorize(w, r) {
+		return
+	}
+
+	var req TenantRequest
+	if err := json_wrapper.ParseJSON(r, &req, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeError(w, r, constants.MsgInvalidTenant, err)
+		return
+	}
+
+	created, err := d.service.InsertTenant(entity.Tenant{ID: req.ID, Name: req.Name})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgTenantCreated)
+	result.Data = created
+	json_wrapper.WriteResponse(w, r, http.StatusCreated, result)
+}
+
+// authorize reports whether r belongs to the default tenant and carries the admin token, answering it with 403
+// Forbidden when it does not. Tokens are compared by their SHA-256 in constant time, so neither their content nor
+// their length leaks through timing.
+func (d *TenantsHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	given := sha256.Sum256([]byte(r.Header.Get(AdminTokenHeader)))
+	if d.enabled && subtle.ConstantTimeCompare(given[:], d.adminToken[:]) == 1 && tenant.FromContext(r.Context()) == tenant.DefaultID {
+		return true
+	}
+
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, constants.MsgTenantAdminOnly)
+	json_wrapper.WriteResponse(w, r, http.StatusForbidden, result)
+	return false
+}
+
+// writeError answers r with the error envelope describing an error returned by the service. Errors that are not
+// part of the API contract are logged and reported as an internal server error so their details do not leak.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := http.StatusInternalServerError, constants.MsgInternalServer
+	switch {
+	case errors.Is(err, entity.ErrTenantNotFound):
+		status, code = http.StatusNotFound, constants.MsgTenantNotFound
+	case errors.Is(err, entity.ErrTenantExists):
+		status, code = http.StatusConflict, constants.MsgTenantExists
+	case errors.Is(err, entity.ErrInvalidTenant):
+		status, code = http.StatusBadRequest, constants.MsgInvalidTenant
+	default:
+		log.Printf("tenants: %v", err)
+	}
+
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, code)
+	json_wrapper.WriteResponse(w, r, status, result)
+}
+
+// writeDecodeError answers r with the error envelope for err, an error returned by json_wrapper.ParseJSON, and the
+// details of the offending field and position. code describes bodies that cannot be decoded into the request.
+func writeDecodeError(w http.ResponseWriter, r *http.Request, code string, err error) {
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	status := result.SetDecodeError(err)
+	switch status {
+	case http.StatusRequestEntityTooLarge:
+		code = constants.MsgRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		code = constants.MsgUnsupportedMediaType
+	}
+	result.SetMessage(constants.Messages, r, code)
+	json_wrapper.WriteResponse(w, r, status, result)
+}
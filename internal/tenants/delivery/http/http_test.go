@@ -0,0 +1,220 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+type mockService struct {
+	GetAllTenantsFunc func() []entity.Tenant
+	GetTenantByIDFunc func(tenantID string) (entity.Tenant, error)
+	InsertTenantFunc  func(parameter entity.Tenant) (entity.Tenant, error)
+}
+
+func (m *mockService) GetAllTenants() []entity.Tenant {
+	return m.GetAllTenantsFunc()
+}
+func (m *mockService) GetTenantByID(tenantID string) (entity.Tenant, error) {
+	return m.GetTenantByIDFunc(tenantID)
+}
+func (m *mockService) InsertTenant(parameter entity.Tenant) (entity.Tenant, error) {
+	return m.InsertTenantFunc(parameter)
+}
+
+// adminToken is the admin token of the handlers under test.
+const adminToken = "s3cret-admin"
+
+// newHandler returns a handler over svc admitting adminToken.
+func newHandler(svc *mockService) *TenantsHandler {
+	handler, _ := NewTenantsHandler(svc, adminToken)
+	return handler
+}
+
+// adminRequest returns a request carrying adminToken.
+func adminRequest(method, body string) *http.Request {
+	r := httptest.NewRequest(method, "/tenants", strings.NewReader(body))
+	r.Header.Set(AdminTokenHeader, adminToken)
+	return r
+}
+
+func TestNewTenantsHandler(t *testing.T) {
+	svc := &mockService{}
+	handler, err := NewTenantsHandler(svc, adminToken)
+	if err != nil {
+		t.Errorf("NewTenantsHandler() error = %v, wantErr nil", err)
+	}
+	if handler.service != svc || !handler.enabled {
+		t.Errorf("NewTenantsHandler() handler = %+v, want service %v with the admin token enabled", handler, svc)
+	}
+}
+
+func TestTenantsHandler_GetAllTenants(t *testing.T) {
+	h := newHandler(&mockService{
+		GetAllTenantsFunc: func() []entity.Tenant {
+			return []entity.Tenant{{ID: "acme", Name: "Acme"}, {ID: "default", Name: "Default"}}
+		},
+	})
+
+	w := httptest.NewRecorder()
+	h.GetAllTenants(w, adminRequest(http.MethodGet, ""))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var got struct {
+		MessageCode string          `json:"message_code"`
+		Data        []entity.Tenant `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.MessageCode != constants.MsgTenantsListed || len(got.Data) != 2 {
+		t.Errorf("unexpected body %s", w.Body.String())
+	}
+}
+
+func TestTenantsHandler_InsertTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		insertErr  error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "created", body: `{"id":"acme","name":"Acme"}`, wantStatus: http.StatusCreated, wantCode: constants.MsgTenantCreated},
+		{name: "taken", body: `{"id":"acme"}`, insertErr: entity.ErrTenantExists, wantStatus: http.StatusConflict, wantCode: constants.MsgTenantExists},
+		{name: "invalid id", body: `{"id":"Acme Store"}`, insertErr: entity.ErrInvalidTenant, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidTenant},
+		{name: "unknown field", body: `{"id":"acme","owner":"x"}`, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHandler(&mockService{
+				InsertTenantFunc: func(parameter entity.Tenant) (entity.Tenant, error) {
+					return parameter, tt.insertErr
+				},
+			})
+
+			w := httptest.NewRecorder()
+			h.InsertTenant(w, adminRequest(http.MethodPost, tt.body))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var got json_wrapper.APIResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.MessageCode != tt.wantCode {
+				t.Errorf("expected message code %s, got %s", tt.wantCode, got.MessageCode)
+			}
+		})
+	}
+}
+
+func TestTenantsHandler_RequiresAdminToken(t *testing.T) {
+	svc := &mockService{
+		GetAllTenantsFunc: func() []entity.Tenant {
+			t.Fatalf("expected the service not to be called")
+			return nil
+		},
+		InsertTenantFunc: func(parameter entity.Tenant) (entity.Tenant, error) {
+			t.Fatalf("expected the service not to be called")
+			return parameter, nil
+		},
+	}
+	enabled := newHandler(svc)
+	disabled, _ := NewTenantsHandler(svc, "")
+
+	tests := []struct {
+		name    string
+		handler *TenantsHandler
+		token   string
+		tenant  string
+	}{
+		{name: "no token", handler: enabled},
+		{name: "wrong token", handler: enabled, token: "guess"},
+		{name: "token prefix", handler: enabled, token: adminToken[:4]},
+		{name: "default tenant without token", handler: enabled, tenant: tenant.DefaultID},
+		{name: "another tenant without token", handler: enabled, tenant: "acme"},
+		{name: "another tenant with token", handler: enabled, token: adminToken, tenant: "acme"},
+		{name: "no admin token configured", handler: disabled},
+	}
+
+	for _, tt := range tests {
+		for _, endpoint := range []struct {
+			method string
+			body   string
+			serve  func(*TenantsHandler) http.HandlerFunc
+		}{
+			{method: http.MethodGet, serve: func(h *TenantsHandler) http.HandlerFunc { return h.GetAllTenants }},
+			{method: http.MethodPost, body: `{"id":"globex"}`, serve: func(h *TenantsHandler) http.HandlerFunc { return h.InsertTenant }},
+		} {
+			t.Run(tt.name+" "+endpoint.method, func(t *testing.T) {
+				r := httptest.NewRequest(endpoint.method, "/tenants", strings.NewReader(endpoint.body))
+				if tt.token != "" {
+					r.Header.Set(AdminTokenHeader, tt.token)
+				}
+				if tt.tenant != "" {
+					r = r.WithContext(tenant.WithID(r.Context(), tt.tenant))
+				}
+
+				w := httptest.NewRecorder()
+				endpoint.serve(tt.handler)(w, r)
+
+				if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), constants.MsgTenantAdminOnly) {
+					t.Errorf("expected 403 %s, got %d: %s", constants.MsgTenantAdminOnly, w.Code, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestTenantsHandler_NegotiatesFormat(t *testing.T) {
+	h := newHandler(&mockService{
+		GetAllTenantsFunc: func() []entity.Tenant {
+			return []entity.Tenant{{ID: "acme", Name: "Acme"}}
+		},
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "listed", token: adminToken, wantStatus: http.StatusOK, wantBody: "acme"},
+		{name: "forbidden", wantStatus: http.StatusForbidden, wantBody: constants.MsgTenantAdminOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/tenants", nil)
+			r.Header.Set("Accept", json_wrapper.MediaTypeXML)
+			if tt.token != "" {
+				r.Header.Set(AdminTokenHeader, tt.token)
+			}
+
+			w := httptest.NewRecorder()
+			h.GetAllTenants(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, json_wrapper.MediaTypeXML) {
+				t.Errorf("expected an XML response, got %q", ct)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("expected the body to contain %q, got %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
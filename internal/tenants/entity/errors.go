@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"errors"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+// ErrTenantNotFound is returned when a tenant is not registered.
+var ErrTenantNotFound = errors.New(constants.ErrTenantNotFound)
+
+// ErrTenantExists is returned when a tenant is registered with an ID that is already taken.
+var ErrTenantExists = errors.New(constants.ErrTenantExists)
+
+// ErrInvalidTenant is returned when a tenant fails validation.
+var ErrInvalidTenant = errors.New(constants.ErrInvalidTenant)
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Tenant is a storefront sharing the deployment. Its categories are isolated from those of every other tenant.
+// ID is the value clients send to select the tenant, e.g. in the X-Tenant-ID header or as a subdomain.
+type Tenant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
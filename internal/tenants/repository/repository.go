@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/entity"
+)
+
+// ITenantsRepository defines an abstraction for storing tenants.
+type ITenantsRepository interface {
+	GetAllTenants() []entity.Tenant
+	GetTenantByID(tenantID string) (entity.Tenant, error)
+	InsertTenant(parameter entity.Tenant) (entity.Tenant, error)
+}
+
+// TenantsRepository keeps tenants in memory. It is safe for concurrent use.
+type TenantsRepository struct {
+	mu      sync.RWMutex
+	tenants map[string]entity.Tenant
+}
+
+// NewTenantsRepository initializes a repository holding the given tenants, e.g. the default tenant.
+func NewTenantsRepository(tenants ...entity.Tenant) (*TenantsRepository, error) {
+	r := &TenantsRepository{tenants: make(map[string]entity.Tenant, len(tenants))}
+	for _, tenant := range tenants {
+		r.tenants[tenant.ID] = tenant
+	}
+
+	return r, nil
+}
+
+// GetAllTenants returns every tenant ordered by ID.
+func (r *TenantsRepository) GetAllTenants() []entity.Tenant {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := make([]entity.Tenant, 0, len(r.tenants))
+	for _, tenant := range r.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+
+	return tenants
+}
+
+// GetTenantByID returns the tenant with the given ID or ErrTenantNotFound.
+func (r *TenantsRepository) GetTenantByID(tenantID string) (entity.Tenant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenant, ok := r.tenants[tenantID]
+	if !ok {
+		return entity.Tenant{}, entity.ErrTenantNotFound
+	}
+
+	return tenant, nil
+}
+
+// InsertTenant stores a new tenant, or returns ErrTenantExists when its ID is taken.
+func (r *TenantsRepository) InsertTenant(parameter entity.Tenant) (entity.Tenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tenants[parameter.ID]; ok {
+		return entity.Tenant{}, entity.ErrTenantExists
+	}
+
+	r.tenants[parameter.ID] = parameter
+	return parameter, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/entity"
+)
+
+func TestTenantsRepository(t *testing.T) {
+	repo, err := NewTenantsRepository(entity.Tenant{ID: "default", Name: "Default"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := repo.InsertTenant(entity.Tenant{ID: "acme", Name: "Acme"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.InsertTenant(entity.Tenant{ID: "acme", Name: "Acme again"}); !errors.Is(err, entity.ErrTenantExists) {
+		t.Errorf("expected ErrTenantExists, got %v", err)
+	}
+
+	got, err := repo.GetTenantByID("acme")
+	if err != nil || got.Name != "Acme" {
+		t.Errorf("expected tenant acme, got %+v, %v", got, err)
+	}
+	if _, err := repo.GetTenantByID("globex"); !errors.Is(err, entity.ErrTenantNotFound) {
+		t.Errorf("expected ErrTenantNotFound, got %v", err)
+	}
+
+	var ids []string
+	for _, tenant := range repo.GetAllTenants() {
+		ids = append(ids, tenant.ID)
+	}
+	if want := []string{"acme", "default"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected tenants %v, got %v", want, ids)
+	}
+}
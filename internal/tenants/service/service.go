@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// ITenantsService provides methods for registering the tenants sharing the deployment.
+// GetAllTenants retrieves every tenant.
+// GetTenantByID retrieves a tenant by its ID.
+// InsertTenant validates and registers a new tenant.
+type ITenantsService interface {
+	GetAllTenants() []entity.Tenant
+	GetTenantByID(tenantID string) (entity.Tenant, error)
+	InsertTenant(parameter entity.Tenant) (entity.Tenant, error)
+}
+
+// TenantsService registers tenants in an ITenantsRepository. The default tenant is always registered.
+type TenantsService struct {
+	repo repository.ITenantsRepository
+	now  func() time.Time
+}
+
+// NewTenantsService initializes a new TenantsService with the provided ITenantsRepository implementation and
+// registers the default tenant in it when it is missing.
+func NewTenantsService(repo repository.ITenantsRepository) (*TenantsService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("tenants repository must not be nil")
+	}
+
+	s := &TenantsService{repo: repo, now: time.Now}
+	if _, err := repo.GetTenantByID(tenant.DefaultID); err != nil {
+		if _, err := repo.InsertTenant(entity.Tenant{ID: tenant.DefaultID, Name: "Default", CreatedAt: s.now().UTC()}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// GetAllTenants retrieves every tenant ordered by ID.
+func (s *TenantsService) GetAllTenants() []entity.Tenant {
+	return s.repo.GetAllTenants()
+}
+
+// GetTenantByID retrieves a tenant by its ID. Returns ErrTenantNotFound if it is not registered.
+func (s *TenantsService) GetTenantByID(tenantID string) (entity.Tenant, error) {
+	return s.repo.GetTenantByID(tenantID)
+}
+
+// InsertTenant registers a tenant with a valid ID and a name, defaulting the name to the ID. Returns
+// ErrInvalidTenant for an invalid ID and ErrTenantExists when the ID is taken.
+func (s *TenantsService) InsertTenant(parameter entity.Tenant) (entity.Tenant, error) {
+	if !tenant.ValidID(parameter.ID) {
+		return entity.Tenant{}, fmt.Errorf("%w: %s", entity.ErrInvalidTenant, tenant.ErrInvalidID)
+	}
+
+	name := strings.TrimSpace(parameter.Name)
+	if name == "" {
+		name = parameter.ID
+	}
+
+	return s.repo.InsertTenant(entity.Tenant{
+		ID:        parameter.ID,
+		Name:      name,
+		CreatedAt: s.now().UTC(),
+	})
+}
+
+// Exists reports whether the tenant is registered. It lets the tenant middleware reject unknown tenants.
+func (s *TenantsService) Exists(tenantID string) bool {
+	_, err := s.repo.GetTenantByID(tenantID)
+	return err == nil
+}
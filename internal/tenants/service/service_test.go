@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/tenants/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+func newTestService(t *testing.T) *TenantsService {
+	t.Helper()
+
+	repo, err := repository.NewTenantsRepository()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc, err := NewTenantsService(repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return svc
+}
+
+func TestNewTenantsService_RegistersDefaultTenant(t *testing.T) {
+	svc := newTestService(t)
+
+	if !svc.Exists(tenant.DefaultID) {
+		t.Errorf("expected the default tenant to be registered")
+	}
+	if _, err := NewTenantsService(nil); err == nil {
+		t.Errorf("expected an error for a nil repository")
+	}
+}
+
+func TestTenantsService_InsertTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		tenant   entity.Tenant
+		wantName string
+		wantErr  error
+	}{
+		{name: "valid", tenant: entity.Tenant{ID: "acme", Name: " Acme Store "}, wantName: "Acme Store"},
+		{name: "name defaults to id", tenant: entity.Tenant{ID: "globex"}, wantName: "globex"},
+		{name: "invalid id", tenant: entity.Tenant{ID: "Acme Store"}, wantErr: entity.ErrInvalidTenant},
+		{name: "taken id", tenant: entity.Tenant{ID: tenant.DefaultID}, wantErr: entity.ErrTenantExists},
+	}
+
+	svc := newTestService(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.InsertTenant(tt.tenant)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.ID != tt.tenant.ID || got.Name != tt.wantName || got.CreatedAt.IsZero() {
+				t.Errorf("unexpected tenant %+v", got)
+			}
+			if !svc.Exists(tt.tenant.ID) {
+				t.Errorf("expected tenant %s to exist", tt.tenant.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+)
+
+// WebhooksHandler serves as an HTTP handler that processes webhook-related requests with the help of IWebhooksService.
+type WebhooksHandler struct {
+	service service.IWebhooksService
+}
+
+// NewWebhooksHandler initializes and returns a new WebhooksHandler instance with the provided IWebhooksService implementation.
+func NewWebhooksHandler(service service.IWebhooksService) (*WebhooksHandler, error) {
+	delegate := &WebhooksHandler{
+		service: service,
+	}
+
+	return delegate, nil
+}
+
+// SubscriptionRequest is the body accepted when registering a webhook.
+type SubscriptionRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// GetAllWebhooks answers with every webhook of the tenant.
+func (d *WebhooksHandler) GetAllWebhooks(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgWebhooksListed)
+	result.Data = d.service.GetAllSubscriptions(r.Context())
+	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetWebhookByID answers with the webhook with the ID in the path.
+func (d *WebhooksHandler) GetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := d.service.GetSubscriptionByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgWebhookFound)
+	result.Data = subscription
+	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// InsertWebhook registers a webhook from the request body and answers with its signing secret.
+func (d *WebhooksHandler) InsertWebhook(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	var req SubscriptionRequest
+	if err := json_wrapper.ParseJSON(r, &req, json_wrapper.WithDisallowUnknownFields()); err != nil {
+		writeDecodeError(w, r, constants.MsgInvalidWebhookRequest, err)
+		return
+	}
+
+	subscription, err := d.service.InsertSubscription(r.Context(), entity.Subscription{
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgWebhookCreated)
+	result.Data = subscription
+	json_wrapper.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// DeleteWebhook removes the webhook with the ID in the path.
+func (d *WebhooksHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := d.service.DeleteSubscription(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgWebhookDeleted, res)
+	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetWebhookDeliveries answers with the delivery log of the webhook with the ID in the path.
+func (d *WebhooksHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := d.service.GetDeliveries(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgWebhookDeliveriesListed)
+	result.Data = deliveries
+	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetDeadLetters answers with the deliveries of the tenant that exhausted their attempts.
+func (d *WebhooksHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgDeadLettersListed)
+	result.Data = d.service.GetDeadLetters(r.Context())
+	json_wrapper.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// RetryDeadLetter sends the dead-lettered delivery with the ID in the path again.
+func (d *WebhooksHandler) RetryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	var result json_wrapper.APIResponse
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := d.service.RetryDelivery(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result.Code = constants.SuccessCode
+	result.SetMessage(constants.Messages, r, constants.MsgDeliveryRetried)
+	result.Data = delivery
+	json_wrapper.WriteJSONResponse(w, http.StatusAccepted, result)
+}
+
+// parseID reads the {id} path value, writing a 400 response and returning false when it is not a positive integer.
+func parseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		var result json_wrapper.APIResponse
+		result.Code = constants.ErrorCode
+		result.SetMessage(constants.Messages, r, constants.MsgInvalidWebhookID)
+		json_wrapper.WriteJSONResponse(w, http.StatusBadRequest, result)
+		return 0, false
+	}
+
+	return id, true
+}
+
+// writeError maps webhook domain errors to HTTP statuses and message codes. Unexpected errors are logged and
+// reported as an internal server error so their details do not leak to clients.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := http.StatusInternalServerError, constants.MsgInternalServer
+	switch {
+	case errors.Is(err, entity.ErrWebhookNotFound):
+		status, code = http.StatusNotFound, constants.MsgWebhookNotFound
+	case errors.Is(err, entity.ErrDeliveryNotFound):
+		status, code = http.StatusNotFound, constants.MsgDeliveryNotFound
+	case errors.Is(err, entity.ErrInvalidWebhook):
+		status, code = http.StatusBadRequest, constants.MsgInvalidWebhookRequest
+	case errors.Is(err, entity.ErrDeliveryNotRetryable):
+		status, code = http.StatusConflict, constants.MsgDeliveryNotRetryable
+	default:
+		log.Printf("webhooks: %v", err)
+	}
+
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, code)
+	json_wrapper.WriteJSONResponse(w, status, result)
+}
+
+// writeDecodeError answers r with the error envelope for err, an error returned by json_wrapper.ParseJSON, and the
+// details of the offending field and position. code describes bodies that cannot be decoded into the request.
+func writeDecodeError(w http.ResponseWriter, r *http.Request, code string, err error) {
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	status := result.SetDecodeError(err)
+	switch status {
+	case http.StatusRequestEntityTooLarge:
+		code = constants.MsgRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		code = constants.MsgUnsupportedMediaType
+	}
+	result.SetMessage(constants.Messages, r, code)
+	json_wrapper.WriteJSONResponse(w, status, result)
+}
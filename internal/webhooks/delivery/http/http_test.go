@@ -0,0 +1,230 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+)
+
+type mockService struct {
+	GetAllSubscriptionsFunc func() []entity.Subscription
+	GetSubscriptionByIDFunc func(subscriptionID int64) (entity.Subscription, error)
+	InsertSubscriptionFunc  func(parameter entity.Subscription) (entity.Subscription, error)
+	DeleteSubscriptionFunc  func(subscriptionID int64) (int64, error)
+	GetDeliveriesFunc       func(subscriptionID int64) ([]entity.Delivery, error)
+	GetDeadLettersFunc      func() []entity.Delivery
+	RetryDeliveryFunc       func(deliveryID int64) (entity.Delivery, error)
+}
+
+func (m *mockService) GetAllSubscriptions(_ context.Context) []entity.Subscription {
+	return m.GetAllSubscriptionsFunc()
+}
+func (m *mockService) GetSubscriptionByID(_ context.Context, subscriptionID int64) (entity.Subscription, error) {
+	return m.GetSubscriptionByIDFunc(subscriptionID)
+}
+func (m *mockService) InsertSubscription(_ context.Context, parameter entity.Subscription) (entity.Subscription, error) {
+	return m.InsertSubscriptionFunc(parameter)
+}
+func (m *mockService) DeleteSubscription(_ context.Context, subscriptionID int64) (int64, error) {
+	return m.DeleteSubscriptionFunc(subscriptionID)
+}
+func (m *mockService) GetDeliveries(_ context.Context, subscriptionID int64) ([]entity.Delivery, error) {
+	return m.GetDeliveriesFunc(subscriptionID)
+}
+func (m *mockService) GetDeadLetters(_ context.Context) []entity.Delivery {
+	return m.GetDeadLettersFunc()
+}
+func (m *mockService) RetryDelivery(_ context.Context, deliveryID int64) (entity.Delivery, error) {
+	return m.RetryDeliveryFunc(deliveryID)
+}
+
+func TestNewWebhooksHandler(t *testing.T) {
+	svc := &mockService{}
+	handler, err := NewWebhooksHandler(svc)
+	if err != nil {
+		t.Errorf("NewWebhooksHandler() error = %v, wantErr nil", err)
+	}
+	if handler.service != svc {
+		t.Errorf("NewWebhooksHandler() handler.service = %v, want %v", handler.service, svc)
+	}
+}
+
+func TestWebhooksHandler_GetWebhookByID(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		mockErr     error
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "found", id: "1", wantStatus: http.StatusOK, wantMessage: "Berhasil mengambil webhook berdasarkan id"},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest, wantMessage: constants.ErrInvalidWebhookID},
+		{name: "zero id", id: "0", wantStatus: http.StatusBadRequest, wantMessage: constants.ErrInvalidWebhookID},
+		{name: "not found", id: "2", mockErr: entity.ErrWebhookNotFound, wantStatus: http.StatusNotFound, wantMessage: constants.ErrWebhookNotFound},
+		{name: "internal", id: "3", mockErr: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantMessage: constants.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				GetSubscriptionByIDFunc: func(subscriptionID int64) (entity.Subscription, error) {
+					return entity.Subscription{ID: subscriptionID}, tt.mockErr
+				},
+			}
+			handler := &WebhooksHandler{service: svc}
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+			handler.GetWebhookByID(w, req)
+
+			assertResponse(t, w, tt.wantStatus, tt.wantMessage)
+		})
+	}
+}
+
+func TestWebhooksHandler_InsertWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		mockErr     error
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "created", body: `{"url":"https://example.com/hook","events":["category.created"]}`, wantStatus: http.StatusCreated, wantMessage: "Berhasil mendaftarkan webhook baru"},
+		{name: "invalid json", body: `{`, wantStatus: http.StatusBadRequest, wantMessage: constants.ErrInvalidWebhookRequest},
+		{name: "unknown field", body: `{"url":"https://example.com/hook","event":"category.created"}`, wantStatus: http.StatusBadRequest, wantMessage: constants.ErrInvalidWebhookRequest},
+		{name: "invalid webhook", body: `{"url":"/hook"}`, mockErr: entity.ErrInvalidWebhook, wantStatus: http.StatusBadRequest, wantMessage: constants.ErrInvalidWebhookRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got entity.Subscription
+			svc := &mockService{
+				InsertSubscriptionFunc: func(parameter entity.Subscription) (entity.Subscription, error) {
+					got = parameter
+					parameter.ID = 1
+					return parameter, tt.mockErr
+				},
+			}
+			handler := &WebhooksHandler{service: svc}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			handler.InsertWebhook(w, req)
+
+			assertResponse(t, w, tt.wantStatus, tt.wantMessage)
+			if tt.wantStatus == http.StatusCreated && (got.URL != "https://example.com/hook" || len(got.Events) != 1) {
+				t.Errorf("unexpected subscription passed to service: %+v", got)
+			}
+		})
+	}
+}
+
+func TestWebhooksHandler_RetryDeadLetter(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockErr     error
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "accepted", wantStatus: http.StatusAccepted, wantMessage: "Berhasil mengirim ulang pengiriman webhook"},
+		{name: "not found", mockErr: entity.ErrDeliveryNotFound, wantStatus: http.StatusNotFound, wantMessage: constants.ErrDeliveryNotFound},
+		{name: "not retryable", mockErr: entity.ErrDeliveryNotRetryable, wantStatus: http.StatusConflict, wantMessage: constants.ErrDeliveryNotRetryable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{
+				RetryDeliveryFunc: func(deliveryID int64) (entity.Delivery, error) {
+					return entity.Delivery{ID: deliveryID, Status: entity.DeliveryPending}, tt.mockErr
+				},
+			}
+			handler := &WebhooksHandler{service: svc}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/dead-letters/5/retry", nil)
+			req.SetPathValue("id", "5")
+			w := httptest.NewRecorder()
+			handler.RetryDeadLetter(w, req)
+
+			assertResponse(t, w, tt.wantStatus, tt.wantMessage)
+		})
+	}
+}
+
+func TestWebhooksHandler_Lists(t *testing.T) {
+	svc := &mockService{
+		GetAllSubscriptionsFunc: func() []entity.Subscription {
+			return []entity.Subscription{{ID: 1, URL: "https://example.com/hook"}}
+		},
+		GetDeadLettersFunc: func() []entity.Delivery {
+			return []entity.Delivery{{ID: 3, Status: entity.DeliveryDead}}
+		},
+		GetDeliveriesFunc: func(subscriptionID int64) ([]entity.Delivery, error) {
+			if subscriptionID != 1 {
+				return nil, entity.ErrWebhookNotFound
+			}
+			return []entity.Delivery{{ID: 2, SubscriptionID: 1}}, nil
+		},
+		DeleteSubscriptionFunc: func(subscriptionID int64) (int64, error) {
+			return subscriptionID, nil
+		},
+	}
+	handler := &WebhooksHandler{service: svc}
+
+	tests := []struct {
+		name        string
+		method      string
+		id          string
+		lang        string
+		handle      http.HandlerFunc
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "all webhooks", method: http.MethodGet, handle: handler.GetAllWebhooks, wantStatus: http.StatusOK, wantMessage: "Berhasil mengambil semua webhook"},
+		{name: "dead letters", method: http.MethodGet, handle: handler.GetDeadLetters, wantStatus: http.StatusOK, wantMessage: "Berhasil mengambil pengiriman webhook yang gagal"},
+		{name: "deliveries", method: http.MethodGet, id: "1", handle: handler.GetWebhookDeliveries, wantStatus: http.StatusOK, wantMessage: "Berhasil mengambil pengiriman webhook"},
+		{name: "deliveries of unknown webhook", method: http.MethodGet, id: "9", handle: handler.GetWebhookDeliveries, wantStatus: http.StatusNotFound, wantMessage: constants.ErrWebhookNotFound},
+		{name: "delete", method: http.MethodDelete, id: "1", handle: handler.DeleteWebhook, wantStatus: http.StatusOK, wantMessage: "Berhasil menghapus webhook dengan id 1"},
+		{name: "english", method: http.MethodGet, lang: "en", handle: handler.GetAllWebhooks, wantStatus: http.StatusOK, wantMessage: "Success get all webhooks"},
+		{name: "english error", method: http.MethodGet, id: "9", lang: "en", handle: handler.GetWebhookDeliveries, wantStatus: http.StatusNotFound, wantMessage: "webhook not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/webhooks", nil)
+			if tt.lang != "" {
+				req.Header.Set("Accept-Language", tt.lang)
+			}
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+			tt.handle(w, req)
+
+			assertResponse(t, w, tt.wantStatus, tt.wantMessage)
+		})
+	}
+}
+
+func assertResponse(t *testing.T, w *httptest.ResponseRecorder, wantStatus int, wantMessage string) {
+	t.Helper()
+
+	if w.Code != wantStatus {
+		t.Errorf("status = %v, want %v", w.Code, wantStatus)
+	}
+
+	var got json_wrapper.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", w.Body.String(), err)
+	}
+	if got.Message != wantMessage {
+		t.Errorf("message = %v, want %v", got.Message, wantMessage)
+	}
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"errors"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+// ErrWebhookNotFound is returned when a webhook subscription does not exist.
+var ErrWebhookNotFound = errors.New(constants.ErrWebhookNotFound)
+
+// ErrDeliveryNotFound is returned when a webhook delivery does not exist.
+var ErrDeliveryNotFound = errors.New(constants.ErrDeliveryNotFound)
+
+// ErrInvalidWebhook is returned when a subscription fails validation.
+var ErrInvalidWebhook = errors.New(constants.ErrInvalidWebhookRequest)
+
+// ErrDeliveryNotRetryable is returned when a manual retry targets a delivery that is not dead-lettered.
+var ErrDeliveryNotRetryable = errors.New(constants.ErrDeliveryNotRetryable)
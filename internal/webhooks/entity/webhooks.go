@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// DeliveryPending marks a delivery that has not succeeded yet and still has attempts left.
+	DeliveryPending = "pending"
+
+	// DeliverySucceeded marks a delivery acknowledged by the subscriber with a 2xx response.
+	DeliverySucceeded = "succeeded"
+
+	// DeliveryDead marks a delivery that exhausted its attempts and was moved to the dead-letter list.
+	DeliveryDead = "dead"
+)
+
+// Subscription represents an endpoint registered to receive the category events of one tenant.
+// An empty Events list subscribes to every event type.
+type Subscription struct {
+	ID        int64     `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Event is the payload sent to subscribers, wrapping the changed category in Data.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	TenantID   string          `json:"tenant_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Delivery records every attempt to send one event to one subscription.
+// Payload holds the exact bytes that are signed and sent, so retries are byte-identical.
+type Delivery struct {
+	ID             int64           `json:"id"`
+	TenantID       string          `json:"tenant_id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Subscribes reports whether the subscription wants events of eventType.
+func (s Subscription) Subscribes(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/entity"
+)
+
+const (
+	// maxDeliveryLog is the size of the delivery log above which its oldest succeeded deliveries are dropped.
+	maxDeliveryLog = 1000
+
+	// maxDeadLetters is the number of dead letters kept in the log; the oldest are dropped once there are more, so a
+	// subscriber that always fails cannot grow the log and the store without bound.
+	maxDeadLetters = 1000
+
+	// flushInterval is how long delivery log changes are collected before they are written to the store together.
+	flushInterval = time.Second
+)
+
+// IWebhooksRepository defines an abstraction for storing webhook subscriptions and their delivery log.
+type IWebhooksRepository interface {
+	GetAllSubscriptions() []entity.Subscription
+	GetSubscriptionByID(subscriptionID int64) (entity.Subscription, error)
+	InsertSubscription(parameter entity.Subscription) (entity.Subscription, error)
+	DeleteSubscription(subscriptionID int64) (int64, error)
+
+	InsertDelivery(parameter entity.Delivery) (entity.Delivery, error)
+	UpdateDelivery(parameter entity.Delivery) (entity.Delivery, error)
+	GetDeliveryByID(deliveryID int64) (entity.Delivery, error)
+	GetDeliveriesBySubscription(subscriptionID int64) []entity.Delivery
+	GetDeliveriesByStatus(status string) []entity.Delivery
+}
+
+// snapshot is the on-disk representation of the repository.
+type snapshot struct {
+	NextSubscriptionID int64                 `json:"next_subscription_id"`
+	NextDeliveryID     int64                 `json:"next_delivery_id"`
+	Subscriptions      []entity.Subscription `json:"subscriptions"`
+	Deliveries         []entity.Delivery     `json:"deliveries"`
+}
+
+// WebhooksRepository keeps subscriptions and deliveries in memory and, when a path is configured, persists them to
+// a JSON file so the delivery log and dead letters survive restarts. Subscription changes are written before they
+// are acknowledged; delivery log changes are batched and written at most once per flushInterval in the background.
+type WebhooksRepository struct {
+	path string
+
+	mu    sync.RWMutex
+	data  snapshot
+	dirty bool
+
+	// fileMu serializes writes of the store so an older snapshot never replaces a newer one.
+	fileMu sync.Mutex
+
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWebhooksRepository initializes a repository persisted at path, loading any existing state from it.
+// An empty path keeps everything in memory only. Close must be called to write the last batch of changes.
+func NewWebhooksRepository(path string) (*WebhooksRepository, error) {
+	r := &WebhooksRepository{path: path}
+
+	if path == "" {
+		return r, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading webhooks store: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, &r.data); err != nil {
+			return nil, fmt.Errorf("error decoding webhooks store: %w", err)
+		}
+	}
+
+	r.wake = make(chan struct{}, 1)
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.flushLoop()
+
+	return r, nil
+}
+
+// GetAllSubscriptions returns every subscription ordered by ID.
+func (r *WebhooksRepository) GetAllSubscriptions() []entity.Subscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entity.Subscription{}, r.data.Subscriptions...)
+}
+
+// GetSubscriptionByID returns the subscription with the given ID or ErrWebhookNotFound.
+func (r *WebhooksRepository) GetSubscriptionByID(subscriptionID int64) (entity.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, subscription := range r.data.Subscriptions {
+		if subscription.ID == subscriptionID {
+			return subscription, nil
+		}
+	}
+
+	return entity.Subscription{}, entity.ErrWebhookNotFound
+}
+
+// InsertSubscription stores a new subscription with the next available ID.
+func (r *WebhooksRepository) InsertSubscription(parameter entity.Subscription) (entity.Subscription, error) {
+	r.mu.Lock()
+
+	r.data.NextSubscriptionID++
+	parameter.ID = r.data.NextSubscriptionID
+	r.data.Subscriptions = append(r.data.Subscriptions, parameter)
+	r.dirty = true
+	r.mu.Unlock()
+
+	return parameter, r.Flush()
+}
+
+// DeleteSubscription removes a subscription by its ID. Its deliveries are kept for auditing.
+func (r *WebhooksRepository) DeleteSubscription(subscriptionID int64) (int64, error) {
+	r.mu.Lock()
+	for i, subscription := range r.data.Subscriptions {
+		if subscription.ID == subscriptionID {
+			r.data.Subscriptions = append(r.data.Subscriptions[:i], r.data.Subscriptions[i+1:]...)
+			r.dirty = true
+			r.mu.Unlock()
+			return subscription.ID, r.Flush()
+		}
+	}
+	r.mu.Unlock()
+
+	return 0, entity.ErrWebhookNotFound
+}
+
+// InsertDelivery appends a delivery to the log with the next available ID, trimming the oldest finished entries.
+// The change reaches the store with the next batch written in the background.
+func (r *WebhooksRepository) InsertDelivery(parameter entity.Delivery) (entity.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data.NextDeliveryID++
+	parameter.ID = r.data.NextDeliveryID
+	r.data.Deliveries = append(r.data.Deliveries, parameter)
+	r.trim()
+	r.markDirty()
+
+	return parameter, nil
+}
+
+// UpdateDelivery replaces a stored delivery or returns ErrDeliveryNotFound, trimming the oldest dead letters when it
+// becomes one. Like InsertDelivery, the change reaches the store with the next batch.
+func (r *WebhooksRepository) UpdateDelivery(parameter entity.Delivery) (entity.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, delivery := range r.data.Deliveries {
+		if delivery.ID == parameter.ID {
+			r.data.Deliveries[i] = parameter
+			if parameter.Status == entity.DeliveryDead && delivery.Status != entity.DeliveryDead {
+				r.trim()
+			}
+			r.markDirty()
+			return parameter, nil
+		}
+	}
+
+	return entity.Delivery{}, entity.ErrDeliveryNotFound
+}
+
+// GetDeliveryByID returns the delivery with the given ID or ErrDeliveryNotFound.
+func (r *WebhooksRepository) GetDeliveryByID(deliveryID int64) (entity.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, delivery := range r.data.Deliveries {
+		if delivery.ID == deliveryID {
+			return delivery, nil
+		}
+	}
+
+	return entity.Delivery{}, entity.ErrDeliveryNotFound
+}
+
+// GetDeliveriesBySubscription returns the delivery log of one subscription, newest first.
+func (r *WebhooksRepository) GetDeliveriesBySubscription(subscriptionID int64) []entity.Delivery {
+	return r.filterDeliveries(func(d entity.Delivery) bool {
+		return d.SubscriptionID == subscriptionID
+	})
+}
+
+// GetDeliveriesByStatus returns every delivery in the given status, newest first.
+func (r *WebhooksRepository) GetDeliveriesByStatus(status string) []entity.Delivery {
+	return r.filterDeliveries(func(d entity.Delivery) bool {
+		return d.Status == status
+	})
+}
+
+// filterDeliveries returns the deliveries accepted by keep, newest first.
+func (r *WebhooksRepository) filterDeliveries(keep func(entity.Delivery) bool) []entity.Delivery {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []entity.Delivery{}
+	for _, delivery := range r.data.Deliveries {
+		if keep(delivery) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].ID > deliveries[j].ID
+	})
+
+	return deliveries
+}
+
+// trim drops the oldest succeeded deliveries once the log exceeds maxDeliveryLog and the oldest dead letters once
+// there are more than maxDeadLetters. Pending deliveries are never dropped, as they are still being retried and
+// become succeeded or dead after at most the configured number of attempts. The caller must hold r.mu.
+func (r *WebhooksRepository) trim() {
+	excessSucceeded := len(r.data.Deliveries) - maxDeliveryLog
+	excessDead := -maxDeadLetters
+	for _, delivery := range r.data.Deliveries {
+		if delivery.Status == entity.DeliveryDead {
+			excessDead++
+		}
+	}
+	if excessSucceeded <= 0 && excessDead <= 0 {
+		return
+	}
+
+	kept := r.data.Deliveries[:0]
+	for _, delivery := range r.data.Deliveries {
+		switch {
+		case excessSucceeded > 0 && delivery.Status == entity.DeliverySucceeded:
+			excessSucceeded--
+		case excessDead > 0 && delivery.Status == entity.DeliveryDead:
+			excessDead--
+		default:
+			kept = append(kept, delivery)
+		}
+	}
+	r.data.Deliveries = kept
+}
+
+// Flush writes the current state to the store unless nothing changed since the last write.
+func (r *WebhooksRepository) Flush() error {
+	if r.path == "" {
+		return nil
+	}
+
+	r.fileMu.Lock()
+	defer r.fileMu.Unlock()
+
+	r.mu.Lock()
+	if !r.dirty {
+		r.mu.Unlock()
+		return nil
+	}
+	content, err := json.MarshalIndent(r.data, "", "  ")
+	if err == nil {
+		r.dirty = false
+	}
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("error encoding webhooks store: %w", err)
+	}
+
+	if err := r.write(content); err != nil {
+		r.mu.Lock()
+		r.dirty = true
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Close stops the background writer and writes the changes it has not written yet.
+func (r *WebhooksRepository) Close() error {
+	if r.path == "" {
+		return nil
+	}
+
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+	})
+	return r.Flush()
+}
+
+// markDirty records a change to be written with the next batch. The caller must hold r.mu.
+func (r *WebhooksRepository) markDirty() {
+	if r.path == "" {
+		return
+	}
+
+	r.dirty = true
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// flushLoop writes the store flushInterval after the first change of every batch until Close is called.
+func (r *WebhooksRepository) flushLoop() {
+	defer close(r.done)
+
+	for {
+		select {
+		case <-r.wake:
+		case <-r.stop:
+			return
+		}
+
+		timer := time.NewTimer(flushInterval)
+		select {
+		case <-timer.C:
+		case <-r.stop:
+			timer.Stop()
+			return
+		}
+
+		if err := r.Flush(); err != nil {
+			log.Printf("webhooks: %v", err)
+		}
+	}
+}
+
+// write atomically replaces the store at r.path with content. The caller must hold r.fileMu.
+func (r *WebhooksRepository) write(content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("error creating webhooks store directory: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return fmt.Errorf("error writing webhooks store: %w", err)
+	}
+
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("error replacing webhooks store: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/entity"
+)
+
+func TestWebhooksRepository_Subscriptions(t *testing.T) {
+	repo, err := NewWebhooksRepository("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, _ := repo.InsertSubscription(entity.Subscription{URL: "https://a.example/hook"})
+	second, _ := repo.InsertSubscription(entity.Subscription{URL: "https://b.example/hook"})
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("expected sequential ids, got %d and %d", first.ID, second.ID)
+	}
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantURL string
+		wantErr error
+	}{
+		{name: "found", id: 2, wantURL: "https://b.example/hook"},
+		{name: "missing", id: 3, wantErr: entity.ErrWebhookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetSubscriptionByID(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got.URL != tt.wantURL {
+				t.Fatalf("expected url %q, got %q", tt.wantURL, got.URL)
+			}
+		})
+	}
+
+	if _, err := repo.DeleteSubscription(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.DeleteSubscription(1); !errors.Is(err, entity.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+	if got := repo.GetAllSubscriptions(); len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("unexpected subscriptions after delete: %+v", got)
+	}
+}
+
+func TestWebhooksRepository_Deliveries(t *testing.T) {
+	repo, _ := NewWebhooksRepository("")
+
+	for _, d := range []entity.Delivery{
+		{SubscriptionID: 1, Status: entity.DeliverySucceeded},
+		{SubscriptionID: 2, Status: entity.DeliveryDead},
+		{SubscriptionID: 1, Status: entity.DeliveryPending},
+	} {
+		if _, err := repo.InsertDelivery(d); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		get     func() []entity.Delivery
+		wantIDs []int64
+	}{
+		{name: "by subscription", get: func() []entity.Delivery { return repo.GetDeliveriesBySubscription(1) }, wantIDs: []int64{3, 1}},
+		{name: "by status", get: func() []entity.Delivery { return repo.GetDeliveriesByStatus(entity.DeliveryDead) }, wantIDs: []int64{2}},
+		{name: "none", get: func() []entity.Delivery { return repo.GetDeliveriesBySubscription(9) }, wantIDs: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.get()
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("expected %d deliveries, got %+v", len(tt.wantIDs), got)
+			}
+			for i, d := range got {
+				if d.ID != tt.wantIDs[i] {
+					t.Fatalf("expected ids %v, got %+v", tt.wantIDs, got)
+				}
+			}
+		})
+	}
+
+	updated, err := repo.UpdateDelivery(entity.Delivery{ID: 3, SubscriptionID: 1, Status: entity.DeliverySucceeded, Attempts: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := repo.GetDeliveryByID(3); got.Status != entity.DeliverySucceeded || got.Attempts != updated.Attempts {
+		t.Fatalf("unexpected delivery after update: %+v", got)
+	}
+	if _, err := repo.UpdateDelivery(entity.Delivery{ID: 42}); !errors.Is(err, entity.ErrDeliveryNotFound) {
+		t.Fatalf("expected ErrDeliveryNotFound, got %v", err)
+	}
+	if _, err := repo.GetDeliveryByID(42); !errors.Is(err, entity.ErrDeliveryNotFound) {
+		t.Fatalf("expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestWebhooksRepository_TrimKeepsDeadLetters(t *testing.T) {
+	repo, _ := NewWebhooksRepository("")
+
+	repo.InsertDelivery(entity.Delivery{Status: entity.DeliveryDead})
+	for i := 0; i < maxDeliveryLog+10; i++ {
+		repo.InsertDelivery(entity.Delivery{Status: entity.DeliverySucceeded})
+	}
+
+	if got := len(repo.data.Deliveries); got != maxDeliveryLog {
+		t.Fatalf("expected log trimmed to %d, got %d", maxDeliveryLog, got)
+	}
+	if _, err := repo.GetDeliveryByID(1); err != nil {
+		t.Fatalf("expected dead letter to survive trimming: %v", err)
+	}
+}
+
+func TestWebhooksRepository_TrimDeadLetters(t *testing.T) {
+	repo, _ := NewWebhooksRepository("")
+
+	pending, _ := repo.InsertDelivery(entity.Delivery{Status: entity.DeliveryPending})
+	for i := 0; i < maxDeadLetters+10; i++ {
+		repo.InsertDelivery(entity.Delivery{Status: entity.DeliveryDead})
+	}
+
+	dead := repo.GetDeliveriesByStatus(entity.DeliveryDead)
+	if len(dead) != maxDeadLetters {
+		t.Fatalf("expected %d dead letters, got %d", maxDeadLetters, len(dead))
+	}
+	if oldest := dead[len(dead)-1].ID; oldest != pending.ID+11 {
+		t.Fatalf("expected the oldest dead letters to be dropped, the oldest kept is %d", oldest)
+	}
+	if _, err := repo.GetDeliveryByID(pending.ID); err != nil {
+		t.Fatalf("expected the pending delivery to survive trimming: %v", err)
+	}
+
+	pending.Status = entity.DeliveryDead
+	if _, err := repo.UpdateDelivery(pending); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(repo.GetDeliveriesByStatus(entity.DeliveryDead)); got != maxDeadLetters {
+		t.Fatalf("expected a delivery dying to keep %d dead letters, got %d", maxDeadLetters, got)
+	}
+}
+
+func TestWebhooksRepository_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store", "webhooks.json")
+
+	repo, err := NewWebhooksRepository(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subscription, _ := repo.InsertSubscription(entity.Subscription{URL: "https://a.example/hook", Secret: "s3cret"})
+	repo.InsertDelivery(entity.Delivery{SubscriptionID: subscription.ID, Status: entity.DeliveryDead})
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error closing store: %v", err)
+	}
+
+	reopened, err := NewWebhooksRepository(path)
+	if err != nil {
+		t.Fatalf("unexpected error reopening store: %v", err)
+	}
+
+	got, err := reopened.GetSubscriptionByID(subscription.ID)
+	if err != nil || got.Secret != "s3cret" {
+		t.Fatalf("expected subscription to be restored, got %+v (%v)", got, err)
+	}
+	if dead := reopened.GetDeliveriesByStatus(entity.DeliveryDead); len(dead) != 1 {
+		t.Fatalf("expected dead letter to be restored, got %+v", dead)
+	}
+
+	next, _ := reopened.InsertSubscription(entity.Subscription{URL: "https://b.example/hook"})
+	if next.ID != subscription.ID+1 {
+		t.Fatalf("expected id sequence to continue, got %d", next.ID)
+	}
+	reopened.Close()
+}
+
+func TestWebhooksRepository_BatchesDeliveryWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	repo, _ := NewWebhooksRepository(path)
+	defer repo.Close()
+
+	subscription, _ := repo.InsertSubscription(entity.Subscription{URL: "https://a.example/hook"})
+	if stored := readStore(t, path); len(stored.Subscriptions) != 1 {
+		t.Fatalf("expected the subscription to be written before it is acknowledged, got %+v", stored)
+	}
+
+	delivery, _ := repo.InsertDelivery(entity.Delivery{SubscriptionID: subscription.ID, Status: entity.DeliveryPending})
+	delivery.Attempts = 1
+	repo.UpdateDelivery(delivery)
+	if stored := readStore(t, path); len(stored.Deliveries) != 0 {
+		t.Fatalf("expected delivery changes to wait for the next batch, got %+v", stored.Deliveries)
+	}
+
+	if err := repo.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored := readStore(t, path); len(stored.Deliveries) != 1 || stored.Deliveries[0].Attempts != 1 {
+		t.Fatalf("expected the batch to be written, got %+v", stored.Deliveries)
+	}
+}
+
+// readStore decodes the store persisted at path.
+func readStore(t *testing.T, path string) snapshot {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading store: %v", err)
+	}
+	var stored snapshot
+	if err := json.Unmarshal(content, &stored); err != nil {
+		t.Fatalf("decoding store: %v", err)
+	}
+	return stored
+}
@@ -0,0 +1,529 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	categoriesEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+const (
+	// DefaultMaxAttempts is the number of delivery attempts before a delivery is dead-lettered.
+	DefaultMaxAttempts = 5
+
+	// DefaultBaseBackoff is the wait before the first retry; it doubles after every failed attempt.
+	DefaultBaseBackoff = time.Second
+
+	// DefaultMaxBackoff caps the wait between two attempts.
+	DefaultMaxBackoff = 5 * time.Minute
+
+	// DefaultTimeout bounds a single delivery attempt.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultMaxConcurrency is the number of deliveries sent at the same time.
+	DefaultMaxConcurrency = 8
+
+	// DefaultQueueSize is the number of published events waiting to be fanned out to subscriptions.
+	DefaultQueueSize = 1024
+
+	// maxResponseBody is how much of a subscriber response is read before the connection is released.
+	maxResponseBody = 64 << 10
+)
+
+// EventTypes lists the event types a subscription may ask for.
+var EventTypes = []string{
+	categoriesEntity.EventCategoryCreated,
+	categoriesEntity.EventCategoryUpdated,
+	categoriesEntity.EventCategoryDeleted,
+}
+
+// IWebhooksService provides methods for managing webhook subscriptions and inspecting their deliveries.
+// GetAllSubscriptions retrieves every subscription without its secret.
+// GetSubscriptionByID retrieves a subscription by its unique identifier without its secret.
+// InsertSubscription validates and stores a new subscription, returning it with its signing secret.
+// DeleteSubscription removes a subscription by its ID.
+// GetDeliveries retrieves the delivery log of a subscription.
+// GetDeadLetters retrieves every delivery that exhausted its attempts.
+// RetryDelivery re-queues a dead-lettered delivery.
+// Every method works on the subscriptions and deliveries of the tenant carried by ctx; those of other tenants are
+// reported as not found.
+type IWebhooksService interface {
+	GetAllSubscriptions(ctx context.Context) []entity.Subscription
+	GetSubscriptionByID(ctx context.Context, subscriptionID int64) (entity.Subscription, error)
+	InsertSubscription(ctx context.Context, parameter entity.Subscription) (entity.Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int64) (int64, error)
+	GetDeliveries(ctx context.Context, subscriptionID int64) ([]entity.Delivery, error)
+	GetDeadLetters(ctx context.Context) []entity.Delivery
+	RetryDelivery(ctx context.Context, deliveryID int64) (entity.Delivery, error)
+}
+
+// Options tunes how WebhooksService sends deliveries. Zero values fall back to the package defaults.
+// AllowPrivateTargets permits subscriptions to loopback, private and link-local addresses, e.g. in development;
+// a custom Client is used as is and must guard its connections itself.
+type Options struct {
+	Client              *http.Client
+	MaxAttempts         int
+	BaseBackoff         time.Duration
+	MaxBackoff          time.Duration
+	MaxConcurrency      int
+	QueueSize           int
+	AllowPrivateTargets bool
+}
+
+// WebhooksService fans category events out to subscribers. Each delivery is signed with HMAC-SHA256,
+// retried with exponential backoff and moved to the dead-letter list once its attempts are exhausted.
+type WebhooksService struct {
+	repo    repository.IWebhooksRepository
+	options Options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	slots  chan struct{}
+	events chan categoriesEntity.CategoryEvent
+}
+
+// NewWebhooksService initializes a new WebhooksService with the provided IWebhooksRepository implementation.
+func NewWebhooksService(repo repository.IWebhooksRepository, options Options) (*WebhooksService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("webhooks repository must not be nil")
+	}
+
+	if options.Client == nil {
+		options.Client = newClient(options.AllowPrivateTargets)
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if options.BaseBackoff <= 0 {
+		options.BaseBackoff = DefaultBaseBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = DefaultMaxBackoff
+	}
+	if options.MaxConcurrency <= 0 {
+		options.MaxConcurrency = DefaultMaxConcurrency
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &WebhooksService{
+		repo:    repo,
+		options: options,
+		ctx:     ctx,
+		cancel:  cancel,
+		slots:   make(chan struct{}, options.MaxConcurrency),
+		events:  make(chan categoriesEntity.CategoryEvent, options.QueueSize),
+	}
+
+	s.wg.Add(1)
+	go s.fanOut()
+
+	return s, nil
+}
+
+// GetAllSubscriptions retrieves every subscription of the tenant without its secret.
+func (s *WebhooksService) GetAllSubscriptions(ctx context.Context) []entity.Subscription {
+	tenantID := tenant.FromContext(ctx)
+
+	subscriptions := []entity.Subscription{}
+	for _, subscription := range s.repo.GetAllSubscriptions() {
+		if tenantOf(subscription.TenantID) != tenantID {
+			continue
+		}
+
+		subscription.Secret = ""
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions
+}
+
+// GetSubscriptionByID retrieves a subscription of the tenant by its ID without its secret.
+func (s *WebhooksService) GetSubscriptionByID(ctx context.Context, subscriptionID int64) (entity.Subscription, error) {
+	subscription, err := s.subscription(ctx, subscriptionID)
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// InsertSubscription validates and stores a new subscription to the events of the tenant. A signing secret is
+// generated when none is given; this is the only response that includes the secret.
+func (s *WebhooksService) InsertSubscription(ctx context.Context, parameter entity.Subscription) (entity.Subscription, error) {
+	if err := validateSubscription(parameter, s.options.AllowPrivateTargets); err != nil {
+		return entity.Subscription{}, err
+	}
+
+	if parameter.Secret == "" {
+		secret, err := randomHex(32)
+		if err != nil {
+			return entity.Subscription{}, err
+		}
+		parameter.Secret = "whsec_" + secret
+	}
+
+	parameter.TenantID = tenant.FromContext(ctx)
+	parameter.CreatedAt = time.Now().UTC()
+	return s.repo.InsertSubscription(parameter)
+}
+
+// DeleteSubscription removes a subscription of the tenant by its ID. Deliveries still in flight for it are dead-lettered.
+func (s *WebhooksService) DeleteSubscription(ctx context.Context, subscriptionID int64) (int64, error) {
+	if _, err := s.subscription(ctx, subscriptionID); err != nil {
+		return 0, err
+	}
+
+	return s.repo.DeleteSubscription(subscriptionID)
+}
+
+// GetDeliveries retrieves the delivery log of a subscription of the tenant, newest first.
+func (s *WebhooksService) GetDeliveries(ctx context.Context, subscriptionID int64) ([]entity.Delivery, error) {
+	if _, err := s.subscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDeliveriesBySubscription(subscriptionID), nil
+}
+
+// GetDeadLetters retrieves every delivery of the tenant that exhausted its attempts, newest first.
+func (s *WebhooksService) GetDeadLetters(ctx context.Context) []entity.Delivery {
+	tenantID := tenant.FromContext(ctx)
+
+	deliveries := []entity.Delivery{}
+	for _, delivery := range s.repo.GetDeliveriesByStatus(entity.DeliveryDead) {
+		if tenantOf(delivery.TenantID) == tenantID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries
+}
+
+// RetryDelivery resets a dead-lettered delivery of the tenant and sends it again with a fresh set of attempts.
+func (s *WebhooksService) RetryDelivery(ctx context.Context, deliveryID int64) (entity.Delivery, error) {
+	delivery, err := s.repo.GetDeliveryByID(deliveryID)
+	if err != nil {
+		return entity.Delivery{}, err
+	}
+	if tenantOf(delivery.TenantID) != tenant.FromContext(ctx) {
+		return entity.Delivery{}, entity.ErrDeliveryNotFound
+	}
+
+	if delivery.Status != entity.DeliveryDead {
+		return entity.Delivery{}, entity.ErrDeliveryNotRetryable
+	}
+
+	delivery.Status = entity.DeliveryPending
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	delivery.ResponseStatus = 0
+	delivery.UpdatedAt = time.Now().UTC()
+
+	delivery, err = s.repo.UpdateDelivery(delivery)
+	if err != nil {
+		return entity.Delivery{}, err
+	}
+
+	s.dispatch(delivery)
+	return delivery, nil
+}
+
+// Publish queues event to be recorded as a delivery for every subscription of the event's tenant interested in it.
+// It never blocks the caller: events are fanned out and sent in the background, and an event is dropped with a log
+// line when the queue is full or the service is closing. It implements the categories service's IEventPublisher.
+func (s *WebhooksService) Publish(event categoriesEntity.CategoryEvent) {
+	if s.ctx.Err() != nil {
+		log.Printf("webhooks: dropping %s event, the service is closing", event.Type)
+		return
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		log.Printf("webhooks: dropping %s event, the queue is full", event.Type)
+	}
+}
+
+// fanOut records and dispatches the deliveries of queued events until the service closes. Events still queued then
+// are recorded as pending deliveries so ResumePending sends them after a restart.
+func (s *WebhooksService) fanOut() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case event := <-s.events:
+			s.record(event)
+		case <-s.ctx.Done():
+			for {
+				select {
+				case event := <-s.events:
+					s.record(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// record stores a delivery of event for every interested subscription of its tenant and dispatches it.
+func (s *WebhooksService) record(event categoriesEntity.CategoryEvent) {
+	payload, err := newPayload(event)
+	if err != nil {
+		log.Printf("webhooks: encoding %s event: %v", event.Type, err)
+		return
+	}
+
+	tenantID := tenantOf(event.TenantID)
+	for _, subscription := range s.repo.GetAllSubscriptions() {
+		if tenantOf(subscription.TenantID) != tenantID || !subscription.Subscribes(event.Type) {
+			continue
+		}
+
+		now := time.Now().UTC()
+		delivery, err := s.repo.InsertDelivery(entity.Delivery{
+			TenantID:       tenantID,
+			SubscriptionID: subscription.ID,
+			EventID:        payload.ID,
+			EventType:      event.Type,
+			Payload:        payload.raw,
+			Status:         entity.DeliveryPending,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+		if err != nil {
+			log.Printf("webhooks: recording delivery for subscription %d: %v", subscription.ID, err)
+			continue
+		}
+
+		s.dispatch(delivery)
+	}
+}
+
+// subscription returns the subscription with the given ID when it belongs to the tenant carried by ctx.
+func (s *WebhooksService) subscription(ctx context.Context, subscriptionID int64) (entity.Subscription, error) {
+	subscription, err := s.repo.GetSubscriptionByID(subscriptionID)
+	if err != nil {
+		return entity.Subscription{}, err
+	}
+	if tenantOf(subscription.TenantID) != tenant.FromContext(ctx) {
+		return entity.Subscription{}, entity.ErrWebhookNotFound
+	}
+
+	return subscription, nil
+}
+
+// tenantOf returns the tenant a record belongs to. Records stored before tenants existed carry no tenant and belong
+// to the default one.
+func tenantOf(tenantID string) string {
+	if tenantID == "" {
+		return tenant.DefaultID
+	}
+	return tenantID
+}
+
+// ResumePending re-sends deliveries left pending by a previous process, e.g. after a restart.
+func (s *WebhooksService) ResumePending() {
+	for _, delivery := range s.repo.GetDeliveriesByStatus(entity.DeliveryPending) {
+		s.dispatch(delivery)
+	}
+}
+
+// Close stops scheduling new attempts and waits for in-flight attempts to finish.
+// Deliveries that are still pending, including those of events still queued, stay in the log and can be resumed
+// with ResumePending.
+func (s *WebhooksService) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// dispatch sends delivery in the background unless the service is closing.
+func (s *WebhooksService) dispatch(delivery entity.Delivery) {
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	s.wg.Add(1)
+	go s.deliver(delivery)
+}
+
+// deliver attempts delivery until it succeeds, is dead-lettered or the service closes, waiting with
+// exponential backoff between attempts.
+func (s *WebhooksService) deliver(delivery entity.Delivery) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case s.slots <- struct{}{}:
+		case <-s.ctx.Done():
+			return
+		}
+
+		delivery = s.attempt(delivery)
+		<-s.slots
+
+		if delivery.Status != entity.DeliveryPending {
+			return
+		}
+
+		timer := time.NewTimer(s.backoff(delivery.Attempts))
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// attempt sends delivery once, records the outcome in the log and returns the updated delivery.
+func (s *WebhooksService) attempt(delivery entity.Delivery) entity.Delivery {
+	delivery.Attempts++
+	delivery.ResponseStatus = 0
+	delivery.LastError = ""
+
+	subscription, err := s.repo.GetSubscriptionByID(delivery.SubscriptionID)
+	if err != nil {
+		delivery.LastError = err.Error()
+		delivery.Status = entity.DeliveryDead
+	} else {
+		status, err := s.send(subscription, delivery)
+		delivery.ResponseStatus = status
+		switch {
+		case err != nil:
+			delivery.LastError = err.Error()
+		case status < 200 || status > 299:
+			delivery.LastError = fmt.Sprintf("unexpected response status %d", status)
+		default:
+			delivery.Status = entity.DeliverySucceeded
+		}
+
+		if delivery.Status == entity.DeliveryPending && delivery.Attempts >= s.options.MaxAttempts {
+			delivery.Status = entity.DeliveryDead
+		}
+	}
+
+	delivery.UpdatedAt = time.Now().UTC()
+	if _, err := s.repo.UpdateDelivery(delivery); err != nil {
+		log.Printf("webhooks: recording attempt %d of delivery %d: %v", delivery.Attempts, delivery.ID, err)
+	}
+
+	return delivery
+}
+
+// send posts the signed payload to the subscription URL and returns the response status.
+func (s *WebhooksService) send(subscription entity.Subscription, delivery entity.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "categories-api-webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.options.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait after the given number of failed attempts: BaseBackoff doubled per attempt, capped at MaxBackoff.
+func (s *WebhooksService) backoff(attempts int) time.Duration {
+	wait := s.options.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= s.options.MaxBackoff {
+			return s.options.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// payload is an encoded Event together with its ID.
+type payload struct {
+	ID  string
+	raw json.RawMessage
+}
+
+// newPayload encodes event into the JSON body sent to subscribers.
+func newPayload(event categoriesEntity.CategoryEvent) (payload, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return payload{}, err
+	}
+
+	data, err := json.Marshal(event.Category)
+	if err != nil {
+		return payload{}, err
+	}
+
+	raw, err := json.Marshal(entity.Event{
+		ID:         "evt_" + id,
+		Type:       event.Type,
+		TenantID:   tenantOf(event.TenantID),
+		OccurredAt: event.OccurredAt,
+		Data:       data,
+	})
+	if err != nil {
+		return payload{}, err
+	}
+
+	return payload{ID: "evt_" + id, raw: raw}, nil
+}
+
+// validateSubscription checks that the subscription targets an allowed http(s) URL and only known event types.
+func validateSubscription(subscription entity.Subscription, allowPrivate bool) error {
+	if err := validateTarget(subscription.URL, allowPrivate); err != nil {
+		return entity.ErrInvalidWebhook
+	}
+
+	for _, eventType := range subscription.Events {
+		known := false
+		for _, candidate := range EventTypes {
+			if eventType == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return entity.ErrInvalidWebhook
+		}
+	}
+
+	return nil
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
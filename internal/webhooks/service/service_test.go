@@ -0,0 +1,434 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	categoriesEntity "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/webhooks/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// receiver is a subscriber endpoint that fails the first failures requests and records the rest.
+type receiver struct {
+	failures int32
+
+	mu       sync.Mutex
+	calls    int32
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.calls++
+	if rc.calls <= rc.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+}
+
+func newTestService(t *testing.T, maxAttempts int) (*WebhooksService, *repository.WebhooksRepository) {
+	t.Helper()
+
+	repo, _ := repository.NewWebhooksRepository("")
+	svc, err := NewWebhooksService(repo, Options{
+		MaxAttempts:         maxAttempts,
+		BaseBackoff:         time.Millisecond,
+		MaxBackoff:          5 * time.Millisecond,
+		AllowPrivateTargets: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(svc.Close)
+	return svc, repo
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met before deadline")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+var testEvent = categoriesEntity.CategoryEvent{
+	Type:       categoriesEntity.EventCategoryCreated,
+	Category:   categoriesEntity.Category{ID: 1, Name: "Elektronik", Description: "Kategori Elektronik"},
+	OccurredAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+func TestNewWebhooksService(t *testing.T) {
+	if _, err := NewWebhooksService(nil, Options{}); err == nil {
+		t.Fatalf("expected error for nil repository")
+	}
+
+	repo, _ := repository.NewWebhooksRepository("")
+	svc, err := NewWebhooksService(repo, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer svc.Close()
+
+	if svc.options.MaxAttempts != DefaultMaxAttempts || svc.options.BaseBackoff != DefaultBaseBackoff ||
+		svc.options.MaxBackoff != DefaultMaxBackoff || svc.options.QueueSize != DefaultQueueSize || svc.options.Client == nil {
+		t.Fatalf("expected defaults, got %+v", svc.options)
+	}
+}
+
+func TestWebhooksService_InsertSubscription(t *testing.T) {
+	repo, _ := repository.NewWebhooksRepository("")
+	svc, _ := NewWebhooksService(repo, Options{})
+	defer svc.Close()
+
+	tests := []struct {
+		name       string
+		input      entity.Subscription
+		wantErr    error
+		wantSecret string
+	}{
+		{name: "generated secret", input: entity.Subscription{URL: "https://example.com/hook"}},
+		{name: "given secret", input: entity.Subscription{URL: "http://example.com/hook", Secret: "mine"}, wantSecret: "mine"},
+		{name: "known events", input: entity.Subscription{URL: "https://example.com/hook", Events: []string{categoriesEntity.EventCategoryDeleted}}},
+		{name: "relative url", input: entity.Subscription{URL: "/hook"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "unsupported scheme", input: entity.Subscription{URL: "ftp://example.com/hook"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "unknown event", input: entity.Subscription{URL: "https://example.com/hook", Events: []string{"category.archived"}}, wantErr: entity.ErrInvalidWebhook},
+		{name: "localhost", input: entity.Subscription{URL: "http://localhost:8080/hook"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "loopback", input: entity.Subscription{URL: "http://127.0.0.1/hook"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "loopback ipv6", input: entity.Subscription{URL: "http://[::1]/hook"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "private", input: entity.Subscription{URL: "https://10.0.0.5/hook"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "link-local", input: entity.Subscription{URL: "http://169.254.169.254/latest/meta-data"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "unspecified", input: entity.Subscription{URL: "http://0.0.0.0/hook"}, wantErr: entity.ErrInvalidWebhook},
+		{name: "mapped private", input: entity.Subscription{URL: "http://[::ffff:192.168.1.1]/hook"}, wantErr: entity.ErrInvalidWebhook},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.InsertSubscription(t.Context(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.ID == 0 || got.CreatedAt.IsZero() {
+				t.Fatalf("expected stored subscription, got %+v", got)
+			}
+			if tt.wantSecret != "" && got.Secret != tt.wantSecret {
+				t.Fatalf("expected secret %q, got %q", tt.wantSecret, got.Secret)
+			}
+			if tt.wantSecret == "" && !strings.HasPrefix(got.Secret, "whsec_") {
+				t.Fatalf("expected generated secret, got %q", got.Secret)
+			}
+
+			read, _ := svc.GetSubscriptionByID(t.Context(), got.ID)
+			if read.Secret != "" {
+				t.Fatalf("expected secret to be hidden on read")
+			}
+		})
+	}
+
+	for _, subscription := range svc.GetAllSubscriptions(t.Context()) {
+		if subscription.Secret != "" {
+			t.Fatalf("expected secrets to be hidden on list, got %+v", subscription)
+		}
+	}
+}
+
+func TestWebhooksService_PublishSignsDelivery(t *testing.T) {
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	svc, repo := newTestService(t, 3)
+	subscribed, _ := svc.InsertSubscription(t.Context(), entity.Subscription{URL: server.URL, Secret: "secret"})
+	svc.InsertSubscription(t.Context(), entity.Subscription{URL: server.URL, Events: []string{categoriesEntity.EventCategoryDeleted}})
+
+	svc.Publish(testEvent)
+
+	waitFor(t, func() bool {
+		deliveries := repo.GetDeliveriesByStatus(entity.DeliverySucceeded)
+		return len(deliveries) == 1
+	})
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if len(rc.requests) != 1 {
+		t.Fatalf("expected only the subscribed endpoint to be called, got %d requests", len(rc.requests))
+	}
+
+	req, body := rc.requests[0], rc.bodies[0]
+	timestamp, _ := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if !VerifySignature("secret", timestamp, body, req.Header.Get(HeaderSignature)) {
+		t.Fatalf("expected a valid signature, got %q", req.Header.Get(HeaderSignature))
+	}
+	if req.Header.Get(HeaderEvent) != categoriesEntity.EventCategoryCreated {
+		t.Fatalf("unexpected event header %q", req.Header.Get(HeaderEvent))
+	}
+
+	var event entity.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if event.Type != categoriesEntity.EventCategoryCreated || !strings.HasPrefix(event.ID, "evt_") || !event.OccurredAt.Equal(testEvent.OccurredAt) {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if !strings.Contains(string(event.Data), `"name":"Elektronik"`) {
+		t.Fatalf("unexpected event data %s", event.Data)
+	}
+
+	deliveries, _ := svc.GetDeliveries(t.Context(), subscribed.ID)
+	if len(deliveries) != 1 || deliveries[0].Attempts != 1 || deliveries[0].ResponseStatus != http.StatusOK {
+		t.Fatalf("unexpected delivery log %+v", deliveries)
+	}
+}
+
+// blockingRepository holds every InsertDelivery until release is closed.
+type blockingRepository struct {
+	*repository.WebhooksRepository
+	release chan struct{}
+}
+
+func (r *blockingRepository) InsertDelivery(parameter entity.Delivery) (entity.Delivery, error) {
+	<-r.release
+	return r.WebhooksRepository.InsertDelivery(parameter)
+}
+
+func TestWebhooksService_PublishDoesNotBlock(t *testing.T) {
+	server := httptest.NewServer(&receiver{})
+	defer server.Close()
+
+	inner, _ := repository.NewWebhooksRepository("")
+	repo := &blockingRepository{WebhooksRepository: inner, release: make(chan struct{})}
+	svc, _ := NewWebhooksService(repo, Options{QueueSize: 1, AllowPrivateTargets: true})
+	defer svc.Close()
+	svc.InsertSubscription(t.Context(), entity.Subscription{URL: server.URL})
+
+	done := make(chan struct{})
+	go func() {
+		// The stalled repository holds one event and the queue one more; the rest are dropped.
+		for range 3 {
+			svc.Publish(testEvent)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected Publish not to wait for the repository")
+	}
+
+	close(repo.release)
+	waitFor(t, func() bool {
+		return len(inner.GetDeliveriesByStatus(entity.DeliverySucceeded)) > 0
+	})
+}
+
+func TestWebhooksService_RefusesPrivateTargets(t *testing.T) {
+	server := httptest.NewServer(&receiver{})
+	defer server.Close()
+
+	// Stored directly, as a name resolving to the loopback address would pass validation.
+	repo, _ := repository.NewWebhooksRepository("")
+	repo.InsertSubscription(entity.Subscription{URL: server.URL})
+
+	svc, _ := NewWebhooksService(repo, Options{MaxAttempts: 1})
+	defer svc.Close()
+	svc.Publish(testEvent)
+
+	waitFor(t, func() bool {
+		return len(repo.GetDeliveriesByStatus(entity.DeliveryDead)) == 1
+	})
+	if dead := repo.GetDeliveriesByStatus(entity.DeliveryDead)[0]; !strings.Contains(dead.LastError, "not a public address") {
+		t.Fatalf("expected the connection to be refused, got %+v", dead)
+	}
+}
+
+func TestWebhooksService_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		maxAttempts  int
+		wantStatus   string
+		wantAttempts int
+	}{
+		{name: "succeeds after retries", failures: 2, maxAttempts: 3, wantStatus: entity.DeliverySucceeded, wantAttempts: 3},
+		{name: "dead-lettered", failures: 10, maxAttempts: 3, wantStatus: entity.DeliveryDead, wantAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(&receiver{failures: tt.failures})
+			defer server.Close()
+
+			svc, repo := newTestService(t, tt.maxAttempts)
+			svc.InsertSubscription(t.Context(), entity.Subscription{URL: server.URL})
+			svc.Publish(testEvent)
+
+			waitFor(t, func() bool {
+				return len(repo.GetDeliveriesByStatus(tt.wantStatus)) == 1
+			})
+
+			delivery := repo.GetDeliveriesByStatus(tt.wantStatus)[0]
+			if delivery.Attempts != tt.wantAttempts {
+				t.Fatalf("expected %d attempts, got %d", tt.wantAttempts, delivery.Attempts)
+			}
+			if tt.wantStatus == entity.DeliveryDead && (delivery.ResponseStatus != http.StatusServiceUnavailable || delivery.LastError == "") {
+				t.Fatalf("expected the last failure to be recorded, got %+v", delivery)
+			}
+		})
+	}
+}
+
+func TestWebhooksService_RetryDelivery(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	svc, repo := newTestService(t, 1)
+	svc.InsertSubscription(t.Context(), entity.Subscription{URL: server.URL})
+	svc.Publish(testEvent)
+
+	waitFor(t, func() bool {
+		return len(svc.GetDeadLetters(t.Context())) == 1
+	})
+	dead := svc.GetDeadLetters(t.Context())[0]
+
+	if _, err := svc.RetryDelivery(t.Context(), 404); !errors.Is(err, entity.ErrDeliveryNotFound) {
+		t.Fatalf("expected ErrDeliveryNotFound, got %v", err)
+	}
+
+	healthy.Store(true)
+	retried, err := svc.RetryDelivery(t.Context(), dead.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retried.Status != entity.DeliveryPending || retried.Attempts != 0 {
+		t.Fatalf("expected delivery to be reset, got %+v", retried)
+	}
+
+	waitFor(t, func() bool {
+		delivery, _ := repo.GetDeliveryByID(dead.ID)
+		return delivery.Status == entity.DeliverySucceeded
+	})
+
+	if _, err := svc.RetryDelivery(t.Context(), dead.ID); !errors.Is(err, entity.ErrDeliveryNotRetryable) {
+		t.Fatalf("expected ErrDeliveryNotRetryable, got %v", err)
+	}
+}
+
+func TestWebhooksService_DeletedSubscriptionIsDeadLettered(t *testing.T) {
+	repo, _ := repository.NewWebhooksRepository("")
+	delivery, _ := repo.InsertDelivery(entity.Delivery{SubscriptionID: 7, Status: entity.DeliveryPending})
+
+	svc, _ := NewWebhooksService(repo, Options{BaseBackoff: time.Millisecond})
+	defer svc.Close()
+	svc.ResumePending()
+
+	waitFor(t, func() bool {
+		got, _ := repo.GetDeliveryByID(delivery.ID)
+		return got.Status == entity.DeliveryDead
+	})
+}
+
+func TestWebhooksService_Backoff(t *testing.T) {
+	svc := &WebhooksService{options: Options{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := svc.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhooksService_TenantIsolation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	svc, _ := newTestService(t, 1)
+	acme := tenant.WithID(t.Context(), "acme")
+	globex := tenant.WithID(t.Context(), "globex")
+
+	subscription, err := svc.InsertSubscription(acme, entity.Subscription{URL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subscription.TenantID != "acme" {
+		t.Fatalf("expected the subscription to belong to acme, got %q", subscription.TenantID)
+	}
+
+	event := testEvent
+	event.TenantID = "globex"
+	svc.Publish(event)
+	event.TenantID = "acme"
+	svc.Publish(event)
+
+	waitFor(t, func() bool {
+		return len(svc.GetDeadLetters(acme)) == 1
+	})
+	dead := svc.GetDeadLetters(acme)[0]
+	if dead.TenantID != "acme" || !strings.Contains(string(dead.Payload), `"tenant_id":"acme"`) {
+		t.Fatalf("expected only acme's event to be delivered, got %+v", dead)
+	}
+
+	if got := svc.GetAllSubscriptions(globex); len(got) != 0 {
+		t.Errorf("expected globex to see no subscriptions, got %v", got)
+	}
+	if _, err := svc.GetSubscriptionByID(globex, subscription.ID); !errors.Is(err, entity.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound for globex, got %v", err)
+	}
+	if _, err := svc.GetDeliveries(globex, subscription.ID); !errors.Is(err, entity.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound for globex's delivery log, got %v", err)
+	}
+	if got := svc.GetDeadLetters(globex); len(got) != 0 {
+		t.Errorf("expected globex to see no dead letters, got %v", got)
+	}
+	if _, err := svc.RetryDelivery(globex, dead.ID); !errors.Is(err, entity.ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound for globex, got %v", err)
+	}
+	if _, err := svc.DeleteSubscription(globex, subscription.ID); !errors.Is(err, entity.ErrWebhookNotFound) {
+		t.Errorf("expected globex not to delete acme's subscription, got %v", err)
+	}
+	if _, err := svc.GetSubscriptionByID(acme, subscription.ID); err != nil {
+		t.Errorf("expected acme's subscription to survive, got %v", err)
+	}
+}
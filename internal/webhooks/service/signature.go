@@ -0,0 +1,46 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HeaderSignature carries the HMAC-SHA256 signature of a delivery as "sha256=<hex>".
+	HeaderSignature = "X-Webhook-Signature"
+
+	// HeaderTimestamp carries the Unix time the delivery was signed at; it is part of the signed content.
+	HeaderTimestamp = "X-Webhook-Timestamp"
+
+	// HeaderEvent carries the event type, e.g. "category.created".
+	HeaderEvent = "X-Webhook-Event"
+
+	// HeaderDelivery carries the delivery ID so receivers can de-duplicate retries.
+	HeaderDelivery = "X-Webhook-Delivery"
+
+	// signaturePrefix identifies the algorithm used in HeaderSignature.
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the value of HeaderSignature for body sent at timestamp, computed as
+// HMAC-SHA256(secret, "<timestamp>.<body>").
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is a valid HeaderSignature for body sent at timestamp.
+// Receivers should also reject timestamps that are too old to prevent replays.
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,40 @@
+package service
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("secret", "1700000000.{}")
+	const want = "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+
+	if got := Sign("secret", 1700000000, []byte("{}")); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"type":"category.created"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{name: "valid", secret: "secret", timestamp: 1700000000, body: body, signature: signature, want: true},
+		{name: "wrong secret", secret: "other", timestamp: 1700000000, body: body, signature: signature},
+		{name: "wrong timestamp", secret: "secret", timestamp: 1700000001, body: body, signature: signature},
+		{name: "tampered body", secret: "secret", timestamp: 1700000000, body: []byte(`{}`), signature: signature},
+		{name: "missing prefix", secret: "secret", timestamp: 1700000000, body: body, signature: signature[len("sha256="):]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.secret, tt.timestamp, tt.body, tt.signature); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
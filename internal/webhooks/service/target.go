@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// validateTarget checks that target is an absolute http(s) URL. Unless allowPrivate is set, hosts that name the
+// local machine or a loopback, private, link-local or otherwise non-public address are rejected, so subscriptions
+// cannot be used to reach the internal network. Names resolving to such addresses are refused when dialing.
+func validateTarget(target string, allowPrivate bool) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("url must not target the local machine")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublic(addr) {
+		return fmt.Errorf("url must not target a loopback, private or link-local address")
+	}
+
+	return nil
+}
+
+// isPublic reports whether addr may be the target of a delivery.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast()
+}
+
+// guardDial refuses connections to addresses that are not public. It runs after name resolution, so names that
+// resolve to the internal network, including through redirects or DNS rebinding, are refused too.
+func guardDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublic(addr) {
+		return fmt.Errorf("webhook target %s is not a public address", addr)
+	}
+	return nil
+}
+
+// newClient returns the HTTP client deliveries are sent with. Unless allowPrivate is set, it only connects to public
+// addresses and does not use a proxy, which would connect to the target on its behalf past the guard.
+func newClient(allowPrivate bool) *http.Client {
+	if allowPrivate {
+		return &http.Client{Timeout: DefaultTimeout}
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: guardDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: DefaultTimeout, Transport: transport}
+}
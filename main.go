@@ -9,11 +9,6 @@ import (
 	"github.com/pandusatrianura/code-with-umam-categories-api/api"
 )
 
-// @title Categories API
-// @version 1.0
-// @host pandusatrianura-categories-api-production.up.railway.app/
-// @BasePath /
-
 // main starts the API server on the specified address and handles errors during its execution.
 func main() {
 	// Load the .env file
@@ -0,0 +1,46 @@
+// Package blob stores binary objects, such as uploaded images, under slash-separated keys. Store abstracts the
+// storage so the API can keep objects on the local filesystem or in an object store without changing its callers.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when no object is stored under a key.
+	ErrNotFound = errors.New("blob: object not found")
+
+	// ErrInvalidKey is returned for keys ValidKey rejects.
+	ErrInvalidKey = errors.New("blob: invalid key")
+)
+
+// Store keeps objects under keys such as "default/3/icon.png". Implementations must be safe for concurrent use.
+// Put stores the content read from r under key, replacing any object stored under it; readers of key see either the
+// old or the new object, never a partial one.
+// Open returns the object stored under key, or ErrNotFound. The caller must close it. Implementations return an
+// io.ReadSeeker when they can, so the object can be served with range requests.
+// Delete removes the object stored under key. Deleting a key without an object is not an error.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidKey reports whether key can name an object: a relative, slash-separated path without empty, "." or ".."
+// elements, none of which starts with a dot or contains a backslash.
+func ValidKey(key string) bool {
+	if !fs.ValidPath(key) || key == "." || strings.Contains(key, `\`) {
+		return false
+	}
+
+	for _, element := range strings.Split(key, "/") {
+		if strings.HasPrefix(element, ".") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+package blob
+
+import "testing"
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "icon.png", want: true},
+		{key: "default/3/0a1b2c.png", want: true},
+		{key: "", want: false},
+		{key: ".", want: false},
+		{key: "/etc/passwd", want: false},
+		{key: "../icon.png", want: false},
+		{key: "default/../../icon.png", want: false},
+		{key: "default//icon.png", want: false},
+		{key: "default/icon.png/", want: false},
+		{key: "default/.upload-1", want: false},
+		{key: `default\icon.png`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := ValidKey(tt.key); got != tt.want {
+				t.Errorf("ValidKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewLocalStore(t *testing.T) {
+	if _, err := NewLocalStore(""); err == nil {
+		t.Errorf("expected an error for an empty directory")
+	}
+
+	dir := filepath.Join(t.TempDir(), "images")
+	if _, err := NewLocalStore(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("expected the directory to be created, got %v", err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewLocalStore(dir)
+	ctx := t.Context()
+
+	if _, err := store.Open(ctx, "default/3/icon.png"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before Put, got %v", err)
+	}
+
+	for _, content := range []string{"first", "second"} {
+		if err := store.Put(ctx, "default/3/icon.png", strings.NewReader(content)); err != nil {
+			t.Fatalf("unexpected put error: %v", err)
+		}
+
+		object, err := store.Open(ctx, "default/3/icon.png")
+		if err != nil {
+			t.Fatalf("unexpected open error: %v", err)
+		}
+		if _, ok := object.(io.ReadSeeker); !ok {
+			t.Errorf("expected an io.ReadSeeker, got %T", object)
+		}
+		got, _ := io.ReadAll(object)
+		object.Close()
+		if string(got) != content {
+			t.Errorf("expected %q, got %q", content, got)
+		}
+	}
+
+	if err := store.Put(ctx, "default/4/icon.png", strings.NewReader("other")); err != nil {
+		t.Fatalf("unexpected put error: %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, "default", "3")); len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left, got %v", entries)
+	}
+
+	if err := store.Delete(ctx, "default/3/icon.png"); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	if _, err := store.Open(ctx, "default/3/icon.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after Delete, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "default", "3")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the emptied directory to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "default", "4", "icon.png")); err != nil {
+		t.Errorf("expected other objects to be kept, got %v", err)
+	}
+	if err := store.Delete(ctx, "default/3/icon.png"); err != nil {
+		t.Errorf("expected deleting a missing object to succeed, got %v", err)
+	}
+}
+
+func TestLocalStore_InvalidKey(t *testing.T) {
+	store, _ := NewLocalStore(t.TempDir())
+	ctx := t.Context()
+
+	if err := store.Put(ctx, "../escape.png", strings.NewReader("x")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey from Put, got %v", err)
+	}
+	if _, err := store.Open(ctx, "/etc/passwd"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey from Open, got %v", err)
+	}
+	if err := store.Delete(ctx, "a/../../b"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey from Delete, got %v", err)
+	}
+}
+
+func TestLocalStore_FailedPut(t *testing.T) {
+	store, _ := NewLocalStore(t.TempDir())
+	ctx := t.Context()
+
+	if err := store.Put(ctx, "icon.png", strings.NewReader("kept")); err != nil {
+		t.Fatalf("unexpected put error: %v", err)
+	}
+	if err := store.Put(ctx, "icon.png", io.MultiReader(strings.NewReader("partial"), failingReader{})); err == nil {
+		t.Fatalf("expected the read error to be returned")
+	}
+
+	object, err := store.Open(ctx, "icon.png")
+	if err != nil {
+		t.Fatalf("unexpected open error: %v", err)
+	}
+	defer object.Close()
+	if got, _ := io.ReadAll(object); string(got) != "kept" {
+		t.Errorf("expected a failed Put to keep the object, got %q", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := store.Put(cancelled, "other.png", strings.NewReader("x")); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// failingReader fails every read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
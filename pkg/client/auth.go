@@ -0,0 +1,41 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Authenticator adds credentials to every request the Client sends, including retries.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator.
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req).
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken authenticates requests with an "Authorization: Bearer" header carrying token.
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		if token == "" {
+			return fmt.Errorf("client: bearer token must not be empty")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// APIKey authenticates requests with key in the given header, e.g. "X-API-Key".
+func APIKey(header, key string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		if header == "" || key == "" {
+			return fmt.Errorf("client: api key header and key must not be empty")
+		}
+		req.Header.Set(header, key)
+		return nil
+	})
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAuthenticators(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       Authenticator
+		wantHeader string
+		wantValue  string
+		wantErr    bool
+	}{
+		{name: "bearer token", auth: BearerToken("s3cret"), wantHeader: "Authorization", wantValue: "Bearer s3cret"},
+		{name: "empty bearer token", auth: BearerToken(""), wantErr: true},
+		{name: "api key", auth: APIKey("X-API-Key", "k3y"), wantHeader: "X-API-Key", wantValue: "k3y"},
+		{name: "api key without header", auth: APIKey("", "k3y"), wantErr: true},
+		{
+			name: "func",
+			auth: AuthenticatorFunc(func(req *http.Request) error {
+				req.Header.Set("X-Tenant", "toko")
+				return nil
+			}),
+			wantHeader: "X-Tenant",
+			wantValue:  "toko",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "http://localhost/api/v1/categories", nil)
+			err := tt.auth.Authenticate(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := req.Header.Get(tt.wantHeader); got != tt.wantValue {
+				t.Errorf("expected %s %q, got %q", tt.wantHeader, tt.wantValue, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+// Package client is a typed Go client for the categories API. It decodes the json_wrapper.APIResponse envelope into
+// Category values and returns API errors as *Error, which match ErrNotFound, ErrInvalidRequest and
+// ErrInternal with errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// DefaultTimeout bounds every attempt of a request sent with the default http.Client.
+const DefaultTimeout = 30 * time.Second
+
+// defaultPath is the path of the API appended to base URLs without one.
+const defaultPath = "/api/v1"
+
+// Client calls the categories API. A Client is safe for concurrent use.
+type Client struct {
+	baseURL  string
+	http     *http.Client
+	auth     Authenticator
+	retry    RetryPolicy
+	language string
+	tenant   string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests with httpClient instead of a client with DefaultTimeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithAuth adds the credentials of auth to every request.
+func WithAuth(auth Authenticator) Option {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithRetry retries failed requests according to policy instead of the default policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithLanguage asks the API for category texts and messages in locale, e.g. "en", via Accept-Language.
+func WithLanguage(locale string) Option {
+	return func(c *Client) {
+		c.language = locale
+	}
+}
+
+// WithTenant sends every request on behalf of the tenant with the given ID via the X-Tenant-ID header. Without it
+// requests go to the tenant the server resolves from the host or credentials, the default tenant if none.
+func WithTenant(id string) Option {
+	return func(c *Client) {
+		c.tenant = id
+	}
+}
+
+// New returns a Client for the API at baseURL, e.g. "http://localhost:8000". Base URLs without a path get the
+// /api/v1 path of the API.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("client: base url %q must be an http or https url", baseURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("client: base url %q has no host", baseURL)
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = defaultPath
+	}
+
+	c := &Client{baseURL: strings.TrimSuffix(u.String(), "/")}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.http == nil {
+		c.http = &http.Client{Timeout: DefaultTimeout}
+	}
+	c.retry = c.retry.withDefaults()
+
+	return c, nil
+}
+
+// BaseURL returns the URL requests are sent to, including the API path.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// List returns every category, in the order set with Reorder.
+func (c *Client) List(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	err := c.do(ctx, http.MethodGet, "/categories", nil, &categories)
+	return categories, err
+}
+
+// ListByAttributes returns the categories whose attributes match filter, in the order set with Reorder. A category
+// matches when, for every key of filter, its value of the attribute equals one of the given values.
+func (c *Client) ListByAttributes(ctx context.Context, filter AttributeFilter) ([]Category, error) {
+	query := url.Values{}
+	for key, values := range filter {
+		query[attributeFilterPrefix+key] = values
+	}
+
+	var categories []Category
+	err := c.do(ctx, http.MethodGet, "/categories?"+query.Encode(), nil, &categories)
+	return categories, err
+}
+
+// Get returns the category with the given ID.
+func (c *Client) Get(ctx context.Context, id int64) (Category, error) {
+	var category Category
+	err := c.do(ctx, http.MethodGet, categoryPath(id), nil, &category)
+	return category, err
+}
+
+// Create creates a category from the name, description, slug and attributes of category and returns it as stored,
+// with the defaults of attributes it has no value for. A non-zero ID is kept. Translations are stored separately with PutTranslation. The request carries a random
+// Idempotency-Key, so retries never create the category twice.
+func (c *Client) Create(ctx context.Context, category Category) (Category, error) {
+	var created Category
+	err := c.do(ctx, http.MethodPost, "/categories", categoryBody(category), &created)
+	return created, err
+}
+
+// Update replaces the name and description of the category with the ID of category and returns it as stored. A
+// given slug replaces the current one; without one, the slug is regenerated only when the name changes. Given
+// attributes replace the current ones, which are kept otherwise.
+func (c *Client) Update(ctx context.Context, category Category) (Category, error) {
+	var updated Category
+	err := c.do(ctx, http.MethodPut, categoryPath(category.ID), categoryBody(category), &updated)
+	return updated, err
+}
+
+// Delete deletes the category with the given ID.
+func (c *Client) Delete(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, categoryPath(id), nil, nil)
+}
+
+// PutTranslation stores the name and description of the category with the given ID in locale and returns the
+// category localized to locale.
+func (c *Client) PutTranslation(ctx context.Context, id int64, locale string, translation Translation) (Category, error) {
+	var category Category
+	err := c.do(ctx, http.MethodPut, categoryPath(id)+"/translations/"+url.PathEscape(locale), translation, &category)
+	return category, err
+}
+
+// Reorder moves the categories with the given IDs, in that order, before all other categories, which keep their
+// relative order, and returns every category in the new order.
+func (c *Client) Reorder(ctx context.Context, ids ...int64) ([]Category, error) {
+	var categories []Category
+	err := c.do(ctx, http.MethodPost, "/categories/reorder", categoryOrder{IDs: ids}, &categories)
+	return categories, err
+}
+
+// categoryPath returns the path of the category with the given ID.
+func categoryPath(id int64) string {
+	return "/categories/" + strconv.FormatInt(id, 10)
+}
+
+// categoryBody returns the fields of category the API accepts when creating or updating it.
+func categoryBody(category Category) Category {
+	category.Locale = ""
+	category.Translations = nil
+	return category
+}
+
+// do sends a request with body encoded as JSON, retrying it according to the retry policy, and decodes the data
+// of the response envelope into out. Non-2xx responses are returned as *Error.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var content []byte
+	if body != nil {
+		var err error
+		content, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("client: encoding request: %w", err)
+		}
+	}
+
+	// Every attempt of a POST shares one key, so the API handles it at most once.
+	var idempotencyKey string
+	if method == http.MethodPost {
+		var err error
+		idempotencyKey, err = newIdempotencyKey()
+		if err != nil {
+			return err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := c.newRequest(ctx, method, path, content)
+		if err != nil {
+			return err
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(middleware.IdempotencyKeyHeader, idempotencyKey)
+		}
+
+		resp, err := c.http.Do(req)
+		if attempt < c.retry.MaxAttempts && retryable(req, resp, err) {
+			wait := c.retry.backoff(attempt, resp)
+			if resp != nil {
+				drain(resp)
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		defer resp.Body.Close()
+		return decode(resp, out)
+	}
+}
+
+// newRequest returns the request for one attempt, with its headers and credentials set.
+func (c *Client) newRequest(ctx context.Context, method, path string, content []byte) (*http.Request, error) {
+	var reader io.Reader
+	if content != nil {
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if content != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+	if c.tenant != "" {
+		req.Header.Set(tenant.DefaultHeader, c.tenant)
+	}
+	if c.auth != nil {
+		if err := c.auth.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// decode decodes the envelope of resp, returning its data in out or, for non-2xx responses, an *Error.
+func decode(resp *http.Response, out interface{}) error {
+	var envelope struct {
+		json_wrapper.APIResponse
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&envelope)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		if decodeErr == nil {
+			apiErr.MessageCode = envelope.MessageCode
+			if envelope.Message != nil {
+				apiErr.Message = fmt.Sprint(envelope.Message)
+			}
+		}
+		return apiErr
+	}
+
+	if decodeErr != nil {
+		return fmt.Errorf("client: decoding response: %w", decodeErr)
+	}
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("client: decoding response data: %w", err)
+	}
+	return nil
+}
+
+// newIdempotencyKey returns a random Idempotency-Key.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("client: generating idempotency key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// drain reads a little of the body of a response that is not used and closes it, so its connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, 4<<10)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,525 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/api/router"
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	categoriesHandler "github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/delivery/http"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/entity"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/repository"
+	"github.com/pandusatrianura/code-with-umam-categories-api/internal/categories/service"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// newAPIServer serves the categories API the way api.Server mounts it, wrapped by wrap when set, and returns a
+// Client for it.
+func newAPIServer(t *testing.T, wrap func(http.Handler) http.Handler, opts ...Option) *Client {
+	t.Helper()
+
+	repo, err := repository.NewCategoriesRepository()
+	if err != nil {
+		t.Fatalf("unexpected repository error: %v", err)
+	}
+	attributes, err := repository.NewAttributesRepository()
+	if err != nil {
+		t.Fatalf("unexpected repository error: %v", err)
+	}
+	color := entity.AttributeDefinition{Key: "color", Type: entity.AttributeEnum, Values: []string{"red", "blue"}}
+	if _, err := attributes.InsertAttribute(context.Background(), color); err != nil {
+		t.Fatalf("unexpected attribute error: %v", err)
+	}
+	svc, err := service.NewCategoriesService(repo, attributes, nil)
+	if err != nil {
+		t.Fatalf("unexpected service error: %v", err)
+	}
+	handler, err := categoriesHandler.NewCategoriesHandler(svc)
+	if err != nil {
+		t.Fatalf("unexpected handler error: %v", err)
+	}
+
+	serveMux := http.NewServeMux()
+	serveMux.Handle("/api/v1/", http.StripPrefix("/api/v1", router.NewRouter(handler).RegisterRoutes()))
+	var mux http.Handler = serveMux
+	if wrap != nil {
+		mux = wrap(mux)
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, opts...)
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	return c
+}
+
+func TestClient_CRUD(t *testing.T) {
+	c := newAPIServer(t, nil)
+	ctx := context.Background()
+
+	categories, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected list error: %v", err)
+	}
+	if len(categories) == 0 || categories[0].Name != "Elektronik" {
+		t.Fatalf("expected the built-in categories, got %+v", categories)
+	}
+
+	created, err := c.Create(ctx, Category{ID: 4101, Name: "Buku Anak", Description: "Bacaan"})
+	if err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Delete(ctx, created.ID) })
+	if created.ID != 4101 || created.Slug != "buku-anak" {
+		t.Errorf("unexpected created category %+v", created)
+	}
+
+	got, err := c.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected get error: %v", err)
+	}
+	if got.Name != "Buku Anak" || got.Description != "Bacaan" {
+		t.Errorf("unexpected category %+v", got)
+	}
+
+	updated, err := c.Update(ctx, Category{ID: created.ID, Name: "Buku", Description: "Bacaan anak"})
+	if err != nil {
+		t.Fatalf("unexpected update error: %v", err)
+	}
+	if updated.Name != "Buku" || updated.Slug != "buku" {
+		t.Errorf("unexpected updated category %+v", updated)
+	}
+
+	translated, err := c.PutTranslation(ctx, created.ID, "en", Translation{Name: "Books", Description: "Reading"})
+	if err != nil {
+		t.Fatalf("unexpected translation error: %v", err)
+	}
+	if translated.Name != "Books" || translated.Locale != "en" {
+		t.Errorf("unexpected translated category %+v", translated)
+	}
+
+	if err := c.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	if _, err := c.Get(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestClient_Reorder(t *testing.T) {
+	c := newAPIServer(t, nil)
+	ctx := context.Background()
+
+	reordered, err := c.Reorder(ctx, 3, 1)
+	if err != nil {
+		t.Fatalf("unexpected reorder error: %v", err)
+	}
+
+	categories, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected list error: %v", err)
+	}
+	var ids []int64
+	for _, category := range categories {
+		ids = append(ids, category.ID)
+	}
+	if want := []int64{3, 1, 2}; !slices.Equal(ids, want) {
+		t.Fatalf("expected categories in order %v, got %v", want, ids)
+	}
+	if !reflect.DeepEqual(reordered, categories) {
+		t.Errorf("expected Reorder to return the new order %+v, got %+v", categories, reordered)
+	}
+
+	if _, err := c.Reorder(ctx, 1, 1); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for a repeated ID, got %v", err)
+	}
+	if _, err := c.Reorder(ctx, 987654); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown ID, got %v", err)
+	}
+}
+
+func TestClient_ListByAttributes(t *testing.T) {
+	c := newAPIServer(t, nil)
+	ctx := context.Background()
+
+	red, err := c.Create(ctx, Category{Name: "Merah", Attributes: map[string]any{"color": "red"}})
+	if err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	blue, err := c.Create(ctx, Category{Name: "Biru", Attributes: map[string]any{"color": "blue"}})
+	if err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	if want := map[string]any{"color": "red"}; !reflect.DeepEqual(red.Attributes, want) {
+		t.Errorf("expected attributes %v, got %v", want, red.Attributes)
+	}
+
+	tests := []struct {
+		name    string
+		filter  AttributeFilter
+		wantIDs []int64
+	}{
+		{name: "one value", filter: AttributeFilter{"color": {"red"}}, wantIDs: []int64{red.ID}},
+		{name: "any of the values", filter: AttributeFilter{"color": {"red", "blue"}}, wantIDs: []int64{red.ID, blue.ID}},
+		{name: "no match", filter: AttributeFilter{"color": {"green"}}, wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, err := c.ListByAttributes(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected list error: %v", err)
+			}
+			var ids []int64
+			for _, category := range categories {
+				ids = append(ids, category.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("expected categories %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+
+	if _, err := c.Create(ctx, Category{Name: "Hijau", Attributes: map[string]any{"color": "green"}}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for a value outside the enum, got %v", err)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	c := newAPIServer(t, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		call            func() error
+		wantErr         error
+		wantStatus      int
+		wantMessageCode string
+	}{
+		{
+			name:            "get unknown category",
+			call:            func() error { _, err := c.Get(ctx, 987654); return err },
+			wantErr:         ErrNotFound,
+			wantStatus:      http.StatusInternalServerError,
+			wantMessageCode: constants.MsgCategoryNotFound,
+		},
+		{
+			name:            "delete unknown category",
+			call:            func() error { return c.Delete(ctx, 987654) },
+			wantErr:         ErrNotFound,
+			wantStatus:      http.StatusInternalServerError,
+			wantMessageCode: constants.MsgCategoryNotFound,
+		},
+		{
+			name: "invalid locale",
+			call: func() error {
+				_, err := c.PutTranslation(ctx, 1, "not a locale", Translation{Name: "x"})
+				return err
+			},
+			wantErr:         ErrInvalidRequest,
+			wantStatus:      http.StatusBadRequest,
+			wantMessageCode: constants.MsgInvalidLocale,
+		},
+		{
+			name: "invalid translation",
+			call: func() error {
+				_, err := c.PutTranslation(ctx, 1, "en", Translation{})
+				return err
+			},
+			wantErr:         ErrInvalidRequest,
+			wantStatus:      http.StatusBadRequest,
+			wantMessageCode: constants.MsgInvalidTranslation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an *Error, got %T", err)
+			}
+			if apiErr.StatusCode != tt.wantStatus || apiErr.MessageCode != tt.wantMessageCode {
+				t.Errorf("expected %d %s, got %d %s", tt.wantStatus, tt.wantMessageCode, apiErr.StatusCode, apiErr.MessageCode)
+			}
+			if apiErr.Message == "" {
+				t.Error("expected the localized message to be kept")
+			}
+		})
+	}
+}
+
+func TestClient_Language(t *testing.T) {
+	c := newAPIServer(t, nil, WithLanguage("en"))
+
+	_, err := c.Get(context.Background(), 987654)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *Error, got %v", err)
+	}
+	if apiErr.Message != constants.Messages.Text("en", constants.MsgCategoryNotFound) {
+		t.Errorf("expected the english message, got %q", apiErr.Message)
+	}
+}
+
+func TestClient_WithTenant(t *testing.T) {
+	resolver, err := tenant.NewResolver(tenant.Options{})
+	if err != nil {
+		t.Fatalf("unexpected resolver error: %v", err)
+	}
+	tenancy, err := middleware.NewTenancy(middleware.TenancyOptions{Resolver: resolver})
+	if err != nil {
+		t.Fatalf("unexpected tenancy error: %v", err)
+	}
+
+	withTenancy := func(next http.Handler) http.Handler {
+		return tenancy.Middleware(next, middleware.WriteJSONError)
+	}
+	acme := newAPIServer(t, withTenancy, WithTenant("acme"))
+	ctx := context.Background()
+
+	created, err := acme.Create(ctx, Category{Name: "Acme Only", Description: "Visible to acme"})
+	if err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	if _, err := acme.Get(ctx, created.ID); err != nil {
+		t.Fatalf("expected the tenant to read its category, got %v", err)
+	}
+
+	other, err := New(acme.BaseURL(), WithTenant("globex"))
+	if err != nil {
+		t.Fatalf("unexpected client error: %v", err)
+	}
+	if _, err := other.Get(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected another tenant to get ErrNotFound, got %v", err)
+	}
+}
+
+func TestClient_AuthAndCustomHTTPClient(t *testing.T) {
+	var transportUsed atomic.Bool
+	httpClient := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		transportUsed.Store(true)
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	requireToken := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	c := newAPIServer(t, requireToken, WithAuth(BearerToken("s3cret")), WithHTTPClient(httpClient))
+	if _, err := c.List(context.Background()); err != nil {
+		t.Fatalf("unexpected list error: %v", err)
+	}
+	if !transportUsed.Load() {
+		t.Error("expected the custom http client to send the request")
+	}
+
+	anonymous := newAPIServer(t, requireToken)
+	_, err := anonymous.List(context.Background())
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.MessageCode != "" {
+		t.Fatalf("expected a 401 without message code, got %v", err)
+	}
+	if errors.Is(err, ErrInternal) || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a 401 to match no sentinel, got %v", err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	fastRetry := WithRetry(RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	tests := []struct {
+		name         string
+		failures     int32
+		failStatus   int
+		call         func(c *Client) error
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{
+			name:         "get retried until it succeeds",
+			failures:     2,
+			failStatus:   http.StatusServiceUnavailable,
+			call:         func(c *Client) error { _, err := c.List(context.Background()); return err },
+			wantAttempts: 3,
+		},
+		{
+			name:         "get gives up after max attempts",
+			failures:     5,
+			failStatus:   http.StatusBadGateway,
+			call:         func(c *Client) error { _, err := c.List(context.Background()); return err },
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:       "post is not retried after a server error",
+			failures:   1,
+			failStatus: http.StatusInternalServerError,
+			call: func(c *Client) error {
+				_, err := c.Create(context.Background(), Category{Name: "Tidak dibuat"})
+				return err
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "client errors are not retried",
+			failures:     1,
+			failStatus:   http.StatusBadRequest,
+			call:         func(c *Client) error { _, err := c.List(context.Background()); return err },
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			flaky := func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if attempts.Add(1) <= tt.failures {
+						w.WriteHeader(tt.failStatus)
+						return
+					}
+					next.ServeHTTP(w, r)
+				})
+			}
+
+			c := newAPIServer(t, flaky, fastRetry)
+			err := tt.call(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, got)
+			}
+		})
+	}
+}
+
+func TestClient_CreateRetryIsIdempotent(t *testing.T) {
+	idempotency, err := middleware.NewIdempotency(middleware.IdempotencyOptions{})
+	if err != nil {
+		t.Fatalf("unexpected idempotency error: %v", err)
+	}
+
+	// The first response is lost on its way back, after the category was created.
+	var attempts atomic.Int32
+	lossy := func(next http.Handler) http.Handler {
+		next = idempotency.Middleware(next, middleware.WriteJSONError)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost && attempts.Add(1) == 1 {
+				next.ServeHTTP(httptest.NewRecorder(), r)
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	c := newAPIServer(t, lossy, WithRetry(RetryPolicy{BaseBackoff: time.Millisecond}))
+	ctx := context.Background()
+
+	before, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected list error: %v", err)
+	}
+
+	created, err := c.Create(ctx, Category{Name: "Sekali Saja"})
+	if err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Delete(ctx, created.ID) })
+
+	after, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected list error: %v", err)
+	}
+	if attempts.Load() != 2 || len(after) != len(before)+1 {
+		t.Errorf("expected 2 attempts creating 1 category, got %d attempts and %d new categories", attempts.Load(), len(after)-len(before))
+	}
+}
+
+func TestClient_RetryStopsWithContext(t *testing.T) {
+	unavailable := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+	}
+	c := newAPIServer(t, unavailable, WithRetry(RetryPolicy{MaxAttempts: 5, MaxBackoff: time.Minute}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.List(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to stop with the context, took %s", elapsed)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+		wantErr string
+	}{
+		{baseURL: "http://localhost:8000", want: "http://localhost:8000/api/v1"},
+		{baseURL: "http://localhost:8000/", want: "http://localhost:8000/api/v1"},
+		{baseURL: "https://example.com/categories/api/v1/", want: "https://example.com/categories/api/v1"},
+		{baseURL: "localhost:8000", wantErr: "must be an http or https url"},
+		{baseURL: "http://", wantErr: "has no host"},
+		{baseURL: "http://%zz", wantErr: "invalid base url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			c, err := New(tt.baseURL)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.BaseURL() != tt.want {
+				t.Errorf("expected base url %q, got %q", tt.want, c.BaseURL())
+			}
+		})
+	}
+}
+
+// roundTripFunc adapts a function to an http.RoundTripper.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
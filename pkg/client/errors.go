@@ -0,0 +1,69 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+var (
+	// ErrNotFound matches API errors for a category that does not exist.
+	ErrNotFound = errors.New("client: category not found")
+
+	// ErrInvalidRequest matches API errors for a request the API rejected as invalid: a malformed body, ID, locale or
+	// translation.
+	ErrInvalidRequest = errors.New("client: invalid request")
+
+	// ErrCategoryInUse matches API errors for deleting a category that still has products.
+	ErrCategoryInUse = errors.New("client: category still has products")
+
+	// ErrInternal matches API errors for failures on the server side.
+	ErrInternal = errors.New("client: internal server error")
+)
+
+// codeErrors maps the message codes of API errors to the sentinel errors they match.
+var codeErrors = map[string]error{
+	constants.MsgCategoryNotFound:     ErrNotFound,
+	constants.MsgInvalidRequest:       ErrInvalidRequest,
+	constants.MsgInvalidCategoryID:    ErrInvalidRequest,
+	constants.MsgInvalidLocale:        ErrInvalidRequest,
+	constants.MsgInvalidTranslation:   ErrInvalidRequest,
+	constants.MsgInvalidOrder:         ErrInvalidRequest,
+	constants.MsgInvalidAttribute:     ErrInvalidRequest,
+	constants.MsgUnsupportedMediaType: ErrInvalidRequest,
+	constants.MsgRequestTooLarge:      ErrInvalidRequest,
+	constants.MsgCategoryInUse:        ErrCategoryInUse,
+	constants.MsgInternalServer:       ErrInternal,
+}
+
+// Error is an error response of the API. Match it against ErrNotFound, ErrInvalidRequest, ErrCategoryInUse and
+// ErrInternal with errors.Is, or use errors.As to read the status and message code.
+type Error struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+
+	// MessageCode is the stable, machine-readable code of Message, one of the constants.Msg* codes. It is empty
+	// when the response was not an API envelope, e.g. an error page of a proxy.
+	MessageCode string
+
+	// Message is the error message, localized for the client.
+	Message string
+}
+
+// Error returns the message together with its code and HTTP status.
+func (e *Error) Error() string {
+	if e.MessageCode == "" {
+		return fmt.Sprintf("client: api error (%d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("client: api error %s (%d): %s", e.MessageCode, e.StatusCode, e.Message)
+}
+
+// Is reports whether target is the sentinel error of the message code. Responses without a known code match
+// ErrInternal when their status is 5xx.
+func (e *Error) Is(target error) bool {
+	if sentinel, ok := codeErrors[e.MessageCode]; ok {
+		return target == sentinel
+	}
+	return target == ErrInternal && e.StatusCode >= 500
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+func TestError_Is(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *Error
+		matches []error
+	}{
+		{
+			name:    "category not found",
+			err:     &Error{StatusCode: http.StatusInternalServerError, MessageCode: constants.MsgCategoryNotFound},
+			matches: []error{ErrNotFound},
+		},
+		{
+			name:    "invalid category id",
+			err:     &Error{StatusCode: http.StatusBadRequest, MessageCode: constants.MsgInvalidCategoryID},
+			matches: []error{ErrInvalidRequest},
+		},
+		{
+			name:    "category in use",
+			err:     &Error{StatusCode: http.StatusConflict, MessageCode: constants.MsgCategoryInUse},
+			matches: []error{ErrCategoryInUse},
+		},
+		{
+			name:    "internal server error",
+			err:     &Error{StatusCode: http.StatusInternalServerError, MessageCode: constants.MsgInternalServer},
+			matches: []error{ErrInternal},
+		},
+		{
+			name:    "gateway error without envelope",
+			err:     &Error{StatusCode: http.StatusBadGateway},
+			matches: []error{ErrInternal},
+		},
+		{
+			name: "unknown client error",
+			err:  &Error{StatusCode: http.StatusUnauthorized},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, sentinel := range []error{ErrNotFound, ErrInvalidRequest, ErrCategoryInUse, ErrInternal} {
+				want := false
+				for _, match := range tt.matches {
+					want = want || match == sentinel
+				}
+				if got := errors.Is(tt.err, sentinel); got != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, sentinel, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	withCode := &Error{StatusCode: http.StatusBadRequest, MessageCode: constants.MsgInvalidLocale, Message: "locale tidak valid"}
+	if got, want := withCode.Error(), "client: api error INVALID_LOCALE (400): locale tidak valid"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	withoutCode := &Error{StatusCode: http.StatusBadGateway, Message: "Bad Gateway"}
+	if got, want := withoutCode.Error(), "client: api error (502): Bad Gateway"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,102 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/client"
+)
+
+// TestClient_PublicAPI exercises the client the way code outside this module does, against a stand-in for the API
+// that only speaks its wire format.
+func TestClient_PublicAPI(t *testing.T) {
+	var gotQuery, gotOrder, gotTranslation string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/categories", func(w http.ResponseWriter, r *http.Request) {
+		var category map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&category)
+		category["id"] = 7
+		category["slug"] = "buku"
+		category["image"] = map[string]any{"content_type": "image/png", "size": 10, "width": 2, "height": 3, "checksum": "abc"}
+		writeData(w, http.StatusCreated, category)
+	})
+	mux.HandleFunc("GET /api/v1/categories", func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		writeData(w, http.StatusOK, []map[string]any{{"id": 7, "name": "Buku", "attributes": map[string]any{"color": "red"}}})
+	})
+	mux.HandleFunc("PUT /api/v1/categories/7/translations/en", func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotTranslation = string(body)
+		writeData(w, http.StatusOK, map[string]any{"id": 7, "name": "Books", "locale": "en"})
+	})
+	mux.HandleFunc("POST /api/v1/categories/reorder", func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotOrder = string(body)
+		writeData(w, http.StatusOK, []map[string]any{{"id": 7}})
+	})
+	mux.HandleFunc("GET /api/v1/categories/8", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": "404", "message_code": "CATEGORY_NOT_FOUND", "message": "Category not found"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := client.New(server.URL)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, client.Category{Name: "Buku", Attributes: map[string]any{"color": "red"}})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	wantImage := &client.CategoryImage{ContentType: "image/png", Size: 10, Width: 2, Height: 3, Checksum: "abc"}
+	if created.ID != 7 || created.Slug != "buku" || created.Attributes["color"] != "red" || !reflect.DeepEqual(created.Image, wantImage) {
+		t.Errorf("expected the created category to be decoded, got %+v", created)
+	}
+
+	listed, err := c.ListByAttributes(ctx, client.AttributeFilter{"color": {"red"}})
+	if err != nil {
+		t.Fatalf("ListByAttributes error: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != 7 || gotQuery != "attr.color=red" {
+		t.Errorf("expected the filter to be sent as attr.color=red, got %q and %+v", gotQuery, listed)
+	}
+
+	translated, err := c.PutTranslation(ctx, 7, "en", client.Translation{Name: "Books", Description: "Reading"})
+	if err != nil {
+		t.Fatalf("PutTranslation error: %v", err)
+	}
+	if translated.Name != "Books" || translated.Locale != "en" || gotTranslation != `{"name":"Books","description":"Reading"}` {
+		t.Errorf("expected the translation to round-trip, sent %s and got %+v", gotTranslation, translated)
+	}
+
+	if _, err := c.Reorder(ctx, 7, 3); err != nil {
+		t.Fatalf("Reorder error: %v", err)
+	}
+	if gotOrder != `{"ids":[7,3]}` {
+		t.Errorf("expected the order to be sent as ids, got %s", gotOrder)
+	}
+
+	_, err = c.Get(ctx, 8)
+	var apiErr *client.Error
+	if !errors.Is(err, client.ErrNotFound) || !errors.As(err, &apiErr) || apiErr.MessageCode != "CATEGORY_NOT_FOUND" {
+		t.Errorf("expected a not found *client.Error, got %v", err)
+	}
+}
+
+// writeData writes data in the response envelope of the API.
+func writeData(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"code": "ok", "message": "ok", "data": data})
+}
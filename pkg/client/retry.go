@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts of a request before its last error is returned.
+	DefaultMaxAttempts = 3
+
+	// DefaultBaseBackoff is the wait before the first retry; it doubles after every failed attempt.
+	DefaultBaseBackoff = 200 * time.Millisecond
+
+	// DefaultMaxBackoff caps the wait between two attempts, including waits asked for with Retry-After.
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// RetryPolicy decides how often and how long apart requests are retried. Only requests that are safe to repeat
+// (GET, PUT, DELETE and POST requests carrying an Idempotency-Key) are retried, and only after a network error or a
+// 429, 502, 503 or 504 response. POST requests are also retried after a 409 telling that their first attempt is
+// still in flight. Zero values fall back to the package defaults; a MaxAttempts of 1 disables retries.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// withDefaults returns the policy with zero values replaced by the package defaults.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = DefaultBaseBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	return p
+}
+
+// backoff returns the wait after the given number of failed attempts. A Retry-After header of resp, in seconds,
+// takes precedence.
+func (p RetryPolicy) backoff(attempts int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, p.MaxBackoff)
+		}
+	}
+
+	wait := p.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// retryable reports whether req, which ended with resp or err, may be sent again.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	idempotencyKey := req.Header.Get(middleware.IdempotencyKeyHeader) != ""
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	case http.MethodPost:
+		if !idempotencyKey {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return idempotencyKey
+	default:
+		return false
+	}
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/middleware"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}.withDefaults()
+
+	tests := []struct {
+		name       string
+		attempts   int
+		retryAfter string
+		want       time.Duration
+	}{
+		{name: "first retry", attempts: 1, want: 100 * time.Millisecond},
+		{name: "doubles", attempts: 3, want: 400 * time.Millisecond},
+		{name: "capped", attempts: 10, want: time.Second},
+		{name: "retry after", attempts: 1, retryAfter: "0", want: 0},
+		{name: "retry after capped", attempts: 1, retryAfter: "120", want: time.Second},
+		{name: "retry after date ignored", attempts: 2, retryAfter: "Wed, 21 Oct 2026 07:28:00 GMT", want: 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := policy.backoff(tt.attempts, resp); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Defaults(t *testing.T) {
+	policy := RetryPolicy{}.withDefaults()
+	if policy.MaxAttempts != DefaultMaxAttempts || policy.BaseBackoff != DefaultBaseBackoff || policy.MaxBackoff != DefaultMaxBackoff {
+		t.Errorf("expected the package defaults, got %+v", policy)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		idempotencyKey bool
+		status         int
+		err            error
+		want           bool
+	}{
+		{name: "get unavailable", method: http.MethodGet, status: http.StatusServiceUnavailable, want: true},
+		{name: "put too many requests", method: http.MethodPut, status: http.StatusTooManyRequests, want: true},
+		{name: "delete gateway timeout", method: http.MethodDelete, status: http.StatusGatewayTimeout, want: true},
+		{name: "get network error", method: http.MethodGet, err: errors.New("connection reset"), want: true},
+		{name: "get internal error", method: http.MethodGet, status: http.StatusInternalServerError},
+		{name: "get not found", method: http.MethodGet, status: http.StatusNotFound},
+		{name: "get conflict", method: http.MethodGet, status: http.StatusConflict},
+		{name: "post unavailable", method: http.MethodPost, status: http.StatusServiceUnavailable},
+		{name: "post network error", method: http.MethodPost, err: errors.New("connection reset")},
+		{name: "keyed post unavailable", method: http.MethodPost, idempotencyKey: true, status: http.StatusServiceUnavailable, want: true},
+		{name: "keyed post network error", method: http.MethodPost, idempotencyKey: true, err: errors.New("connection reset"), want: true},
+		{name: "keyed post in flight", method: http.MethodPost, idempotencyKey: true, status: http.StatusConflict, want: true},
+		{name: "keyed post bad request", method: http.MethodPost, idempotencyKey: true, status: http.StatusBadRequest},
+		{name: "get canceled", method: http.MethodGet, err: context.Canceled},
+		{name: "get deadline", method: http.MethodGet, err: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "http://localhost/api/v1/categories", nil)
+			if tt.idempotencyKey {
+				req.Header.Set(middleware.IdempotencyKeyHeader, "k1")
+			}
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			if got := retryable(req, resp, tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+// Category is a category as served by the API. Slug is generated from the name unless one is given. Position is
+// the rank of the category in the order set with Reorder, lowest first. Attributes holds the values of the
+// attributes defined for the tenant's categories. Image describes the category's uploaded image, or is nil when it
+// has none. Locale is the locale Name and Description are returned in. Translations is never filled in by the
+// client; translations are stored one locale at a time with PutTranslation.
+type Category struct {
+	ID           int64                  `json:"id"`
+	Name         string                 `json:"name"`
+	Slug         string                 `json:"slug"`
+	Description  string                 `json:"description"`
+	Position     int64                  `json:"position"`
+	Attributes   map[string]any         `json:"attributes,omitempty"`
+	Image        *CategoryImage         `json:"image,omitempty"`
+	Locale       string                 `json:"locale,omitempty"`
+	Translations map[string]Translation `json:"translations,omitempty"`
+}
+
+// Translation is a category's name and description in one locale.
+type Translation struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CategoryImage describes the image stored for a category. Checksum is the hex-encoded SHA-256 of the image and
+// changes whenever a different image is stored.
+type CategoryImage struct {
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Checksum    string `json:"checksum"`
+}
+
+// AttributeFilter selects categories by attribute value: a category matches when, for every key, its value of the
+// attribute equals one of the given values. Values are given as text and compared according to the type of the
+// stored value, so "1.50" matches the number 1.5 and "TRUE" matches true.
+type AttributeFilter map[string][]string
+
+// attributeFilterPrefix prefixes the query parameters that filter category lists by attribute.
+const attributeFilterPrefix = "attr."
+
+// categoryOrder is the body of a reorder request.
+type categoryOrder struct {
+	IDs []int64 `json:"ids"`
+}
@@ -0,0 +1,48 @@
+package graphiql
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// PlaygroundHTML renders a GraphiQL page that sends its queries to optionsInput.Endpoint.
+func PlaygroundHTML(optionsInput *Options) (string, error) {
+	if optionsInput == nil {
+		return "", fmt.Errorf("options must be provided")
+	}
+
+	options := DefaultOptions(*optionsInput)
+
+	// json.Marshal escapes <, > and & so the endpoint cannot break out of the script element
+	endpoint, err := json.Marshal(options.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	cdn := html.EscapeString(strings.TrimSuffix(options.CDN, "/"))
+
+	return fmt.Sprintf(`
+    <!DOCTYPE html>
+    <html>
+      <head>
+        <title>%s</title>
+        <meta charset="utf-8" />
+        <meta name="viewport" content="width=device-width, initial-scale=1" />
+        <link rel="stylesheet" href="%s/graphiql@3/graphiql.min.css" />
+        <style>body { margin: 0; } #graphiql { height: 100vh; }</style>
+      </head>
+      <body>
+        <div id="graphiql">Loading...</div>
+        <script crossorigin src="%s/react@18/umd/react.production.min.js"></script>
+        <script crossorigin src="%s/react-dom@18/umd/react-dom.production.min.js"></script>
+        <script crossorigin src="%s/graphiql@3/graphiql.min.js"></script>
+        <script>
+          const fetcher = GraphiQL.createFetcher({ url: new URL(%s, window.location.href).toString() });
+          ReactDOM.createRoot(document.getElementById('graphiql')).render(React.createElement(GraphiQL, { fetcher }));
+        </script>
+      </body>
+    </html>
+  `, html.EscapeString(options.PageTitle), cdn, cdn, cdn, cdn, endpoint), nil
+}
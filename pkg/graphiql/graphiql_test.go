@@ -0,0 +1,73 @@
+package graphiql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlaygroundHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *Options
+		wantErr bool
+		check   func(t *testing.T, got string)
+	}{
+		{
+			name:    "missing",
+			options: nil,
+			wantErr: true,
+		},
+		{
+			name:    "defaults",
+			options: &Options{},
+			check: func(t *testing.T, got string) {
+				if !strings.Contains(got, "<title>GraphiQL</title>") {
+					t.Fatalf("expected default title in output")
+				}
+				if !strings.Contains(got, `new URL("../graphql", window.location.href)`) {
+					t.Fatalf("expected default endpoint in output")
+				}
+				if !strings.Contains(got, DefaultCDN+"/graphiql@3/graphiql.min.js") {
+					t.Fatalf("expected default cdn in output")
+				}
+			},
+		},
+		{
+			name: "escaped",
+			options: &Options{
+				Endpoint:  "</script><script>alert(1)</script>",
+				PageTitle: "<b>Title</b>",
+				CDN:       "https://cdn.example.com/",
+			},
+			check: func(t *testing.T, got string) {
+				if strings.Contains(got, "<b>Title</b>") || !strings.Contains(got, "&lt;b&gt;Title&lt;/b&gt;") {
+					t.Fatalf("expected escaped title in output")
+				}
+				if strings.Contains(got, "</script><script>alert(1)") {
+					t.Fatalf("expected escaped endpoint in output")
+				}
+				if !strings.Contains(got, "https://cdn.example.com/react@18") {
+					t.Fatalf("expected trailing slash of cdn to be trimmed")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlaygroundHTML(tt.options)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package graphiql
+
+// DefaultCDN is the base URL the playground page loads GraphiQL and React from.
+const DefaultCDN = "https://unpkg.com"
+
+// DefaultEndpoint is the GraphQL endpoint queried by the playground, relative to the playground page URL.
+const DefaultEndpoint = "../graphql"
+
+// Options configures the GraphiQL playground page.
+type Options struct {
+	CDN       string
+	Endpoint  string
+	PageTitle string
+}
+
+// DefaultOptions configures the default settings for the playground options
+func DefaultOptions(option Options) *Options {
+	returnOptions := option
+
+	if returnOptions.CDN == "" {
+		returnOptions.CDN = DefaultCDN
+	}
+
+	if returnOptions.Endpoint == "" {
+		returnOptions.Endpoint = DefaultEndpoint
+	}
+
+	if returnOptions.PageTitle == "" {
+		returnOptions.PageTitle = "GraphiQL"
+	}
+
+	return &returnOptions
+}
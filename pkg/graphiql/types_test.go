@@ -0,0 +1,38 @@
+package graphiql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Options
+		expected Options
+	}{
+		{
+			name:     "defaults",
+			input:    Options{},
+			expected: Options{CDN: DefaultCDN, Endpoint: DefaultEndpoint, PageTitle: "GraphiQL"},
+		},
+		{
+			name:     "keep",
+			input:    Options{CDN: "https://cdn.example.com", Endpoint: "/graphql", PageTitle: "Docs"},
+			expected: Options{CDN: "https://cdn.example.com", Endpoint: "/graphql", PageTitle: "Docs"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inputCopy := tc.input
+			got := DefaultOptions(tc.input)
+			if !reflect.DeepEqual(*got, tc.expected) {
+				t.Fatalf("unexpected result: got %+v, want %+v", *got, tc.expected)
+			}
+			if !reflect.DeepEqual(tc.input, inputCopy) {
+				t.Fatalf("input mutated: got %+v, want %+v", tc.input, inputCopy)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Bundle maps stable message codes to their text in one locale. Texts may contain fmt verbs filled in from the
+// arguments given to Catalog.Text.
+type Bundle map[string]string
+
+// Catalog translates stable message codes into text in the locale a request prefers, falling back to a default
+// locale for locales and codes it has no translation for. It is safe for concurrent use once created.
+type Catalog struct {
+	fallback string
+	locales  []string
+	bundles  map[string]Bundle
+}
+
+// NewCatalog creates a Catalog from bundles keyed by BCP 47 locale. The fallback bundle must exist and define every
+// code used by the other bundles, so every code can always be answered.
+func NewCatalog(fallback string, bundles map[string]Bundle) (*Catalog, error) {
+	fallback, err := ParseLocale(fallback)
+	if err != nil {
+		return nil, fmt.Errorf("catalog fallback locale: %w", err)
+	}
+
+	c := &Catalog{
+		fallback: fallback,
+		bundles:  make(map[string]Bundle, len(bundles)),
+	}
+
+	for locale, bundle := range bundles {
+		canonical, err := ParseLocale(locale)
+		if err != nil {
+			return nil, fmt.Errorf("catalog locale %q: %w", locale, err)
+		}
+		c.bundles[canonical] = bundle
+		if canonical != fallback {
+			c.locales = append(c.locales, canonical)
+		}
+	}
+	sort.Strings(c.locales)
+
+	base, ok := c.bundles[fallback]
+	if !ok {
+		return nil, fmt.Errorf("catalog has no bundle for fallback locale %q", fallback)
+	}
+
+	for _, locale := range c.locales {
+		for code := range c.bundles[locale] {
+			if _, ok := base[code]; !ok {
+				return nil, fmt.Errorf("message %q in locale %q is missing from fallback locale %q", code, locale, fallback)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// MustNewCatalog is like NewCatalog but panics on error. It is meant for catalogs defined in package variables.
+func MustNewCatalog(fallback string, bundles map[string]Bundle) *Catalog {
+	c, err := NewCatalog(fallback, bundles)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Locales returns the fallback locale followed by the other locales the catalog has bundles for.
+func (c *Catalog) Locales() []string {
+	return append([]string{c.fallback}, c.locales...)
+}
+
+// Locale returns the catalog locale that best matches the ?lang= parameter and Accept-Language header of r.
+func (c *Catalog) Locale(r *http.Request) string {
+	return Match(Preferences(r), c.locales, c.fallback)
+}
+
+// Text returns the text of code in locale formatted with args. Codes missing from locale use the fallback locale,
+// and codes the catalog does not know are returned unchanged.
+func (c *Catalog) Text(locale, code string, args ...any) string {
+	text, ok := c.bundles[locale][code]
+	if !ok {
+		text, ok = c.bundles[c.fallback][code]
+	}
+	if !ok {
+		return code
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Localize returns the text of code in the locale r prefers, formatted with args.
+func (c *Catalog) Localize(r *http.Request, code string, args ...any) string {
+	return c.Text(c.Locale(r), code, args...)
+}
@@ -0,0 +1,105 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestNewCatalog(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback string
+		bundles  map[string]Bundle
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			fallback: "id",
+			bundles:  map[string]Bundle{"id": {"HELLO": "Halo"}, "en": {"HELLO": "Hello"}},
+		},
+		{
+			name:     "partial translation",
+			fallback: "id",
+			bundles:  map[string]Bundle{"id": {"HELLO": "Halo", "BYE": "Dah"}, "en": {"HELLO": "Hello"}},
+		},
+		{
+			name:     "missing fallback bundle",
+			fallback: "id",
+			bundles:  map[string]Bundle{"en": {"HELLO": "Hello"}},
+			wantErr:  true,
+		},
+		{
+			name:     "code unknown to fallback",
+			fallback: "id",
+			bundles:  map[string]Bundle{"id": {"HELLO": "Halo"}, "en": {"BYE": "Bye"}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid locale",
+			fallback: "id",
+			bundles:  map[string]Bundle{"id": {"HELLO": "Halo"}, "english": {"HELLO": "Hello"}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid fallback",
+			fallback: "",
+			bundles:  map[string]Bundle{"id": {"HELLO": "Halo"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCatalog(tt.fallback, tt.bundles)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCatalog_Localize(t *testing.T) {
+	catalog := MustNewCatalog("id", map[string]Bundle{
+		"id": {"HELLO": "Halo %s", "BYE": "Sampai jumpa"},
+		"en": {"HELLO": "Hello %s"},
+	})
+
+	if got, want := catalog.Locales(), []string{"id", "en"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected locales %v, got %v", want, got)
+	}
+
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		code           string
+		want           string
+	}{
+		{name: "default", target: "/", code: "HELLO", want: "Halo Budi"},
+		{name: "accept language", target: "/", acceptLanguage: "en-GB", code: "HELLO", want: "Hello Budi"},
+		{name: "query parameter", target: "/?lang=en", acceptLanguage: "id", code: "HELLO", want: "Hello Budi"},
+		{name: "unsupported locale", target: "/", acceptLanguage: "fr", code: "HELLO", want: "Halo Budi"},
+		{name: "missing translation", target: "/?lang=en", code: "BYE", want: "Sampai jumpa"},
+		{name: "unknown code", target: "/", code: "UNKNOWN", want: "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			var args []any
+			if tt.code == "HELLO" {
+				args = append(args, "Budi")
+			}
+
+			if got := catalog.Localize(req, tt.code, args...); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// LangQueryParam is the query parameter that overrides the Accept-Language header, e.g. "?lang=en".
+const LangQueryParam = "lang"
+
+// ErrInvalidLocale is returned by ParseLocale for text that is not a well-formed BCP 47 language tag.
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// ParseLocale validates a BCP 47 language tag such as "en" or "id-ID" and returns it in canonical form.
+func ParseLocale(locale string) (string, error) {
+	if strings.TrimSpace(locale) == "" {
+		return "", ErrInvalidLocale
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return "", ErrInvalidLocale
+	}
+
+	return tag.String(), nil
+}
+
+// Preferences returns the locales the client asked for, most preferred first. The ?lang= query parameter takes
+// precedence over the Accept-Language header; malformed values are ignored.
+func Preferences(r *http.Request) []language.Tag {
+	var preferred []language.Tag
+
+	if lang := r.URL.Query().Get(LangQueryParam); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			preferred = append(preferred, tag)
+		}
+	}
+
+	if header := r.Header.Get("Accept-Language"); header != "" {
+		if tags, _, err := language.ParseAcceptLanguage(header); err == nil {
+			preferred = append(preferred, tags...)
+		}
+	}
+
+	return preferred
+}
+
+// Match returns the entry of available that best satisfies preferred, or fallback when none of them is an
+// acceptable match. Regional variants fall back to their language, so "en-GB" matches "en".
+func Match(preferred []language.Tag, available []string, fallback string) string {
+	if len(preferred) == 0 || len(available) == 0 {
+		return fallback
+	}
+
+	// The matcher answers with its first tag when nothing matches, so the fallback goes first.
+	candidates := append([]string{fallback}, available...)
+	tags := make([]language.Tag, 0, len(candidates))
+	for _, candidate := range candidates {
+		tags = append(tags, language.Make(candidate))
+	}
+
+	_, index, confidence := language.NewMatcher(tags).Match(preferred...)
+	if confidence == language.No {
+		return fallback
+	}
+
+	return candidates[index]
+}
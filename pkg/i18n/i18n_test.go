@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		want    string
+		wantErr error
+	}{
+		{name: "language", locale: "en", want: "en"},
+		{name: "region is canonicalized", locale: "EN-us", want: "en-US"},
+		{name: "indonesian", locale: "id-ID", want: "id-ID"},
+		{name: "empty", locale: "", wantErr: ErrInvalidLocale},
+		{name: "undetermined", locale: "und", wantErr: ErrInvalidLocale},
+		{name: "malformed", locale: "not a locale", wantErr: ErrInvalidLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLocale(tt.locale)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		want           []language.Tag
+	}{
+		{name: "none", target: "/categories"},
+		{
+			name:           "accept language by quality",
+			target:         "/categories",
+			acceptLanguage: "id;q=0.5, en-GB",
+			want:           []language.Tag{language.BritishEnglish, language.Indonesian},
+		},
+		{
+			name:           "query parameter first",
+			target:         "/categories?lang=en",
+			acceptLanguage: "id",
+			want:           []language.Tag{language.English, language.Indonesian},
+		},
+		{
+			name:           "malformed values are ignored",
+			target:         "/categories?lang=%21%21",
+			acceptLanguage: "!!;q=x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			if got := Preferences(req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	available := []string{"id", "en", "ja"}
+
+	tests := []struct {
+		name      string
+		preferred []language.Tag
+		available []string
+		want      string
+	}{
+		{name: "no preference", available: available, want: "id"},
+		{name: "exact", preferred: []language.Tag{language.English}, available: available, want: "en"},
+		{name: "regional variant", preferred: []language.Tag{language.AmericanEnglish}, available: available, want: "en"},
+		{name: "first acceptable wins", preferred: []language.Tag{language.French, language.Japanese}, available: available, want: "ja"},
+		{name: "unsupported falls back", preferred: []language.Tag{language.French}, available: available, want: "id"},
+		{name: "nothing available", preferred: []language.Tag{language.English}, want: "id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.preferred, tt.available, "id"); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
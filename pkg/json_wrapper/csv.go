@@ -0,0 +1,142 @@
+package json_wrapper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// csvColumn is a column of a CSV response: the JSON name of a field, dotted for fields of nested objects, and the
+// index of the field in the row type.
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// encodeCSV writes the rows of v, a list or an APIResponse with list data, to w with a header of the JSON field names.
+// Fields holding maps, lists or other values that do not fit a cell are left out.
+func encodeCSV(w io.Writer, v any) error {
+	rows, ok := csvRows(v)
+	if !ok {
+		return fmt.Errorf("json_wrapper: %T has no list data to write as csv", v)
+	}
+
+	columns := csvColumns(rowType(rows.Type()), nil, "")
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		for j, column := range columns {
+			record[j] = csvCell(row, column.index)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvEncodable reports whether v, or the data of v when it is an APIResponse, is a list of objects.
+func csvEncodable(v any) bool {
+	_, ok := csvRows(v)
+	return ok
+}
+
+// csvRows returns v, or the data of v when it is an APIResponse, when it is a list of objects.
+func csvRows(v any) (reflect.Value, bool) {
+	var data any
+	switch v := v.(type) {
+	case APIResponse:
+		data = v.Data
+	case *APIResponse:
+		data = v.Data
+	default:
+		data = v
+	}
+
+	rows := reflect.ValueOf(data)
+	if rows.Kind() != reflect.Slice || rowType(rows.Type()).Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return rows, true
+}
+
+// rowType returns the item type of the slice type t, dereferencing pointers.
+func rowType(t reflect.Type) reflect.Type {
+	t = t.Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// csvColumns returns the columns of struct type t. Fields of nested structs get the name of their parent as prefix.
+func csvColumns(t reflect.Type, index []int, prefix string) []csvColumn {
+	var columns []csvColumn
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		name = prefix + name
+		fieldIndex := append(append([]int(nil), index...), field.Index...)
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		switch fieldType.Kind() {
+		case reflect.Struct:
+			columns = append(columns, csvColumns(fieldType, fieldIndex, name+".")...)
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Interface, reflect.Chan, reflect.Func:
+		default:
+			columns = append(columns, csvColumn{name: name, index: fieldIndex})
+		}
+	}
+	return columns
+}
+
+// csvCell formats the field at index of row. Fields behind a nil pointer are empty.
+func csvCell(row reflect.Value, index []int) string {
+	value := row
+	for _, i := range index {
+		for value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return ""
+			}
+			value = value.Elem()
+		}
+		value = value.Field(i)
+	}
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
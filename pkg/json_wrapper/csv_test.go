@@ -0,0 +1,69 @@
+package json_wrapper
+
+import (
+	"bytes"
+	"testing"
+)
+
+type csvParent struct {
+	ID int64 `json:"id"`
+}
+
+type csvRow struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Score   float64           `json:"score"`
+	Hidden  string            `json:"-"`
+	Labels  map[string]string `json:"labels"`
+	Parent  csvParent         `json:"parent"`
+	Sibling *csvParent        `json:"sibling,omitempty"`
+	Plain   string
+}
+
+func TestEncodeCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "rows",
+			value: APIResponse{Data: []csvRow{
+				{ID: 1, Name: "Buku, Alat", Score: 0.25, Hidden: "x", Labels: map[string]string{"a": "b"}, Parent: csvParent{ID: 9}, Plain: "p"},
+				{ID: 2, Name: "Pena", Sibling: &csvParent{ID: 3}},
+			}},
+			want: "id,name,score,parent.id,sibling.id,Plain\n" +
+				"1,\"Buku, Alat\",0.25,9,,p\n" +
+				"2,Pena,0,0,3,\n",
+		},
+		{
+			name:  "empty list keeps the header",
+			value: &APIResponse{Data: []*csvParent{}},
+			want:  "id\n",
+		},
+		{name: "single object", value: APIResponse{Data: csvParent{ID: 1}}, wantErr: true},
+		{name: "list of scalars", value: APIResponse{Data: []int{1}}, wantErr: true},
+		{name: "bare list", value: []csvParent{{ID: 1}}, want: "id\n1\n"},
+		{name: "bare object", value: csvParent{ID: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := encodeCSV(&buf, tt.value)
+			if tt.wantErr {
+				if err == nil || csvEncodable(tt.value) {
+					t.Fatalf("expected %T not to be encodable as csv", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
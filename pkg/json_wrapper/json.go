@@ -1,16 +1,36 @@
 package json_wrapper
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"reflect"
+	"strings"
 )
 
 // APIResponse represents the standardized structure for API responses, encapsulating status, message, and optional data.
+// MessageCode is a stable, machine-readable identifier of Message, which is localized for the client.
 type APIResponse struct {
-	Code    string      `json:"code"`
-	Message interface{} `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Code        string       `json:"code"`
+	MessageCode string       `json:"message_code,omitempty"`
+	Message     interface{}  `json:"message"`
+	Data        interface{}  `json:"data,omitempty"`
+	Error       *ErrorDetail `json:"error,omitempty"`
+}
+
+// Localizer translates a message code into text in the language preferred by the client making r.
+type Localizer interface {
+	Localize(r *http.Request, code string, args ...any) string
+}
+
+// SetMessage sets MessageCode to code and Message to its text localized for r and formatted with args.
+func (a *APIResponse) SetMessage(l Localizer, r *http.Request, code string, args ...any) {
+	a.MessageCode = code
+	a.Message = l.Localize(r, code, args...)
 }
 
 // WriteJSONResponse writes a JSON response with the provided status code and value to the http.ResponseWriter.
@@ -20,10 +40,295 @@ func WriteJSONResponse(w http.ResponseWriter, status int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-// ParseJSON decodes a JSON body from an HTTP request into the specified payload object.
-func ParseJSON(r *http.Request, payload any) error {
+// SetDecodeError sets Error to the details of err, an error returned by ParseJSON, and returns the HTTP status the
+// request should be answered with.
+func (a *APIResponse) SetDecodeError(err error) int {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		detail := decodeErr.Detail
+		a.Error = &detail
+	}
+	return DecodeStatus(err)
+}
+
+// DefaultMaxBodyBytes is the largest request body ParseJSON accepts unless WithMaxBytes says otherwise.
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// Reasons of an ErrorDetail, telling clients why a request body was rejected.
+const (
+	ReasonEmptyBody            = "empty_body"
+	ReasonSyntax               = "syntax_error"
+	ReasonTypeMismatch         = "type_mismatch"
+	ReasonUnknownField         = "unknown_field"
+	ReasonTrailingData         = "trailing_data"
+	ReasonTooLarge             = "body_too_large"
+	ReasonUnsupportedMediaType = "unsupported_media_type"
+	ReasonInvalidValue         = "invalid_value"
+)
+
+// ErrorDetail describes why a request body was rejected, pointing at the offending field and position when known.
+// Line and Column are 1-based and count bytes.
+type ErrorDetail struct {
+	Reason  string `json:"reason"`
+	Field   string `json:"field,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// DecodeError is returned by ParseJSON for request bodies that cannot be decoded. Status is the HTTP status the
+// request should be answered with: 413, 415 or 400.
+type DecodeError struct {
+	Status int
+	Detail ErrorDetail
+	Err    error
+}
+
+// Error returns the message of the detail.
+func (e *DecodeError) Error() string {
+	return e.Detail.Message
+}
+
+// Unwrap returns the underlying decoding error, if any.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeStatus returns the HTTP status a request whose body failed ParseJSON with err should be answered with.
+func DecodeStatus(err error) int {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return decodeErr.Status
+	}
+	return http.StatusBadRequest
+}
+
+// DecodeOption configures ParseJSON.
+type DecodeOption func(*decodeOptions)
+
+// decodeOptions holds the settings of one ParseJSON call.
+type decodeOptions struct {
+	maxBytes              int64
+	disallowUnknownFields bool
+}
+
+// WithMaxBytes limits the request body to n bytes instead of DefaultMaxBodyBytes.
+func WithMaxBytes(n int64) DecodeOption {
+	return func(o *decodeOptions) {
+		o.maxBytes = n
+	}
+}
+
+// WithDisallowUnknownFields rejects bodies with object keys that do not match a field of the payload.
+func WithDisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// ParseJSON decodes a JSON body from an HTTP request into the specified payload object. The body must hold exactly
+// one JSON value of at most DefaultMaxBodyBytes, and a Content-Type, when sent, must be application/json or a
+// +json type. Bodies that break these rules or do not match payload fail with a *DecodeError.
+func ParseJSON(r *http.Request, payload any, opts ...DecodeOption) error {
+	options := decodeOptions{maxBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if r.Body == nil {
 		return fmt.Errorf("missing body request")
 	}
-	return json.NewDecoder(r.Body).Decode(payload)
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !isJSONMediaType(contentType) {
+		return &DecodeError{
+			Status: http.StatusUnsupportedMediaType,
+			Detail: ErrorDetail{
+				Reason:  ReasonUnsupportedMediaType,
+				Message: fmt.Sprintf("content type %q is not application/json", contentType),
+			},
+		}
+	}
+
+	// A nil ResponseWriter is fine: it only lets the server close the connection, which it does on its own once
+	// the unread body exceeds its limits.
+	r.Body = http.MaxBytesReader(nil, r.Body, options.maxBytes)
+
+	// The bytes read so far are kept to translate decoder offsets into lines and columns.
+	var read bytes.Buffer
+	dec := json.NewDecoder(io.TeeReader(r.Body, &read))
+	if options.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(payload); err != nil {
+		return decodeError(err, read.Bytes(), dec.InputOffset())
+	}
+
+	end := dec.InputOffset()
+	if _, err := dec.Token(); err != io.EOF {
+		var tooLargeErr *http.MaxBytesError
+		if errors.As(err, &tooLargeErr) {
+			return decodeError(err, read.Bytes(), end)
+		}
+		data := read.Bytes()
+		offset := end + int64(len(data[end:])-len(bytes.TrimLeft(data[end:], " \t\r\n")))
+		line, column := position(data, offset)
+		return &DecodeError{
+			Status: http.StatusBadRequest,
+			Detail: ErrorDetail{
+				Reason:  ReasonTrailingData,
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("unexpected data after the JSON value at line %d, column %d", line, column),
+			},
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// decodeError describes err, returned by a json.Decoder that had read data and stopped at offset.
+func decodeError(err error, data []byte, offset int64) *DecodeError {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		tooLargeErr *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &tooLargeErr):
+		return &DecodeError{
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: ErrorDetail{
+				Reason:  ReasonTooLarge,
+				Message: fmt.Sprintf("request body must not be larger than %d bytes", tooLargeErr.Limit),
+			},
+			Err: err,
+		}
+	case errors.Is(err, io.EOF) && len(bytes.TrimSpace(data)) == 0:
+		return &DecodeError{
+			Status: http.StatusBadRequest,
+			Detail: ErrorDetail{Reason: ReasonEmptyBody, Message: "request body must not be empty"},
+			Err:    err,
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		line, column := position(data, int64(len(data)))
+		return syntaxError(err, line, column, "request body ends in the middle of a JSON value")
+	case errors.As(err, &syntaxErr):
+		line, column := position(data, syntaxErr.Offset-1)
+		return syntaxError(err, line, column, syntaxErr.Error())
+	case errors.As(err, &typeErr):
+		line, column := position(data, valueStart(data, typeErr.Offset))
+		return &DecodeError{
+			Status: http.StatusBadRequest,
+			Detail: ErrorDetail{
+				Reason:  ReasonTypeMismatch,
+				Field:   typeErr.Field,
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("field %q must be a JSON %s, not %s, at line %d, column %d", typeErr.Field, jsonKind(typeErr), typeErr.Value, line, column),
+			},
+			Err: err,
+		}
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if key := bytes.LastIndex(data[:min(offset, int64(len(data)))], []byte(field)); key >= 0 {
+			offset = int64(key)
+		}
+		field = strings.Trim(field, `"`)
+		line, column := position(data, offset)
+		return &DecodeError{
+			Status: http.StatusBadRequest,
+			Detail: ErrorDetail{
+				Reason:  ReasonUnknownField,
+				Field:   field,
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("unknown field %q at line %d, column %d", field, line, column),
+			},
+			Err: err,
+		}
+	}
+
+	return &DecodeError{
+		Status: http.StatusBadRequest,
+		Detail: ErrorDetail{Reason: ReasonSyntax, Message: err.Error()},
+		Err:    err,
+	}
+}
+
+// syntaxError returns the DecodeError for malformed JSON found at line and column.
+func syntaxError(err error, line, column int, message string) *DecodeError {
+	return &DecodeError{
+		Status: http.StatusBadRequest,
+		Detail: ErrorDetail{
+			Reason:  ReasonSyntax,
+			Line:    line,
+			Column:  column,
+			Message: fmt.Sprintf("%s at line %d, column %d", strings.TrimPrefix(message, "json: "), line, column),
+		},
+		Err: err,
+	}
+}
+
+// jsonKind names the kind of JSON value expected by the Go type in err.
+func jsonKind(err *json.UnmarshalTypeError) string {
+	switch err.Type.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "number"
+	}
+}
+
+// valueStart returns the offset of the first byte of the string, number or literal that ends at offset in data.
+// Offsets of other values are returned unchanged.
+func valueStart(data []byte, offset int64) int64 {
+	if offset <= 0 || offset > int64(len(data)) {
+		return offset
+	}
+
+	i := offset - 1
+	if data[i] == '"' {
+		for i--; i >= 0; i-- {
+			if data[i] == '"' && !escaped(data[:i]) {
+				return i
+			}
+		}
+		return offset
+	}
+	for i >= 0 && !bytes.ContainsRune([]byte(" \t\r\n,:[{"), rune(data[i])) {
+		i--
+	}
+	return i + 1
+}
+
+// escaped reports whether the byte following data is escaped by an odd number of trailing backslashes.
+func escaped(data []byte) bool {
+	return (len(data)-len(bytes.TrimRight(data, `\`)))%2 == 1
+}
+
+// position returns the 1-based line and column of the byte at offset in data.
+func position(data []byte, offset int64) (line, column int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// isJSONMediaType reports whether contentType is application/json or a +json type such as
+// application/merge-patch+json.
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
 }
@@ -2,9 +2,11 @@ package json_wrapper
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -33,7 +35,7 @@ func TestParseJSON(t *testing.T) {
 		{
 			name:          "malformed JSON",
 			requestBody:   `{"key":`,
-			expectedError: "unexpected EOF",
+			expectedError: "request body ends in the middle of a JSON value at line 1, column 8",
 			payload:       &map[string]string{},
 		},
 		{
@@ -70,6 +72,148 @@ func TestParseJSON(t *testing.T) {
 	}
 }
 
+func TestParseJSON_Strict(t *testing.T) {
+	type payload struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		opts        []DecodeOption
+		wantStatus  int
+		wantDetail  ErrorDetail
+	}{
+		{
+			name:        "json content type with charset",
+			body:        `{"id":1}`,
+			contentType: "application/json; charset=utf-8",
+		},
+		{
+			name:        "json suffix content type",
+			body:        `{"id":1}`,
+			contentType: "application/merge-patch+json",
+		},
+		{
+			name:        "other content type",
+			body:        `{"id":1}`,
+			contentType: "text/plain",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantDetail:  ErrorDetail{Reason: ReasonUnsupportedMediaType},
+		},
+		{
+			name:       "empty body",
+			body:       " ",
+			wantStatus: http.StatusBadRequest,
+			wantDetail: ErrorDetail{Reason: ReasonEmptyBody},
+		},
+		{
+			name:       "syntax error",
+			body:       "{\n  \"id\" 1}",
+			wantStatus: http.StatusBadRequest,
+			wantDetail: ErrorDetail{Reason: ReasonSyntax, Line: 2, Column: 8},
+		},
+		{
+			name:       "type mismatch",
+			body:       "{\"id\":1,\n \"name\": 12}",
+			wantStatus: http.StatusBadRequest,
+			wantDetail: ErrorDetail{Reason: ReasonTypeMismatch, Field: "name", Line: 2, Column: 10},
+		},
+		{
+			name: "unknown field allowed by default",
+			body: `{"id":1,"colour":"red"}`,
+		},
+		{
+			name:       "unknown field disallowed",
+			body:       "{\"id\":1,\n \"colour\":\"red\"}",
+			opts:       []DecodeOption{WithDisallowUnknownFields()},
+			wantStatus: http.StatusBadRequest,
+			wantDetail: ErrorDetail{Reason: ReasonUnknownField, Field: "colour", Line: 2, Column: 2},
+		},
+		{
+			name:       "trailing data",
+			body:       `{"id":1} {"id":2}`,
+			wantStatus: http.StatusBadRequest,
+			wantDetail: ErrorDetail{Reason: ReasonTrailingData, Line: 1, Column: 10},
+		},
+		{
+			name: "trailing whitespace",
+			body: "{\"id\":1}\n\t ",
+		},
+		{
+			name:       "body too large",
+			body:       `{"name":"` + strings.Repeat("a", 64) + `"}`,
+			opts:       []DecodeOption{WithMaxBytes(32)},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantDetail: ErrorDetail{Reason: ReasonTooLarge},
+		},
+		{
+			name:       "trailing data past the limit",
+			body:       `{"id":1}` + strings.Repeat(" ", 64) + "x",
+			opts:       []DecodeOption{WithMaxBytes(32)},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantDetail: ErrorDetail{Reason: ReasonTooLarge},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var p payload
+			err := ParseJSON(r, &p, tt.opts...)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if p.ID != 1 {
+					t.Errorf("expected the payload to be decoded, got %+v", p)
+				}
+				return
+			}
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected a *DecodeError, got %v", err)
+			}
+			if decodeErr.Status != tt.wantStatus || DecodeStatus(err) != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, decodeErr.Status)
+			}
+			got := decodeErr.Detail
+			if got.Message == "" {
+				t.Errorf("expected a message, got none")
+			}
+			got.Message = ""
+			if got != tt.wantDetail {
+				t.Errorf("expected detail %+v, got %+v", tt.wantDetail, got)
+			}
+		})
+	}
+}
+
+func TestAPIResponse_SetDecodeError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":`))
+	err := ParseJSON(r, &map[string]any{})
+
+	var result APIResponse
+	if status := result.SetDecodeError(err); status != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", status)
+	}
+	if result.Error == nil || result.Error.Reason != ReasonSyntax || result.Error.Column != 7 {
+		t.Errorf("expected the syntax error detail, got %+v", result.Error)
+	}
+
+	result = APIResponse{}
+	if status := result.SetDecodeError(fmt.Errorf("missing body request")); status != http.StatusBadRequest || result.Error != nil {
+		t.Errorf("expected 400 without detail for other errors, got %d %+v", status, result.Error)
+	}
+}
+
 func TestWriteJSONResponse(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,57 @@
+package json_wrapper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// encodeMsgPack writes v to w as MessagePack. Fields are named and omitted as in JSON.
+func encodeMsgPack(w io.Writer, v any) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	return enc.Encode(v)
+}
+
+// decodeMsgPack decodes the MessagePack body of r into payload, matching map keys to the JSON names of its fields.
+// MessagePack is binary, so errors carry no line or column.
+func decodeMsgPack(r *http.Request, payload any, options decodeOptions) error {
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		return decodeError(err, content, int64(len(content)))
+	}
+	if len(content) == 0 {
+		return decodeError(io.EOF, content, 0)
+	}
+
+	reader := bytes.NewReader(content)
+	dec := msgpack.NewDecoder(reader)
+	dec.SetCustomStructTag("json")
+	dec.DisallowUnknownFields(options.disallowUnknownFields)
+
+	if err := dec.Decode(payload); err != nil {
+		detail := ErrorDetail{Reason: ReasonSyntax, Message: strings.TrimPrefix(err.Error(), "msgpack: ")}
+		if field, ok := strings.CutPrefix(err.Error(), "msgpack: unknown field "); ok {
+			detail.Reason, detail.Field = ReasonUnknownField, strings.Trim(field, `"`)
+		} else if strings.Contains(err.Error(), "msgpack: invalid code") {
+			detail.Reason = ReasonTypeMismatch
+		}
+		return &DecodeError{Status: http.StatusBadRequest, Detail: detail, Err: err}
+	}
+
+	if reader.Len() > 0 {
+		return &DecodeError{
+			Status: http.StatusBadRequest,
+			Detail: ErrorDetail{
+				Reason:  ReasonTrailingData,
+				Message: fmt.Sprintf("unexpected data after the MessagePack value at byte %d", len(content)-reader.Len()),
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package json_wrapper
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestEncodeMsgPack_UsesJSONNames(t *testing.T) {
+	var buf bytes.Buffer
+	if err := encodeMsgPack(&buf, APIResponse{Code: "1000", Message: "ok"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got map[string]any
+	if err := msgpack.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got["code"] != "1000" || got["message"] != "ok" {
+		t.Errorf("expected the JSON field names without empty fields, got %v", got)
+	}
+}
+
+func TestDecodeMsgPack_Errors(t *testing.T) {
+	encode := func(values ...any) []byte {
+		var buf bytes.Buffer
+		for _, v := range values {
+			if err := encodeMsgPack(&buf, v); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name       string
+		body       []byte
+		opts       []DecodeOption
+		wantReason string
+		wantField  string
+	}{
+		{name: "empty", body: nil, wantReason: ReasonEmptyBody},
+		{name: "truncated", body: encode(map[string]any{"name": "Buku"})[:4], wantReason: ReasonSyntax},
+		{name: "type mismatch", body: encode(map[string]any{"id": "one"}), wantReason: ReasonTypeMismatch},
+		{name: "unknown field", body: encode(map[string]any{"colour": "red"}), opts: []DecodeOption{WithDisallowUnknownFields()}, wantReason: ReasonUnknownField, wantField: "colour"},
+		{name: "trailing data", body: encode(map[string]any{"id": 1}, map[string]any{"id": 2}), wantReason: ReasonTrailingData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			r.Header.Set("Content-Type", MediaTypeMsgPack)
+
+			var got negotiateItem
+			err := ParseRequest(r, &got, tt.opts...)
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected a *DecodeError, got %v", err)
+			}
+			if decodeErr.Status != http.StatusBadRequest || decodeErr.Detail.Reason != tt.wantReason || decodeErr.Detail.Field != tt.wantField {
+				t.Errorf("expected 400 %s %q, got %d %+v", tt.wantReason, tt.wantField, decodeErr.Status, decodeErr.Detail)
+			}
+		})
+	}
+}
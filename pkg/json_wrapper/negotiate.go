@@ -0,0 +1,177 @@
+package json_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Media types of the representations WriteResponse and ParseRequest support.
+const (
+	MediaTypeJSON    = "application/json"
+	MediaTypeXML     = "application/xml"
+	MediaTypeMsgPack = "application/msgpack"
+	MediaTypeCSV     = "text/csv"
+)
+
+// format is one representation of responses and request bodies. decode is nil for formats only used in responses,
+// and listsOnly marks formats that can only encode list data.
+type format struct {
+	contentType string
+	mediaTypes  []string
+	listsOnly   bool
+	encode      func(w io.Writer, v any) error
+	decode      func(r *http.Request, payload any, options decodeOptions) error
+}
+
+// formats lists the supported representations in order of preference; JSON, the first, is the default.
+var formats = []format{
+	{
+		contentType: MediaTypeJSON,
+		mediaTypes:  []string{MediaTypeJSON},
+		encode:      func(w io.Writer, v any) error { return json.NewEncoder(w).Encode(v) },
+	},
+	{
+		contentType: MediaTypeXML + "; charset=utf-8",
+		mediaTypes:  []string{MediaTypeXML, "text/xml"},
+		encode:      encodeXML,
+		decode:      decodeXML,
+	},
+	{
+		contentType: MediaTypeMsgPack,
+		mediaTypes:  []string{MediaTypeMsgPack, "application/x-msgpack", "application/vnd.msgpack"},
+		encode:      encodeMsgPack,
+		decode:      decodeMsgPack,
+	},
+	{
+		contentType: MediaTypeCSV + "; charset=utf-8",
+		mediaTypes:  []string{MediaTypeCSV},
+		listsOnly:   true,
+		encode:      encodeCSV,
+	},
+}
+
+// WriteResponse writes v with the provided status code in the representation the client making r prefers in its
+// Accept header: JSON, XML, MessagePack or, for list data, CSV. JSON is written when the client accepts anything or
+// none of them, so responses are byte for byte those of WriteJSONResponse unless another format is asked for. CSV
+// holds only the rows of a list of objects, or of the data of an APIResponse, so it is never chosen for other
+// values.
+func WriteResponse(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Add("Vary", "Accept")
+
+	f := negotiate(r.Header.Get("Accept"), v)
+	if f.contentType != MediaTypeJSON {
+		var buf bytes.Buffer
+		if err := f.encode(&buf, v); err == nil {
+			w.Header().Set("Content-Type", f.contentType)
+			w.WriteHeader(status)
+			_, _ = w.Write(buf.Bytes())
+			return
+		}
+	}
+
+	WriteJSONResponse(w, status, v)
+}
+
+// ParseRequest decodes the body of r into payload from the representation named by its Content-Type: JSON, XML or
+// MessagePack. Bodies without a Content-Type are decoded as JSON. The rules and options of ParseJSON apply to every
+// format, and other content types fail with a 415 *DecodeError.
+func ParseRequest(r *http.Request, payload any, opts ...DecodeOption) error {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" || isJSONMediaType(contentType) {
+		return ParseJSON(r, payload, opts...)
+	}
+
+	options := decodeOptions{maxBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if r.Body == nil {
+		return fmt.Errorf("missing body request")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, f := range formats {
+		if f.decode != nil && containsFold(f.mediaTypes, mediaType) {
+			r.Body = http.MaxBytesReader(nil, r.Body, options.maxBytes)
+			return f.decode(r, payload, options)
+		}
+	}
+
+	return &DecodeError{
+		Status: http.StatusUnsupportedMediaType,
+		Detail: ErrorDetail{
+			Reason:  ReasonUnsupportedMediaType,
+			Message: fmt.Sprintf("content type %q is not supported", contentType),
+		},
+	}
+}
+
+// negotiate returns the format of the response carrying v preferred by accept, the Accept header of the request.
+func negotiate(accept string, v any) format {
+	best, bestQuality := formats[0], 0.0
+	for _, f := range formats {
+		if f.listsOnly && !csvEncodable(v) {
+			continue
+		}
+		if quality := acceptQuality(accept, f.mediaTypes); quality > bestQuality {
+			best, bestQuality = f, quality
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the highest quality accept, an Accept header, gives any of mediaTypes. An empty header
+// accepts everything with quality 1.
+func acceptQuality(accept string, mediaTypes []string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+
+	best := 0.0
+	for _, entry := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		for _, mediaType := range mediaTypes {
+			if mediaRangeMatches(mediaRange, mediaType) && quality > best {
+				best = quality
+			}
+		}
+	}
+	return best
+}
+
+// mediaRangeMatches reports whether mediaRange, e.g. "*/*", "application/*" or "application/xml", covers mediaType.
+func mediaRangeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || strings.EqualFold(mediaRange, mediaType) {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(mediaRange, "/*")
+	return ok && strings.HasPrefix(strings.ToLower(mediaType), strings.ToLower(prefix)+"/")
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
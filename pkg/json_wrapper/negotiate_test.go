@@ -0,0 +1,160 @@
+package json_wrapper
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type negotiateItem struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestWriteResponse(t *testing.T) {
+	list := APIResponse{Code: "1000", Message: "ok", Data: []negotiateItem{{ID: 1, Name: "Buku"}}}
+	single := APIResponse{Code: "1000", Message: "ok", Data: negotiateItem{ID: 1, Name: "Buku"}}
+
+	tests := []struct {
+		name            string
+		accept          string
+		value           any
+		wantContentType string
+		wantBody        string
+	}{
+		{name: "no accept", value: single, wantContentType: MediaTypeJSON, wantBody: `{"code":"1000","message":"ok","data":{"id":1,"name":"Buku"}}` + "\n"},
+		{name: "anything", accept: "*/*", value: single, wantContentType: MediaTypeJSON},
+		{name: "xml", accept: "application/xml", value: single, wantContentType: "application/xml; charset=utf-8",
+			wantBody: xml.Header + `<response><code>1000</code><message>ok</message><data><id>1</id><name>Buku</name></data></response>`},
+		{name: "text xml", accept: "text/xml", value: single, wantContentType: "application/xml; charset=utf-8"},
+		{name: "msgpack", accept: "application/x-msgpack", value: single, wantContentType: MediaTypeMsgPack},
+		{name: "quality", accept: "application/json;q=0.5, application/xml", value: single, wantContentType: "application/xml; charset=utf-8"},
+		{name: "csv list", accept: "text/csv", value: list, wantContentType: "text/csv; charset=utf-8", wantBody: "id,name\n1,Buku\n"},
+		{name: "csv single falls back", accept: "text/csv", value: single, wantContentType: MediaTypeJSON},
+		{name: "csv next choice", accept: "text/csv, application/xml;q=0.5", value: single, wantContentType: "application/xml; charset=utf-8"},
+		{name: "unsupported falls back", accept: "image/png", value: single, wantContentType: MediaTypeJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			WriteResponse(w, r, http.StatusOK, tt.value)
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("expected content type %s, got %s", tt.wantContentType, got)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", got)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriteResponse_MatchesWriteJSONResponse(t *testing.T) {
+	value := APIResponse{Code: "2000", MessageCode: "X", Message: "<gagal>", Data: map[string]int{"a": 1}}
+
+	want := httptest.NewRecorder()
+	WriteJSONResponse(want, http.StatusBadRequest, value)
+
+	got := httptest.NewRecorder()
+	WriteResponse(got, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusBadRequest, value)
+
+	if got.Code != want.Code || got.Body.String() != want.Body.String() || got.Header().Get("Content-Type") != want.Header().Get("Content-Type") {
+		t.Errorf("expected the JSON response %d %q, got %d %q", want.Code, want.Body.String(), got.Code, got.Body.String())
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	msgpackBody := func(v any) string {
+		var buf bytes.Buffer
+		if err := encodeMsgPack(&buf, v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []DecodeOption
+		want        negotiateItem
+		wantStatus  int
+		wantReason  string
+	}{
+		{name: "json", contentType: "application/json", body: `{"id":1,"name":"Buku"}`, want: negotiateItem{ID: 1, Name: "Buku"}},
+		{name: "no content type", body: `{"id":1,"name":"Buku"}`, want: negotiateItem{ID: 1, Name: "Buku"}},
+		{name: "xml", contentType: "application/xml", body: `<category><id>1</id><name>Buku</name></category>`, want: negotiateItem{ID: 1, Name: "Buku"}},
+		{name: "text xml with charset", contentType: "text/xml; charset=utf-8", body: `<category><id>1</id><name>Buku</name></category>`, want: negotiateItem{ID: 1, Name: "Buku"}},
+		{name: "msgpack", contentType: "application/msgpack", body: msgpackBody(negotiateItem{ID: 1, Name: "Buku"}), want: negotiateItem{ID: 1, Name: "Buku"}},
+		{
+			name: "xml too large", contentType: "application/xml", body: `<category><name>` + strings.Repeat("a", 64) + `</name></category>`,
+			opts: []DecodeOption{WithMaxBytes(32)}, wantStatus: http.StatusRequestEntityTooLarge, wantReason: ReasonTooLarge,
+		},
+		{name: "csv", contentType: "text/csv", body: "id,name\n1,Buku\n", wantStatus: http.StatusUnsupportedMediaType, wantReason: ReasonUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var got negotiateItem
+			err := ParseRequest(r, &got, tt.opts...)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("expected %+v, got %+v", tt.want, got)
+				}
+				return
+			}
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected a *DecodeError, got %v", err)
+			}
+			if decodeErr.Status != tt.wantStatus || decodeErr.Detail.Reason != tt.wantReason {
+				t.Errorf("expected %d %s, got %d %s", tt.wantStatus, tt.wantReason, decodeErr.Status, decodeErr.Detail.Reason)
+			}
+		})
+	}
+}
+
+func TestAcceptQuality(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   float64
+	}{
+		{accept: "", want: 1},
+		{accept: "application/xml", want: 1},
+		{accept: "application/*;q=0.3", want: 0.3},
+		{accept: "text/html, */*;q=0.1", want: 0.1},
+		{accept: "application/xml;q=0", want: 0},
+		{accept: "text/html", want: 0},
+		{accept: "application/xml;q=oops", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			if got := acceptQuality(tt.accept, []string{MediaTypeXML}); got != tt.want {
+				t.Errorf("expected quality %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package json_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// XML documents mirror the JSON representation of a value: the root element is named xmlRoot, object keys become
+// child elements, array items become xmlItem elements and scalars become text. Keys that are not valid XML names
+// become xmlEntry elements with the key in their xmlKey attribute. Null values are left out.
+const (
+	xmlRoot  = "response"
+	xmlItem  = "item"
+	xmlEntry = "entry"
+	xmlKey   = "key"
+)
+
+// encodeXML writes the XML document mirroring the JSON representation of v to w.
+func encodeXML(w io.Writer, v any) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if err := writeXMLValue(dec, enc, xmlRoot); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// writeXMLValue reads the next JSON value from dec and writes it to enc as an element called name.
+func writeXMLValue(dec *json.Decoder, enc *xml.Encoder, name string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !isXMLName(name) {
+		start = xml.StartElement{
+			Name: xml.Name{Local: xmlEntry},
+			Attr: []xml.Attr{{Name: xml.Name{Local: xmlKey}, Value: name}},
+		}
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		for dec.More() {
+			child := xmlItem
+			if token == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child = key.(string)
+			}
+			if err := writeXMLValue(dec, enc, child); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(token))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// isXMLName reports whether name can be used as an XML element name as it is.
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r), r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// xmlNode is an element of a decoded XML document, with the position of its start tag.
+type xmlNode struct {
+	name     string
+	key      string
+	text     string
+	children []*xmlNode
+	line     int
+	column   int
+}
+
+// decodeXML decodes the XML body of r into payload. The document is read as encodeXML writes it, guided by the
+// JSON field names of payload, so its root element may have any name.
+func decodeXML(r *http.Request, payload any, options decodeOptions) error {
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		return decodeError(err, content, int64(len(content)))
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return decodeError(io.EOF, content, 0)
+	}
+
+	root, err := parseXML(content)
+	if err != nil {
+		return err
+	}
+
+	d := xmlDecoder{disallowUnknownFields: options.disallowUnknownFields}
+	return d.decode(root, reflect.ValueOf(payload), "")
+}
+
+// parseXML parses content, a document with a single root element, into a tree.
+func parseXML(content []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(content))
+
+	var (
+		root  *xmlNode
+		stack []*xmlNode
+	)
+	for {
+		line, column := dec.InputPos()
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line, column = syntaxErr.Line, 0
+			}
+			return nil, syntaxError(err, line, column, strings.TrimPrefix(err.Error(), "xml: "))
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, trailingDataError(line, column)
+			}
+			node := &xmlNode{name: token.Name.Local, line: line, column: column}
+			for _, attr := range token.Attr {
+				if attr.Name.Local == xmlKey {
+					node.key = attr.Value
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(token)
+			} else if len(bytes.TrimSpace(token)) > 0 {
+				return nil, trailingDataError(line, column)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, syntaxError(io.ErrUnexpectedEOF, 1, 1, "request body holds no XML element")
+	}
+	return root, nil
+}
+
+// trailingDataError returns the DecodeError for data found at line and column after the root element.
+func trailingDataError(line, column int) *DecodeError {
+	return &DecodeError{
+		Status: http.StatusBadRequest,
+		Detail: ErrorDetail{
+			Reason:  ReasonTrailingData,
+			Line:    line,
+			Column:  column,
+			Message: fmt.Sprintf("unexpected data after the root element at line %d, column %d", line, column),
+		},
+	}
+}
+
+// xmlDecoder stores XML trees into Go values, matching elements to fields by their JSON names.
+type xmlDecoder struct {
+	disallowUnknownFields bool
+}
+
+// decode stores node into v. field is the dotted path of node in the document, used in errors.
+func (d xmlDecoder) decode(node *xmlNode, v reflect.Value, field string) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if !v.CanSet() {
+				return fmt.Errorf("json_wrapper: cannot decode into a nil %s", v.Type())
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := jsonFields(v.Type())
+		for _, child := range node.children {
+			name := child.elementKey()
+			index, ok := fields[name]
+			if !ok {
+				if d.disallowUnknownFields {
+					return unknownXMLField(child, joinField(field, name))
+				}
+				continue
+			}
+			if err := d.decode(child, v.FieldByIndex(index), joinField(field, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return d.mismatch(node, v, field)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for _, child := range node.children {
+			name := child.elementKey()
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(child, elem, joinField(field, name)); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), elem)
+		}
+		return nil
+	case reflect.Slice:
+		items := reflect.MakeSlice(v.Type(), len(node.children), len(node.children))
+		for i, child := range node.children {
+			if err := d.decode(child, items.Index(i), joinField(field, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		v.Set(items)
+		return nil
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return d.mismatch(node, v, field)
+		}
+		if len(node.children) == 0 {
+			v.Set(reflect.ValueOf(strings.TrimSpace(node.text)))
+			return nil
+		}
+		object := make(map[string]any, len(node.children))
+		if err := d.decode(node, reflect.ValueOf(&object), field); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(object))
+		return nil
+	}
+
+	if len(node.children) > 0 {
+		return d.mismatch(node, v, field)
+	}
+
+	text := node.text
+	if v.Kind() != reflect.String {
+		text = strings.TrimSpace(text)
+	}
+
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(text); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(text, 10, v.Type().Bits()); err == nil {
+			v.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(text, 10, v.Type().Bits()); err == nil {
+			v.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(text, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		err = fmt.Errorf("unsupported type %s", v.Type())
+	}
+	if err != nil {
+		return d.mismatch(node, v, field)
+	}
+	return nil
+}
+
+// mismatch returns the DecodeError for node, which cannot be stored in v.
+func (d xmlDecoder) mismatch(node *xmlNode, v reflect.Value, field string) *DecodeError {
+	kind := jsonKind(&json.UnmarshalTypeError{Type: v.Type()})
+	return &DecodeError{
+		Status: http.StatusBadRequest,
+		Detail: ErrorDetail{
+			Reason:  ReasonTypeMismatch,
+			Field:   field,
+			Line:    node.line,
+			Column:  node.column,
+			Message: fmt.Sprintf("field %q must be a %s at line %d, column %d", field, kind, node.line, node.column),
+		},
+	}
+}
+
+// unknownXMLField returns the DecodeError for node, which matches no field of the payload.
+func unknownXMLField(node *xmlNode, field string) *DecodeError {
+	return &DecodeError{
+		Status: http.StatusBadRequest,
+		Detail: ErrorDetail{
+			Reason:  ReasonUnknownField,
+			Field:   field,
+			Line:    node.line,
+			Column:  node.column,
+			Message: fmt.Sprintf("unknown field %q at line %d, column %d", field, node.line, node.column),
+		},
+	}
+}
+
+// elementKey returns the object key node stands for: its key attribute for entry elements, its name otherwise.
+func (n *xmlNode) elementKey() string {
+	if n.name == xmlEntry && n.key != "" {
+		return n.key
+	}
+	return n.name
+}
+
+// jsonFields maps the JSON names of the fields of struct type t to their indexes. Fields of embedded structs are
+// promoted as encoding/json does.
+func jsonFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = field.Index
+		}
+	}
+	return fields
+}
+
+// joinField appends name to the dotted field path parent.
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,131 @@
+package json_wrapper
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type xmlTranslation struct {
+	Name string `json:"name"`
+}
+
+type xmlCategory struct {
+	ID           int64                     `json:"id"`
+	Name         string                    `json:"name"`
+	Active       bool                      `json:"active,omitempty"`
+	Score        float64                   `json:"score,omitempty"`
+	Tags         []string                  `json:"tags,omitempty"`
+	Translations map[string]xmlTranslation `json:"translations,omitempty"`
+	Parent       *xmlCategory              `json:"parent,omitempty"`
+}
+
+func TestEncodeXML(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "object",
+			value: xmlCategory{ID: 1, Name: "Buku & Alat", Tags: []string{"a", "b"}},
+			want:  `<response><id>1</id><name>Buku &amp; Alat</name><tags><item>a</item><item>b</item></tags></response>`,
+		},
+		{
+			name:  "map keys",
+			value: map[string]any{"en-US": "x", "1st": "y", "nil": nil},
+			want:  `<response><entry key="1st">y</entry><en-US>x</en-US></response>`,
+		},
+		{
+			name:  "list",
+			value: []int{1, 2},
+			want:  `<response><item>1</item><item>2</item></response>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := encodeXML(&buf, tt.value); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.TrimPrefix(buf.String(), xml.Header); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeXML_RoundTrip(t *testing.T) {
+	want := xmlCategory{
+		ID: 7, Name: " Buku ", Active: true, Score: 1.5, Tags: []string{"a", "b"},
+		Translations: map[string]xmlTranslation{"en": {Name: "Book"}, "1st": {Name: "First"}},
+		Parent:       &xmlCategory{ID: 1, Name: "Root"},
+	}
+
+	var buf bytes.Buffer
+	if err := encodeXML(&buf, want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got xmlCategory
+	r := httptest.NewRequest(http.MethodPost, "/", &buf)
+	r.Header.Set("Content-Type", MediaTypeXML)
+	if err := ParseRequest(r, &got, WithDisallowUnknownFields()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.ID != want.ID || got.Name != want.Name || !got.Active || got.Score != want.Score || strings.Join(got.Tags, ",") != "a,b" ||
+		got.Translations["en"] != want.Translations["en"] || got.Translations["1st"] != want.Translations["1st"] ||
+		got.Parent == nil || got.Parent.Name != "Root" {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDecodeXML_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		opts       []DecodeOption
+		wantDetail ErrorDetail
+	}{
+		{name: "empty", body: "  ", wantDetail: ErrorDetail{Reason: ReasonEmptyBody}},
+		{name: "syntax", body: "<category>\n<id>1</name></category>", wantDetail: ErrorDetail{Reason: ReasonSyntax, Line: 2}},
+		{name: "type mismatch", body: "<category>\n  <id>one</id>\n</category>", wantDetail: ErrorDetail{Reason: ReasonTypeMismatch, Field: "id", Line: 2, Column: 3}},
+		{name: "nested type mismatch", body: "<c><parent><id>x</id></parent></c>", wantDetail: ErrorDetail{Reason: ReasonTypeMismatch, Field: "parent.id", Line: 1, Column: 12}},
+		{name: "object for scalar", body: "<c><name><a>1</a></name></c>", wantDetail: ErrorDetail{Reason: ReasonTypeMismatch, Field: "name", Line: 1, Column: 4}},
+		{name: "unknown field", body: "<c><colour>red</colour></c>", opts: []DecodeOption{WithDisallowUnknownFields()}, wantDetail: ErrorDetail{Reason: ReasonUnknownField, Field: "colour", Line: 1, Column: 4}},
+		{name: "trailing element", body: "<c></c>\n<c></c>", wantDetail: ErrorDetail{Reason: ReasonTrailingData, Line: 2, Column: 1}},
+		{name: "trailing text", body: "<c></c> x", wantDetail: ErrorDetail{Reason: ReasonTrailingData, Line: 1, Column: 8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", MediaTypeXML)
+
+			var got xmlCategory
+			err := ParseRequest(r, &got, tt.opts...)
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected a *DecodeError, got %v", err)
+			}
+			if decodeErr.Status != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", decodeErr.Status)
+			}
+			detail := decodeErr.Detail
+			if detail.Message == "" {
+				t.Errorf("expected a message, got none")
+			}
+			detail.Message = ""
+			if detail != tt.wantDetail {
+				t.Errorf("expected detail %+v, got %+v", tt.wantDetail, detail)
+			}
+		})
+	}
+}
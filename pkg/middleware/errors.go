@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
+)
+
+// ErrorWriter answers r with an error response with status, described by the message code. The middlewares take
+// one per mount, so every API version answers their errors in its own format.
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, status int, code string)
+
+// WriteJSONError is the ErrorWriter of /api/v1: it answers with the json_wrapper.APIResponse envelope for code.
+func WriteJSONError(w http.ResponseWriter, r *http.Request, status int, code string) {
+	var result json_wrapper.APIResponse
+	result.Code = constants.ErrorCode
+	result.SetMessage(constants.Messages, r, code)
+	json_wrapper.WriteJSONResponse(w, status, result)
+}
+
+// WriteProblemError is the ErrorWriter of /api/v2: it answers with the application/problem+json problem for code.
+func WriteProblemError(w http.ResponseWriter, r *http.Request, status int, code string) {
+	problem.WriteMessage(w, r, constants.Messages, status, code, "", nil)
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/problem"
+)
+
+// expectProblem fails t unless rec holds an application/problem+json response with status and the message code.
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != problem.MediaType {
+		t.Fatalf("expected content type %s, got %q", problem.MediaType, ct)
+	}
+	var got struct {
+		Status int    `json:"status"`
+		Title  string `json:"title"`
+		Code   string `json:"code"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != status || got.Code != code || got.Title == "" {
+		t.Errorf("expected a problem with status %d and code %s, got %s", status, code, rec.Body.String())
+	}
+}
+
+func TestErrorWriters(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteJSONError(rec, httptest.NewRequest(http.MethodGet, "/categories", nil), http.StatusNotFound, constants.MsgTenantNotFound)
+
+		var got json_wrapper.APIResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.Code != http.StatusNotFound || got.Code != constants.ErrorCode || got.MessageCode != constants.MsgTenantNotFound {
+			t.Errorf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("problem", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		WriteProblemError(rec, httptest.NewRequest(http.MethodGet, "/api/v2/categories", nil), http.StatusNotFound, constants.MsgTenantNotFound)
+		expectProblem(t, rec, http.StatusNotFound, constants.MsgTenantNotFound)
+	})
+}
@@ -0,0 +1,347 @@
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set to "true" on responses replayed from an earlier request with the same key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyTTL is how long a stored response is replayed when no TTL is configured.
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// DefaultIdempotencyMaxEntries is the number of responses stored when no limit is configured.
+	DefaultIdempotencyMaxEntries = 10000
+
+	// DefaultIdempotencyMaxBytes is the total size, in bytes, of the responses stored when no limit is configured.
+	DefaultIdempotencyMaxBytes int64 = 64 << 20
+
+	// maxIdempotencyKeyLength is the longest idempotency key accepted.
+	maxIdempotencyKeyLength = 255
+
+	// idempotencySweepInterval is the least time between two sweeps of expired responses.
+	idempotencySweepInterval = time.Minute
+)
+
+// IdempotencyOptions tunes Idempotency. Zero values fall back to the defaults.
+type IdempotencyOptions struct {
+	// TTL is how long a stored response is replayed. Zero means DefaultIdempotencyTTL.
+	TTL time.Duration
+
+	// Methods lists the request methods keys apply to. Empty means POST only.
+	Methods []string
+
+	// ClientID identifies the client sending r, so clients cannot replay each other's responses. Nil means the IP
+	// address of r.RemoteAddr.
+	ClientID func(r *http.Request) string
+
+	// MaxEntries is the number of responses stored; the least recently used are dropped first. Zero means
+	// DefaultIdempotencyMaxEntries.
+	MaxEntries int
+
+	// MaxBytes is the total size of the responses stored; the least recently used are dropped first. Zero means
+	// DefaultIdempotencyMaxBytes.
+	MaxBytes int64
+
+	// MaxBodyBytes is the size of the largest request body read and of the largest response body stored. Larger
+	// requests are answered with 413 Request Entity Too Large; larger responses are passed through but not stored.
+	// Zero means json_wrapper.DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+}
+
+// Idempotency makes requests that carry an Idempotency-Key header safe to retry. The first response for a tenant,
+// client and key is stored for the TTL and replayed verbatim, with Idempotent-Replayed set, to later requests with that
+// key. While the first request is in flight, requests with its key are answered with 409 Conflict; a key sent again
+// with a different method, path or body is answered with 422 Unprocessable Entity. Responses with a 5xx status are
+// not stored, so the request can be retried. The store is bounded in entries and bytes and drops the least recently
+// used responses first. Idempotency is safe for concurrent use.
+type Idempotency struct {
+	options IdempotencyOptions
+	now     func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	order     *list.List
+	size      int64
+	lastSweep time.Time
+}
+
+// idempotencyEntry is the request stored under one client and key, with its response once it completed. Completed
+// entries are kept in the LRU order under element.
+type idempotencyEntry struct {
+	fingerprint string
+	done        bool
+	expires     time.Time
+	element     *list.Element
+
+	status int
+	header http.Header
+	body   []byte
+}
+
+// NewIdempotency initializes a new Idempotency middleware with the given options.
+func NewIdempotency(options IdempotencyOptions) (*Idempotency, error) {
+	if options.TTL < 0 {
+		return nil, fmt.Errorf("idempotency ttl must not be negative")
+	}
+	if options.TTL == 0 {
+		options.TTL = DefaultIdempotencyTTL
+	}
+	if len(options.Methods) == 0 {
+		options.Methods = []string{http.MethodPost}
+	}
+	if options.ClientID == nil {
+		options.ClientID = remoteIP
+	}
+	if options.MaxEntries < 0 || options.MaxBytes < 0 || options.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("idempotency limits must not be negative")
+	}
+	if options.MaxEntries == 0 {
+		options.MaxEntries = DefaultIdempotencyMaxEntries
+	}
+	if options.MaxBytes == 0 {
+		options.MaxBytes = DefaultIdempotencyMaxBytes
+	}
+	if options.MaxBodyBytes == 0 {
+		options.MaxBodyBytes = json_wrapper.DefaultMaxBodyBytes
+	}
+
+	return &Idempotency{
+		options: options,
+		now:     time.Now,
+		entries: make(map[string]*idempotencyEntry),
+		order:   list.New(),
+	}, nil
+}
+
+// Middleware wraps next so requests with an Idempotency-Key header are handled at most once per client and key.
+// Requests without the header, or with another method, are passed through untouched. Rejected requests are answered
+// by writeError; every mount shares the store, so a key cannot be reused across them.
+func (i *Idempotency) Middleware(next http.Handler, writeError ErrorWriter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys, ok := r.Header[IdempotencyKeyHeader]
+		if !ok || !slices.Contains(i.options.Methods, r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := keys[0]
+		if len(keys) != 1 || key == "" || len(key) > maxIdempotencyKeyLength {
+			writeError(w, r, http.StatusBadRequest, constants.MsgInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, i.options.MaxBodyBytes+1))
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, constants.MsgInvalidRequest)
+			return
+		}
+		if int64(len(body)) > i.options.MaxBodyBytes {
+			writeError(w, r, http.StatusRequestEntityTooLarge, constants.MsgRequestTooLarge)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		id := tenant.FromContext(r.Context()) + "\x00" + i.options.ClientID(r) + "\x00" + key
+		fingerprint := requestFingerprint(r, body)
+
+		entry, replay, status := i.begin(id, fingerprint)
+		switch {
+		case status != 0:
+			code := constants.MsgIdempotencyConflict
+			if status == http.StatusUnprocessableEntity {
+				code = constants.MsgIdempotencyKeyReused
+			}
+			writeError(w, r, status, code)
+			return
+		case replay:
+			for name, values := range entry.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(entry.status)
+			_, _ = w.Write(entry.body)
+			return
+		}
+
+		rec := &idempotencyRecorder{ResponseWriter: w, limit: i.options.MaxBodyBytes}
+		completed := false
+		defer func() {
+			i.finish(id, entry, rec, completed)
+		}()
+
+		next.ServeHTTP(rec, r)
+		completed = true
+	})
+}
+
+// begin looks up the entry stored under id. It returns the entry to replay with replay set, a non-zero status to
+// reject the request with, or a new in-flight entry the caller must finish.
+func (i *Idempotency) begin(id, fingerprint string) (entry *idempotencyEntry, replay bool, status int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	i.sweep(now)
+
+	if existing, ok := i.entries[id]; ok {
+		switch {
+		case existing.done && now.After(existing.expires):
+			i.remove(id, existing)
+		case existing.fingerprint != fingerprint:
+			return nil, false, http.StatusUnprocessableEntity
+		case !existing.done:
+			return nil, false, http.StatusConflict
+		default:
+			i.order.MoveToFront(existing.element)
+			return existing, true, 0
+		}
+	}
+
+	entry = &idempotencyEntry{fingerprint: fingerprint}
+	i.entries[id] = entry
+	return entry, false, 0
+}
+
+// finish stores the response recorded for entry, or forgets entry when the handler did not complete, failed with
+// a 5xx status so the request can be retried, or wrote a body too large to store.
+func (i *Idempotency) finish(id string, entry *idempotencyEntry, rec *idempotencyRecorder, completed bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if !completed || status >= http.StatusInternalServerError || rec.truncated {
+		if i.entries[id] == entry {
+			delete(i.entries, id)
+		}
+		return
+	}
+
+	entry.done = true
+	entry.expires = i.now().Add(i.options.TTL)
+	entry.status = status
+	entry.header = rec.header
+	if entry.header == nil {
+		entry.header = rec.Header().Clone()
+	}
+	entry.body = rec.body.Bytes()
+	entry.element = i.order.PushFront(id)
+	i.size += entry.size(id)
+
+	for i.order.Len() > i.options.MaxEntries || i.size > i.options.MaxBytes {
+		oldest := i.order.Back()
+		oldestID := oldest.Value.(string)
+		i.remove(oldestID, i.entries[oldestID])
+	}
+}
+
+// remove forgets the completed entry stored under id. The caller must hold i.mu.
+func (i *Idempotency) remove(id string, entry *idempotencyEntry) {
+	delete(i.entries, id)
+	i.order.Remove(entry.element)
+	i.size -= entry.size(id)
+}
+
+// size returns the number of bytes the completed entry stored under id is accounted for.
+func (e *idempotencyEntry) size(id string) int64 {
+	size := len(id) + len(e.fingerprint) + len(e.body)
+	for name, values := range e.header {
+		size += len(name)
+		for _, value := range values {
+			size += len(value)
+		}
+	}
+	return int64(size)
+}
+
+// sweep forgets expired responses, at most once per idempotencySweepInterval. The caller must hold i.mu.
+func (i *Idempotency) sweep(now time.Time) {
+	if now.Sub(i.lastSweep) < idempotencySweepInterval {
+		return
+	}
+	i.lastSweep = now
+
+	for id, entry := range i.entries {
+		if entry.done && now.After(entry.expires) {
+			i.remove(id, entry)
+		}
+	}
+}
+
+// idempotencyRecorder passes a response through while recording its status, headers and up to limit bytes of its
+// body. truncated is set when the body was larger.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status    int
+	header    http.Header
+	body      bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+// WriteHeader records the status and a copy of the headers before delegating to the underlying writer.
+func (w *idempotencyRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records b before delegating to the underlying writer.
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.truncated {
+		if int64(w.body.Len()+len(b)) > w.limit {
+			w.truncated = true
+			w.body = bytes.Buffer{}
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying http.ResponseWriter so http.ResponseController can reach optional interfaces.
+func (w *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// requestFingerprint identifies the method, path and body of r, so a key reused for another request is detected.
+func requestFingerprint(r *http.Request, body []byte) string {
+	sum := sha256.Sum256(body)
+	return r.Method + " " + r.URL.Path + " " + hex.EncodeToString(sum[:])
+}
+
+// remoteIP returns the IP address of the client sending r.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
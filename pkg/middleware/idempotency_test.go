@@ -0,0 +1,415 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// countingHandler answers with status and a body that counts the calls made to it.
+func countingHandler(calls *atomic.Int32, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("X-Call", fmt.Sprint(n))
+		json_wrapper.WriteJSONResponse(w, status, map[string]int32{"call": n})
+	})
+}
+
+// idempotentRequest returns a request from remoteAddr with the given key and body.
+func idempotentRequest(method, key, body, remoteAddr string) *http.Request {
+	req := httptest.NewRequest(method, "/categories", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		method     string
+		key        string
+		body       string
+		remoteAddr string
+	}
+	post := func(key, body string) request {
+		return request{method: http.MethodPost, key: key, body: body, remoteAddr: "10.0.0.1:1234"}
+	}
+
+	tests := []struct {
+		name        string
+		status      int
+		requests    []request
+		wantCalls   int32
+		wantStatus  []int
+		wantBody    []string
+		wantReplays []bool
+		wantCode    string
+	}{
+		{
+			name:        "replays the first response",
+			status:      http.StatusCreated,
+			requests:    []request{post("k1", `{"name":"Buku"}`), post("k1", `{"name":"Buku"}`)},
+			wantCalls:   1,
+			wantStatus:  []int{http.StatusCreated, http.StatusCreated},
+			wantBody:    []string{`{"call":1}`, `{"call":1}`},
+			wantReplays: []bool{false, true},
+		},
+		{
+			name:       "requests without a key are not deduplicated",
+			status:     http.StatusCreated,
+			requests:   []request{post("", `{}`), post("", `{}`)},
+			wantCalls:  2,
+			wantStatus: []int{http.StatusCreated, http.StatusCreated},
+			wantBody:   []string{`{"call":1}`, `{"call":2}`},
+		},
+		{
+			name:       "keys are scoped to the client",
+			status:     http.StatusCreated,
+			requests:   []request{post("k1", `{}`), {method: http.MethodPost, key: "k1", body: `{}`, remoteAddr: "10.0.0.2:1234"}},
+			wantCalls:  2,
+			wantStatus: []int{http.StatusCreated, http.StatusCreated},
+			wantBody:   []string{`{"call":1}`, `{"call":2}`},
+		},
+		{
+			name:       "key reused with another body",
+			status:     http.StatusCreated,
+			requests:   []request{post("k1", `{"name":"Buku"}`), post("k1", `{"name":"Pena"}`)},
+			wantCalls:  1,
+			wantStatus: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			wantCode:   constants.MsgIdempotencyKeyReused,
+		},
+		{
+			name:       "server errors are not stored",
+			status:     http.StatusInternalServerError,
+			requests:   []request{post("k1", `{}`), post("k1", `{}`)},
+			wantCalls:  2,
+			wantStatus: []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			wantBody:   []string{`{"call":1}`, `{"call":2}`},
+		},
+		{
+			name:       "client errors are stored",
+			status:     http.StatusBadRequest,
+			requests:   []request{post("k1", `{`), post("k1", `{`)},
+			wantCalls:  1,
+			wantStatus: []int{http.StatusBadRequest, http.StatusBadRequest},
+			wantBody:   []string{`{"call":1}`, `{"call":1}`},
+		},
+		{
+			name:       "other methods pass through",
+			status:     http.StatusOK,
+			requests:   []request{{method: http.MethodPut, key: "k1", remoteAddr: "10.0.0.1:1"}, {method: http.MethodPut, key: "k1", remoteAddr: "10.0.0.1:1"}},
+			wantCalls:  2,
+			wantStatus: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:       "key too long",
+			status:     http.StatusCreated,
+			requests:   []request{post(strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)},
+			wantCalls:  0,
+			wantStatus: []int{http.StatusBadRequest},
+			wantCode:   constants.MsgInvalidIdempotencyKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idempotency, err := NewIdempotency(IdempotencyOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var calls atomic.Int32
+			handler := idempotency.Middleware(countingHandler(&calls, tt.status), WriteJSONError)
+
+			var last *httptest.ResponseRecorder
+			for i, req := range tt.requests {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, idempotentRequest(req.method, req.key, req.body, req.remoteAddr))
+				last = rec
+
+				if rec.Code != tt.wantStatus[i] {
+					t.Fatalf("request %d: expected status %d, got %d", i, tt.wantStatus[i], rec.Code)
+				}
+				if tt.wantBody != nil && strings.TrimSpace(rec.Body.String()) != tt.wantBody[i] {
+					t.Errorf("request %d: expected body %s, got %s", i, tt.wantBody[i], rec.Body.String())
+				}
+				if tt.wantReplays != nil {
+					replayed := rec.Header().Get(IdempotentReplayedHeader) == "true"
+					if replayed != tt.wantReplays[i] {
+						t.Errorf("request %d: expected replayed %v, got %v", i, tt.wantReplays[i], replayed)
+					}
+					if replayed && rec.Header().Get("X-Call") != "1" {
+						t.Errorf("request %d: expected the stored headers to be replayed, got %v", i, rec.Header())
+					}
+				}
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d handler calls, got %d", tt.wantCalls, got)
+			}
+			if tt.wantCode != "" {
+				var result json_wrapper.APIResponse
+				if err := json.Unmarshal(last.Body.Bytes(), &result); err != nil {
+					t.Fatalf("expected a JSON envelope, got %s", last.Body.String())
+				}
+				if result.MessageCode != tt.wantCode {
+					t.Errorf("expected message code %s, got %s", tt.wantCode, result.MessageCode)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotency_Problem(t *testing.T) {
+	idempotency, _ := NewIdempotency(IdempotencyOptions{MaxBodyBytes: 16})
+	var calls atomic.Int32
+	handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteProblemError)
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1"))
+
+	tests := []struct {
+		name       string
+		key        string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid key", key: strings.Repeat("k", maxIdempotencyKeyLength+1), body: `{}`, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidIdempotencyKey},
+		{name: "body too large", key: "k2", body: `{"name":"Buku Baru"}`, wantStatus: http.StatusRequestEntityTooLarge, wantCode: constants.MsgRequestTooLarge},
+		{name: "key reused", key: "k1", body: `{"a":1}`, wantStatus: http.StatusUnprocessableEntity, wantCode: constants.MsgIdempotencyKeyReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, tt.key, tt.body, "10.0.0.1:1"))
+			expectProblem(t, rec, tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestIdempotency_ConcurrentRequestConflicts(t *testing.T) {
+	idempotency, _ := NewIdempotency(IdempotencyOptions{})
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}), WriteJSONError)
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1"))
+		close(done)
+	}()
+	<-entered
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1"))
+	if second.Code != http.StatusConflict {
+		t.Fatalf("expected 409 while the first request is in flight, got %d", second.Code)
+	}
+	if !strings.Contains(second.Body.String(), constants.MsgIdempotencyConflict) {
+		t.Errorf("expected the conflict message code, got %s", second.Body.String())
+	}
+
+	close(release)
+	<-done
+
+	third := httptest.NewRecorder()
+	handler.ServeHTTP(third, idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1"))
+	if third.Code != http.StatusCreated || third.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected the stored 201 to be replayed, got %d %v", third.Code, third.Header())
+	}
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	idempotency, _ := NewIdempotency(IdempotencyOptions{})
+
+	var calls atomic.Int32
+	handler := Recovery(idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}), WriteJSONError), WriteJSONError)
+
+	for i, want := range []int{http.StatusInternalServerError, http.StatusCreated} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1"))
+		if rec.Code != want {
+			t.Fatalf("request %d: expected status %d, got %d", i, want, rec.Code)
+		}
+	}
+}
+
+func TestIdempotency_Expiry(t *testing.T) {
+	idempotency, _ := NewIdempotency(IdempotencyOptions{TTL: time.Hour})
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	idempotency.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteJSONError)
+	send := func() {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1"))
+	}
+
+	send()
+	now = now.Add(59 * time.Minute)
+	send()
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected the response to be replayed within the ttl, got %d calls", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	send()
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected the key to expire after the ttl, got %d calls", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	idempotency.sweep(now)
+	if len(idempotency.entries) != 0 {
+		t.Errorf("expected expired responses to be swept, got %d", len(idempotency.entries))
+	}
+}
+
+func TestIdempotency_Limits(t *testing.T) {
+	var calls atomic.Int32
+	send := func(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, key, body, "10.0.0.1:1"))
+		return rec
+	}
+
+	t.Run("max entries", func(t *testing.T) {
+		idempotency, _ := NewIdempotency(IdempotencyOptions{MaxEntries: 2})
+		handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteJSONError)
+
+		calls.Store(0)
+		send(handler, "k1", `{}`)
+		send(handler, "k2", `{}`)
+		send(handler, "k1", `{}`)
+		send(handler, "k3", `{}`)
+		if got := calls.Load(); got != 3 {
+			t.Fatalf("expected k1 to be replayed, got %d calls", got)
+		}
+		if len(idempotency.entries) != 2 || idempotency.entries[idempotentID("k2")] != nil {
+			t.Fatalf("expected the least recently used key to be dropped, got %d entries", len(idempotency.entries))
+		}
+
+		send(handler, "k1", `{}`)
+		if got := calls.Load(); got != 3 {
+			t.Errorf("expected k1 to be kept, got %d calls", got)
+		}
+	})
+
+	t.Run("max bytes", func(t *testing.T) {
+		idempotency, _ := NewIdempotency(IdempotencyOptions{MaxBytes: 300})
+		handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteJSONError)
+
+		for _, key := range []string{"k1", "k2", "k3", "k4"} {
+			send(handler, key, `{}`)
+		}
+		if idempotency.size > 300 || len(idempotency.entries) == 4 {
+			t.Fatalf("expected the store to stay within 300 bytes, got %d bytes in %d entries", idempotency.size, len(idempotency.entries))
+		}
+		if idempotency.entries[idempotentID("k4")] == nil {
+			t.Errorf("expected the newest response to be kept")
+		}
+	})
+
+	t.Run("request body too large", func(t *testing.T) {
+		idempotency, _ := NewIdempotency(IdempotencyOptions{MaxBodyBytes: 8})
+		handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteJSONError)
+
+		calls.Store(0)
+		rec := send(handler, "k1", `{"name":"too long"}`)
+		if rec.Code != http.StatusRequestEntityTooLarge || calls.Load() != 0 {
+			t.Fatalf("expected 413 without calling the handler, got %d after %d calls", rec.Code, calls.Load())
+		}
+	})
+
+	t.Run("response body too large", func(t *testing.T) {
+		idempotency, _ := NewIdempotency(IdempotencyOptions{MaxBodyBytes: 8})
+		handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteJSONError)
+
+		calls.Store(0)
+		rec := send(handler, "k1", `{}`)
+		if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"call":1`) {
+			t.Fatalf("expected the response to be passed through, got %d %s", rec.Code, rec.Body.String())
+		}
+		if len(idempotency.entries) != 0 {
+			t.Errorf("expected a response over the limit not to be stored, got %d entries", len(idempotency.entries))
+		}
+	})
+}
+
+// idempotentID returns the store key of key sent by idempotentRequest for the default tenant.
+func idempotentID(key string) string {
+	return tenant.DefaultID + "\x00" + "10.0.0.1" + "\x00" + key
+}
+
+func TestIdempotency_CustomClientID(t *testing.T) {
+	idempotency, _ := NewIdempotency(IdempotencyOptions{
+		ClientID: func(r *http.Request) string { return r.Header.Get("Authorization") },
+	})
+
+	var calls atomic.Int32
+	handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteJSONError)
+	for _, token := range []string{"Bearer a", "Bearer a", "Bearer b"} {
+		req := idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1")
+		req.Header.Set("Authorization", token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected one call per client, got %d", got)
+	}
+}
+
+func TestIdempotency_KeysScopedToTenant(t *testing.T) {
+	idempotency, _ := NewIdempotency(IdempotencyOptions{})
+
+	var calls atomic.Int32
+	handler := idempotency.Middleware(countingHandler(&calls, http.StatusCreated), WriteJSONError)
+	for _, tenantID := range []string{"acme", "acme", "globex"} {
+		req := idempotentRequest(http.MethodPost, "k1", `{}`, "10.0.0.1:1")
+		handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(tenant.WithID(req.Context(), tenantID)))
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected one call per tenant, got %d", got)
+	}
+}
+
+func TestNewIdempotency(t *testing.T) {
+	if _, err := NewIdempotency(IdempotencyOptions{TTL: -time.Second}); err == nil {
+		t.Fatal("expected an error for a negative ttl")
+	}
+	if _, err := NewIdempotency(IdempotencyOptions{MaxBytes: -1}); err == nil {
+		t.Fatal("expected an error for a negative limit")
+	}
+
+	idempotency, err := NewIdempotency(IdempotencyOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if idempotency.options.TTL != DefaultIdempotencyTTL || len(idempotency.options.Methods) != 1 || idempotency.options.ClientID == nil ||
+		idempotency.options.MaxEntries != DefaultIdempotencyMaxEntries || idempotency.options.MaxBytes != DefaultIdempotencyMaxBytes ||
+		idempotency.options.MaxBodyBytes != json_wrapper.DefaultMaxBodyBytes {
+		t.Errorf("expected the defaults, got %+v", idempotency.options)
+	}
+}
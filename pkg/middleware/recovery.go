@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+)
+
+// recoveryWriter tracks whether the wrapped handler has already started writing its response.
+type recoveryWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader records that the response status has been sent before delegating to the underlying writer.
+func (w *recoveryWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records that the response has started before delegating to the underlying writer.
+func (w *recoveryWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying http.ResponseWriter so http.ResponseController can reach optional interfaces.
+func (w *recoveryWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Recovery wraps next so that a panicking handler is logged with its stack trace and answered with a 500 response
+// written by writeError. http.ErrAbortHandler is re-panicked so net/http can abort the connection as intended.
+func Recovery(next http.Handler, writeError ErrorWriter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &recoveryWriter{ResponseWriter: w}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			log.Printf("panic recovered on %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+
+			if rw.wroteHeader {
+				return
+			}
+
+			writeError(w, r, http.StatusInternalServerError, constants.MsgInternalServer)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
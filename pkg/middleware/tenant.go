@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// TenancyOptions configures Tenancy.
+type TenancyOptions struct {
+	// Resolver finds the tenant of a request. It is required.
+	Resolver *tenant.Resolver
+
+	// Exists reports whether a tenant is registered. Nil accepts every tenant.
+	Exists func(tenantID string) bool
+}
+
+// Tenancy resolves the tenant every request belongs to and stores it in the request context, where handlers and
+// storage read it with tenant.FromContext. Requests whose tenant cannot be resolved are answered with 400 Bad
+// Request for an invalid ID, 401 Unauthorized for a missing or invalid token, 403 Forbidden when the token names another
+// tenant than the request and 404 Not Found for a tenant that is not registered.
+type Tenancy struct {
+	options TenancyOptions
+}
+
+// NewTenancy initializes a new Tenancy middleware with the given options.
+func NewTenancy(options TenancyOptions) (*Tenancy, error) {
+	if options.Resolver == nil {
+		return nil, fmt.Errorf("tenant resolver must not be nil")
+	}
+	if options.Exists == nil {
+		options.Exists = func(string) bool { return true }
+	}
+
+	return &Tenancy{options: options}, nil
+}
+
+// Middleware wraps next so it only sees requests of registered tenants, with the tenant in their context. Rejected
+// requests are answered by writeError.
+func (t *Tenancy) Middleware(next http.Handler, writeError ErrorWriter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := t.options.Resolver.Resolve(r.Host, r.Header)
+		switch {
+		case errors.Is(err, tenant.ErrMissingToken):
+			writeError(w, r, http.StatusUnauthorized, constants.MsgTenantTokenRequired)
+			return
+		case errors.Is(err, tenant.ErrInvalidToken):
+			writeError(w, r, http.StatusUnauthorized, constants.MsgInvalidTenantToken)
+			return
+		case errors.Is(err, tenant.ErrConflict):
+			writeError(w, r, http.StatusForbidden, constants.MsgTenantMismatch)
+			return
+		case err != nil:
+			writeError(w, r, http.StatusBadRequest, constants.MsgInvalidTenant)
+			return
+		case !t.options.Exists(tenantID):
+			writeError(w, r, http.StatusNotFound, constants.MsgTenantNotFound)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenantID)))
+	})
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pandusatrianura/code-with-umam-categories-api/constants"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/json_wrapper"
+	"github.com/pandusatrianura/code-with-umam-categories-api/pkg/tenant"
+)
+
+// signToken returns an HS256 token with claims, signed with key.
+func signToken(t *testing.T, claims map[string]any, key []byte) string {
+	t.Helper()
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestTenancy(t *testing.T) {
+	var seen string
+	newHandler := func(options tenant.Options) http.Handler {
+		resolver, _ := tenant.NewResolver(options)
+		tenancy, err := NewTenancy(TenancyOptions{
+			Resolver: resolver,
+			Exists:   func(tenantID string) bool { return tenantID == tenant.DefaultID || tenantID == "acme" },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return tenancy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = tenant.FromContext(r.Context())
+		}), WriteJSONError)
+	}
+	plain := newHandler(tenant.Options{BaseDomain: "shop.example.com"})
+	signed := newHandler(tenant.Options{BaseDomain: "shop.example.com", Secret: []byte("s3cret")})
+
+	tests := []struct {
+		name       string
+		signed     bool
+		host       string
+		header     map[string]string
+		wantStatus int
+		wantTenant string
+		wantCode   string
+	}{
+		{name: "no tenant", wantStatus: http.StatusOK, wantTenant: tenant.DefaultID},
+		{name: "header", header: map[string]string{"X-Tenant-ID": "acme"}, wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "subdomain", host: "acme.shop.example.com", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "unknown tenant", header: map[string]string{"X-Tenant-ID": "globex"}, wantStatus: http.StatusNotFound, wantCode: constants.MsgTenantNotFound},
+		{name: "invalid tenant", header: map[string]string{"X-Tenant-ID": "Acme Inc"}, wantStatus: http.StatusBadRequest, wantCode: constants.MsgInvalidTenant},
+		{name: "invalid token", signed: true, header: map[string]string{"Authorization": "Bearer abc"}, wantStatus: http.StatusUnauthorized, wantCode: constants.MsgInvalidTenantToken},
+		{name: "missing token", signed: true, wantStatus: http.StatusUnauthorized, wantCode: constants.MsgTenantTokenRequired},
+		{name: "header without token", signed: true, header: map[string]string{"X-Tenant-ID": "acme"}, wantStatus: http.StatusUnauthorized, wantCode: constants.MsgTenantTokenRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = ""
+			req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+
+			handler := plain
+			if tt.signed {
+				handler = signed
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if seen != tt.wantTenant {
+				t.Errorf("expected the handler to see tenant %q, got %q", tt.wantTenant, seen)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+
+			var got json_wrapper.APIResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.MessageCode != tt.wantCode {
+				t.Errorf("expected message code %s, got %s", tt.wantCode, got.MessageCode)
+			}
+		})
+	}
+}
+
+func TestTenancy_Problem(t *testing.T) {
+	resolver, _ := tenant.NewResolver(tenant.Options{Secret: []byte("s3cret")})
+	tenancy, _ := NewTenancy(TenancyOptions{
+		Resolver: resolver,
+		Exists:   func(tenantID string) bool { return tenantID == tenant.DefaultID },
+	})
+	handler := tenancy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected the request to be rejected")
+	}), WriteProblemError)
+
+	acme := signToken(t, map[string]any{"tenant": "acme"}, []byte("s3cret"))
+	tests := []struct {
+		name       string
+		header     map[string]string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "missing token", wantStatus: http.StatusUnauthorized, wantCode: constants.MsgTenantTokenRequired},
+		{name: "invalid token", header: map[string]string{"Authorization": "Bearer abc"}, wantStatus: http.StatusUnauthorized, wantCode: constants.MsgInvalidTenantToken},
+		{name: "mismatch", header: map[string]string{"Authorization": "Bearer " + acme, "X-Tenant-ID": "globex"}, wantStatus: http.StatusForbidden, wantCode: constants.MsgTenantMismatch},
+		{name: "unknown tenant", header: map[string]string{"Authorization": "Bearer " + acme}, wantStatus: http.StatusNotFound, wantCode: constants.MsgTenantNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/categories", nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			expectProblem(t, rec, tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestNewTenancy(t *testing.T) {
+	if _, err := NewTenancy(TenancyOptions{}); err == nil {
+		t.Fatal("expected an error without a resolver")
+	}
+
+	resolver, _ := tenant.NewResolver(tenant.Options{})
+	tenancy, err := NewTenancy(TenancyOptions{Resolver: resolver})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenancy.options.Exists == nil || !tenancy.options.Exists("any") {
+		t.Errorf("expected every tenant to be accepted by default")
+	}
+}
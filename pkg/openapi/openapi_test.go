@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func testDocument(t *testing.T) *Document {
+	t.Helper()
+
+	doc, err := Build(Config{Info: Info{Title: "Items", Version: "1.0.0"}, Envelope: envelope{}}, []Route{{
+		Method:  http.MethodGet,
+		Path:    "/items/{id}",
+		Handler: noop,
+		Doc: &Doc{
+			ID:        "getItem",
+			Params:    []Param{{Name: "id", In: "path"}},
+			Responses: []ResponseDoc{{Status: http.StatusOK, Data: address{}}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return doc
+}
+
+func TestDocument_JSON(t *testing.T) {
+	content, err := testDocument(t).JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+	if decoded["openapi"] != Version {
+		t.Errorf("expected openapi %s, got %v", Version, decoded["openapi"])
+	}
+	if !strings.Contains(string(content), `"$ref": "#/components/schemas/openapi.address"`) {
+		t.Errorf("expected a reference to openapi.address, got %s", content)
+	}
+}
+
+func TestDocument_YAML(t *testing.T) {
+	doc := testDocument(t)
+
+	content, err := doc.YAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(string(content), "openapi: 3.1.0\n") {
+		t.Errorf("expected the openapi field first in block style, got %s", content)
+	}
+	// Empty mappings such as the "any value" schema can only be written in flow style.
+	block := strings.ReplaceAll(string(content), "{}", "")
+	if strings.Contains(block, ": {") || strings.Contains(block, ": [") || strings.Contains(block, "- {") {
+		t.Errorf("expected block style only, got %s", content)
+	}
+
+	jsonContent, err := doc.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var fromYAML, fromJSON interface{}
+	if err := yaml.Unmarshal(content, &fromYAML); err != nil {
+		t.Fatalf("expected valid YAML, got %v", err)
+	}
+	if err := json.Unmarshal(jsonContent, &fromJSON); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	// Round-trip the YAML document through JSON so both sides use the same Go types.
+	normalized, err := json.Marshal(fromYAML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fromYAMLNormalized interface{}
+	if err := json.Unmarshal(normalized, &fromYAMLNormalized); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromYAMLNormalized, fromJSON) {
+		t.Fatalf("expected YAML and JSON to describe the same document\nyaml: %s\njson: %s", content, jsonContent)
+	}
+}
@@ -0,0 +1,349 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathParam matches a wildcard segment of a net/http route pattern, e.g. {id} or {path...}.
+var pathParam = regexp.MustCompile(`\{([^}]*)\}`)
+
+// Route is one entry of a route table: the handler served for Method and Path and, when Doc is set, its
+// documentation. Routes without a Doc, such as documentation pages, are served but left out of the spec.
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+	Doc     *Doc
+}
+
+// Pattern returns the net/http ServeMux pattern of the route, e.g. "GET /categories/{id}".
+func (r Route) Pattern() string {
+	return r.Method + " " + r.Path
+}
+
+// Doc documents the operation served by a Route.
+type Doc struct {
+	ID          string
+	Summary     string
+	Description string
+	Tags        []string
+	Params      []Param
+	// Body is a value of the type decoded from the request body, or nil when the operation takes none.
+	Body interface{}
+	// BodyContentType is the only media type the request body is accepted in, e.g. multipart/form-data. When empty,
+	// the body is offered as application/json and MediaTypes.
+	BodyContentType string
+	Responses       []ResponseDoc
+	// MediaTypes lists the media types the request body and the responses without an explicit ContentType are
+	// offered in besides application/json, with the same schema.
+	MediaTypes []string
+}
+
+// Param documents a path, query or header parameter. Type is a value of the parameter's Go type; nil means string.
+// Path parameters are always required.
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Type        interface{}
+}
+
+// ResponseDoc documents one response status of an operation. Data is a value of the type placed in the data field
+// of the response envelope, or nil when the response carries none. Raw responses are not enveloped: Data describes
+// the whole body. ContentType defaults to application/json.
+type ResponseDoc struct {
+	Status      int
+	Description string
+	Data        interface{}
+	ContentType string
+	Raw         bool
+}
+
+// Config holds the document-wide settings of Build.
+type Config struct {
+	Info    Info
+	Servers []Server
+	// Envelope is a value of the type every non-raw JSON response is wrapped in. Its data field is described per
+	// response by ResponseDoc.Data.
+	Envelope interface{}
+}
+
+// Build generates an OpenAPI document from the documented routes. It fails when a route's path parameters do not
+// match its pattern, or when two routes share a pattern or an operation ID.
+func Build(cfg Config, routes []Route) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    cfg.Info,
+		Servers: cfg.Servers,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	g := newSchemaGenerator()
+	envelope := g.schemaFor(cfg.Envelope)
+
+	patterns := make(map[string]bool)
+	ids := make(map[string]bool)
+	tags := make(map[string]bool)
+
+	for _, route := range routes {
+		if route.Doc == nil {
+			continue
+		}
+
+		if patterns[route.Pattern()] {
+			return nil, fmt.Errorf("openapi: duplicate route %q", route.Pattern())
+		}
+		patterns[route.Pattern()] = true
+
+		if route.Doc.ID == "" || ids[route.Doc.ID] {
+			return nil, fmt.Errorf("openapi: route %q needs a unique operation id, got %q", route.Pattern(), route.Doc.ID)
+		}
+		ids[route.Doc.ID] = true
+
+		operation, err := buildOperation(g, envelope, route)
+		if err != nil {
+			return nil, err
+		}
+
+		path := specPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		if err := item.set(route.Method, operation); err != nil {
+			return nil, fmt.Errorf("openapi: route %q: %w", route.Pattern(), err)
+		}
+
+		for _, tag := range route.Doc.Tags {
+			if !tags[tag] {
+				tags[tag] = true
+				doc.Tags = append(doc.Tags, Tag{Name: tag})
+			}
+		}
+	}
+
+	if len(g.components) > 0 {
+		doc.Components.Schemas = g.components
+	}
+
+	return doc, nil
+}
+
+// Operation returns the operation documented for method on path, a path in OpenAPI form such as /categories/{id}.
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	case http.MethodPatch:
+		return item.Patch
+	case http.MethodHead:
+		return item.Head
+	default:
+		return nil
+	}
+}
+
+// Operations returns the "METHOD path" key of every operation in the document, sorted.
+func (d *Document) Operations() []string {
+	var keys []string
+	for path, item := range d.Paths {
+		for method, operation := range map[string]*Operation{
+			http.MethodGet:    item.Get,
+			http.MethodPut:    item.Put,
+			http.MethodPost:   item.Post,
+			http.MethodDelete: item.Delete,
+			http.MethodPatch:  item.Patch,
+			http.MethodHead:   item.Head,
+		} {
+			if operation != nil {
+				keys = append(keys, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// set stores operation under method, refusing methods the PathItem cannot hold.
+func (p *PathItem) set(method string, operation *Operation) error {
+	switch method {
+	case http.MethodGet:
+		p.Get = operation
+	case http.MethodPut:
+		p.Put = operation
+	case http.MethodPost:
+		p.Post = operation
+	case http.MethodDelete:
+		p.Delete = operation
+	case http.MethodPatch:
+		p.Patch = operation
+	case http.MethodHead:
+		p.Head = operation
+	default:
+		return fmt.Errorf("unsupported method %q", method)
+	}
+	return nil
+}
+
+// buildOperation describes the documented route as an OpenAPI operation.
+func buildOperation(g *schemaGenerator, envelope *Schema, route Route) (*Operation, error) {
+	doc := route.Doc
+	operation := &Operation{
+		OperationID: doc.ID,
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Tags:        doc.Tags,
+		Responses:   make(map[string]*Response, len(doc.Responses)),
+	}
+
+	wildcards := PathParams(route.Path)
+	documented := make(map[string]bool)
+	for _, param := range doc.Params {
+		switch param.In {
+		case "path":
+			documented[param.Name] = true
+			param.Required = true
+		case "query", "header":
+		default:
+			return nil, fmt.Errorf("openapi: route %q: parameter %q has unknown location %q", route.Pattern(), param.Name, param.In)
+		}
+
+		schema := &Schema{Type: "string"}
+		if param.Type != nil {
+			schema = g.schemaFor(param.Type)
+		}
+
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      schema,
+		})
+	}
+
+	if len(documented) != len(wildcards) {
+		return nil, fmt.Errorf("openapi: route %q documents path parameters %v, pattern has %v", route.Pattern(), keys(documented), wildcards)
+	}
+	for _, name := range wildcards {
+		if !documented[name] {
+			return nil, fmt.Errorf("openapi: route %q does not document path parameter %q", route.Pattern(), name)
+		}
+	}
+
+	if doc.Body != nil {
+		content := mediaTypes(doc.MediaTypes, "application/json", g.schemaFor(doc.Body))
+		if doc.BodyContentType != "" {
+			content = map[string]MediaType{doc.BodyContentType: {Schema: g.schemaFor(doc.Body)}}
+		}
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  content,
+		}
+	}
+
+	if len(doc.Responses) == 0 {
+		return nil, fmt.Errorf("openapi: route %q documents no responses", route.Pattern())
+	}
+	for _, response := range doc.Responses {
+		status := strconv.Itoa(response.Status)
+		if _, ok := operation.Responses[status]; ok {
+			return nil, fmt.Errorf("openapi: route %q documents status %s twice", route.Pattern(), status)
+		}
+
+		description := response.Description
+		if description == "" {
+			description = http.StatusText(response.Status)
+		}
+
+		contentType := response.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+
+		var schema *Schema
+		switch {
+		case response.Raw:
+			schema = g.schemaFor(response.Data)
+		case envelope == nil:
+			return nil, fmt.Errorf("openapi: route %q has enveloped responses but no envelope is configured", route.Pattern())
+		case response.Data == nil:
+			schema = envelope
+		default:
+			schema = &Schema{AllOf: []*Schema{envelope, {
+				Type:       "object",
+				Properties: map[string]*Schema{"data": g.schemaFor(response.Data)},
+			}}}
+		}
+
+		var content map[string]MediaType
+		switch {
+		case schema == nil:
+		case response.ContentType != "":
+			content = map[string]MediaType{contentType: {Schema: schema}}
+		default:
+			content = mediaTypes(doc.MediaTypes, contentType, schema)
+		}
+
+		operation.Responses[status] = &Response{Description: description, Content: content}
+	}
+
+	return operation, nil
+}
+
+// mediaTypes returns the content of a request body or response offered in contentType and the alternates, all
+// with schema.
+func mediaTypes(alternates []string, contentType string, schema *Schema) map[string]MediaType {
+	content := make(map[string]MediaType, len(alternates)+1)
+	content[contentType] = MediaType{Schema: schema}
+	for _, alternate := range alternates {
+		content[alternate] = MediaType{Schema: schema}
+	}
+	return content
+}
+
+// PathParams returns the names of the wildcards in a net/http route path, in order.
+func PathParams(path string) []string {
+	var names []string
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		name := strings.TrimSuffix(match[1], "...")
+		if name != "$" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// specPath converts a net/http route path to OpenAPI form: {path...} becomes {path} and the {$} anchor is dropped.
+func specPath(path string) string {
+	path = strings.ReplaceAll(path, "{$}", "")
+	return strings.ReplaceAll(path, "...}", "}")
+}
+
+// keys returns the keys of set, sorted.
+func keys(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,254 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type envelope struct {
+	Code string      `json:"code"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+func noop(http.ResponseWriter, *http.Request) {}
+
+type upload struct {
+	File Binary `json:"file"`
+}
+
+func TestBuild(t *testing.T) {
+	routes := []Route{
+		{
+			Method:  http.MethodGet,
+			Path:    "/items/{id}",
+			Handler: noop,
+			Doc: &Doc{
+				ID:        "getItem",
+				Tags:      []string{"items"},
+				Params:    []Param{{Name: "id", In: "path", Type: int64(0)}, {Name: "lang", In: "query"}},
+				Responses: []ResponseDoc{{Status: http.StatusOK, Data: address{}}, {Status: http.StatusNotFound}},
+			},
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/items",
+			Handler: noop,
+			Doc: &Doc{
+				ID:   "createItem",
+				Tags: []string{"items"},
+				Body: address{},
+				Responses: []ResponseDoc{
+					{Status: http.StatusCreated, Data: address{}},
+					{Status: http.StatusOK, Raw: true, Data: address{}},
+					{Status: http.StatusBadRequest, ContentType: "application/problem+json", Raw: true, Data: address{}},
+				},
+				MediaTypes: []string{"application/xml"},
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/files/{path...}",
+			Handler: noop,
+			Doc: &Doc{
+				ID:        "getFile",
+				Tags:      []string{"files"},
+				Params:    []Param{{Name: "path", In: "path"}},
+				Responses: []ResponseDoc{{Status: http.StatusOK, ContentType: "text/plain", Raw: true, Data: ""}},
+			},
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/files/{path...}",
+			Handler: noop,
+			Doc: &Doc{
+				ID:              "putFile",
+				Tags:            []string{"files"},
+				Params:          []Param{{Name: "path", In: "path"}},
+				Body:            upload{},
+				BodyContentType: "multipart/form-data",
+				MediaTypes:      []string{"application/xml"},
+				Responses:       []ResponseDoc{{Status: http.StatusNoContent}},
+			},
+		},
+		{Method: http.MethodGet, Path: "/docs", Handler: noop},
+	}
+
+	doc, err := Build(Config{Info: Info{Title: "Items", Version: "1.0.0"}, Envelope: envelope{}}, routes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doc.OpenAPI != Version {
+		t.Errorf("expected version %s, got %s", Version, doc.OpenAPI)
+	}
+
+	wantOperations := []string{"GET /files/{path}", "GET /items/{id}", "POST /items", "PUT /files/{path}"}
+	if got := doc.Operations(); !reflect.DeepEqual(got, wantOperations) {
+		t.Fatalf("expected operations %v, got %v", wantOperations, got)
+	}
+
+	wantTags := []Tag{{Name: "items"}, {Name: "files"}}
+	if !reflect.DeepEqual(doc.Tags, wantTags) {
+		t.Errorf("expected tags %v, got %v", wantTags, doc.Tags)
+	}
+
+	getItem := doc.Operation(http.MethodGet, "/items/{id}")
+	if len(getItem.Parameters) != 2 || !getItem.Parameters[0].Required || getItem.Parameters[1].Required {
+		t.Errorf("expected a required path parameter and an optional query parameter, got %+v", getItem.Parameters)
+	}
+
+	envelopeRef := &Schema{Ref: "#/components/schemas/openapi.envelope"}
+	wantOK := &Schema{AllOf: []*Schema{envelopeRef, {
+		Type:       "object",
+		Properties: map[string]*Schema{"data": {Ref: "#/components/schemas/openapi.address"}},
+	}}}
+	if got := getItem.Responses["200"].Content["application/json"].Schema; !reflect.DeepEqual(got, wantOK) {
+		t.Errorf("expected enveloped data schema %+v, got %+v", wantOK, got)
+	}
+	if got := getItem.Responses["404"]; got.Description != "Not Found" || !reflect.DeepEqual(got.Content["application/json"].Schema, envelopeRef) {
+		t.Errorf("expected bare envelope for 404, got %+v", got)
+	}
+
+	getFile := doc.Operation(http.MethodGet, "/files/{path}")
+	if got := getFile.Responses["200"].Content["text/plain"].Schema; !reflect.DeepEqual(got, &Schema{Type: "string"}) {
+		t.Errorf("expected raw string response, got %+v", got)
+	}
+
+	putFile := doc.Operation(http.MethodPut, "/files/{path}")
+	if got := putFile.RequestBody.Content; len(got) != 1 || got["multipart/form-data"].Schema == nil {
+		t.Errorf("expected only multipart/form-data for an explicit body content type, got %v", got)
+	}
+	wantUpload := &Schema{Type: "object", Properties: map[string]*Schema{"file": {Type: "string", Format: "binary"}}, Required: []string{"file"}}
+	if got := doc.Components.Schemas["openapi.upload"]; !reflect.DeepEqual(got, wantUpload) {
+		t.Errorf("expected upload schema %+v, got %+v", wantUpload, got)
+	}
+
+	createItem := doc.Operation(http.MethodPost, "/items")
+	if createItem.RequestBody == nil || !createItem.RequestBody.Required {
+		t.Errorf("expected a required request body, got %+v", createItem.RequestBody)
+	}
+	for _, contentType := range []string{"application/json", "application/xml"} {
+		if _, ok := createItem.RequestBody.Content[contentType]; !ok {
+			t.Errorf("expected the request body in %s, got %v", contentType, createItem.RequestBody.Content)
+		}
+		if _, ok := createItem.Responses["201"].Content[contentType]; !ok {
+			t.Errorf("expected the response in %s, got %v", contentType, createItem.Responses["201"].Content)
+		}
+		if _, ok := createItem.Responses["200"].Content[contentType]; !ok {
+			t.Errorf("expected the raw response in %s, got %v", contentType, createItem.Responses["200"].Content)
+		}
+	}
+	if got := createItem.Responses["400"].Content; len(got) != 1 || got["application/problem+json"].Schema == nil {
+		t.Errorf("expected only application/problem+json for an explicit content type, got %v", got)
+	}
+	if len(getItem.Responses["200"].Content) != 1 {
+		t.Errorf("expected only application/json without media types, got %v", getItem.Responses["200"].Content)
+	}
+
+	for _, name := range []string{"openapi.envelope", "openapi.address"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("expected component %q, got %v", name, doc.Components.Schemas)
+		}
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	ok := []ResponseDoc{{Status: http.StatusOK}}
+
+	tests := []struct {
+		name   string
+		routes []Route
+	}{
+		{
+			name:   "undocumented path parameter",
+			routes: []Route{{Method: http.MethodGet, Path: "/items/{id}", Doc: &Doc{ID: "a", Responses: ok}}},
+		},
+		{
+			name: "unknown path parameter",
+			routes: []Route{{Method: http.MethodGet, Path: "/items", Doc: &Doc{
+				ID: "a", Params: []Param{{Name: "id", In: "path"}}, Responses: ok,
+			}}},
+		},
+		{
+			name: "misnamed path parameter",
+			routes: []Route{{Method: http.MethodGet, Path: "/items/{id}", Doc: &Doc{
+				ID: "a", Params: []Param{{Name: "slug", In: "path"}}, Responses: ok,
+			}}},
+		},
+		{
+			name: "unknown parameter location",
+			routes: []Route{{Method: http.MethodGet, Path: "/items", Doc: &Doc{
+				ID: "a", Params: []Param{{Name: "session", In: "cookie"}}, Responses: ok,
+			}}},
+		},
+		{
+			name: "duplicate operation id",
+			routes: []Route{
+				{Method: http.MethodGet, Path: "/items", Doc: &Doc{ID: "a", Responses: ok}},
+				{Method: http.MethodPost, Path: "/items", Doc: &Doc{ID: "a", Responses: ok}},
+			},
+		},
+		{
+			name: "duplicate route",
+			routes: []Route{
+				{Method: http.MethodGet, Path: "/items", Doc: &Doc{ID: "a", Responses: ok}},
+				{Method: http.MethodGet, Path: "/items", Doc: &Doc{ID: "b", Responses: ok}},
+			},
+		},
+		{
+			name:   "missing operation id",
+			routes: []Route{{Method: http.MethodGet, Path: "/items", Doc: &Doc{Responses: ok}}},
+		},
+		{
+			name:   "no responses",
+			routes: []Route{{Method: http.MethodGet, Path: "/items", Doc: &Doc{ID: "a"}}},
+		},
+		{
+			name: "duplicate status",
+			routes: []Route{{Method: http.MethodGet, Path: "/items", Doc: &Doc{
+				ID: "a", Responses: []ResponseDoc{{Status: http.StatusOK}, {Status: http.StatusOK}},
+			}}},
+		},
+		{
+			name:   "unsupported method",
+			routes: []Route{{Method: http.MethodOptions, Path: "/items", Doc: &Doc{ID: "a", Responses: ok}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Build(Config{Envelope: envelope{}}, tt.routes); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestBuild_NoEnvelope(t *testing.T) {
+	routes := []Route{{Method: http.MethodGet, Path: "/items", Doc: &Doc{ID: "a", Responses: []ResponseDoc{{Status: http.StatusOK}}}}}
+	if _, err := Build(Config{}, routes); err == nil {
+		t.Fatalf("expected an error for enveloped responses without an envelope")
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "/items", want: nil},
+		{path: "/items/{id}", want: []string{"id"}},
+		{path: "/items/{id}/translations/{locale}", want: []string{"id", "locale"}},
+		{path: "/files/{path...}", want: []string{"path"}},
+		{path: "/{$}", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := PathParams(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	byteSliceType  = reflect.TypeOf([]byte{})
+	binaryType     = reflect.TypeOf(Binary{})
+)
+
+// Binary documents raw bytes that are not JSON-encoded, such as a file part of a multipart body or an image response.
+type Binary []byte
+
+// invalidComponentChars matches characters not allowed in component names, e.g. the brackets of generic types.
+var invalidComponentChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// schemaGenerator derives JSON Schemas from Go types the way encoding/json serializes them. Named struct types become
+// reusable components that are referenced with $ref.
+type schemaGenerator struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+// newSchemaGenerator returns a generator with no components.
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// schemaFor returns the schema of the dynamic type of v, or nil when v is nil.
+func (g *schemaGenerator) schemaFor(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	return g.schemaOf(reflect.TypeOf(v))
+}
+
+// schemaOf returns the schema of t. Named structs are registered as components and returned as a reference.
+func (g *schemaGenerator) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	case byteSliceType:
+		return &Schema{Type: "string", ContentEncoding: "base64"}
+	case binaryType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	default:
+		// Interfaces and anything else encoding/json can hold are described as "any value".
+		return &Schema{}
+	}
+}
+
+// component registers the named struct t as a component, if it is not registered yet, and returns its name.
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	base := invalidComponentChars.ReplaceAllString(path.Base(t.PkgPath())+"."+t.Name(), "_")
+	name := base
+	for n := 2; g.components[name] != nil; n++ {
+		name = base + strconv.Itoa(n)
+	}
+
+	// Register the name before building the schema so recursive types refer to themselves.
+	g.names[t] = name
+	g.components[name] = &Schema{}
+	*g.components[name] = *g.structSchema(t)
+
+	return name
+}
+
+// structSchema returns the inline object schema of struct t, following the encoding/json field rules.
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, asString, ok := jsonField(field)
+		if !ok {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inline := g.structSchema(embedded)
+				for property, propertySchema := range inline.Properties {
+					schema.Properties[property] = propertySchema
+				}
+				schema.Required = append(schema.Required, inline.Required...)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaOf(field.Type)
+		if asString {
+			property = &Schema{Type: "string"}
+		}
+		if description := field.Tag.Get("doc"); description != "" {
+			property = withDescription(property, description)
+		}
+		schema.Properties[name] = property
+
+		if !omitEmpty && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// jsonField parses the json tag of field. ok is false for fields encoding/json ignores.
+func jsonField(field reflect.StructField) (name string, omitEmpty, asString, ok bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false, false, false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		switch option {
+		case "omitempty", "omitzero":
+			omitEmpty = true
+		case "string":
+			asString = true
+		}
+	}
+
+	if !field.IsExported() && parts[0] == "" && field.Type.Kind() != reflect.Struct {
+		return "", false, false, false
+	}
+
+	return parts[0], omitEmpty, asString, true
+}
+
+// withDescription returns schema with description set. References are wrapped because siblings of $ref describe
+// the reference itself rather than the referenced schema in older tools.
+func withDescription(schema *Schema, description string) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Description: description}
+	}
+
+	described := *schema
+	described.Description = description
+	return &described
+}
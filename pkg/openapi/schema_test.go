@@ -0,0 +1,147 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type address struct {
+	Street string `json:"street"`
+}
+
+type base struct {
+	ID int64 `json:"id"`
+}
+
+type sample struct {
+	base
+	Name      string            `json:"name" doc:"Display name"`
+	Note      string            `json:"note,omitempty"`
+	Count     uint              `json:"count"`
+	Ratio     float64           `json:"ratio"`
+	Active    bool              `json:"active"`
+	Created   time.Time         `json:"created"`
+	Raw       json.RawMessage   `json:"raw"`
+	Blob      []byte            `json:"blob"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	Home      *address          `json:"home"`
+	Any       interface{}       `json:"any"`
+	Quoted    int64             `json:"quoted,string"`
+	Skipped   string            `json:"-"`
+	NoTag     string
+	unexposed string
+}
+
+type node struct {
+	Children []node `json:"children"`
+}
+
+func TestSchemaGenerator_Primitives(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  *Schema
+	}{
+		{name: "bool", value: true, want: &Schema{Type: "boolean"}},
+		{name: "int", value: 1, want: &Schema{Type: "integer", Format: "int64"}},
+		{name: "int32", value: int32(1), want: &Schema{Type: "integer", Format: "int32"}},
+		{name: "float64", value: 1.5, want: &Schema{Type: "number", Format: "double"}},
+		{name: "string", value: "", want: &Schema{Type: "string"}},
+		{name: "time", value: time.Time{}, want: &Schema{Type: "string", Format: "date-time"}},
+		{name: "bytes", value: []byte{}, want: &Schema{Type: "string", ContentEncoding: "base64"}},
+		{name: "binary", value: Binary{}, want: &Schema{Type: "string", Format: "binary"}},
+		{name: "slice", value: []string{}, want: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+		{name: "map", value: map[string]bool{}, want: &Schema{Type: "object", AdditionalProperties: &Schema{Type: "boolean"}}},
+		{name: "pointer", value: new(string), want: &Schema{Type: "string"}},
+		{name: "any", value: map[string]interface{}{}, want: &Schema{Type: "object", AdditionalProperties: &Schema{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newSchemaGenerator()
+			got := g.schemaFor(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			if len(g.components) != 0 {
+				t.Fatalf("expected no components, got %v", g.components)
+			}
+		})
+	}
+}
+
+func TestSchemaGenerator_Struct(t *testing.T) {
+	g := newSchemaGenerator()
+
+	got := g.schemaFor(sample{})
+	if got.Ref != "#/components/schemas/openapi.sample" {
+		t.Fatalf("expected reference to openapi.sample, got %+v", got)
+	}
+
+	schema := g.components["openapi.sample"]
+	if schema == nil {
+		t.Fatalf("expected component openapi.sample, got %v", g.components)
+	}
+
+	wantProperties := []string{"id", "name", "note", "count", "ratio", "active", "created", "raw", "blob", "tags", "labels", "home", "any", "quoted", "NoTag"}
+	if len(schema.Properties) != len(wantProperties) {
+		t.Fatalf("expected properties %v, got %v", wantProperties, schema.Properties)
+	}
+	for _, name := range wantProperties {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("expected property %q", name)
+		}
+	}
+
+	tests := []struct {
+		property string
+		want     *Schema
+	}{
+		{property: "name", want: &Schema{Type: "string", Description: "Display name"}},
+		{property: "home", want: &Schema{Ref: "#/components/schemas/openapi.address"}},
+		{property: "quoted", want: &Schema{Type: "string"}},
+		{property: "raw", want: &Schema{}},
+	}
+	for _, tt := range tests {
+		if got := schema.Properties[tt.property]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("property %q: expected %+v, got %+v", tt.property, tt.want, got)
+		}
+	}
+
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, name := range []string{"id", "name", "count"} {
+		if !required[name] {
+			t.Errorf("expected %q to be required, got %v", name, schema.Required)
+		}
+	}
+	for _, name := range []string{"note", "home"} {
+		if required[name] {
+			t.Errorf("expected %q to be optional, got %v", name, schema.Required)
+		}
+	}
+
+	if _, ok := g.components["openapi.address"]; !ok {
+		t.Errorf("expected component openapi.address, got %v", g.components)
+	}
+}
+
+func TestSchemaGenerator_Recursive(t *testing.T) {
+	g := newSchemaGenerator()
+	g.schemaFor(node{})
+
+	schema := g.components["openapi.node"]
+	if schema == nil {
+		t.Fatalf("expected component openapi.node, got %v", g.components)
+	}
+
+	want := &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/openapi.node"}}
+	if got := schema.Properties["children"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,81 @@
+// Package pagination reads the page of a list a client asks for from the page and per_page query parameters and
+// describes the other pages in the X-Total-Count and Link response headers.
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPerPage is the number of items on a page when no per_page is given.
+	DefaultPerPage = 20
+
+	// MaxPerPage is the largest page size a client may request.
+	MaxPerPage = 100
+
+	// TotalCountHeader carries the number of items of a paginated list across all pages.
+	TotalCountHeader = "X-Total-Count"
+)
+
+// FromQuery returns the page and page size asked for in query, defaulting to the first page of DefaultPerPage.
+func FromQuery(query url.Values) (page, perPage int, err error) {
+	page, perPage = 1, DefaultPerPage
+	if value := query.Get("page"); value != "" {
+		if page, err = strconv.Atoi(value); err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("page must be a number from 1")
+		}
+	}
+	if value := query.Get("per_page"); value != "" {
+		if perPage, err = strconv.Atoi(value); err != nil || perPage < 1 || perPage > MaxPerPage {
+			return 0, 0, fmt.Errorf("per_page must be a number from 1 to %d", MaxPerPage)
+		}
+	}
+	return page, perPage, nil
+}
+
+// Offset returns the number of items before page.
+func Offset(page, perPage int) int {
+	return (page - 1) * perPage
+}
+
+// Links returns the RFC 8288 Link header pointing from page of a list of total items to its first, previous, next
+// and last pages, all relative to u.
+func Links(u *url.URL, page, perPage, total int) string {
+	last := max((total+perPage-1)/perPage, 1)
+
+	link := func(page int, rel string) string {
+		query := u.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(perPage))
+		target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", target.String(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if page > 1 {
+		links = append(links, link(min(page-1, last), "prev"))
+	}
+	if page < last {
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(last, "last"))
+	return strings.Join(links, ", ")
+}
+
+// SetHeaders sets the TotalCountHeader and Link headers of the response to r, which lists page of total items.
+// The links keep the path r was sent to, before any route prefix was stripped, and its other query parameters.
+func SetHeaders(w http.ResponseWriter, r *http.Request, page, perPage, total int) {
+	u := r.URL
+	if r.RequestURI != "" {
+		if requested, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			u = requested
+		}
+	}
+
+	w.Header().Set(TotalCountHeader, strconv.Itoa(total))
+	w.Header().Set("Link", Links(u, page, perPage, total))
+}
@@ -0,0 +1,91 @@
+package pagination
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantPage    int
+		wantPerPage int
+		wantErr     bool
+	}{
+		{name: "defaults", wantPage: 1, wantPerPage: DefaultPerPage},
+		{name: "explicit", query: "page=3&per_page=5", wantPage: 3, wantPerPage: 5},
+		{name: "largest page size", query: "per_page=100", wantPage: 1, wantPerPage: MaxPerPage},
+		{name: "page zero", query: "page=0", wantErr: true},
+		{name: "page not a number", query: "page=two", wantErr: true},
+		{name: "page size too large", query: "per_page=101", wantErr: true},
+		{name: "page size zero", query: "per_page=0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			page, perPage, err := FromQuery(query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if page != tt.wantPage || perPage != tt.wantPerPage {
+				t.Errorf("expected page %d of %d, got %d of %d", tt.wantPage, tt.wantPerPage, page, perPage)
+			}
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	u, _ := url.Parse("/api/v1/products?lang=en")
+
+	tests := []struct {
+		name    string
+		page    int
+		perPage int
+		total   int
+		want    string
+	}{
+		{
+			name: "empty", page: 1, perPage: 20, total: 0,
+			want: `</api/v1/products?lang=en&page=1&per_page=20>; rel="first", </api/v1/products?lang=en&page=1&per_page=20>; rel="last"`,
+		},
+		{
+			name: "middle", page: 2, perPage: 2, total: 5,
+			want: `</api/v1/products?lang=en&page=1&per_page=2>; rel="first", </api/v1/products?lang=en&page=1&per_page=2>; rel="prev", ` +
+				`</api/v1/products?lang=en&page=3&per_page=2>; rel="next", </api/v1/products?lang=en&page=3&per_page=2>; rel="last"`,
+		},
+		{
+			name: "past the end", page: 9, perPage: 2, total: 3,
+			want: `</api/v1/products?lang=en&page=1&per_page=2>; rel="first", </api/v1/products?lang=en&page=2&per_page=2>; rel="prev", ` +
+				`</api/v1/products?lang=en&page=2&per_page=2>; rel="last"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Links(u, tt.page, tt.perPage, tt.total); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	// Routers strip their prefix from r.URL, but links must point at the path the client used.
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/products?per_page=10", nil)
+	r.URL.Path = "/products"
+	w := httptest.NewRecorder()
+
+	SetHeaders(w, r, 1, 10, 4)
+
+	if got := w.Header().Get(TotalCountHeader); got != "4" {
+		t.Errorf("expected %s: 4, got %q", TotalCountHeader, got)
+	}
+	want := `</api/v1/products?page=1&per_page=10>; rel="first", </api/v1/products?page=1&per_page=10>; rel="last"`
+	if got := w.Header().Get("Link"); got != want {
+		t.Errorf("expected Link %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,142 @@
+// Package problem writes error responses as RFC 7807 problem details (application/problem+json).
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MediaType is the content type of problem details.
+const MediaType = "application/problem+json"
+
+// typePrefix starts the type URI of every problem; the rest is derived from its message code.
+const typePrefix = "urn:problem-type:categories-api:"
+
+// Problem describes an error as RFC 7807 problem details. Type identifies the kind of problem and Title is its
+// short, human-readable summary; Detail explains this occurrence and Instance names the request it happened on.
+// Extensions are written as additional members next to the standard ones, which they cannot override.
+type Problem struct {
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+// standardMembers are the members defined by RFC 7807, which extensions cannot use.
+var standardMembers = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true}
+
+// TypeURI returns the type URI of the problem with the given message code, e.g.
+// "urn:problem-type:categories-api:category-not-found" for CATEGORY_NOT_FOUND.
+func TypeURI(code string) string {
+	return typePrefix + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// Error returns the title and detail of the problem.
+func (p Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+	return fmt.Sprintf("%s: %s", p.Title, p.Detail)
+}
+
+// MarshalJSON encodes the standard members followed by the extensions.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type standard Problem
+	content, err := json.Marshal(standard(p))
+	if err != nil {
+		return nil, err
+	}
+
+	extensions := make(map[string]any, len(p.Extensions))
+	for name, value := range p.Extensions {
+		if !standardMembers[name] {
+			extensions[name] = value
+		}
+	}
+	if len(extensions) == 0 {
+		return content, nil
+	}
+
+	more, err := json.Marshal(extensions)
+	if err != nil {
+		return nil, err
+	}
+	// Both are JSON objects: drop the closing brace of the first and the opening brace of the second.
+	return append(append(content[:len(content)-1], ','), more[1:]...), nil
+}
+
+// UnmarshalJSON decodes the standard members and keeps every other member in Extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	type standard Problem
+	var decoded standard
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for name, value := range members {
+		if standardMembers[name] {
+			continue
+		}
+		var extension any
+		if err := json.Unmarshal(value, &extension); err != nil {
+			return err
+		}
+		if decoded.Extensions == nil {
+			decoded.Extensions = make(map[string]any)
+		}
+		decoded.Extensions[name] = extension
+	}
+
+	*p = Problem(decoded)
+	return nil
+}
+
+// Localizer translates a message code into text in the language preferred by the client making r.
+type Localizer interface {
+	Localize(r *http.Request, code string, args ...any) string
+}
+
+// WriteMessage answers r with the problem for the message code, whose text localized by l is the title, and the
+// given detail. The code is added to extensions as "code"; args format the title. Instance is the path the client
+// requested, before any route prefix was stripped.
+func WriteMessage(w http.ResponseWriter, r *http.Request, l Localizer, status int, code, detail string, extensions map[string]any, args ...any) {
+	if extensions == nil {
+		extensions = make(map[string]any, 1)
+	}
+	extensions["code"] = code
+
+	Write(w, Problem{
+		Type:       TypeURI(code),
+		Title:      l.Localize(r, code, args...),
+		Status:     status,
+		Detail:     detail,
+		Instance:   requestPath(r),
+		Extensions: extensions,
+	})
+}
+
+// requestPath returns the path of r as the client sent it.
+func requestPath(r *http.Request) string {
+	if r.RequestURI != "" {
+		if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			return u.Path
+		}
+	}
+	return r.URL.Path
+}
+
+// Write writes p as an application/problem+json response with its status.
+func Write(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,144 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProblem_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem Problem
+		want    string
+	}{
+		{
+			name:    "standard members",
+			problem: Problem{Type: "urn:x", Title: "Not found", Status: http.StatusNotFound},
+			want:    `{"type":"urn:x","title":"Not found","status":404}`,
+		},
+		{
+			name: "extensions",
+			problem: Problem{
+				Type: "urn:x", Title: "Invalid", Status: http.StatusBadRequest, Detail: "bad field", Instance: "/items",
+				Extensions: map[string]any{"code": "INVALID", "line": 2},
+			},
+			want: `{"type":"urn:x","title":"Invalid","status":400,"detail":"bad field","instance":"/items","code":"INVALID","line":2}`,
+		},
+		{
+			name:    "extensions cannot override standard members",
+			problem: Problem{Type: "urn:x", Title: "Gone", Status: http.StatusGone, Extensions: map[string]any{"status": 200}},
+			want:    `{"type":"urn:x","title":"Gone","status":410}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.problem)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProblem_UnmarshalJSON(t *testing.T) {
+	var got Problem
+	err := json.Unmarshal([]byte(`{"type":"urn:x","title":"Invalid","status":400,"code":"INVALID","field":"name"}`), &got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Problem{
+		Type: "urn:x", Title: "Invalid", Status: http.StatusBadRequest,
+		Extensions: map[string]any{"code": "INVALID", "field": "name"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestTypeURI(t *testing.T) {
+	if got := TypeURI("CATEGORY_NOT_FOUND"); got != "urn:problem-type:categories-api:category-not-found" {
+		t.Errorf("unexpected type uri %s", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	w := httptest.NewRecorder()
+	Write(w, Problem{Type: "urn:x", Title: "Conflict", Status: http.StatusConflict})
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != MediaType {
+		t.Errorf("expected content type %s, got %s", MediaType, got)
+	}
+	if got := strings.TrimSpace(w.Body.String()); got != `{"type":"urn:x","title":"Conflict","status":409}` {
+		t.Errorf("unexpected body %s", got)
+	}
+}
+
+// localizer localizes every code to "<code> in <lang>", with the language taken from the lang query parameter.
+type localizer struct{}
+
+func (localizer) Localize(r *http.Request, code string, args ...any) string {
+	return code + " in " + r.URL.Query().Get("lang")
+}
+
+func TestWriteMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		stripped   string
+		extensions map[string]any
+		want       Problem
+	}{
+		{
+			name:   "localized title",
+			target: "/api/v2/categories/9?lang=en",
+			want: Problem{
+				Type: "urn:problem-type:categories-api:category-not-found", Title: "CATEGORY_NOT_FOUND in en",
+				Status: http.StatusNotFound, Detail: "no such id", Instance: "/api/v2/categories/9",
+				Extensions: map[string]any{"code": "CATEGORY_NOT_FOUND"},
+			},
+		},
+		{
+			name:       "prefix stripped",
+			target:     "/api/v2/categories/9?lang=id",
+			stripped:   "/categories/9",
+			extensions: map[string]any{"id": "9"},
+			want: Problem{
+				Type: "urn:problem-type:categories-api:category-not-found", Title: "CATEGORY_NOT_FOUND in id",
+				Status: http.StatusNotFound, Detail: "no such id", Instance: "/api/v2/categories/9",
+				Extensions: map[string]any{"code": "CATEGORY_NOT_FOUND", "id": "9"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.stripped != "" {
+				req.URL.Path = tt.stripped
+			}
+			w := httptest.NewRecorder()
+
+			WriteMessage(w, req, localizer{}, http.StatusNotFound, "CATEGORY_NOT_FOUND", "no such id", tt.extensions)
+
+			var got Problem
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
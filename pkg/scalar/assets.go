@@ -0,0 +1,79 @@
+package scalar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BundleVersion is the version of @scalar/api-reference vendored as BundleName.
+const BundleVersion = "1.25.0"
+
+// BundleName is the file name of the vendored Scalar browser bundle in the assets directory.
+const BundleName = "standalone.js"
+
+// BundleChecksumName is the file in the assets directory pinning the SHA-256 of BundleName, in sha256sum format.
+const BundleChecksumName = BundleName + ".sha256"
+
+// The download is pinned to BundleVersion and checked against the committed checksum; to update the bundle, change
+// both the version here and the checksum file.
+//
+//go:generate sh -c "curl -fsSL -o assets/.standalone.js.tmp https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.25.0/dist/browser/standalone.js && cd assets && sed 's/  standalone.js/  .standalone.js.tmp/' standalone.js.sha256 | sha256sum -c - && mv .standalone.js.tmp standalone.js"
+
+// assets holds the vendored Scalar assets.
+//
+//go:embed assets
+var assets embed.FS
+
+// assetsModTime is reported as the modification time of the embedded bundle, which embed.FS does not record.
+var assetsModTime = time.Now()
+
+// bundle reads and verifies the embedded bundle once.
+var bundle = sync.OnceValues(func() ([]byte, bool) {
+	content, err := fs.ReadFile(assets, "assets/"+BundleName)
+	if err != nil {
+		return nil, false
+	}
+	checksum, err := fs.ReadFile(assets, "assets/"+BundleChecksumName)
+	if err != nil || !verifyChecksum(content, string(checksum)) {
+		return nil, false
+	}
+	return content, true
+})
+
+// Bundle returns the vendored Scalar browser bundle and whether it is vendored and matches its pinned checksum.
+func Bundle() ([]byte, bool) {
+	return bundle()
+}
+
+// verifyChecksum reports whether content hashes to the SHA-256 in checksum, a line in sha256sum format.
+func verifyChecksum(content []byte, checksum string) bool {
+	fields := strings.Fields(checksum)
+	if len(fields) == 0 {
+		return false
+	}
+	sum := sha256.Sum256(content)
+	return strings.EqualFold(fields[0], hex.EncodeToString(sum[:]))
+}
+
+// BundleHandler serves the vendored Scalar browser bundle, answering 404 when it is not vendored.
+// Mount it at the URL given as Options.CDN.
+func BundleHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := Bundle()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		http.ServeContent(w, r, BundleName, assetsModTime, bytes.NewReader(content))
+	})
+}
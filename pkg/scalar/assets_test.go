@@ -0,0 +1,79 @@
+package scalar
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAssets_EmbedReadme(t *testing.T) {
+	if _, err := fs.Stat(assets, "assets/README.md"); err != nil {
+		t.Fatalf("expected the assets README to be embedded: %v", err)
+	}
+}
+
+func TestBundle(t *testing.T) {
+	if _, ok := Bundle(); !ok {
+		t.Fatalf("expected %s to be vendored and to match %s; run go generate ./pkg/scalar", BundleName, BundleChecksumName)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	// The SHA-256 of "scalar".
+	const sum = "da3a456ba70b340ad4038f5748ed4b17f832208abfec2d454ea1f9f4b6985ceb"
+
+	tests := []struct {
+		name     string
+		content  string
+		checksum string
+		want     bool
+	}{
+		{name: "match", content: "scalar", checksum: sum + "  standalone.js\n", want: true},
+		{name: "upper case", content: "scalar", checksum: strings.ToUpper(sum), want: true},
+		{name: "mismatch", content: "scalar!", checksum: sum + "  standalone.js\n"},
+		{name: "empty", content: "scalar", checksum: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyChecksum([]byte(tt.content), tt.checksum); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBundleHandler(t *testing.T) {
+	content, vendored := Bundle()
+	if !vendored {
+		t.Fatalf("expected %s to be vendored; run go generate ./pkg/scalar", BundleName)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		wantStatus int
+	}{
+		{name: "get", method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "head", method: http.MethodHead, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			BundleHandler().ServeHTTP(rec, httptest.NewRequest(tt.method, "/"+BundleName, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "text/javascript; charset=utf-8" {
+				t.Fatalf("expected javascript content type, got %q", got)
+			}
+			if tt.method == http.MethodGet && rec.Body.Len() != len(content) {
+				t.Fatalf("expected %d bytes, got %d", len(content), rec.Body.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package scalar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFetchTimeout bounds a whole spec download, including redirects and reading the body.
+	DefaultFetchTimeout = 10 * time.Second
+
+	// DefaultMaxSpecSize is the largest spec, in bytes, a Fetcher downloads unless configured otherwise.
+	DefaultMaxSpecSize int64 = 5 << 20
+
+	// DefaultCacheSize is the number of bytes of fetched specs a Fetcher keeps for revalidation unless configured
+	// otherwise.
+	DefaultCacheSize = 4 * DefaultMaxSpecSize
+)
+
+var (
+	// ErrHostNotAllowed is returned for spec URLs, or redirects, to a scheme or host the Fetcher does not allow.
+	ErrHostNotAllowed = errors.New("scalar: spec host not allowed")
+
+	// ErrSpecTooLarge is returned when a spec is larger than the Fetcher's maximum size.
+	ErrSpecTooLarge = errors.New("scalar: spec too large")
+
+	// ErrUnexpectedStatus is returned when the spec server answers with a status other than 2xx or 304.
+	ErrUnexpectedStatus = errors.New("scalar: unexpected status fetching spec")
+)
+
+// defaultFetcher fetches remote specs for Options without a Fetcher, from the host of Options.SpecURL only.
+var defaultFetcher = &Fetcher{}
+
+// Fetcher downloads remote specs referenced by Options.SpecURL. Downloads are bounded in time and size, limited to
+// http and https URLs on AllowedHosts, and revalidated with ETags so an unchanged spec is not downloaded again.
+// The zero value is ready to use and allows no host. A Fetcher is safe for concurrent use.
+type Fetcher struct {
+	// Client sends the requests. Nil means http.DefaultTransport.
+	Client *http.Client
+
+	// Timeout bounds each download. Zero means DefaultFetchTimeout.
+	Timeout time.Duration
+
+	// MaxSize is the largest spec, in bytes, that is accepted. Zero means DefaultMaxSpecSize.
+	MaxSize int64
+
+	// AllowedHosts lists the hosts specs may be fetched from, as a host name or host:port. Matching ignores case.
+	// Empty allows no host. Redirects are checked against the list too.
+	AllowedHosts []string
+
+	// CacheSize is the number of bytes of fetched specs kept for revalidation; the oldest specs are dropped first.
+	// Zero means DefaultCacheSize.
+	CacheSize int64
+
+	mu         sync.Mutex
+	cache      map[string]cachedSpec
+	cacheOrder []string
+	cacheBytes int64
+}
+
+// cachedSpec is a previously fetched spec together with the ETag it was served with.
+type cachedSpec struct {
+	etag    string
+	content string
+}
+
+// Fetch downloads the spec at specURL, giving up when ctx is done. When the spec was fetched before with an ETag,
+// the request is conditional and a 304 answer returns the cached content.
+func (f *Fetcher) Fetch(ctx context.Context, specURL string) (string, error) {
+	return f.fetch(ctx, specURL, f.AllowedHosts)
+}
+
+// fetch downloads the spec at specURL like Fetch, allowing the hosts in allowed.
+func (f *Fetcher) fetch(ctx context.Context, specURL string, allowed []string) (string, error) {
+	u, err := url.Parse(specURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing spec url: %w", err)
+	}
+	if err := checkURL(u, allowed); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating spec request: %w", err)
+	}
+
+	cached, hasCached := f.cached(specURL)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := f.client(allowed).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error getting file content: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		return cached.content, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%w: %s from %s", ErrUnexpectedStatus, resp.Status, u.Redacted())
+	}
+
+	maxSize := f.maxSize()
+	if resp.ContentLength > maxSize {
+		return "", fmt.Errorf("%w: %d bytes, limit is %d", ErrSpecTooLarge, resp.ContentLength, maxSize)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("error reading file content: %w", err)
+	}
+	if int64(len(content)) > maxSize {
+		return "", fmt.Errorf("%w: limit is %d bytes", ErrSpecTooLarge, maxSize)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		f.store(specURL, cachedSpec{etag: etag, content: string(content)})
+	}
+
+	return string(content), nil
+}
+
+// checkURL reports whether u may be fetched: it must be an http or https URL on one of the allowed hosts.
+func checkURL(u *url.URL, allowed []string) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: unsupported scheme %q", ErrHostNotAllowed, u.Scheme)
+	}
+
+	for _, host := range allowed {
+		if strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname()) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Host)
+}
+
+// client returns the configured client, with redirects checked against the allowed hosts.
+func (f *Fetcher) client(allowed []string) *http.Client {
+	var client http.Client
+	if f.Client != nil {
+		client = *f.Client
+	}
+
+	next := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := checkURL(req.URL, allowed); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+
+	return &client
+}
+
+// timeout returns the configured timeout or DefaultFetchTimeout.
+func (f *Fetcher) timeout() time.Duration {
+	if f.Timeout > 0 {
+		return f.Timeout
+	}
+	return DefaultFetchTimeout
+}
+
+// maxSize returns the configured maximum spec size or DefaultMaxSpecSize.
+func (f *Fetcher) maxSize() int64 {
+	if f.MaxSize > 0 {
+		return f.MaxSize
+	}
+	return DefaultMaxSpecSize
+}
+
+// cached returns the spec previously fetched from specURL, if any.
+func (f *Fetcher) cached(specURL string) (cachedSpec, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	spec, ok := f.cache[specURL]
+	return spec, ok
+}
+
+// store remembers the spec fetched from specURL for revalidation, dropping the oldest specs to stay within the
+// cache size. Specs larger than the whole cache are not kept.
+func (f *Fetcher) store(specURL string, spec cachedSpec) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.forget(specURL)
+
+	limit := f.cacheSize()
+	size := int64(len(spec.content))
+	if size > limit {
+		return
+	}
+	for f.cacheBytes+size > limit && len(f.cacheOrder) > 0 {
+		f.forget(f.cacheOrder[0])
+	}
+
+	if f.cache == nil {
+		f.cache = make(map[string]cachedSpec)
+	}
+	f.cache[specURL] = spec
+	f.cacheOrder = append(f.cacheOrder, specURL)
+	f.cacheBytes += size
+}
+
+// forget drops the spec fetched from specURL from the cache. The caller must hold f.mu.
+func (f *Fetcher) forget(specURL string) {
+	spec, ok := f.cache[specURL]
+	if !ok {
+		return
+	}
+
+	delete(f.cache, specURL)
+	f.cacheBytes -= int64(len(spec.content))
+	for i, cached := range f.cacheOrder {
+		if cached == specURL {
+			f.cacheOrder = append(f.cacheOrder[:i], f.cacheOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// cacheSize returns the configured cache size or DefaultCacheSize.
+func (f *Fetcher) cacheSize() int64 {
+	if f.CacheSize > 0 {
+		return f.CacheSize
+	}
+	return DefaultCacheSize
+}
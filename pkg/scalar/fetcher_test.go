@@ -0,0 +1,211 @@
+package scalar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetcher_Fetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("x", 64))
+	})
+	mux.HandleFunc("/chunked", func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 8; i++ {
+			_, _ = io.WriteString(w, strings.Repeat("x", 8))
+			w.(http.Flusher).Flush()
+		}
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.invalid/spec.json", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	cases := []struct {
+		name    string
+		fetcher *Fetcher
+		url     string
+		want    string
+		wantErr error
+	}{
+		{name: "ok", fetcher: &Fetcher{AllowedHosts: []string{host}}, url: server.URL + "/ok", want: "ok"},
+		{name: "bad", fetcher: &Fetcher{AllowedHosts: []string{host}}, url: "http://[::1"},
+		{name: "scheme", fetcher: &Fetcher{AllowedHosts: []string{host}}, url: "file:///etc/passwd", wantErr: ErrHostNotAllowed},
+		{name: "no allowed hosts", fetcher: &Fetcher{}, url: server.URL + "/ok", wantErr: ErrHostNotAllowed},
+		{name: "status", fetcher: &Fetcher{AllowedHosts: []string{host}}, url: server.URL + "/missing", wantErr: ErrUnexpectedStatus},
+		{name: "too large", fetcher: &Fetcher{AllowedHosts: []string{host}, MaxSize: 16}, url: server.URL + "/large", wantErr: ErrSpecTooLarge},
+		{name: "too large chunked", fetcher: &Fetcher{AllowedHosts: []string{host}, MaxSize: 16}, url: server.URL + "/chunked", wantErr: ErrSpecTooLarge},
+		{name: "within size", fetcher: &Fetcher{AllowedHosts: []string{host}, MaxSize: 64}, url: server.URL + "/large", want: strings.Repeat("x", 64)},
+		{name: "timeout", fetcher: &Fetcher{AllowedHosts: []string{host}, Timeout: 20 * time.Millisecond}, url: server.URL + "/slow", wantErr: context.DeadlineExceeded},
+		{name: "allowed host name", fetcher: &Fetcher{AllowedHosts: []string{"127.0.0.1"}}, url: server.URL + "/ok", want: "ok"},
+		{name: "disallowed host", fetcher: &Fetcher{AllowedHosts: []string{"docs.example.com"}}, url: server.URL + "/ok", wantErr: ErrHostNotAllowed},
+		{name: "disallowed redirect", fetcher: &Fetcher{AllowedHosts: []string{host}}, url: server.URL + "/redirect", wantErr: ErrHostNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.fetcher.Fetch(context.Background(), tc.url)
+			if tc.want == "" {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %q got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFetcher_FetchETag(t *testing.T) {
+	var requests, notModified atomic.Int32
+	spec := "v1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		etag := `"` + spec + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = io.WriteString(w, spec)
+	}))
+	defer server.Close()
+
+	fetcher := &Fetcher{AllowedHosts: []string{strings.TrimPrefix(server.URL, "http://")}}
+	steps := []struct {
+		name            string
+		spec            string
+		want            string
+		wantNotModified int32
+	}{
+		{name: "first fetch", spec: "v1", want: "v1", wantNotModified: 0},
+		{name: "unchanged", spec: "v1", want: "v1", wantNotModified: 1},
+		{name: "changed", spec: "v2", want: "v2", wantNotModified: 1},
+		{name: "unchanged again", spec: "v2", want: "v2", wantNotModified: 2},
+	}
+
+	for i, step := range steps {
+		spec = step.spec
+		got, err := fetcher.Fetch(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("%s: Fetch error: %v", step.name, err)
+		}
+		if got != step.want {
+			t.Fatalf("%s: want %q got %q", step.name, step.want, got)
+		}
+		if requests.Load() != int32(i+1) {
+			t.Fatalf("%s: expected %d requests, got %d", step.name, i+1, requests.Load())
+		}
+		if notModified.Load() != step.wantNotModified {
+			t.Fatalf("%s: expected %d not modified answers, got %d", step.name, step.wantNotModified, notModified.Load())
+		}
+	}
+}
+
+func TestFetcher_UsesClient(t *testing.T) {
+	var used atomic.Bool
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		used.Store(true)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("custom")),
+			Header:     http.Header{},
+			Request:    r,
+		}, nil
+	})}
+
+	u := &url.URL{Scheme: "https", Host: "specs.example.com", Path: "/openapi.json"}
+	got, err := (&Fetcher{Client: client, AllowedHosts: []string{u.Host}}).Fetch(context.Background(), u.String())
+	if err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if got != "custom" || !used.Load() {
+		t.Fatalf("expected the custom client to be used, got %q", got)
+	}
+}
+
+func TestFetcher_FetchCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	fetcher := &Fetcher{AllowedHosts: []string{strings.TrimPrefix(server.URL, "http://")}}
+	if _, err := fetcher.Fetch(ctx, server.URL); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the fetch to end with its context, got %v", err)
+	}
+}
+
+func TestFetcher_CacheSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+		_, _ = io.WriteString(w, strings.Repeat("x", 10))
+	}))
+	defer server.Close()
+
+	fetcher := &Fetcher{AllowedHosts: []string{strings.TrimPrefix(server.URL, "http://")}, CacheSize: 25}
+	for _, path := range []string{"/a", "/b", "/c"} {
+		if _, err := fetcher.Fetch(t.Context(), server.URL+path); err != nil {
+			t.Fatalf("Fetch error: %v", err)
+		}
+	}
+
+	if fetcher.cacheBytes != 20 || len(fetcher.cache) != 2 {
+		t.Fatalf("expected the cache to hold 2 specs in 20 bytes, got %d in %d", len(fetcher.cache), fetcher.cacheBytes)
+	}
+	if _, ok := fetcher.cached(server.URL + "/a"); ok {
+		t.Errorf("expected the oldest spec to be dropped")
+	}
+	if _, ok := fetcher.cached(server.URL + "/c"); !ok {
+		t.Errorf("expected the newest spec to be kept")
+	}
+
+	small := &Fetcher{AllowedHosts: fetcher.AllowedHosts, CacheSize: 5}
+	if _, err := small.Fetch(t.Context(), server.URL+"/a"); err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if len(small.cache) != 0 {
+		t.Errorf("expected a spec larger than the cache not to be kept")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,168 @@
+package scalar
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultSwaggerUICDN is the base URL the Swagger UI renderer loads swagger-ui-dist from.
+	DefaultSwaggerUICDN = "https://unpkg.com/swagger-ui-dist@5"
+
+	// DefaultRedocCDN is the URL the Redoc renderer loads the Redoc standalone bundle from.
+	DefaultRedocCDN = "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+)
+
+// Renderer renders an API reference page. spec is the page's spec as returned by LoadSpec: compact JSON that is
+// safe to place in a script element. Renderers must escape every other value taken from options.
+type Renderer interface {
+	Render(options *Options, spec []byte) (string, error)
+}
+
+// renderers maps the names accepted by RendererByName to their renderer with default settings.
+var renderers = map[string]Renderer{
+	"scalar":     Scalar{},
+	"swagger-ui": SwaggerUI{},
+	"redoc":      Redoc{},
+}
+
+// RendererByName returns the renderer with default settings registered under name: "scalar", "swagger-ui" or
+// "redoc". Matching ignores case.
+func RendererByName(name string) (Renderer, error) {
+	renderer, ok := renderers[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(renderers))
+		for name := range renderers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown api reference renderer %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return renderer, nil
+}
+
+// RenderHTML loads the spec configured by optionsInput and renders it with renderer. Loading a remote spec is
+// abandoned when ctx is done.
+func RenderHTML(ctx context.Context, renderer Renderer, optionsInput *Options) (string, error) {
+	if optionsInput == nil {
+		return "", fmt.Errorf("options must be provided")
+	}
+
+	options := DefaultOptions(*optionsInput)
+
+	spec, err := LoadSpec(ctx, options)
+	if err != nil {
+		return "", err
+	}
+
+	return renderer.Render(options, spec)
+}
+
+// pageTitle returns the escaped page title of options or fallback when none is set.
+func pageTitle(options *Options, fallback string) string {
+	if options.CustomOptions.PageTitle != "" {
+		return html.EscapeString(options.CustomOptions.PageTitle)
+	}
+	return html.EscapeString(fallback)
+}
+
+// Scalar renders the Scalar API reference, loading Scalar from Options.CDN and configuring it with Options.
+type Scalar struct{}
+
+// Render renders the Scalar page for spec.
+func (Scalar) Render(options *Options, spec []byte) (string, error) {
+	// The spec is the content of the page, so the configuration does not repeat it or make Scalar fetch it again.
+	config := *options
+	config.SpecURL = ""
+	config.SpecContent = nil
+
+	customThemeCss := CustomThemeCSS
+	if options.Theme != "" {
+		customThemeCss = ""
+	}
+
+	return fmt.Sprintf(`
+    <!DOCTYPE html>
+    <html>
+      <head>
+        <title>%s</title>
+        <meta charset="utf-8" />
+        <meta name="viewport" content="width=device-width, initial-scale=1" />
+        <style>%s</style>
+      </head>
+      <body>
+        <script id="api-reference" type="application/json" data-configuration="%s">%s</script>
+        <script src="%s"></script>
+      </body>
+    </html>
+  `, pageTitle(options, "Scalar API Reference"), customThemeCss, safeJSONConfiguration(&config), spec, html.EscapeString(options.CDN)), nil
+}
+
+// SwaggerUI renders the spec with Swagger UI. CDN is the base URL of swagger-ui-dist; empty means
+// DefaultSwaggerUICDN.
+type SwaggerUI struct {
+	CDN string
+}
+
+// Render renders the Swagger UI page for spec.
+func (s SwaggerUI) Render(options *Options, spec []byte) (string, error) {
+	cdn := s.CDN
+	if cdn == "" {
+		cdn = DefaultSwaggerUICDN
+	}
+	cdn = html.EscapeString(strings.TrimSuffix(cdn, "/"))
+
+	return fmt.Sprintf(`
+    <!DOCTYPE html>
+    <html>
+      <head>
+        <title>%s</title>
+        <meta charset="utf-8" />
+        <meta name="viewport" content="width=device-width, initial-scale=1" />
+        <link rel="stylesheet" href="%s/swagger-ui.css" />
+      </head>
+      <body>
+        <div id="swagger-ui"></div>
+        <script crossorigin src="%s/swagger-ui-bundle.js"></script>
+        <script>
+          window.ui = SwaggerUIBundle({ spec: %s, dom_id: "#swagger-ui" });
+        </script>
+      </body>
+    </html>
+  `, pageTitle(options, "Swagger UI"), cdn, cdn, spec), nil
+}
+
+// Redoc renders the spec with Redoc. CDN is the URL of the Redoc standalone bundle; empty means DefaultRedocCDN.
+type Redoc struct {
+	CDN string
+}
+
+// Render renders the Redoc page for spec.
+func (r Redoc) Render(options *Options, spec []byte) (string, error) {
+	cdn := r.CDN
+	if cdn == "" {
+		cdn = DefaultRedocCDN
+	}
+
+	return fmt.Sprintf(`
+    <!DOCTYPE html>
+    <html>
+      <head>
+        <title>%s</title>
+        <meta charset="utf-8" />
+        <meta name="viewport" content="width=device-width, initial-scale=1" />
+        <style>body { margin: 0; }</style>
+      </head>
+      <body>
+        <div id="redoc"></div>
+        <script src="%s"></script>
+        <script>
+          Redoc.init(%s, {}, document.getElementById("redoc"));
+        </script>
+      </body>
+    </html>
+  `, pageTitle(options, "Redoc"), html.EscapeString(cdn), spec), nil
+}
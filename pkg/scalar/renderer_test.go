@@ -0,0 +1,137 @@
+package scalar
+
+import (
+	"strings"
+	"testing"
+)
+
+const maliciousTitle = `</title><script>alert("title")</script>`
+
+const maliciousSpec = `{"info": {"title": "</script><script>alert('spec')</script>"}}`
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		name         string
+		renderer     Renderer
+		wantContains []string
+	}{
+		{
+			name:     "scalar",
+			renderer: Scalar{},
+			wantContains: []string{
+				`<script id="api-reference" type="application/json"`,
+				`<script src="` + DefaultCDN + `"></script>`,
+			},
+		},
+		{
+			name:     "swagger ui",
+			renderer: SwaggerUI{},
+			wantContains: []string{
+				`<script crossorigin src="` + DefaultSwaggerUICDN + `/swagger-ui-bundle.js"></script>`,
+				`SwaggerUIBundle({ spec: {"info"`,
+			},
+		},
+		{
+			name:     "swagger ui cdn",
+			renderer: SwaggerUI{CDN: "https://cdn.example.com/swagger/"},
+			wantContains: []string{
+				`href="https://cdn.example.com/swagger/swagger-ui.css"`,
+			},
+		},
+		{
+			name:     "redoc",
+			renderer: Redoc{},
+			wantContains: []string{
+				`<script src="` + DefaultRedocCDN + `"></script>`,
+				`Redoc.init({"info"`,
+			},
+		},
+		{
+			name:     "redoc cdn",
+			renderer: Redoc{CDN: `https://cdn.example.com/redoc.js?a=1&b="2"`},
+			wantContains: []string{
+				`<script src="https://cdn.example.com/redoc.js?a=1&amp;b=&#34;2&#34;"></script>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderHTML(t.Context(), tt.renderer, &Options{
+				SpecContent:   maliciousSpec,
+				CustomOptions: CustomOptions{PageTitle: maliciousTitle},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected output to contain %q, got %s", want, got)
+				}
+			}
+
+			if !strings.Contains(got, "<title>&lt;/title&gt;&lt;script&gt;alert(&#34;title&#34;)&lt;/script&gt;</title>") {
+				t.Errorf("expected escaped page title, got %s", got)
+			}
+			if !strings.Contains(got, `\u003c/script\u003e\u003cscript\u003ealert('spec')`) {
+				t.Errorf("expected escaped spec content, got %s", got)
+			}
+			if strings.Contains(got, "<script>alert") {
+				t.Errorf("expected no injected script element, got %s", got)
+			}
+		})
+	}
+}
+
+func TestScalar_RenderConfiguration(t *testing.T) {
+	got, err := ApiReferenceHTML(&Options{SpecContent: `{"openapi": "3.1.0"}`, DarkMode: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(got, `&quot;darkMode&quot;:true`) {
+		t.Errorf("expected options in the configuration, got %s", got)
+	}
+	if strings.Contains(got, "specContent") {
+		t.Errorf("expected the spec to be left out of the configuration, got %s", got)
+	}
+}
+
+func TestRendererByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Renderer
+		wantErr bool
+	}{
+		{name: "scalar", want: Scalar{}},
+		{name: "Swagger-UI", want: SwaggerUI{}},
+		{name: "redoc", want: Redoc{}},
+		{name: "rapidoc", wantErr: true},
+		{name: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RendererByName(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("want %#v got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderHTML_NilOptions(t *testing.T) {
+	if _, err := RenderHTML(t.Context(), Scalar{}, nil); err == nil {
+		t.Fatalf("expected error")
+	}
+}
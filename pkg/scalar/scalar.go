@@ -1,8 +1,8 @@
 package scalar
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"strings"
 )
 
@@ -34,66 +34,8 @@ func specContentHandler(specContent interface{}) string {
 	}
 }
 
+// ApiReferenceHTML renders the Scalar API reference page for the spec configured by optionsInput. A remote spec is
+// only bounded by the Fetcher timeout; use RenderHTML to tie it to a request context.
 func ApiReferenceHTML(optionsInput *Options) (string, error) {
-	options := DefaultOptions(*optionsInput)
-
-	if options.SpecURL == "" && options.SpecContent == nil {
-		return "", fmt.Errorf("specURL or specContent must be provided")
-	}
-
-	if options.SpecContent == nil && options.SpecURL != "" {
-
-		if strings.HasPrefix(options.SpecURL, "http") {
-			content, err := fetchContentFromURL(options.SpecURL)
-			if err != nil {
-				return "", err
-			}
-			options.SpecContent = content
-		} else {
-			urlPath, err := ensureFileURL(options.SpecURL)
-			if err != nil {
-				return "", err
-			}
-
-			content, err := readFileFromURL(urlPath)
-			if err != nil {
-				return "", err
-			}
-
-			options.SpecContent = string(content)
-		}
-	}
-
-	dataConfig := safeJSONConfiguration(options)
-	specContentHTML := specContentHandler(options.SpecContent)
-
-	var pageTitle string
-
-	if options.CustomOptions.PageTitle != "" {
-		pageTitle = options.CustomOptions.PageTitle
-	} else {
-		pageTitle = "Scalar API Reference"
-	}
-
-	customThemeCss := CustomThemeCSS
-
-	if options.Theme != "" {
-		customThemeCss = ""
-	}
-
-	return fmt.Sprintf(`
-    <!DOCTYPE html>
-    <html>
-      <head>
-        <title>%s</title>
-        <meta charset="utf-8" />
-        <meta name="viewport" content="width=device-width, initial-scale=1" />
-        <style>%s</style>
-      </head>
-      <body>
-        <script id="api-reference" type="application/json" data-configuration="%s">%s</script>
-        <script src="%s"></script>
-      </body>
-    </html>
-  `, pageTitle, customThemeCss, dataConfig, specContentHTML, options.CDN), nil
+	return RenderHTML(context.Background(), Scalar{}, optionsInput)
 }
@@ -91,12 +91,17 @@ func TestSpecContentHandler(t *testing.T) {
 func TestApiReferenceHTML(t *testing.T) {
 	tmpDir := t.TempDir()
 	filePath := filepath.Join(tmpDir, "spec.json")
-	if err := os.WriteFile(filePath, []byte("file-spec"), 0o600); err != nil {
+	if err := os.WriteFile(filePath, []byte("info:\n  title: file-spec\n"), 0o600); err != nil {
 		t.Fatalf("write temp file: %v", err)
 	}
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte("remote-spec"))
+		if r.URL.Path == "/redirect" {
+			// Without a Fetcher only the host of SpecURL is allowed.
+			http.Redirect(w, r, "http://localhost:1/spec.json", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"info": {"title": "remote-spec"}}`))
 	}))
 	defer server.Close()
 
@@ -135,7 +140,7 @@ func TestApiReferenceHTML(t *testing.T) {
 		{
 			name: "customTitleTheme",
 			options: &Options{
-				SpecContent: "spec",
+				SpecContent: "openapi: 3.1.0",
 				Theme:       ThemeMoon,
 				CustomOptions: CustomOptions{
 					PageTitle: "My Title",
@@ -148,7 +153,7 @@ func TestApiReferenceHTML(t *testing.T) {
 				if strings.Contains(got, "--scalar-color-1") {
 					t.Fatalf("expected no custom theme css in output")
 				}
-				if !strings.Contains(got, ">spec</script>") {
+				if !strings.Contains(got, `>{"openapi":"3.1.0"}</script>`) {
 					t.Fatalf("expected spec content in output")
 				}
 			},
@@ -164,13 +169,28 @@ func TestApiReferenceHTML(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "http redirect to another host",
+			options: &Options{
+				SpecURL: server.URL + "/redirect",
+			},
+			wantErr: true,
+		},
+		{
+			name: "http disallowed host",
+			options: &Options{
+				SpecURL: server.URL,
+				Fetcher: &Fetcher{AllowedHosts: []string{"docs.example.com"}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "file",
 			options: &Options{
 				SpecURL: filePath,
 			},
 			check: func(t *testing.T, got string) {
-				if !strings.Contains(got, "file-spec") {
+				if !strings.Contains(got, `{"info":{"title":"file-spec"}}`) {
 					t.Fatalf("expected file spec content in output")
 				}
 			},
@@ -0,0 +1,155 @@
+package scalar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidSpec is returned when a spec is neither a JSON nor a YAML object.
+var ErrInvalidSpec = errors.New("scalar: spec must be a JSON or YAML object")
+
+// LoadSpec loads the spec configured by options from SpecContent or, when that is nil, from SpecURL, a remote URL
+// or a local file. Remote specs are fetched with options.Fetcher, or from the host of SpecURL only when it is nil,
+// and the download is abandoned when ctx is done. JSON and YAML specs are both returned as compact JSON with <, >
+// and & escaped, so the result can be placed in a script element as JSON or as a JavaScript object literal.
+func LoadSpec(ctx context.Context, options *Options) ([]byte, error) {
+	content, err := specSource(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeSpec([]byte(content))
+}
+
+// specSource returns the raw spec configured by options.
+func specSource(ctx context.Context, options *Options) (string, error) {
+	if options.SpecContent != nil {
+		return specContentHandler(options.SpecContent), nil
+	}
+
+	if options.SpecURL == "" {
+		return "", fmt.Errorf("specURL or specContent must be provided")
+	}
+
+	if strings.HasPrefix(options.SpecURL, "http") {
+		if options.Fetcher != nil {
+			return options.Fetcher.Fetch(ctx, options.SpecURL)
+		}
+
+		u, err := url.Parse(options.SpecURL)
+		if err != nil {
+			return "", fmt.Errorf("error parsing spec url: %w", err)
+		}
+		return defaultFetcher.fetch(ctx, options.SpecURL, []string{u.Host})
+	}
+
+	urlPath, err := ensureFileURL(options.SpecURL)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := readFileFromURL(urlPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// normalizeSpec converts a JSON or YAML spec to compact, HTML-safe JSON. JSON specs keep their key order; YAML
+// specs are converted node by node so theirs is kept too.
+func normalizeSpec(content []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(content)
+
+	var spec []byte
+	if json.Valid(trimmed) {
+		spec = trimmed
+	} else {
+		var node yaml.Node
+		if err := yaml.Unmarshal(trimmed, &node); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+		}
+
+		var buf bytes.Buffer
+		if err := writeYAMLAsJSON(&buf, &node); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+		}
+		spec = buf.Bytes()
+	}
+
+	if len(spec) == 0 || spec[0] != '{' {
+		return nil, ErrInvalidSpec
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, spec); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+
+	var safe bytes.Buffer
+	json.HTMLEscape(&safe, buf.Bytes())
+	return safe.Bytes(), nil
+}
+
+// writeYAMLAsJSON writes the YAML node as JSON, keeping the order of mapping keys.
+func writeYAMLAsJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return errors.New("empty document")
+		}
+		return writeYAMLAsJSON(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeYAMLAsJSON(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeYAMLAsJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeYAMLAsJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.ScalarNode:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		content, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(content)
+		return nil
+	default:
+		return fmt.Errorf("unsupported yaml node kind %d", node.Kind)
+	}
+}
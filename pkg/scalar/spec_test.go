@@ -0,0 +1,96 @@
+package scalar
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "json keeps key order",
+			content: `{"openapi": "3.1.0", "info": {"title": "T"}, "paths": {"/b": {}, "/a": {}}}`,
+			want:    `{"openapi":"3.1.0","info":{"title":"T"},"paths":{"/b":{},"/a":{}}}`,
+		},
+		{
+			name:    "yaml keeps key order",
+			content: "openapi: 3.1.0\ninfo:\n  title: T\n  version: 1\npaths:\n  /b: {}\n  /a: {}\ntags: [b, a]\n",
+			want:    `{"openapi":"3.1.0","info":{"title":"T","version":1},"paths":{"/b":{},"/a":{}},"tags":["b","a"]}`,
+		},
+		{
+			name:    "yaml alias",
+			content: "base: &b\n  type: string\nname: *b\n",
+			want:    `{"base":{"type":"string"},"name":{"type":"string"}}`,
+		},
+		{
+			name:    "html escaped",
+			content: `{"info": {"title": "</script><script>alert(1)</script> & more"}}`,
+			want:    `{"info":{"title":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e \u0026 more"}}`,
+		},
+		{
+			name:    "yaml html escaped",
+			content: "info:\n  title: </script>\n",
+			want:    `{"info":{"title":"\u003c/script\u003e"}}`,
+		},
+		{name: "scalar", content: "spec", wantErr: true},
+		{name: "array", content: `[1, 2]`, wantErr: true},
+		{name: "empty", content: "", wantErr: true},
+		{name: "invalid", content: "a: [", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeSpec([]byte(tt.content))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSpec) {
+					t.Fatalf("expected ErrInvalidSpec, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("want %s got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *Options
+		want    string
+		wantErr bool
+	}{
+		{name: "content", options: &Options{SpecContent: "openapi: 3.1.0"}, want: `{"openapi":"3.1.0"}`},
+		{name: "map", options: &Options{SpecContent: map[string]interface{}{"openapi": "3.1.0"}}, want: `{"openapi":"3.1.0"}`},
+		{name: "unsupported content", options: &Options{SpecContent: 123}, wantErr: true},
+		{name: "missing", options: &Options{}, wantErr: true},
+		{name: "missing file", options: &Options{SpecURL: "/does/not/exist.json"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadSpec(t.Context(), tt.options)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("want %s got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -117,6 +117,8 @@ type Options struct {
 	PathRouting        string              `json:"pathRouting,omitempty"`
 	BaseServerURL      string              `json:"baseServerUrl,omitempty"`
 	WithDefaultFonts   bool                `json:"withDefaultFonts,omitempty"`
+	// Fetcher downloads SpecURL when it is an http or https URL. Nil uses a Fetcher with the default limits.
+	Fetcher *Fetcher `json:"-"`
 	CustomOptions
 }
 
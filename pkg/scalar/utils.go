@@ -2,8 +2,6 @@ package scalar
 
 import (
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -43,22 +41,6 @@ func ensureFileURL(filePath string) (string, error) {
 	return "file://" + resolvedPath, nil
 }
 
-// fetchContentFromURL reads the content from a URL and returns it as a string
-func fetchContentFromURL(fileURL string) (string, error) {
-	resp, err := http.Get(fileURL)
-	if err != nil {
-		return "", fmt.Errorf("error getting file content: %w", err)
-	}
-	defer resp.Body.Close()
-
-	content, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("error reading file content: %w", err)
-	}
-
-	return string(content), nil
-}
-
 func readFileFromURL(fileURL string) ([]byte, error) {
 	parsedURL, err := url.Parse(fileURL)
 	if err != nil {
@@ -1,9 +1,6 @@
 package scalar
 
 import (
-	"io"
-	"net/http"
-	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -60,41 +57,6 @@ func TestEnsureFileURL(t *testing.T) {
 	}
 }
 
-func TestFetchContentFromURL(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, _ = io.WriteString(w, "ok")
-	}))
-	defer server.Close()
-
-	cases := []struct {
-		name    string
-		url     string
-		want    string
-		wantErr bool
-	}{
-		{name: "ok", url: server.URL, want: "ok"},
-		{name: "bad", url: "http://[::1", wantErr: true},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			got, err := fetchContentFromURL(tc.url)
-			if tc.wantErr {
-				if err == nil {
-					t.Fatalf("expected error")
-				}
-				return
-			}
-			if err != nil {
-				t.Fatalf("fetchContentFromURL error: %v", err)
-			}
-			if got != tc.want {
-				t.Fatalf("want %q got %q", tc.want, got)
-			}
-		})
-	}
-}
-
 func TestReadFileFromURL(t *testing.T) {
 	tempDir := t.TempDir()
 	filePath := filepath.Join(tempDir, "data.txt")
@@ -0,0 +1,165 @@
+// Package tenant identifies which tenant, e.g. which storefront, a request belongs to and carries that tenant
+// through a context.Context so storage can be partitioned by it.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultID is the tenant of requests that name none. It always exists and holds the built-in categories.
+	DefaultID = "default"
+
+	// DefaultHeader is the request header naming the tenant when no other header is configured.
+	DefaultHeader = "X-Tenant-ID"
+
+	// DefaultClaim is the token claim naming the tenant when no other claim is configured.
+	DefaultClaim = "tenant"
+)
+
+var (
+	// ErrInvalidID is returned for tenant IDs that are not lower-case letters, digits and inner hyphens.
+	ErrInvalidID = errors.New("tenant id must be 1-63 lower-case letters, digits or inner hyphens")
+
+	// ErrInvalidToken is returned for bearer tokens that are malformed, expired or not signed with the secret.
+	ErrInvalidToken = errors.New("tenant token is invalid")
+
+	// ErrMissingToken is returned for requests without a bearer token while token resolution is enabled.
+	ErrMissingToken = errors.New("tenant token is required")
+
+	// ErrConflict is returned when the tenant of the token and the tenant named by the request disagree.
+	ErrConflict = errors.New("tenant of the token does not match the tenant of the request")
+)
+
+// idPattern matches tenant IDs: a DNS label, so every tenant can also be addressed by subdomain.
+var idPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidID reports whether id can name a tenant.
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// contextKey is the key under which the tenant ID is stored in a context.
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant id.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ID carried by ctx, or DefaultID when it carries none.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultID
+}
+
+// Options configures where a Resolver looks for the tenant of a request. Zero values fall back to the defaults.
+type Options struct {
+	// Header names the request header carrying the tenant ID. Empty means DefaultHeader.
+	Header string
+
+	// BaseDomain enables subdomain resolution: a request to acme.BaseDomain belongs to tenant acme. Empty disables it.
+	BaseDomain string
+
+	// Secret enables token resolution: every request must carry an HS256 bearer token signed with Secret, and the
+	// tenant is read from its Claim. Empty disables it.
+	Secret []byte
+
+	// Claim names the token claim carrying the tenant ID. Empty means DefaultClaim.
+	Claim string
+}
+
+// Resolver finds the tenant a request belongs to.
+type Resolver struct {
+	options Options
+}
+
+// NewResolver initializes a Resolver with the given options.
+func NewResolver(options Options) (*Resolver, error) {
+	if options.Header == "" {
+		options.Header = DefaultHeader
+	}
+	if options.Claim == "" {
+		options.Claim = DefaultClaim
+	}
+	options.BaseDomain = strings.Trim(strings.ToLower(options.BaseDomain), ".")
+
+	return &Resolver{options: options}, nil
+}
+
+// Header returns the request header the resolver reads the tenant ID from.
+func (t *Resolver) Header() string {
+	return t.options.Header
+}
+
+// Resolve returns the tenant of a request to host with the given header. With token resolution enabled, the
+// tenant is the one the bearer token claims, DefaultID for tokens claiming none, and the header and subdomain must
+// agree with it when they name a tenant too, so they can never reach another tenant. Without it, the header is
+// preferred over the subdomain. Requests naming no tenant belong to DefaultID.
+func (t *Resolver) Resolve(host string, header http.Header) (string, error) {
+	named := strings.TrimSpace(header.Get(t.options.Header))
+	if named == "" {
+		named = t.subdomain(host)
+	}
+
+	if len(t.options.Secret) > 0 {
+		claimed, err := t.claim(header)
+		if err != nil {
+			return "", err
+		}
+		if named != "" && named != claimed {
+			return "", ErrConflict
+		}
+		named = claimed
+	}
+	if named == "" {
+		return DefaultID, nil
+	}
+
+	if !ValidID(named) {
+		return "", ErrInvalidID
+	}
+	return named, nil
+}
+
+// claim returns the tenant claimed by the bearer token in header, DefaultID when the token claims none, or
+// ErrMissingToken when the request carries no bearer token.
+func (t *Resolver) claim(header http.Header) (string, error) {
+	token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return "", ErrMissingToken
+	}
+
+	claims, err := verifyToken(strings.TrimSpace(token), t.options.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	if id, _ := claims[t.options.Claim].(string); id != "" {
+		return id, nil
+	}
+	return DefaultID, nil
+}
+
+// subdomain returns the first label of host when host lies directly under the base domain, or "".
+func (t *Resolver) subdomain(host string) string {
+	if t.options.BaseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+t.options.BaseDomain)
+	if !ok || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -0,0 +1,159 @@
+package tenant
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var secret = []byte("s3cret")
+
+// signToken returns an HS256 token with claims, signed with key.
+func signToken(t *testing.T, claims map[string]any, key []byte) string {
+	t.Helper()
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != DefaultID {
+		t.Errorf("expected %s without a tenant, got %s", DefaultID, got)
+	}
+	if got := FromContext(WithID(context.Background(), "acme")); got != "acme" {
+		t.Errorf("expected acme, got %s", got)
+	}
+}
+
+func TestValidID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "acme", want: true},
+		{id: "toko-2", want: true},
+		{id: "a", want: true},
+		{id: "", want: false},
+		{id: "Acme", want: false},
+		{id: "-acme", want: false},
+		{id: "acme-", want: false},
+		{id: "acme.shop", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := ValidID(tt.id); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	plain, err := NewResolver(Options{BaseDomain: "shop.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	signed, err := NewResolver(Options{BaseDomain: "shop.example.com", Secret: secret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acmeToken := "Bearer " + signToken(t, map[string]any{"tenant": "acme"}, secret)
+	tests := []struct {
+		name    string
+		signed  bool
+		host    string
+		header  map[string]string
+		want    string
+		wantErr error
+	}{
+		{name: "nothing", host: "api.example.com", want: DefaultID},
+		{name: "header", header: map[string]string{"X-Tenant-ID": "acme"}, want: "acme"},
+		{name: "subdomain", host: "acme.shop.example.com:8000", want: "acme"},
+		{name: "nested subdomain", host: "a.acme.shop.example.com", want: DefaultID},
+		{name: "header before subdomain", host: "acme.shop.example.com", header: map[string]string{"X-Tenant-ID": "globex"}, want: "globex"},
+		{name: "invalid id", header: map[string]string{"X-Tenant-ID": "Acme Inc"}, wantErr: ErrInvalidID},
+		{name: "claim", signed: true, header: map[string]string{"Authorization": acmeToken}, want: "acme"},
+		{name: "claim agreeing with header", signed: true, header: map[string]string{"Authorization": acmeToken, "X-Tenant-ID": "acme"}, want: "acme"},
+		{name: "claim contradicting header", signed: true, header: map[string]string{"Authorization": acmeToken, "X-Tenant-ID": "globex"}, wantErr: ErrConflict},
+		{name: "claim contradicting subdomain", signed: true, host: "globex.shop.example.com", header: map[string]string{"Authorization": acmeToken}, wantErr: ErrConflict},
+		{name: "no claim", signed: true, header: map[string]string{"Authorization": "Bearer " + signToken(t, map[string]any{"sub": "ops"}, secret)}, want: DefaultID},
+		{
+			name:    "no claim contradicting header",
+			signed:  true,
+			header:  map[string]string{"Authorization": "Bearer " + signToken(t, map[string]any{"sub": "ops"}, secret), "X-Tenant-ID": "acme"},
+			wantErr: ErrConflict,
+		},
+		{name: "no token", signed: true, wantErr: ErrMissingToken},
+		{name: "header without token", signed: true, header: map[string]string{"X-Tenant-ID": "acme"}, wantErr: ErrMissingToken},
+		{name: "subdomain without token", signed: true, host: "acme.shop.example.com", wantErr: ErrMissingToken},
+		{name: "forged token", signed: true, header: map[string]string{"Authorization": "Bearer " + signToken(t, map[string]any{"tenant": "acme"}, []byte("guess"))}, wantErr: ErrInvalidToken},
+		{name: "expired token", signed: true, header: map[string]string{"Authorization": "Bearer " + signToken(t, map[string]any{"tenant": "acme", "exp": 1}, secret)}, wantErr: ErrInvalidToken},
+		{name: "malformed token", signed: true, header: map[string]string{"Authorization": "Bearer abc"}, wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.header {
+				header.Set(name, value)
+			}
+
+			resolver := plain
+			if tt.signed {
+				resolver = signed
+			}
+			got, err := resolver.Resolve(tt.host, header)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected tenant %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolver_TokensDisabled(t *testing.T) {
+	resolver, err := NewResolver(Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without a secret the token is not trusted, so it neither selects nor contradicts a tenant.
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+signToken(t, map[string]any{"tenant": "acme"}, secret))
+	header.Set("X-Tenant-ID", "globex")
+
+	got, err := resolver.Resolve("acme.shop.example.com", header)
+	if err != nil || got != "globex" {
+		t.Errorf("expected globex, got %q, %v", got, err)
+	}
+}
+
+func TestVerifyToken_Expiry(t *testing.T) {
+	defer func(original func() time.Time) { now = original }(now)
+	now = func() time.Time { return time.Unix(100, 0) }
+
+	if _, err := verifyToken(signToken(t, map[string]any{"exp": 101}, secret), secret); err != nil {
+		t.Errorf("expected a live token to verify, got %v", err)
+	}
+	if _, err := verifyToken(signToken(t, map[string]any{"exp": 100}, secret), secret); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected an expired token to fail, got %v", err)
+	}
+}